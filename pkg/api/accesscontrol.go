@@ -26,6 +26,7 @@ var (
 	ScopeProvisionersDatasources   = ac.Scope("provisioners", "datasources")
 	ScopeProvisionersNotifications = ac.Scope("provisioners", "notifications")
 	ScopeProvisionersAlertRules    = ac.Scope("provisioners", "alerting")
+	ScopeProvisionersLivePipeline  = ac.Scope("provisioners", "live")
 )
 
 // declareFixedRoles declares to the AccessControl service fixed roles and their
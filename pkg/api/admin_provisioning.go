@@ -107,3 +107,26 @@ func (hs *HTTPServer) AdminProvisioningReloadAlerting(c *models.ReqContext) resp
 	}
 	return response.Success("Alerting config reloaded")
 }
+
+// swagger:route POST /admin/provisioning/live/reload admin_provisioning adminProvisioningReloadLivePipeline
+//
+// Reload Live pipeline provisioning configurations.
+//
+// Reloads the provisioning config files for Live pipeline channel rules and write configs again. It won’t return until the new provisioned entities are already stored in the database.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `provisioning:reload` and scope `provisioners:live`.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminProvisioningReloadLivePipeline(c *models.ReqContext) response.Response {
+	err := hs.ProvisioningService.ProvisionLivePipeline(c.Req.Context())
+	if err != nil {
+		return response.Error(500, "Failed to reload live pipeline config", err)
+	}
+	return response.Success("Live pipeline config reloaded")
+}
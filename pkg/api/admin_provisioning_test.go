@@ -162,6 +162,27 @@ func TestAPI_AdminProvisioningReload_AccessControl(t *testing.T) {
 			url:          "/api/admin/provisioning/alerting/reload",
 			exit:         true,
 		},
+		{
+			desc:         "should work for live pipeline with specific scope",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"message":"Live pipeline config reloaded"}`,
+			permissions: []accesscontrol.Permission{
+				{
+					Action: ActionProvisioningReload,
+					Scope:  ScopeProvisionersLivePipeline,
+				},
+			},
+			url: "/api/admin/provisioning/live/reload",
+			checkCall: func(mock provisioning.ProvisioningServiceMock) {
+				assert.Len(t, mock.Calls.ProvisionLivePipeline, 1)
+			},
+		},
+		{
+			desc:         "should fail for live pipeline with no permission",
+			expectedCode: http.StatusForbidden,
+			url:          "/api/admin/provisioning/live/reload",
+			exit:         true,
+		},
 	}
 
 	cfg := setting.NewCfg()
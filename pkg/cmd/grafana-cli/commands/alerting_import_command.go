@@ -0,0 +1,142 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// prometheusRulesImport imports the rules of Prometheus rule files to a folder of a running Grafana server, which converts
+// them to Grafana managed rules.
+type prometheusRulesImport struct {
+	client        *http.Client
+	grafanaURL    string
+	token         string
+	folder        string
+	datasourceUID string
+	dryRun        bool
+}
+
+func importPrometheusRulesCommand(c utils.CommandLine) error {
+	path := c.Args().First()
+	if path == "" {
+		return errors.New("missing path of the Prometheus rule file")
+	}
+	i := prometheusRulesImport{
+		client:        &http.Client{Timeout: time.Minute},
+		grafanaURL:    c.String("url"),
+		token:         c.String("token"),
+		folder:        c.String("folder"),
+		datasourceUID: c.String("datasource-uid"),
+		dryRun:        c.Bool("dry-run"),
+	}
+	if i.folder == "" {
+		return errors.New("missing folder flag")
+	}
+	if i.datasourceUID == "" {
+		return errors.New("missing datasource-uid flag")
+	}
+
+	result, err := i.importFile(path)
+	if err != nil {
+		return err
+	}
+
+	rules := 0
+	for _, group := range result.Groups {
+		rules += len(group.Rules)
+	}
+	if i.dryRun {
+		logger.Infof("%d rules of %d rule groups can be imported to folder %s\n", rules, len(result.Groups), i.folder)
+	} else {
+		logger.Infof("%s %d rules of %d rule groups imported to folder %s\n", color.GreenString("✔"), rules, len(result.Groups), i.folder)
+	}
+	if len(result.Skipped) > 0 {
+		logger.Infof("%d rules could not be imported:\n", len(result.Skipped))
+	}
+	for _, skipped := range result.Skipped {
+		logger.Infof("%s %s %s %s: %s\n", color.RedString("✗"), skipped.Group, color.YellowString("/"), skipped.Rule, skipped.Reason)
+	}
+	return nil
+}
+
+// importFile reads the Prometheus rule file and posts its rule groups to the import endpoint of Grafana.
+func (i prometheusRulesImport) importFile(path string) (*apimodels.PrometheusRulesImportResult, error) {
+	// We can ignore the gosec G304 warning since the path is an argument of the command.
+	// nolint:gosec
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rule file: %w", err)
+	}
+	var file apimodels.PrometheusRuleFile
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse rule file: %w", err)
+	}
+	if len(file.Groups) == 0 {
+		return nil, errors.New("rule file does not have any rule groups")
+	}
+
+	body, err := json.Marshal(apimodels.PrometheusRulesImportBody{
+		DatasourceUID: i.datasourceUID,
+		DryRun:        i.dryRun,
+		Groups:        file.Groups,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	u := strings.TrimSuffix(i.grafanaURL, "/") + "/api/ruler/grafana/api/v1/import/prometheus/" + url.PathEscape(i.folder)
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if i.token != "" {
+		req.Header.Set("Authorization", "Bearer "+i.token)
+	}
+
+	logger.Debugf("importing rules to %s\n", u)
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "err", err)
+		}
+	}()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		var errResp struct {
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(respBody, &errResp); err != nil || errResp.Message == "" {
+			errResp.Message = string(respBody)
+		}
+		return nil, fmt.Errorf("failed to import rules: %s: %s", resp.Status, errResp.Message)
+	}
+
+	var result apimodels.PrometheusRulesImportResult
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &result, nil
+}
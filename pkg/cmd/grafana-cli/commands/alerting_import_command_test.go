@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+const testPrometheusRuleFile = `
+groups:
+  - name: api
+    interval: 30s
+    rules:
+      - alert: HighErrorRate
+        expr: rate(errors_total[5m]) > 0.5
+        for: 5m
+        labels:
+          severity: critical
+        annotations:
+          summary: Error rate is high
+      - record: job:up:sum
+        expr: sum by (job) (up)
+`
+
+func TestImportPrometheusRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testPrometheusRuleFile), 0600))
+
+	t.Run("should post the rule groups of the file", func(t *testing.T) {
+		var received apimodels.PrometheusRulesImportBody
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPost, r.Method)
+			require.Equal(t, "/api/ruler/grafana/api/v1/import/prometheus/My%20Folder", r.URL.EscapedPath())
+			require.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+			require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"groups": [], "skipped": [{"group": "api", "rule": "HighErrorRate", "reason": "invalid"}]}`))
+		}))
+		t.Cleanup(server.Close)
+
+		i := prometheusRulesImport{
+			client:        server.Client(),
+			grafanaURL:    server.URL + "/",
+			token:         "secret",
+			folder:        "My Folder",
+			datasourceUID: "prom",
+			dryRun:        true,
+		}
+		result, err := i.importFile(path)
+		require.NoError(t, err)
+		require.Equal(t, []apimodels.SkippedPrometheusRule{{Group: "api", Rule: "HighErrorRate", Reason: "invalid"}}, result.Skipped)
+
+		require.Equal(t, "prom", received.DatasourceUID)
+		require.True(t, received.DryRun)
+		require.Len(t, received.Groups, 1)
+		group := received.Groups[0]
+		require.Equal(t, "api", group.Name)
+		require.Equal(t, model.Duration(30*time.Second), group.Interval)
+		require.Len(t, group.Rules, 2)
+		require.Equal(t, "HighErrorRate", group.Rules[0].Alert)
+		require.Equal(t, "rate(errors_total[5m]) > 0.5", group.Rules[0].Expr)
+		require.Equal(t, model.Duration(5*time.Minute), *group.Rules[0].For)
+		require.Equal(t, map[string]string{"severity": "critical"}, group.Rules[0].Labels)
+		require.Equal(t, "job:up:sum", group.Rules[1].Record)
+	})
+
+	t.Run("should return the error message of the response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "folder does not exist"}`))
+		}))
+		t.Cleanup(server.Close)
+
+		i := prometheusRulesImport{client: server.Client(), grafanaURL: server.URL, folder: "unknown", datasourceUID: "prom"}
+		_, err := i.importFile(path)
+		require.EqualError(t, err, "failed to import rules: 404 Not Found: folder does not exist")
+	})
+
+	t.Run("should fail if the file has no rule groups", func(t *testing.T) {
+		empty := filepath.Join(t.TempDir(), "empty.yaml")
+		require.NoError(t, os.WriteFile(empty, []byte("groups: []"), 0600))
+
+		i := prometheusRulesImport{client: http.DefaultClient, grafanaURL: "http://localhost", folder: "folder", datasourceUID: "prom"}
+		_, err := i.importFile(empty)
+		require.EqualError(t, err, "rule file does not have any rule groups")
+	})
+}
@@ -38,6 +38,12 @@ func RunCLI(version string) int {
 				Value:   "https://grafana.com/api/plugins",
 				EnvVars: []string{"GF_PLUGIN_REPO"},
 			},
+			&cli.StringFlag{
+				Name:    "repoConfig",
+				Usage:   "Path to a YAML file with plugin repositories to use instead of the repo URL",
+				Value:   "",
+				EnvVars: []string{"GF_PLUGIN_REPO_CONFIG"},
+			},
 			&cli.StringFlag{
 				Name:    "pluginUrl",
 				Usage:   "Full url to the plugin zip file instead of downloading the plugin from grafana.com/api",
@@ -70,7 +76,7 @@ func RunCLI(version string) int {
 	}
 
 	app.Before = func(c *cli.Context) error {
-		services.Init(version, c.Bool("insecure"), c.Bool("debug"))
+		services.Init(version, c.Bool("debug"))
 		return nil
 	}
 
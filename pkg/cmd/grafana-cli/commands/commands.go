@@ -10,7 +10,6 @@ import (
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/secretsmigrations"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/runner"
-	"github.com/grafana/grafana/pkg/cmd/grafana-cli/services"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
@@ -103,15 +102,23 @@ func runPluginCommand(command func(commandLine utils.CommandLine) error) func(co
 	}
 }
 
-// Command contains command state.
-type Command struct {
-	Client utils.ApiClient
-}
+func runAlertingCommand(command func(commandLine utils.CommandLine) error) func(context *cli.Context) error {
+	return func(context *cli.Context) error {
+		cmd := &utils.ContextCommandLine{Context: context}
+		if err := command(cmd); err != nil {
+			return err
+		}
 
-var cmd Command = Command{
-	Client: &services.GrafanaComClient{},
+		logger.Info("\n")
+		return nil
+	}
 }
 
+// Command contains command state.
+type Command struct{}
+
+var cmd Command = Command{}
+
 var pluginCommands = []*cli.Command{
 	{
 		Name:   "install",
@@ -227,6 +234,40 @@ var adminCommands = []*cli.Command{
 	},
 }
 
+var alertingCommands = []*cli.Command{
+	{
+		Name:   "import-prometheus-rules",
+		Usage:  "import-prometheus-rules <rule file>. Converts the rules of a Prometheus rule file to Grafana managed rules of a folder, replacing rule groups with the same names",
+		Action: runAlertingCommand(importPrometheusRulesCommand),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "url",
+				Usage:   "URL of the Grafana server",
+				Value:   "http://localhost:3000",
+				EnvVars: []string{"GRAFANA_URL"},
+			},
+			&cli.StringFlag{
+				Name:    "token",
+				Usage:   "Service account token or API key used to authenticate with the Grafana server",
+				EnvVars: []string{"GRAFANA_TOKEN"},
+			},
+			&cli.StringFlag{
+				Name:  "folder",
+				Usage: "Title of the folder to import the rules to",
+			},
+			&cli.StringFlag{
+				Name:  "datasource-uid",
+				Usage: "UID of the Prometheus or Loki data source that is queried by the rules",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Convert the rules and report rules that cannot be converted, without saving them",
+				Value: false,
+			},
+		},
+	},
+}
+
 var Commands = []*cli.Command{
 	{
 		Name:        "plugins",
@@ -238,4 +279,9 @@ var Commands = []*cli.Command{
 		Usage:       "Grafana admin commands",
 		Subcommands: adminCommands,
 	},
+	{
+		Name:        "alerting",
+		Usage:       "Grafana alerting commands",
+		Subcommands: alertingCommands,
+	},
 }
@@ -8,7 +8,6 @@ import (
 	"runtime"
 	"strings"
 
-	"github.com/grafana/grafana/pkg/cmd/grafana-cli/models"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/services"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
 	"github.com/grafana/grafana/pkg/plugins/repo"
@@ -52,16 +51,40 @@ func (cmd Command) installCommand(c utils.CommandLine) error {
 	return installPlugin(context.Background(), pluginID, version, c)
 }
 
-// installPlugin downloads the plugin code as a zip file from the Grafana.com API
+// pluginRepository returns the plugin repository set with the repo flag, or the
+// list of repositories configured in the file set with the repoConfig flag.
+func pluginRepository(c utils.CommandLine) (*repo.Manager, error) {
+	skipTLSVerify := c.Bool("insecure")
+	if repoConfig := c.PluginRepoConfig(); repoConfig != "" {
+		repositories, err := repo.ReadRepositoriesConfig(repoConfig)
+		if err != nil {
+			return nil, err
+		}
+		if skipTLSVerify {
+			for i := range repositories {
+				repositories[i].SkipTLSVerify = true
+			}
+		}
+		return repo.NewWithRepositories(repositories, services.Logger), nil
+	}
+	return repo.New(skipTLSVerify, c.PluginRepoURL(), services.Logger), nil
+}
+
+func compatOpts() repo.CompatOpts {
+	return repo.NewCompatOpts(services.GrafanaVersion, runtime.GOOS, runtime.GOARCH)
+}
+
+// installPlugin downloads the plugin code as a zip file from the plugin repositories
 // and then extracts the zip into the plugin's directory.
 func installPlugin(ctx context.Context, pluginID, version string, c utils.CommandLine) error {
-	skipTLSVerify := c.Bool("insecure")
-	repository := repo.New(skipTLSVerify, c.PluginRepoURL(), services.Logger)
+	repository, err := pluginRepository(c)
+	if err != nil {
+		return err
+	}
 
-	compatOpts := repo.NewCompatOpts(services.GrafanaVersion, runtime.GOOS, runtime.GOARCH)
+	compatOpts := compatOpts()
 
 	var archive *repo.PluginArchive
-	var err error
 	pluginZipURL := c.PluginURL()
 	if pluginZipURL != "" {
 		if archive, err = repository.GetPluginArchiveByURL(ctx, pluginZipURL, compatOpts); err != nil {
@@ -100,7 +123,7 @@ func osAndArchString() string {
 	return osString + "-" + arch
 }
 
-func supportsCurrentArch(version *models.Version) bool {
+func supportsCurrentArch(version *repo.Version) bool {
 	if version.Arch == nil {
 		return true
 	}
@@ -112,7 +135,7 @@ func supportsCurrentArch(version *models.Version) bool {
 	return false
 }
 
-func latestSupportedVersion(plugin *models.Plugin) *models.Version {
+func latestSupportedVersion(plugin *repo.Plugin) *repo.Version {
 	for _, v := range plugin.Versions {
 		ver := v
 		if supportsCurrentArch(&ver) {
@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"context"
+
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
 )
@@ -8,12 +10,16 @@ import (
 // listRemoteCommand prints out all plugins in the remote repo with latest version supported on current platform.
 // If there are no supported versions for plugin it is skipped.
 func (cmd Command) listRemoteCommand(c utils.CommandLine) error {
-	plugin, err := cmd.Client.ListAllPlugins(c.PluginRepoURL())
+	repository, err := pluginRepository(c)
+	if err != nil {
+		return err
+	}
+	plugins, err := repository.ListPlugins(context.Background(), compatOpts())
 	if err != nil {
 		return err
 	}
 
-	for _, p := range plugin.Plugins {
+	for _, p := range plugins {
 		plugin := p
 		if len(plugin.Versions) > 0 {
 			ver := latestSupportedVersion(&plugin)
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"errors"
 
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
@@ -23,7 +24,11 @@ func (cmd Command) listVersionsCommand(c utils.CommandLine) error {
 
 	pluginToList := c.Args().First()
 
-	plugin, err := cmd.Client.GetPlugin(pluginToList, c.String("repo"))
+	repository, err := pluginRepository(c)
+	if err != nil {
+		return err
+	}
+	plugin, err := repository.GetPlugin(context.Background(), pluginToList, compatOpts())
 	if err != nil {
 		return err
 	}
@@ -7,10 +7,11 @@ import (
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/models"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/services"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/plugins/repo"
 	"github.com/hashicorp/go-version"
 )
 
-func shouldUpgrade(installed string, remote *models.Plugin) bool {
+func shouldUpgrade(installed string, remote *repo.Plugin) bool {
 	installedVersion, err := version.NewVersion(installed)
 	if err != nil {
 		return false
@@ -29,7 +30,11 @@ func (cmd Command) upgradeAllCommand(c utils.CommandLine) error {
 
 	localPlugins := services.GetLocalPlugins(pluginsDir)
 
-	remotePlugins, err := cmd.Client.ListAllPlugins(c.String("repo"))
+	repository, err := pluginRepository(c)
+	if err != nil {
+		return err
+	}
+	remotePlugins, err := repository.ListPlugins(context.Background(), compatOpts())
 	if err != nil {
 		return err
 	}
@@ -37,7 +42,7 @@ func (cmd Command) upgradeAllCommand(c utils.CommandLine) error {
 	pluginsToUpgrade := make([]models.InstalledPlugin, 0)
 
 	for _, localPlugin := range localPlugins {
-		for _, p := range remotePlugins.Plugins {
+		for _, p := range remotePlugins {
 			remotePlugin := p
 			if localPlugin.ID != remotePlugin.ID {
 				continue
@@ -20,9 +20,13 @@ func (cmd Command) upgradeCommand(c utils.CommandLine) error {
 		return err
 	}
 
-	plugin, err2 := cmd.Client.GetPlugin(pluginName, c.PluginRepoURL())
-	if err2 != nil {
-		return err2
+	repository, err := pluginRepository(c)
+	if err != nil {
+		return err
+	}
+	plugin, err := repository.GetPlugin(context.Background(), pluginName, compatOpts())
+	if err != nil {
+		return err
 	}
 
 	if shouldUpgrade(localPlugin.Info.Version, &plugin) {
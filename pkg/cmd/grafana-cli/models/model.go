@@ -42,11 +42,6 @@ type ArchMeta struct {
 	SHA256 string `json:"sha256"`
 }
 
-type PluginRepo struct {
-	Plugins []Plugin `json:"plugins"`
-	Version string   `json:"version"`
-}
-
 type IoUtil interface {
 	Stat(path string) (os.FileInfo, error)
 	RemoveAll(path string) error
@@ -1,70 +1,25 @@
 package services
 
 import (
-	"crypto/tls"
 	"encoding/json"
 	"errors"
-	"fmt"
-	"net"
-	"net/http"
 	"path/filepath"
-	"time"
 
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/models"
 )
 
 var (
-	IoHelper            models.IoUtil = IoUtilImp{}
-	HttpClient          http.Client
-	HttpClientNoTimeout http.Client
-	GrafanaVersion      string
-	ErrNotFoundError    = errors.New("404 not found error")
-	Logger              *logger.CLILogger
+	IoHelper       models.IoUtil = IoUtilImp{}
+	GrafanaVersion string
+	Logger         *logger.CLILogger
 )
 
-type BadRequestError struct {
-	Message string
-	Status  string
-}
-
-func (e *BadRequestError) Error() string {
-	if len(e.Message) > 0 {
-		return fmt.Sprintf("%s: %s", e.Status, e.Message)
-	}
-	return e.Status
-}
-
-func Init(version string, skipTLSVerify bool, debugMode bool) {
+func Init(version string, debugMode bool) {
 	GrafanaVersion = version
-
-	HttpClient = makeHttpClient(skipTLSVerify, 10*time.Second)
-	HttpClientNoTimeout = makeHttpClient(skipTLSVerify, 0)
 	Logger = logger.New(debugMode)
 }
 
-func makeHttpClient(skipTLSVerify bool, timeout time.Duration) http.Client {
-	tr := &http.Transport{
-		Proxy: http.ProxyFromEnvironment,
-		DialContext: (&net.Dialer{
-			Timeout:   30 * time.Second,
-			KeepAlive: 30 * time.Second,
-		}).DialContext,
-		MaxIdleConns:          100,
-		IdleConnTimeout:       90 * time.Second,
-		TLSHandshakeTimeout:   10 * time.Second,
-		ExpectContinueTimeout: 1 * time.Second,
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: skipTLSVerify,
-		},
-	}
-
-	return http.Client{
-		Timeout:   timeout,
-		Transport: tr,
-	}
-}
-
 func ReadPlugin(pluginDir, pluginName string) (models.InstalledPlugin, error) {
 	distPluginDataPath := filepath.Join(pluginDir, pluginName, "dist", "plugin.json")
 
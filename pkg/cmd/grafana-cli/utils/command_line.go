@@ -1,9 +1,6 @@
 package utils
 
 import (
-	"os"
-
-	"github.com/grafana/grafana/pkg/cmd/grafana-cli/models"
 	"github.com/urfave/cli/v2"
 )
 
@@ -21,15 +18,10 @@ type CommandLine interface {
 
 	PluginDirectory() string
 	PluginRepoURL() string
+	PluginRepoConfig() string
 	PluginURL() string
 }
 
-type ApiClient interface {
-	GetPlugin(pluginId, repoUrl string) (models.Plugin, error)
-	DownloadFile(pluginName string, tmpFile *os.File, url string, checksum string) (err error)
-	ListAllPlugins(repoUrl string) (models.PluginRepo, error)
-}
-
 type ContextCommandLine struct {
 	*cli.Context
 }
@@ -58,6 +50,10 @@ func (c *ContextCommandLine) PluginRepoURL() string {
 	return c.String("repo")
 }
 
+func (c *ContextCommandLine) PluginRepoConfig() string {
+	return c.String("repoConfig")
+}
+
 func (c *ContextCommandLine) PluginURL() string {
 	return c.String("pluginUrl")
 }
@@ -44,6 +44,9 @@ type condition struct {
 	// min and max functions.
 	Reducer reducer
 
+	// ReducerParams are the parameters of the reducer, e.g. the percentile of the percentile reducer.
+	ReducerParams []float64
+
 	// Evaluator evaluates the reduced time series, instant metric, or result of another expression
 	// against an evaluator. An example of an evaluator is checking if it exceeds a threshold,
 	// falls within a range, or does not contain a value.
@@ -88,7 +91,7 @@ func (cmd *ConditionsCmd) Execute(_ context.Context, vars mathexp.Vars) (mathexp
 				reducedNum = mathexp.NewNumber("no data", nil)
 				reducedNum.SetValue(nil)
 			case mathexp.Series:
-				reducedNum = c.Reducer.Reduce(v)
+				reducedNum = c.Reducer.Reduce(v, c.ReducerParams...)
 				name = v.GetName()
 			case mathexp.Number:
 				reducedNum = v
@@ -216,7 +219,8 @@ type ConditionQueryJSON struct {
 
 type ConditionReducerJSON struct {
 	Type string `json:"type"`
-	// Params []interface{} `json:"params"` (Unused)
+	// Params are only used by the percentile reducer.
+	Params []interface{} `json:"params"`
 }
 
 // UnmarshalConditionsCmd creates a new ConditionsCmd.
@@ -252,6 +256,15 @@ func UnmarshalConditionsCmd(rawQuery map[string]interface{}, refID string) (*Con
 		if !cond.Reducer.ValidReduceFunc() {
 			return nil, fmt.Errorf("invalid reducer '%v' in condition %v", cond.Reducer, i+1)
 		}
+		if cond.Reducer.HasParams() {
+			cond.ReducerParams, err = parseReducerParams(cj.Reducer.Params)
+			if err != nil {
+				return nil, fmt.Errorf("invalid parameters of reducer '%v' in condition %v: %w", cond.Reducer, i+1, err)
+			}
+			if err = cond.Reducer.ValidateParams(cond.ReducerParams); err != nil {
+				return nil, fmt.Errorf("invalid parameters of reducer '%v' in condition %v: %w", cond.Reducer, i+1, err)
+			}
+		}
 
 		cond.Evaluator, err = newAlertEvaluator(cj.Evaluator)
 		if err != nil {
@@ -263,3 +276,24 @@ func UnmarshalConditionsCmd(rawQuery map[string]interface{}, refID string) (*Con
 
 	return c, nil
 }
+
+// parseReducerParams converts the parameters of a reducer to numbers. Numbers can be specified as strings
+// because the parameters of the reducer are edited as text.
+func parseReducerParams(raw []interface{}) ([]float64, error) {
+	params := make([]float64, 0, len(raw))
+	for _, r := range raw {
+		switch v := r.(type) {
+		case float64:
+			params = append(params, v)
+		case string:
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("parameter '%v' is not a number", v)
+			}
+			params = append(params, f)
+		default:
+			return nil, fmt.Errorf("parameter '%v' is not a number", v)
+		}
+	}
+	return params, nil
+}
@@ -98,6 +98,46 @@ func TestUnmarshalConditionCMD(t *testing.T) {
 			},
 			needsVars: []string{"A"},
 		},
+		{
+			name: "percentile condition",
+			rawJSON: `{
+				"conditions": [
+				  {
+					"evaluator": {
+					  "params": [
+						2
+					  ],
+					  "type": "gt"
+					},
+					"operator": {
+					  "type": "and"
+					},
+					"query": {
+					  "params": [
+						"A"
+					  ]
+					},
+					"reducer": {
+					  "params": ["95"],
+					  "type": "percentile"
+					},
+					"type": "query"
+				  }
+				]
+			}`,
+			expectedCommand: &ConditionsCmd{
+				Conditions: []condition{
+					{
+						InputRefID:    "A",
+						Reducer:       reducer("percentile"),
+						ReducerParams: []float64{95},
+						Operator:      "and",
+						Evaluator:     &thresholdEvaluator{Type: "gt", Threshold: 2},
+					},
+				},
+			},
+			needsVars: []string{"A"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -113,6 +153,25 @@ func TestUnmarshalConditionCMD(t *testing.T) {
 			require.Equal(t, tt.needsVars, cmd.NeedsVars())
 		})
 	}
+
+	t.Run("fails if percentile is not valid", func(t *testing.T) {
+		for _, params := range []string{`[]`, `[101]`, `["p95"]`, `[50, 95]`} {
+			var rq map[string]interface{}
+			err := json.Unmarshal([]byte(`{
+				"conditions": [{
+					"evaluator": {"params": [2], "type": "gt"},
+					"operator": {"type": "and"},
+					"query": {"params": ["A"]},
+					"reducer": {"params": `+params+`, "type": "percentile"},
+					"type": "query"
+				}]
+			}`), &rq)
+			require.NoError(t, err)
+
+			_, err = UnmarshalConditionsCmd(rq, "")
+			require.Errorf(t, err, "params %s", params)
+		}
+	})
 }
 
 func TestConditionsCmdExecute(t *testing.T) {
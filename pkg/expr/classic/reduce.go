@@ -1,6 +1,7 @@
 package classic
 
 import (
+	"fmt"
 	"math"
 	"sort"
 
@@ -15,12 +16,33 @@ func (cr reducer) ValidReduceFunc() bool {
 		return true
 	case "diff", "diff_abs", "percent_diff", "percent_diff_abs", "count_non_null":
 		return true
+	case "first", "stddev", "percentile":
+		return true
 	}
 	return false
 }
 
+// HasParams returns true if the reducer requires parameters.
+func (cr reducer) HasParams() bool {
+	return cr == "percentile"
+}
+
+// ValidateParams checks that the parameters are valid for the reducer.
+func (cr reducer) ValidateParams(params []float64) error {
+	if cr != "percentile" {
+		return nil
+	}
+	if len(params) != 1 {
+		return fmt.Errorf("expected exactly one parameter, got %d", len(params))
+	}
+	if params[0] < 0 || params[0] > 100 || math.IsNaN(params[0]) {
+		return fmt.Errorf("percentile must be between 0 and 100, got %v", params[0])
+	}
+	return nil
+}
+
 //nolint:gocyclo
-func (cr reducer) Reduce(series mathexp.Series) mathexp.Number {
+func (cr reducer) Reduce(series mathexp.Series, params ...float64) mathexp.Number {
 	num := mathexp.NewNumber("", nil)
 
 	if series.GetLabels() != nil {
@@ -124,6 +146,39 @@ func (cr reducer) Reduce(series mathexp.Series) mathexp.Number {
 				value = (values[(length/2)-1] + values[length/2]) / 2
 			}
 		}
+	case "first":
+		for i := 0; i < ff.Len(); i++ {
+			f := ff.GetValue(i)
+			if !nilOrNaN(f) {
+				value = *f
+				allNull = false
+				break
+			}
+		}
+	case "stddev":
+		values := nonNullValues(ff)
+		if len(values) > 0 {
+			allNull = false
+			var avg float64
+			for _, v := range values {
+				avg += v
+			}
+			avg /= float64(len(values))
+			for _, v := range values {
+				value += (v - avg) * (v - avg)
+			}
+			value = math.Sqrt(value / float64(len(values)))
+		}
+	case "percentile":
+		values := nonNullValues(ff)
+		if len(values) > 0 && len(params) == 1 {
+			allNull = false
+			sort.Float64s(values)
+			rank := params[0] / 100 * float64(len(values)-1)
+			lower := int(math.Floor(rank))
+			upper := int(math.Ceil(rank))
+			value = values[lower] + (values[upper]-values[lower])*(rank-float64(lower))
+		}
 	case "diff":
 		allNull, value = calculateDiff(ff, allNull, value, diff)
 	case "diff_abs":
@@ -182,6 +237,19 @@ func calculateDiff(ff mathexp.Float64Field, allNull bool, value float64, fn func
 	return allNull, value
 }
 
+// nonNullValues returns the values of the field that are neither null nor NaN.
+func nonNullValues(ff mathexp.Float64Field) []float64 {
+	var values []float64
+	for i := 0; i < ff.Len(); i++ {
+		f := ff.GetValue(i)
+		if nilOrNaN(f) {
+			continue
+		}
+		values = append(values, *f)
+	}
+	return values
+}
+
 func nilOrNaN(f *float64) bool {
 	return f == nil || math.IsNaN(*f)
 }
@@ -15,6 +15,7 @@ func TestReducer(t *testing.T) {
 	var tests = []struct {
 		name           string
 		reducer        reducer
+		params         []float64
 		inputSeries    mathexp.Series
 		expectedNumber mathexp.Number
 	}{
@@ -114,12 +115,57 @@ func TestReducer(t *testing.T) {
 			inputSeries:    valBasedSeries(nil, nil),
 			expectedNumber: valBasedNumber(nil),
 		},
+		{
+			name:           "first should ignore null values",
+			reducer:        reducer("first"),
+			inputSeries:    valBasedSeries(nil, ptr.Float64(math.NaN()), ptr.Float64(3), ptr.Float64(4)),
+			expectedNumber: valBasedNumber(ptr.Float64(3)),
+		},
+		{
+			name:           "first with only nulls",
+			reducer:        reducer("first"),
+			inputSeries:    valBasedSeries(nil, nil),
+			expectedNumber: valBasedNumber(nil),
+		},
+		{
+			name:           "stddev should ignore null values",
+			reducer:        reducer("stddev"),
+			inputSeries:    valBasedSeries(ptr.Float64(2), nil, ptr.Float64(4), ptr.Float64(4), ptr.Float64(4), ptr.Float64(5), ptr.Float64(5), ptr.Float64(7), ptr.Float64(9)),
+			expectedNumber: valBasedNumber(ptr.Float64(2)),
+		},
+		{
+			name:           "stddev with only nulls",
+			reducer:        reducer("stddev"),
+			inputSeries:    valBasedSeries(nil),
+			expectedNumber: valBasedNumber(nil),
+		},
+		{
+			name:           "percentile should ignore null values",
+			reducer:        reducer("percentile"),
+			params:         []float64{75},
+			inputSeries:    valBasedSeries(ptr.Float64(4), nil, ptr.Float64(1), ptr.Float64(3), ptr.Float64(2), ptr.Float64(5)),
+			expectedNumber: valBasedNumber(ptr.Float64(4)),
+		},
+		{
+			name:           "percentile interpolates between values",
+			reducer:        reducer("percentile"),
+			params:         []float64{50},
+			inputSeries:    valBasedSeries(ptr.Float64(1), ptr.Float64(2)),
+			expectedNumber: valBasedNumber(ptr.Float64(1.5)),
+		},
+		{
+			name:           "percentile with only nulls",
+			reducer:        reducer("percentile"),
+			params:         []float64{95},
+			inputSeries:    valBasedSeries(nil),
+			expectedNumber: valBasedNumber(nil),
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			require.Equal(t, true, tt.reducer.ValidReduceFunc())
-			num := tt.reducer.Reduce(tt.inputSeries)
+			num := tt.reducer.Reduce(tt.inputSeries, tt.params...)
 			require.Equal(t, tt.expectedNumber, num)
 		})
 	}
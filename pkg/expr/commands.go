@@ -2,6 +2,7 @@ package expr
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -23,7 +24,9 @@ type Command interface {
 type MathCommand struct {
 	RawExpression string
 	Expression    *mathexp.Expr
-	refID         string
+	// Matching controls how the results of the two sides of binary operations are joined by their labels.
+	Matching mathexp.LabelMatching
+	refID    string
 }
 
 // NewMathCommand creates a new MathCommand. It will return an error
@@ -55,6 +58,21 @@ func UnmarshalMathCommand(rn *rawNode) (*MathCommand, error) {
 	if err != nil {
 		return nil, fmt.Errorf("invalid math command type: %w", err)
 	}
+
+	if rawMatching, ok := rn.Query["matching"]; ok && rawMatching != nil {
+		jsonFromM, err := json.Marshal(rawMatching)
+		if err != nil {
+			return nil, fmt.Errorf("failed to remarshal label matching of math command: %w", err)
+		}
+		var matching mathexp.LabelMatching
+		if err := json.Unmarshal(jsonFromM, &matching); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal label matching of math command: %w", err)
+		}
+		if err := matching.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid label matching of math command: %w", err)
+		}
+		gm.Matching = matching
+	}
 	return gm, nil
 }
 
@@ -67,29 +85,32 @@ func (gm *MathCommand) NeedsVars() []string {
 // Execute runs the command and returns the results or an error if the command
 // failed to execute.
 func (gm *MathCommand) Execute(ctx context.Context, vars mathexp.Vars) (mathexp.Results, error) {
-	return gm.Expression.Execute(gm.refID, vars)
+	return gm.Expression.ExecuteWithMatching(gm.refID, vars, gm.Matching)
 }
 
 // ReduceCommand is an expression command for reduction of a timeseries such as a min, mean, or max.
 type ReduceCommand struct {
-	Reducer      string
-	VarToReduce  string
-	refID        string
-	seriesMapper mathexp.ReduceMapper
+	Reducer string
+	// ReducerParams are the parameters of the reducer, e.g. the percentile of the percentile reducer.
+	ReducerParams []float64
+	VarToReduce   string
+	refID         string
+	seriesMapper  mathexp.ReduceMapper
 }
 
 // NewReduceCommand creates a new ReduceCMD.
-func NewReduceCommand(refID, reducer, varToReduce string, mapper mathexp.ReduceMapper) (*ReduceCommand, error) {
-	_, err := mathexp.GetReduceFunc(reducer)
+func NewReduceCommand(refID, reducer, varToReduce string, mapper mathexp.ReduceMapper, params ...float64) (*ReduceCommand, error) {
+	_, err := mathexp.GetReduceFunc(reducer, params...)
 	if err != nil {
 		return nil, err
 	}
 
 	return &ReduceCommand{
-		Reducer:      reducer,
-		VarToReduce:  varToReduce,
-		refID:        refID,
-		seriesMapper: mapper,
+		Reducer:       reducer,
+		ReducerParams: params,
+		VarToReduce:   varToReduce,
+		refID:         refID,
+		seriesMapper:  mapper,
 	}, nil
 }
 
@@ -114,6 +135,21 @@ func UnmarshalReduceCommand(rn *rawNode) (*ReduceCommand, error) {
 		return nil, fmt.Errorf("expected reducer to be a string, got %T", rawReducer)
 	}
 
+	var params []float64
+	if rawParams, ok := rn.Query["reducerParams"]; ok {
+		list, ok := rawParams.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected reducerParams to be an array, got %T", rawParams)
+		}
+		for _, rawParam := range list {
+			param, ok := rawParam.(float64)
+			if !ok {
+				return nil, fmt.Errorf("expected reducerParams to contain numbers, got %T", rawParam)
+			}
+			params = append(params, param)
+		}
+	}
+
 	var mapper mathexp.ReduceMapper = nil
 	settings, ok := rn.Query["settings"]
 	if ok {
@@ -143,7 +179,7 @@ func UnmarshalReduceCommand(rn *rawNode) (*ReduceCommand, error) {
 			return nil, fmt.Errorf("field settings must be an object, got %T for refId %v", s, rn.RefID)
 		}
 	}
-	return NewReduceCommand(rn.RefID, redFunc, varToReduce, mapper)
+	return NewReduceCommand(rn.RefID, redFunc, varToReduce, mapper, params...)
 }
 
 // NeedsVars returns the variable names (refIds) that are dependencies
@@ -159,7 +195,7 @@ func (gr *ReduceCommand) Execute(_ context.Context, vars mathexp.Vars) (mathexp.
 	for _, val := range vars[gr.VarToReduce].Values {
 		switch v := val.(type) {
 		case mathexp.Series:
-			num, err := v.Reduce(gr.refID, gr.Reducer, gr.seriesMapper)
+			num, err := v.Reduce(gr.refID, gr.Reducer, gr.seriesMapper, gr.ReducerParams...)
 			if err != nil {
 				return newRes, err
 			}
@@ -303,6 +339,8 @@ func (gt CommandType) String() string {
 		return "resample"
 	case TypeClassicConditions:
 		return "classic_conditions"
+	case TypeThreshold:
+		return "threshold"
 	default:
 		return "unknown"
 	}
@@ -96,6 +96,117 @@ func Test_UnmarshalReduceCommand_Settings(t *testing.T) {
 	}
 }
 
+func Test_UnmarshalReduceCommand_ReducerParams(t *testing.T) {
+	var tests = []struct {
+		name           string
+		query          string
+		isError        bool
+		expectedParams []float64
+	}{
+		{
+			name:  "no params when reducerParams is not specified",
+			query: `{ "expression" : "$A", "reducer": "sum" }`,
+		},
+		{
+			name:           "percentile with a parameter",
+			query:          `{ "expression" : "$A", "reducer": "percentile", "reducerParams": [95] }`,
+			expectedParams: []float64{95},
+		},
+		{
+			name:    "error when percentile has no parameter",
+			query:   `{ "expression" : "$A", "reducer": "percentile" }`,
+			isError: true,
+		},
+		{
+			name:    "error when reducerParams is not an array",
+			query:   `{ "expression" : "$A", "reducer": "percentile", "reducerParams": 95 }`,
+			isError: true,
+		},
+		{
+			name:    "error when reducerParams contains strings",
+			query:   `{ "expression" : "$A", "reducer": "percentile", "reducerParams": ["95"] }`,
+			isError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var qmap = make(map[string]interface{})
+			require.NoError(t, json.Unmarshal([]byte(test.query), &qmap))
+
+			cmd, err := UnmarshalReduceCommand(&rawNode{
+				RefID: "A",
+				Query: qmap,
+			})
+
+			if test.isError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expectedParams, cmd.ReducerParams)
+		})
+	}
+}
+
+func Test_UnmarshalMathCommand_Matching(t *testing.T) {
+	var tests = []struct {
+		name             string
+		query            string
+		isError          bool
+		expectedMatching mathexp.LabelMatching
+	}{
+		{
+			name:  "default matching when matching is not specified",
+			query: `{ "expression" : "$A / $B" }`,
+		},
+		{
+			name:  "matching on labels with group",
+			query: `{ "expression" : "$A / $B", "matching": { "mode": "on", "labels": ["host"], "group": "left", "include": ["unit"] } }`,
+			expectedMatching: mathexp.LabelMatching{
+				Mode:    mathexp.MatchingModeOn,
+				Labels:  []string{"host"},
+				Group:   mathexp.MatchingGroupLeft,
+				Include: []string{"unit"},
+			},
+		},
+		{
+			name:    "error when mode is unknown",
+			query:   `{ "expression" : "$A / $B", "matching": { "mode": "by" } }`,
+			isError: true,
+		},
+		{
+			name:    "error when included labels are set without group",
+			query:   `{ "expression" : "$A / $B", "matching": { "mode": "ignoring", "include": ["unit"] } }`,
+			isError: true,
+		},
+		{
+			name:    "error when matching is not an object",
+			query:   `{ "expression" : "$A / $B", "matching": "on" }`,
+			isError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var qmap = make(map[string]interface{})
+			require.NoError(t, json.Unmarshal([]byte(test.query), &qmap))
+
+			cmd, err := UnmarshalMathCommand(&rawNode{
+				RefID: "C",
+				Query: qmap,
+			})
+
+			if test.isError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expectedMatching, cmd.Matching)
+		})
+	}
+}
+
 func TestReduceExecute(t *testing.T) {
 	varToReduce := util.GenerateShortUID()
 	cmd, err := NewReduceCommand(util.GenerateShortUID(), randomReduceFunc(), varToReduce, nil)
@@ -0,0 +1,131 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+// loadedDimensionsKey is the key of the query model that holds the labels of the dimensions that are firing.
+const loadedDimensionsKey = "loadedDimensions"
+
+// HysteresisCommand is a threshold command with separate thresholds to fire and to resolve, so that a dimension
+// whose value stays around the firing threshold does not flap between firing and resolved:
+//   - the loading threshold is used for the dimensions that are not firing and decides whether they start to fire.
+//   - the unloading threshold is used for the dimensions that are firing and decides whether they are resolved.
+type HysteresisCommand struct {
+	RefID                  string
+	ReferenceVar           string
+	LoadingThresholdFunc   ThresholdCommand
+	UnloadingThresholdFunc ThresholdCommand
+	// LoadedDimensions are the labels of the dimensions that were firing before this evaluation, keyed by their string representation.
+	LoadedDimensions map[string]struct{}
+}
+
+// NewHysteresisCommand creates a new HysteresisCommand. loading is the condition that makes a dimension fire and
+// unloading is the condition that resolves a dimension that is firing.
+func NewHysteresisCommand(refID, referenceVar string, loading, unloading ConditionEvalJSON, loadedDimensions []data.Labels) (*HysteresisCommand, error) {
+	loaded := make(map[string]struct{}, len(loadedDimensions))
+	for _, l := range loadedDimensions {
+		loaded[l.String()] = struct{}{}
+	}
+	return &HysteresisCommand{
+		RefID:        refID,
+		ReferenceVar: referenceVar,
+		LoadingThresholdFunc: ThresholdCommand{
+			ReferenceVar:  referenceVar,
+			RefID:         refID,
+			ThresholdFunc: loading.Type,
+			Conditions:    loading.Params,
+		},
+		UnloadingThresholdFunc: ThresholdCommand{
+			ReferenceVar:  referenceVar,
+			RefID:         refID,
+			ThresholdFunc: unloading.Type,
+			Conditions:    unloading.Params,
+			// a firing dimension keeps firing as long as it does not meet the condition to resolve.
+			Invert: true,
+		},
+		LoadedDimensions: loaded,
+	}, nil
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (h *HysteresisCommand) NeedsVars() []string {
+	return []string{h.ReferenceVar}
+}
+
+func (h *HysteresisCommand) Execute(ctx context.Context, vars mathexp.Vars) (mathexp.Results, error) {
+	if len(h.LoadedDimensions) == 0 {
+		return h.LoadingThresholdFunc.Execute(ctx, vars)
+	}
+
+	var loaded, unloaded mathexp.Values
+	for _, value := range vars[h.ReferenceVar].Values {
+		_, isNoData := value.(mathexp.NoData)
+		if _, ok := h.LoadedDimensions[value.GetLabels().String()]; ok && !isNoData {
+			loaded = append(loaded, value)
+			continue
+		}
+		unloaded = append(unloaded, value)
+	}
+
+	result := mathexp.Results{}
+	if len(unloaded) > 0 {
+		loadingResults, err := h.LoadingThresholdFunc.Execute(ctx, mathexp.Vars{h.ReferenceVar: mathexp.Results{Values: unloaded}})
+		if err != nil {
+			return mathexp.Results{}, err
+		}
+		result.Values = append(result.Values, loadingResults.Values...)
+	}
+	if len(loaded) > 0 {
+		unloadingResults, err := h.UnloadingThresholdFunc.Execute(ctx, mathexp.Vars{h.ReferenceVar: mathexp.Results{Values: loaded}})
+		if err != nil {
+			return mathexp.Results{}, err
+		}
+		result.Values = append(result.Values, unloadingResults.Values...)
+	}
+	return result, nil
+}
+
+// SetLoadedDimensionsToHysteresisCommand sets the labels of the dimensions that are firing to the query model of a
+// threshold expression that has an unload evaluator. It returns false and does not change the query if the query
+// is not such an expression.
+func SetLoadedDimensionsToHysteresisCommand(query map[string]interface{}, dimensions []data.Labels) bool {
+	if t, _ := query["type"].(string); t != TypeThreshold.String() {
+		return false
+	}
+	jsonFromM, err := json.Marshal(query["conditions"])
+	if err != nil {
+		return false
+	}
+	var conditions []ThresholdConditionJSON
+	if err := json.Unmarshal(jsonFromM, &conditions); err != nil {
+		return false
+	}
+	if len(conditions) != 1 || conditions[0].UnloadEvaluator == nil {
+		return false
+	}
+	query[loadedDimensionsKey] = dimensions
+	return true
+}
+
+func unmarshalLoadedDimensions(raw interface{}) ([]data.Labels, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	jsonFromM, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remarshal loaded dimensions of threshold expression: %w", err)
+	}
+	var dimensions []data.Labels
+	if err := json.Unmarshal(jsonFromM, &dimensions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal loaded dimensions of threshold expression: %w", err)
+	}
+	return dimensions, nil
+}
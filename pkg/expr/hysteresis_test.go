@@ -0,0 +1,95 @@
+package expr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+func TestHysteresisExecute(t *testing.T) {
+	number := func(labels data.Labels, value float64) mathexp.Number {
+		n := mathexp.NewNumber("A", labels)
+		n.SetValue(&value)
+		return n
+	}
+	vars := mathexp.Vars{
+		"A": mathexp.Results{Values: mathexp.Values{
+			number(data.Labels{"host": "firing"}, 75),
+			number(data.Labels{"host": "normal"}, 75),
+			number(data.Labels{"host": "recovered"}, 65),
+		}},
+	}
+	loading := ConditionEvalJSON{Type: ThresholdIsAbove, Params: []float64{80}}
+	unloading := ConditionEvalJSON{Type: ThresholdIsBelow, Params: []float64{70}}
+
+	resultsByHost := func(t *testing.T, results mathexp.Results) map[string]float64 {
+		t.Helper()
+		byHost := make(map[string]float64, len(results.Values))
+		for _, v := range results.Values {
+			n, ok := v.(mathexp.Number)
+			require.True(t, ok)
+			byHost[n.GetLabels()["host"]] = *n.GetFloat64Value()
+		}
+		return byHost
+	}
+
+	t.Run("should use the loading threshold if no dimension is loaded", func(t *testing.T) {
+		cmd, err := NewHysteresisCommand("B", "A", loading, unloading, nil)
+		require.NoError(t, err)
+
+		results, err := cmd.Execute(context.Background(), vars)
+		require.NoError(t, err)
+		require.Equal(t, map[string]float64{"firing": 0, "normal": 0, "recovered": 0}, resultsByHost(t, results))
+	})
+
+	t.Run("should keep loaded dimensions firing until they meet the unloading threshold", func(t *testing.T) {
+		cmd, err := NewHysteresisCommand("B", "A", loading, unloading, []data.Labels{
+			{"host": "firing"},
+			{"host": "recovered"},
+		})
+		require.NoError(t, err)
+
+		results, err := cmd.Execute(context.Background(), vars)
+		require.NoError(t, err)
+		require.Equal(t, map[string]float64{"firing": 1, "normal": 0, "recovered": 0}, resultsByHost(t, results))
+	})
+}
+
+func TestSetLoadedDimensionsToHysteresisCommand(t *testing.T) {
+	t.Run("should set loaded dimensions to threshold with unload evaluator", func(t *testing.T) {
+		query := map[string]interface{}{
+			"type": "threshold",
+			"conditions": []interface{}{
+				map[string]interface{}{
+					"evaluator":       map[string]interface{}{"type": "gt", "params": []interface{}{80}},
+					"unloadEvaluator": map[string]interface{}{"type": "lt", "params": []interface{}{70}},
+				},
+			},
+		}
+		dims := []data.Labels{{"host": "a"}}
+		require.True(t, SetLoadedDimensionsToHysteresisCommand(query, dims))
+		require.Equal(t, dims, query["loadedDimensions"])
+	})
+
+	t.Run("should not change threshold without unload evaluator", func(t *testing.T) {
+		query := map[string]interface{}{
+			"type": "threshold",
+			"conditions": []interface{}{
+				map[string]interface{}{
+					"evaluator": map[string]interface{}{"type": "gt", "params": []interface{}{80}},
+				},
+			},
+		}
+		require.False(t, SetLoadedDimensionsToHysteresisCommand(query, []data.Labels{{"host": "a"}}))
+		require.NotContains(t, query, "loadedDimensions")
+	})
+
+	t.Run("should not change other expressions", func(t *testing.T) {
+		query := map[string]interface{}{"type": "math", "expression": "$A > 1"}
+		require.False(t, SetLoadedDimensionsToHysteresisCommand(query, []data.Labels{{"host": "a"}}))
+	})
+}
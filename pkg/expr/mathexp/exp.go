@@ -21,9 +21,10 @@ type State struct {
 	*Expr
 	Vars Vars
 	// Could hold more properties that change behavior around:
-	//  - Unions (How many result A and many Result B in case A + B are joined)
 	//  - NaN/Null behavior
 	RefID string
+	// Matching controls how the results of the two sides of binary operations are joined.
+	Matching LabelMatching
 }
 
 // Vars holds the results of datasource queries or other expression commands.
@@ -44,10 +45,16 @@ func New(expr string, funcs ...map[string]parse.Func) (*Expr, error) {
 
 // Execute applies a parse expression to the context and executes it
 func (e *Expr) Execute(refID string, vars Vars) (r Results, err error) {
+	return e.ExecuteWithMatching(refID, vars, LabelMatching{})
+}
+
+// ExecuteWithMatching is like Execute but joins the results of the two sides of binary operations with the given label matching.
+func (e *Expr) ExecuteWithMatching(refID string, vars Vars, matching LabelMatching) (r Results, err error) {
 	s := &State{
-		Expr:  e,
-		Vars:  vars,
-		RefID: refID,
+		Expr:     e,
+		Vars:     vars,
+		RefID:    refID,
+		Matching: matching,
 	}
 	return e.executeState(s)
 }
@@ -257,7 +264,15 @@ func (e *State) walkBinary(node *parse.BinaryNode) (Results, error) {
 	if err != nil {
 		return res, err
 	}
-	unions := union(ar, br)
+	var unions []*Union
+	if e.Matching.IsDefault() {
+		unions = union(ar, br)
+	} else {
+		unions, err = matchingUnion(ar, br, e.Matching)
+		if err != nil {
+			return res, err
+		}
+	}
 	for _, uni := range unions {
 		var value Value
 		switch at := uni.A.(type) {
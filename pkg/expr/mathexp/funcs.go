@@ -1,7 +1,11 @@
 package mathexp
 
 import (
+	"fmt"
 	"math"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 
 	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
 )
@@ -68,6 +72,33 @@ var builtins = map[string]parse.Func{
 		VariantReturn: true,
 		F:             floor,
 	},
+	"rate": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      rate,
+	},
+	"delta": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      delta,
+	},
+	"time_shift": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeString},
+		Return: parse.TypeSeriesSet,
+		F:      timeShift,
+		Check:  checkTimeShift,
+	},
+	"moving_avg": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeScalar},
+		Return: parse.TypeSeriesSet,
+		F:      movingAvg,
+		Check:  checkMovingAvg,
+	},
+	"cumsum": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      cumSum,
+	},
 }
 
 // abs returns the absolute value for each result in NumberSet, SeriesSet, or Scalar
@@ -303,3 +334,206 @@ func floor(e *State, varSet Results) (Results, error) {
 	}
 	return newRes, nil
 }
+
+// rate returns, for each series in the SeriesSet, the per-second rate of change between each point and the point before it.
+// The first point of a series has no previous point and is dropped. If either value is null the result is null.
+func rate(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "rate", varSet, func(s Series) (Series, error) {
+		return betweenPoints(e, s, func(t1, t2 time.Time, f1, f2 float64) *float64 {
+			elapsed := t2.Sub(t1).Seconds()
+			if elapsed <= 0 {
+				return nil
+			}
+			r := (f2 - f1) / elapsed
+			return &r
+		}), nil
+	})
+}
+
+// delta returns, for each series in the SeriesSet, the difference between each point and the point before it.
+// The first point of a series has no previous point and is dropped. If either value is null the result is null.
+func delta(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "delta", varSet, func(s Series) (Series, error) {
+		return betweenPoints(e, s, func(_, _ time.Time, f1, f2 float64) *float64 {
+			d := f2 - f1
+			return &d
+		}), nil
+	})
+}
+
+// timeShift moves each point of each series in the SeriesSet forward in time by the duration, so that
+// for example time_shift($A, "1d") can be compared with $A to compare the values with the ones of the day before.
+// A negative duration moves the points backward in time.
+func timeShift(e *State, varSet Results, rawDuration string) (Results, error) {
+	d, err := parseTimeShift(rawDuration)
+	if err != nil {
+		return Results{}, err
+	}
+	return perSeries(e, "time_shift", varSet, func(s Series) (Series, error) {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			newSeries.SetPoint(i, t.Add(d), f)
+		}
+		return newSeries, nil
+	})
+}
+
+// movingAvg returns, for each point of each series in the SeriesSet, the average of the non-null values of the
+// point and the n-1 points before it. Points at the beginning of the series are averaged over the points available so far.
+// If all values in the window are null the result is null.
+func movingAvg(e *State, varSet Results, window Results) (Results, error) {
+	n, err := movingAvgWindow(window)
+	if err != nil {
+		return Results{}, err
+	}
+	return perSeries(e, "moving_avg", varSet, func(s Series) (Series, error) {
+		sorted := sortedSeries(e, s)
+		newSeries := NewSeries(e.RefID, s.GetLabels(), sorted.Len())
+		sum, count := 0.0, 0
+		for i := 0; i < sorted.Len(); i++ {
+			t, f := sorted.GetPoint(i)
+			if f != nil {
+				sum += *f
+				count++
+			}
+			if i >= n {
+				if old := sorted.GetValue(i - n); old != nil {
+					sum -= *old
+					count--
+				}
+			}
+			if count == 0 {
+				newSeries.SetPoint(i, t, nil)
+				continue
+			}
+			avg := sum / float64(count)
+			newSeries.SetPoint(i, t, &avg)
+		}
+		return newSeries, nil
+	})
+}
+
+// cumSum returns, for each point of each series in the SeriesSet, the sum of the value of the point and of all points before it.
+// Null values do not change the sum and the result at their position is null.
+func cumSum(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "cumsum", varSet, func(s Series) (Series, error) {
+		sorted := sortedSeries(e, s)
+		newSeries := NewSeries(e.RefID, s.GetLabels(), sorted.Len())
+		sum := 0.0
+		for i := 0; i < sorted.Len(); i++ {
+			t, f := sorted.GetPoint(i)
+			if f == nil {
+				newSeries.SetPoint(i, t, nil)
+				continue
+			}
+			sum += *f
+			total := sum
+			newSeries.SetPoint(i, t, &total)
+		}
+		return newSeries, nil
+	})
+}
+
+// perSeries passes each Series of the results to seriesF. NoData is passed through as is.
+// Any other type returns an error because the functions that use it only make sense on time series.
+func perSeries(e *State, name string, varSet Results, seriesF func(s Series) (Series, error)) (Results, error) {
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		switch v := res.(type) {
+		case Series:
+			newSeries, err := seriesF(v)
+			if err != nil {
+				return newRes, err
+			}
+			newRes.Values = append(newRes.Values, newSeries)
+		case NoData:
+			newRes.Values = append(newRes.Values, v.New())
+		default:
+			return newRes, fmt.Errorf("%s can only be applied to time series, got %v", name, res.Type())
+		}
+	}
+	return newRes, nil
+}
+
+// betweenPoints returns a series with a point for each point of the input series but the first.
+// The value of the point is computed by pairF from the point and the point before it. If either value is null the result is null.
+func betweenPoints(e *State, s Series, pairF func(t1, t2 time.Time, f1, f2 float64) *float64) Series {
+	sorted := sortedSeries(e, s)
+	size := sorted.Len() - 1
+	if size < 0 {
+		size = 0
+	}
+	newSeries := NewSeries(e.RefID, s.GetLabels(), size)
+	for i := 1; i < sorted.Len(); i++ {
+		t1, f1 := sorted.GetPoint(i - 1)
+		t2, f2 := sorted.GetPoint(i)
+		var f *float64
+		if f1 != nil && f2 != nil {
+			f = pairF(t1, t2, *f1, *f2)
+		}
+		newSeries.SetPoint(i-1, t2, f)
+	}
+	return newSeries
+}
+
+// sortedSeries returns a copy of the series sorted from oldest to newest. The input series is not modified.
+func sortedSeries(e *State, s Series) Series {
+	newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+	for i := 0; i < s.Len(); i++ {
+		t, f := s.GetPoint(i)
+		newSeries.SetPoint(i, t, f)
+	}
+	newSeries.SortByTime(false)
+	return newSeries
+}
+
+func parseTimeShift(rawDuration string) (time.Duration, error) {
+	negative := len(rawDuration) > 0 && rawDuration[0] == '-'
+	if negative {
+		rawDuration = rawDuration[1:]
+	}
+	d, err := gtime.ParseDuration(rawDuration)
+	if err != nil {
+		return 0, fmt.Errorf("time_shift: failed to parse duration %q: %w", rawDuration, err)
+	}
+	if negative {
+		d = -d
+	}
+	return d, nil
+}
+
+func movingAvgWindow(window Results) (int, error) {
+	if len(window.Values) != 1 {
+		return 0, fmt.Errorf("moving_avg: the number of points must be a scalar")
+	}
+	s, ok := window.Values[0].(Scalar)
+	if !ok {
+		return 0, fmt.Errorf("moving_avg: the number of points must be a scalar, got %v", window.Values[0].Type())
+	}
+	f := s.GetFloat64Value()
+	if f == nil || *f < 1 || *f != math.Trunc(*f) {
+		return 0, fmt.Errorf("moving_avg: the number of points must be a positive integer")
+	}
+	return int(*f), nil
+}
+
+// checkTimeShift validates the duration of time_shift at parse time if it is a string constant.
+func checkTimeShift(_ *parse.Tree, f *parse.FuncNode) error {
+	if s, ok := f.Args[1].(*parse.StringNode); ok {
+		if _, err := parseTimeShift(s.Text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkMovingAvg validates the number of points of moving_avg at parse time if it is a number constant.
+func checkMovingAvg(_ *parse.Tree, f *parse.FuncNode) error {
+	if n, ok := f.Args[1].(*parse.ScalarNode); ok {
+		if n.Float64 < 1 || n.Float64 != math.Trunc(n.Float64) {
+			return fmt.Errorf("moving_avg: the number of points must be a positive integer")
+		}
+	}
+	return nil
+}
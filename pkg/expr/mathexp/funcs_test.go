@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
 )
 
@@ -159,3 +160,173 @@ func TestIsNumberFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestSeriesFuncs(t *testing.T) {
+	series := func(points ...tp) Vars {
+		return Vars{"A": Results{[]Value{makeSeries("", data.Labels{"host": "a"}, points...)}}}
+	}
+	var tests = []struct {
+		name      string
+		expr      string
+		vars      Vars
+		newErrIs  require.ErrorAssertionFunc
+		execErrIs require.ErrorAssertionFunc
+		results   Results
+	}{
+		{
+			name: "rate returns the per second rate between points",
+			expr: "rate($A)",
+			vars: series(
+				tp{time.Unix(10, 0), float64Pointer(10)},
+				tp{time.Unix(0, 0), float64Pointer(0)},
+				tp{time.Unix(20, 0), nil},
+				tp{time.Unix(30, 0), float64Pointer(50)},
+			),
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{makeSeries("", data.Labels{"host": "a"},
+				tp{time.Unix(10, 0), float64Pointer(1)},
+				tp{time.Unix(20, 0), nil},
+				tp{time.Unix(30, 0), nil},
+			)}},
+		},
+		{
+			name: "delta returns the difference between points",
+			expr: "delta($A)",
+			vars: series(
+				tp{time.Unix(0, 0), float64Pointer(5)},
+				tp{time.Unix(10, 0), float64Pointer(3)},
+				tp{time.Unix(20, 0), float64Pointer(7)},
+			),
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{makeSeries("", data.Labels{"host": "a"},
+				tp{time.Unix(10, 0), float64Pointer(-2)},
+				tp{time.Unix(20, 0), float64Pointer(4)},
+			)}},
+		},
+		{
+			name:      "delta of a single point is an empty series",
+			expr:      "delta($A)",
+			vars:      series(tp{time.Unix(0, 0), float64Pointer(5)}),
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   Results{[]Value{makeSeries("", data.Labels{"host": "a"})}},
+		},
+		{
+			name: "time_shift moves points forward in time",
+			expr: `time_shift($A, "1d")`,
+			vars: series(
+				tp{time.Unix(0, 0), float64Pointer(1)},
+				tp{time.Unix(10, 0), float64Pointer(2)},
+			),
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{makeSeries("", data.Labels{"host": "a"},
+				tp{time.Unix(86400, 0), float64Pointer(1)},
+				tp{time.Unix(86410, 0), float64Pointer(2)},
+			)}},
+		},
+		{
+			name:      "time_shift with a negative duration moves points backward in time",
+			expr:      `time_shift($A, "-1m")`,
+			vars:      series(tp{time.Unix(600, 0), float64Pointer(1)}),
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   Results{[]Value{makeSeries("", data.Labels{"host": "a"}, tp{time.Unix(540, 0), float64Pointer(1)})}},
+		},
+		{
+			name:     "time_shift with an invalid duration should error",
+			expr:     `time_shift($A, "yesterday")`,
+			newErrIs: require.Error,
+		},
+		{
+			name: "time_shift can be compared to the series",
+			expr: `$A - time_shift($A, "10s")`,
+			vars: series(
+				tp{time.Unix(0, 0), float64Pointer(1)},
+				tp{time.Unix(10, 0), float64Pointer(4)},
+			),
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{makeSeries("", data.Labels{"host": "a"},
+				tp{time.Unix(10, 0), float64Pointer(3)},
+			)}},
+		},
+		{
+			name: "moving_avg averages the non-null values of the window",
+			expr: "moving_avg($A, 2)",
+			vars: series(
+				tp{time.Unix(0, 0), float64Pointer(2)},
+				tp{time.Unix(10, 0), float64Pointer(4)},
+				tp{time.Unix(20, 0), nil},
+				tp{time.Unix(30, 0), nil},
+				tp{time.Unix(40, 0), float64Pointer(8)},
+			),
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{makeSeries("", data.Labels{"host": "a"},
+				tp{time.Unix(0, 0), float64Pointer(2)},
+				tp{time.Unix(10, 0), float64Pointer(3)},
+				tp{time.Unix(20, 0), float64Pointer(4)},
+				tp{time.Unix(30, 0), nil},
+				tp{time.Unix(40, 0), float64Pointer(8)},
+			)}},
+		},
+		{
+			name:     "moving_avg with a window that is not a positive integer should error",
+			expr:     "moving_avg($A, 1.5)",
+			newErrIs: require.Error,
+		},
+		{
+			name:      "moving_avg with a negative window should error on execution",
+			expr:      "moving_avg($A, -2)",
+			vars:      series(tp{time.Unix(0, 0), float64Pointer(2)}),
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name: "cumsum returns the running sum",
+			expr: "cumsum($A)",
+			vars: series(
+				tp{time.Unix(0, 0), float64Pointer(1)},
+				tp{time.Unix(10, 0), nil},
+				tp{time.Unix(20, 0), float64Pointer(2)},
+				tp{time.Unix(30, 0), float64Pointer(3)},
+			),
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{makeSeries("", data.Labels{"host": "a"},
+				tp{time.Unix(0, 0), float64Pointer(1)},
+				tp{time.Unix(10, 0), nil},
+				tp{time.Unix(20, 0), float64Pointer(3)},
+				tp{time.Unix(30, 0), float64Pointer(6)},
+			)}},
+		},
+		{
+			name:      "cumsum on number should error",
+			expr:      "cumsum($A)",
+			vars:      Vars{"A": Results{[]Value{makeNumber("", nil, float64Pointer(1))}}},
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name:     "rate on scalar should error",
+			expr:     "rate(1)",
+			newErrIs: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			tt.newErrIs(t, err)
+			if e != nil {
+				res, err := e.Execute("", tt.vars)
+				tt.execErrIs(t, err)
+				if tt.results.Values != nil {
+					require.Equal(t, tt.results, res)
+				}
+			}
+		})
+	}
+}
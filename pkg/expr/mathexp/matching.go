@@ -0,0 +1,197 @@
+package mathexp
+
+import (
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+// MatchingMode defines which labels are used to match the results of the two sides of a binary operation.
+type MatchingMode string
+
+const (
+	// MatchingModeDefault matches results whose labels are equal or where the labels of one result contain the labels of the other.
+	MatchingModeDefault MatchingMode = ""
+	// MatchingModeOn matches results only by the values of the listed labels.
+	MatchingModeOn MatchingMode = "on"
+	// MatchingModeIgnoring matches results by the values of all labels but the listed ones.
+	MatchingModeIgnoring MatchingMode = "ignoring"
+)
+
+// MatchingGroup defines which side of a binary operation can have several results that match the same result of the other side.
+type MatchingGroup string
+
+const (
+	// MatchingGroupNone only allows one-to-one matching.
+	MatchingGroupNone MatchingGroup = ""
+	// MatchingGroupLeft allows many-to-one matching, several results of the left side can match one result of the right side.
+	MatchingGroupLeft MatchingGroup = "left"
+	// MatchingGroupRight allows one-to-many matching, one result of the left side can match several results of the right side.
+	MatchingGroupRight MatchingGroup = "right"
+)
+
+// LabelMatching controls how the results of the two sides of a binary operation are matched by their labels,
+// similar to the vector matching of PromQL (on, ignoring, group_left and group_right).
+type LabelMatching struct {
+	Mode MatchingMode `json:"mode,omitempty"`
+	// Labels are the labels that are used (on) or not used (ignoring) to match results.
+	Labels []string      `json:"labels,omitempty"`
+	Group  MatchingGroup `json:"group,omitempty"`
+	// Include are the labels of the "one" side that are copied to the results when Group is set.
+	Include []string `json:"include,omitempty"`
+}
+
+// IsDefault returns true if the matching is the default label union matching.
+func (m LabelMatching) IsDefault() bool {
+	return m.Mode == MatchingModeDefault && m.Group == MatchingGroupNone
+}
+
+// Validate returns an error if the matching is not valid.
+func (m LabelMatching) Validate() error {
+	switch m.Mode {
+	case MatchingModeDefault:
+		if len(m.Labels) > 0 {
+			return fmt.Errorf("labels can only be set with the matching mode %q or %q", MatchingModeOn, MatchingModeIgnoring)
+		}
+	case MatchingModeOn, MatchingModeIgnoring:
+	default:
+		return fmt.Errorf("unsupported matching mode %q, must be %q or %q", m.Mode, MatchingModeOn, MatchingModeIgnoring)
+	}
+	switch m.Group {
+	case MatchingGroupNone:
+		if len(m.Include) > 0 {
+			return fmt.Errorf("included labels can only be set with the matching group %q or %q", MatchingGroupLeft, MatchingGroupRight)
+		}
+	case MatchingGroupLeft, MatchingGroupRight:
+	default:
+		return fmt.Errorf("unsupported matching group %q, must be %q or %q", m.Group, MatchingGroupLeft, MatchingGroupRight)
+	}
+	if m.Mode == MatchingModeOn {
+		for _, l := range m.Include {
+			if contains(m.Labels, l) {
+				return fmt.Errorf("label %q must not be both matched on and included", l)
+			}
+		}
+	}
+	return nil
+}
+
+// signature returns the labels that are used to match a result.
+func (m LabelMatching) signature(labels data.Labels) data.Labels {
+	sig := data.Labels{}
+	for k, v := range labels {
+		if m.Mode == MatchingModeOn && !contains(m.Labels, k) {
+			continue
+		}
+		if m.Mode != MatchingModeOn && contains(m.Labels, k) {
+			continue
+		}
+		sig[k] = v
+	}
+	return sig
+}
+
+// matchingUnion creates Union objects like union does, but matches the results of the two sides by the labels
+// selected by the matching. In one-to-one matching a result that matches several results of the other side is an error.
+func matchingUnion(aResults, bResults Results, m LabelMatching) ([]*Union, error) {
+	unions := []*Union{}
+	if len(aResults.Values) == 0 || len(bResults.Values) == 0 {
+		return unions, nil
+	}
+	if aResults.Values[0].Type() == parse.TypeNoData || bResults.Values[0].Type() == parse.TypeNoData {
+		return unions, nil
+	}
+
+	// the "one" side of the matching, the side where a signature must be unique.
+	one, many := bResults.Values, aResults.Values
+	oneSide, manySide := "right", "left"
+	if m.Group == MatchingGroupRight {
+		one, many = many, one
+		oneSide, manySide = manySide, oneSide
+	}
+
+	oneBySig := make(map[string]Value, len(one))
+	for _, v := range one {
+		if v.Type() == parse.TypeScalar {
+			continue
+		}
+		sig := m.signature(v.GetLabels()).String()
+		if _, ok := oneBySig[sig]; ok {
+			return nil, fmt.Errorf("found duplicate results for the match group {%s} on the %s side of the operation", sig, oneSide)
+		}
+		oneBySig[sig] = v
+	}
+
+	manySigs := make(map[string]struct{}, len(many))
+	for _, v := range many {
+		if v.Type() == parse.TypeScalar {
+			// scalars do not have labels and match every result of the other side.
+			for _, o := range one {
+				unions = append(unions, newMatchingUnion(m, v, o, o.GetLabels().Copy()))
+			}
+			continue
+		}
+		sig := m.signature(v.GetLabels())
+		key := sig.String()
+		if m.Group == MatchingGroupNone {
+			if _, ok := manySigs[key]; ok {
+				return nil, fmt.Errorf("found duplicate results for the match group {%s} on the %s side of the operation, use a matching group for many-to-one matching", key, manySide)
+			}
+			manySigs[key] = struct{}{}
+		}
+		for _, o := range one {
+			if o.Type() == parse.TypeScalar {
+				unions = append(unions, newMatchingUnion(m, v, o, v.GetLabels().Copy()))
+			}
+		}
+		o, ok := oneBySig[key]
+		if !ok {
+			continue
+		}
+		unions = append(unions, newMatchingUnion(m, v, o, m.resultLabels(v.GetLabels(), o.GetLabels(), sig)))
+	}
+	return unions, nil
+}
+
+// resultLabels returns the labels of the result of the operation on a result of the "many" side and a result of the "one" side.
+func (m LabelMatching) resultLabels(many, one, sig data.Labels) data.Labels {
+	if m.Group == MatchingGroupNone {
+		// in one-to-one matching only the labels that identify the match are kept, like in PromQL.
+		if m.Mode == MatchingModeOn {
+			return sig.Copy()
+		}
+		labels := many.Copy()
+		for _, l := range m.Labels {
+			delete(labels, l)
+		}
+		return labels
+	}
+	labels := many.Copy()
+	for _, l := range m.Include {
+		if v, ok := one[l]; ok {
+			labels[l] = v
+		} else {
+			delete(labels, l)
+		}
+	}
+	return labels
+}
+
+// newMatchingUnion creates a Union where A is the left side and B the right side of the operation.
+func newMatchingUnion(m LabelMatching, many, one Value, labels data.Labels) *Union {
+	if m.Group == MatchingGroupRight {
+		return &Union{Labels: labels, A: one, B: many}
+	}
+	return &Union{Labels: labels, A: many, B: one}
+}
+
+func contains(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,185 @@
+package mathexp
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_matchingUnion(t *testing.T) {
+	var tests = []struct {
+		name     string
+		matching LabelMatching
+		aResults Results
+		bResults Results
+		isError  bool
+		unions   []*Union
+	}{
+		{
+			name:     "on matches by the listed labels and keeps only them",
+			matching: LabelMatching{Mode: MatchingModeOn, Labels: []string{"host"}},
+			aResults: Results{Values: Values{
+				makeNumber("a", data.Labels{"host": "1", "source": "prom"}, nil),
+				makeNumber("a", data.Labels{"host": "2", "source": "prom"}, nil),
+			}},
+			bResults: Results{Values: Values{
+				makeNumber("b", data.Labels{"host": "2", "db": "capacity"}, nil),
+			}},
+			unions: []*Union{
+				{
+					Labels: data.Labels{"host": "2"},
+					A:      makeNumber("a", data.Labels{"host": "2", "source": "prom"}, nil),
+					B:      makeNumber("b", data.Labels{"host": "2", "db": "capacity"}, nil),
+				},
+			},
+		},
+		{
+			name:     "ignoring matches by all other labels and drops the ignored ones",
+			matching: LabelMatching{Mode: MatchingModeIgnoring, Labels: []string{"source"}},
+			aResults: Results{Values: Values{
+				makeNumber("a", data.Labels{"host": "1", "source": "prom"}, nil),
+			}},
+			bResults: Results{Values: Values{
+				makeNumber("b", data.Labels{"host": "1", "source": "sql"}, nil),
+				makeNumber("b", data.Labels{"host": "2", "source": "sql"}, nil),
+			}},
+			unions: []*Union{
+				{
+					Labels: data.Labels{"host": "1"},
+					A:      makeNumber("a", data.Labels{"host": "1", "source": "prom"}, nil),
+					B:      makeNumber("b", data.Labels{"host": "1", "source": "sql"}, nil),
+				},
+			},
+		},
+		{
+			name:     "one-to-one matching fails if the left side has duplicates",
+			matching: LabelMatching{Mode: MatchingModeOn, Labels: []string{"host"}},
+			aResults: Results{Values: Values{
+				makeNumber("a", data.Labels{"host": "1", "cpu": "0"}, nil),
+				makeNumber("a", data.Labels{"host": "1", "cpu": "1"}, nil),
+			}},
+			bResults: Results{Values: Values{
+				makeNumber("b", data.Labels{"host": "1"}, nil),
+			}},
+			isError: true,
+		},
+		{
+			name:     "group left matches many results of the left side and copies included labels",
+			matching: LabelMatching{Mode: MatchingModeOn, Labels: []string{"host"}, Group: MatchingGroupLeft, Include: []string{"unit"}},
+			aResults: Results{Values: Values{
+				makeNumber("a", data.Labels{"host": "1", "cpu": "0"}, nil),
+				makeNumber("a", data.Labels{"host": "1", "cpu": "1"}, nil),
+			}},
+			bResults: Results{Values: Values{
+				makeNumber("b", data.Labels{"host": "1", "unit": "cores"}, nil),
+			}},
+			unions: []*Union{
+				{
+					Labels: data.Labels{"host": "1", "cpu": "0", "unit": "cores"},
+					A:      makeNumber("a", data.Labels{"host": "1", "cpu": "0"}, nil),
+					B:      makeNumber("b", data.Labels{"host": "1", "unit": "cores"}, nil),
+				},
+				{
+					Labels: data.Labels{"host": "1", "cpu": "1", "unit": "cores"},
+					A:      makeNumber("a", data.Labels{"host": "1", "cpu": "1"}, nil),
+					B:      makeNumber("b", data.Labels{"host": "1", "unit": "cores"}, nil),
+				},
+			},
+		},
+		{
+			name:     "group left fails if the right side has duplicates",
+			matching: LabelMatching{Mode: MatchingModeOn, Labels: []string{"host"}, Group: MatchingGroupLeft},
+			aResults: Results{Values: Values{
+				makeNumber("a", data.Labels{"host": "1"}, nil),
+			}},
+			bResults: Results{Values: Values{
+				makeNumber("b", data.Labels{"host": "1", "cpu": "0"}, nil),
+				makeNumber("b", data.Labels{"host": "1", "cpu": "1"}, nil),
+			}},
+			isError: true,
+		},
+		{
+			name:     "group right keeps the operands in order",
+			matching: LabelMatching{Mode: MatchingModeOn, Labels: []string{"host"}, Group: MatchingGroupRight},
+			aResults: Results{Values: Values{
+				makeNumber("a", data.Labels{"host": "1"}, nil),
+			}},
+			bResults: Results{Values: Values{
+				makeNumber("b", data.Labels{"host": "1", "cpu": "0"}, nil),
+			}},
+			unions: []*Union{
+				{
+					Labels: data.Labels{"host": "1", "cpu": "0"},
+					A:      makeNumber("a", data.Labels{"host": "1"}, nil),
+					B:      makeNumber("b", data.Labels{"host": "1", "cpu": "0"}, nil),
+				},
+			},
+		},
+		{
+			name:     "scalars match every result",
+			matching: LabelMatching{Mode: MatchingModeOn, Labels: []string{"host"}},
+			aResults: Results{Values: Values{
+				makeNumber("a", data.Labels{"host": "1"}, nil),
+			}},
+			bResults: NewScalarResults("b", nil),
+			unions: []*Union{
+				{
+					Labels: data.Labels{"host": "1"},
+					A:      makeNumber("a", data.Labels{"host": "1"}, nil),
+					B:      NewScalar("b", nil),
+				},
+			},
+		},
+		{
+			name:     "no data results in no unions",
+			matching: LabelMatching{Mode: MatchingModeOn, Labels: []string{"host"}},
+			aResults: Results{Values: Values{
+				makeNumber("a", data.Labels{"host": "1"}, nil),
+			}},
+			bResults: Results{Values: Values{NoData{}.New()}},
+			unions:   []*Union{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unions, err := matchingUnion(tt.aResults, tt.bResults, tt.matching)
+			if tt.isError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.unions, unions)
+		})
+	}
+}
+
+func TestExecuteWithMatching(t *testing.T) {
+	e, err := New("$A / $B")
+	require.NoError(t, err)
+	vars := Vars{
+		"A": Results{Values: Values{
+			makeNumber("", data.Labels{"host": "1", "job": "node"}, float64Pointer(5)),
+			makeNumber("", data.Labels{"host": "2", "job": "node"}, float64Pointer(3)),
+		}},
+		"B": Results{Values: Values{
+			makeNumber("", data.Labels{"host": "1", "table": "capacity"}, float64Pointer(10)),
+			makeNumber("", data.Labels{"host": "2", "table": "capacity"}, float64Pointer(4)),
+		}},
+	}
+
+	t.Run("default matching drops results with different label sets", func(t *testing.T) {
+		res, err := e.Execute("C", vars)
+		require.NoError(t, err)
+		require.Empty(t, res.Values)
+	})
+
+	t.Run("matching on a label joins the results", func(t *testing.T) {
+		res, err := e.ExecuteWithMatching("C", vars, LabelMatching{Mode: MatchingModeOn, Labels: []string{"host"}})
+		require.NoError(t, err)
+		require.Equal(t, Values{
+			makeNumber("C", data.Labels{"host": "1"}, float64Pointer(0.5)),
+			makeNumber("C", data.Labels{"host": "2"}, float64Pointer(0.75)),
+		}, res.Values)
+	})
+}
@@ -3,6 +3,7 @@ package mathexp
 import (
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
@@ -81,7 +82,89 @@ func Last(fv *Float64Field) *float64 {
 	return fv.GetValue(fv.Len() - 1)
 }
 
-func GetReduceFunc(rFunc string) (ReducerFunc, error) {
+func First(fv *Float64Field) *float64 {
+	var f float64
+	if fv.Len() == 0 {
+		f = math.NaN()
+		return &f
+	}
+	return fv.GetValue(0)
+}
+
+// Diff returns the difference between the last and the first value.
+func Diff(fv *Float64Field) *float64 {
+	f := math.NaN()
+	if fv.Len() == 0 {
+		return &f
+	}
+	first, last := fv.GetValue(0), fv.GetValue(fv.Len()-1)
+	if first == nil || last == nil {
+		return &f
+	}
+	f = *last - *first
+	return &f
+}
+
+// CountNonNull returns the number of values that are neither null nor NaN.
+func CountNonNull(fv *Float64Field) *float64 {
+	var f float64
+	for i := 0; i < fv.Len(); i++ {
+		v := fv.GetValue(i)
+		if v == nil || math.IsNaN(*v) {
+			continue
+		}
+		f++
+	}
+	return &f
+}
+
+// StdDev returns the population standard deviation of the values.
+func StdDev(fv *Float64Field) *float64 {
+	avg := Avg(fv)
+	if math.IsNaN(*avg) {
+		return avg
+	}
+	var sum float64
+	for i := 0; i < fv.Len(); i++ {
+		d := *fv.GetValue(i) - *avg
+		sum += d * d
+	}
+	f := math.Sqrt(sum / float64(fv.Len()))
+	return &f
+}
+
+func Median(fv *Float64Field) *float64 {
+	return Percentile(50)(fv)
+}
+
+// Percentile returns a reducer that calculates the p-th percentile of the values, with p between 0 and 100.
+// The percentile is interpolated linearly between the closest ranks.
+func Percentile(p float64) ReducerFunc {
+	return func(fv *Float64Field) *float64 {
+		nan := math.NaN()
+		if fv.Len() == 0 {
+			return &nan
+		}
+		values := make([]float64, 0, fv.Len())
+		for i := 0; i < fv.Len(); i++ {
+			v := fv.GetValue(i)
+			if v == nil || math.IsNaN(*v) {
+				return &nan
+			}
+			values = append(values, *v)
+		}
+		sort.Float64s(values)
+		rank := p / 100 * float64(len(values)-1)
+		lower := int(math.Floor(rank))
+		upper := int(math.Ceil(rank))
+		f := values[lower] + (values[upper]-values[lower])*(rank-float64(lower))
+		return &f
+	}
+}
+
+// GetReduceFunc returns the reduction function with the given name. Reducers that require parameters,
+// such as percentile, are configured with params.
+func GetReduceFunc(rFunc string, params ...float64) (ReducerFunc, error) {
 	switch strings.ToLower(rFunc) {
 	case "sum":
 		return Sum, nil
@@ -95,6 +178,24 @@ func GetReduceFunc(rFunc string) (ReducerFunc, error) {
 		return Count, nil
 	case "last":
 		return Last, nil
+	case "first":
+		return First, nil
+	case "diff":
+		return Diff, nil
+	case "count_non_null":
+		return CountNonNull, nil
+	case "stddev":
+		return StdDev, nil
+	case "median":
+		return Median, nil
+	case "percentile":
+		if len(params) != 1 {
+			return nil, fmt.Errorf("reduction %v requires exactly one parameter, got %d", rFunc, len(params))
+		}
+		if params[0] < 0 || params[0] > 100 || math.IsNaN(params[0]) {
+			return nil, fmt.Errorf("reduction %v requires a parameter between 0 and 100, got %v", rFunc, params[0])
+		}
+		return Percentile(params[0]), nil
 	default:
 		return nil, fmt.Errorf("reduction %v not implemented", rFunc)
 	}
@@ -102,13 +203,14 @@ func GetReduceFunc(rFunc string) (ReducerFunc, error) {
 
 // GetSupportedReduceFuncs returns collection of supported function names
 func GetSupportedReduceFuncs() []string {
-	return []string{"sum", "mean", "min", "max", "count", "last"}
+	return []string{"sum", "mean", "min", "max", "count", "last", "first", "diff", "count_non_null", "stddev", "median", "percentile"}
 }
 
 // Reduce turns the Series into a Number based on the given reduction function
 // if ReduceMapper is defined it applies it to the provided series and performs reduction of the resulting series.
 // Otherwise, the reduction operation is done against the original series.
-func (s Series) Reduce(refID, rFunc string, mapper ReduceMapper) (Number, error) {
+// The params configure reduction functions that require parameters, e.g. the percentile.
+func (s Series) Reduce(refID, rFunc string, mapper ReduceMapper, params ...float64) (Number, error) {
 	var l data.Labels
 	if s.GetLabels() != nil {
 		l = s.GetLabels().Copy()
@@ -121,7 +223,7 @@ func (s Series) Reduce(refID, rFunc string, mapper ReduceMapper) (Number, error)
 	}
 	fVec := series.Frame.Fields[seriesTypeValIdx]
 	floatField := Float64Field(*fVec)
-	reduceFunc, err := GetReduceFunc(rFunc)
+	reduceFunc, err := GetReduceFunc(rFunc, params...)
 	if err != nil {
 		return number, fmt.Errorf("invalid expression '%s': %w", refID, err)
 	}
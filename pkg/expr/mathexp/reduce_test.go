@@ -514,3 +514,59 @@ func TestSeriesReduceReplaceNN(t *testing.T) {
 		})
 	}
 }
+
+func TestReduceFuncs(t *testing.T) {
+	values := []*float64{float64Pointer(4), float64Pointer(1), float64Pointer(3), float64Pointer(2), float64Pointer(10)}
+	withNil := []*float64{float64Pointer(4), nil, float64Pointer(3)}
+
+	var tests = []struct {
+		name     string
+		red      string
+		params   []float64
+		values   []*float64
+		expected *float64
+	}{
+		{name: "first", red: "first", values: values, expected: float64Pointer(4)},
+		{name: "first of empty series", red: "first", values: nil, expected: NaN},
+		{name: "diff", red: "diff", values: values, expected: float64Pointer(6)},
+		{name: "diff with nil", red: "diff", values: []*float64{nil, float64Pointer(1)}, expected: NaN},
+		{name: "count_non_null", red: "count_non_null", values: withNil, expected: float64Pointer(2)},
+		{name: "count_non_null of empty series", red: "count_non_null", values: nil, expected: float64Pointer(0)},
+		{name: "stddev", red: "stddev", values: []*float64{float64Pointer(2), float64Pointer(4), float64Pointer(4), float64Pointer(4), float64Pointer(5), float64Pointer(5), float64Pointer(7), float64Pointer(9)}, expected: float64Pointer(2)},
+		{name: "stddev with nil", red: "stddev", values: withNil, expected: NaN},
+		{name: "median of odd number of values", red: "median", values: values, expected: float64Pointer(3)},
+		{name: "median of even number of values", red: "median", values: values[:4], expected: float64Pointer(2.5)},
+		{name: "median with nil", red: "median", values: withNil, expected: NaN},
+		{name: "percentile 0", red: "percentile", params: []float64{0}, values: values, expected: float64Pointer(1)},
+		{name: "percentile 100", red: "percentile", params: []float64{100}, values: values, expected: float64Pointer(10)},
+		{name: "percentile 90 interpolates", red: "percentile", params: []float64{90}, values: values, expected: float64Pointer(7.6)},
+		{name: "percentile of empty series", red: "percentile", params: []float64{95}, values: nil, expected: NaN},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn, err := GetReduceFunc(tt.red, tt.params...)
+			require.NoError(t, err)
+			field := Float64Field(*data.NewField("", nil, tt.values))
+			actual := fn(&field)
+			require.NotNil(t, actual)
+			if math.IsNaN(*tt.expected) {
+				require.Truef(t, math.IsNaN(*actual), "expected NaN, got %v", *actual)
+				return
+			}
+			require.InDelta(t, *tt.expected, *actual, 1e-9)
+		})
+	}
+
+	t.Run("percentile requires a parameter", func(t *testing.T) {
+		_, err := GetReduceFunc("percentile")
+		require.Error(t, err)
+	})
+
+	t.Run("percentile requires a parameter between 0 and 100", func(t *testing.T) {
+		_, err := GetReduceFunc("percentile", 101)
+		require.Error(t, err)
+		_, err = GetReduceFunc("percentile", -1)
+		require.Error(t, err)
+	})
+}
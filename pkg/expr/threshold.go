@@ -14,6 +14,8 @@ type ThresholdCommand struct {
 	RefID         string
 	ThresholdFunc string
 	Conditions    []float64
+	// Invert negates the result of the threshold function.
+	Invert bool
 }
 
 const (
@@ -38,6 +40,9 @@ func NewThresholdCommand(refID, referenceVar, thresholdFunc string, conditions [
 
 type ThresholdConditionJSON struct {
 	Evaluator ConditionEvalJSON `json:"evaluator"`
+	// UnloadEvaluator is the optional condition that resolves the dimensions that are firing.
+	// If it is set, Evaluator is only used for the dimensions that are not firing.
+	UnloadEvaluator *ConditionEvalJSON `json:"unloadEvaluator,omitempty"`
 }
 
 type ConditionEvalJSON struct {
@@ -45,8 +50,9 @@ type ConditionEvalJSON struct {
 	Type   string    `json:"type"` // e.g. "gt"
 }
 
-// UnmarshalResampleCommand creates a ResampleCMD from Grafana's frontend query.
-func UnmarshalThresholdCommand(rn *rawNode) (*ThresholdCommand, error) {
+// UnmarshalThresholdCommand creates a ThresholdCommand, or a HysteresisCommand if the condition has
+// an unload evaluator, from Grafana's frontend query.
+func UnmarshalThresholdCommand(rn *rawNode) (Command, error) {
 	rawQuery := rn.Query
 
 	rawExpression, ok := rawQuery["expression"]
@@ -79,7 +85,23 @@ func UnmarshalThresholdCommand(rn *rawNode) (*ThresholdCommand, error) {
 	}
 	firstCondition := conditions[0]
 
-	return NewThresholdCommand(rn.RefID, referenceVar, firstCondition.Evaluator.Type, firstCondition.Evaluator.Params)
+	if firstCondition.UnloadEvaluator == nil {
+		return NewThresholdCommand(rn.RefID, referenceVar, firstCondition.Evaluator.Type, firstCondition.Evaluator.Params)
+	}
+
+	if !IsSupportedThresholdFunc(firstCondition.UnloadEvaluator.Type) {
+		return nil, fmt.Errorf("expected unload threshold function to be one of %s, got %s", strings.Join(supportedThresholdFuncs, ", "), firstCondition.UnloadEvaluator.Type)
+	}
+	for _, evaluator := range []ConditionEvalJSON{firstCondition.Evaluator, *firstCondition.UnloadEvaluator} {
+		if err := validateThresholdParams(evaluator.Type, evaluator.Params); err != nil {
+			return nil, err
+		}
+	}
+	loadedDimensions, err := unmarshalLoadedDimensions(rawQuery[loadedDimensionsKey])
+	if err != nil {
+		return nil, err
+	}
+	return NewHysteresisCommand(rn.RefID, referenceVar, firstCondition.Evaluator, *firstCondition.UnloadEvaluator, loadedDimensions)
 }
 
 // NeedsVars returns the variable names (refIds) that are dependencies
@@ -93,6 +115,9 @@ func (tc *ThresholdCommand) Execute(ctx context.Context, vars mathexp.Vars) (mat
 	if err != nil {
 		return mathexp.Results{}, err
 	}
+	if tc.Invert {
+		mathExpression = fmt.Sprintf("!(%s)", mathExpression)
+	}
 
 	mathCommand, err := NewMathCommand(tc.ReferenceVar, mathExpression)
 	if err != nil {
@@ -118,6 +143,18 @@ func createMathExpression(referenceVar string, thresholdFunc string, args []floa
 	}
 }
 
+// validateThresholdParams returns an error if the threshold function does not have enough parameters.
+func validateThresholdParams(thresholdFunc string, params []float64) error {
+	required := 1
+	if thresholdFunc == ThresholdIsWithinRange || thresholdFunc == ThresholdIsOutsideRange {
+		required = 2
+	}
+	if len(params) < required {
+		return fmt.Errorf("threshold function %s requires %d parameters, got %d", thresholdFunc, required, len(params))
+	}
+	return nil
+}
+
 func IsSupportedThresholdFunc(name string) bool {
 	isSupported := false
 
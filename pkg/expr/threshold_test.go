@@ -71,6 +71,63 @@ func TestUnmarshalThresholdCommand(t *testing.T) {
 			shouldError:   true,
 			expectedError: "expected threshold function to be one of",
 		},
+		{
+			description: "unmarshal with unload evaluator creates hysteresis command",
+			query: `{
+				"expression" : "A",
+				"type": "threshold",
+				"conditions": [{
+					"evaluator": {
+						"type": "gt",
+						"params": [80]
+					},
+					"unloadEvaluator": {
+						"type": "lt",
+						"params": [70]
+					}
+				}],
+				"loadedDimensions": [{"host": "a"}]
+			}`,
+			shouldError: false,
+		},
+		{
+			description: "unmarshal with unsupported unload threshold function",
+			query: `{
+				"expression" : "A",
+				"type": "threshold",
+				"conditions": [{
+					"evaluator": {
+						"type": "gt",
+						"params": [80]
+					},
+					"unloadEvaluator": {
+						"type": "foo",
+						"params": [70]
+					}
+				}]
+			}`,
+			shouldError:   true,
+			expectedError: "expected unload threshold function to be one of",
+		},
+		{
+			description: "unmarshal with unload evaluator without enough params",
+			query: `{
+				"expression" : "A",
+				"type": "threshold",
+				"conditions": [{
+					"evaluator": {
+						"type": "gt",
+						"params": [80]
+					},
+					"unloadEvaluator": {
+						"type": "outside_range",
+						"params": [70]
+					}
+				}]
+			}`,
+			shouldError:   true,
+			expectedError: "requires 2 parameters",
+		},
 		{
 			description: "unmarshal with bad expression",
 			query: `{
@@ -19,6 +19,9 @@ type Cfg struct {
 	PluginSettings       setting.PluginSettings
 	PluginsAllowUnsigned []string
 
+	// PluginRepositoriesConfig is a path to a YAML file with plugin repositories.
+	PluginRepositoriesConfig string
+
 	EnterpriseLicensePath string
 
 	// AWS Plugin Auth
@@ -53,15 +56,16 @@ func NewCfg(settingProvider setting.Provider, grafanaCfg *setting.Cfg) *Cfg {
 	}
 
 	return &Cfg{
-		log:                     logger,
-		PluginsPath:             grafanaCfg.PluginsPath,
-		BuildVersion:            grafanaCfg.BuildVersion,
-		DevMode:                 settingProvider.KeyValue("", "app_mode").MustBool(grafanaCfg.Env == setting.Dev),
-		EnterpriseLicensePath:   settingProvider.KeyValue("enterprise", "license_path").MustString(grafanaCfg.EnterpriseLicensePath),
-		PluginSettings:          extractPluginSettings(settingProvider),
-		PluginsAllowUnsigned:    allowedUnsigned,
-		AWSAllowedAuthProviders: allowedAuth,
-		AWSAssumeRoleEnabled:    aws.KeyValue("assume_role_enabled").MustBool(grafanaCfg.AWSAssumeRoleEnabled),
+		log:                      logger,
+		PluginsPath:              grafanaCfg.PluginsPath,
+		BuildVersion:             grafanaCfg.BuildVersion,
+		DevMode:                  settingProvider.KeyValue("", "app_mode").MustBool(grafanaCfg.Env == setting.Dev),
+		EnterpriseLicensePath:    settingProvider.KeyValue("enterprise", "license_path").MustString(grafanaCfg.EnterpriseLicensePath),
+		PluginSettings:           extractPluginSettings(settingProvider),
+		PluginsAllowUnsigned:     allowedUnsigned,
+		PluginRepositoriesConfig: grafanaCfg.PluginRepositoriesConfig,
+		AWSAllowedAuthProviders:  allowedAuth,
+		AWSAssumeRoleEnabled:     aws.KeyValue("assume_role_enabled").MustBool(grafanaCfg.AWSAssumeRoleEnabled),
 		Azure: &azsettings.AzureSettings{
 			Cloud:                   azure.KeyValue("cloud").MustString(grafanaCfg.Azure.Cloud),
 			ManagedIdentityEnabled:  azure.KeyValue("managed_identity_enabled").MustBool(grafanaCfg.Azure.ManagedIdentityEnabled),
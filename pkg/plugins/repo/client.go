@@ -22,10 +22,19 @@ type Client struct {
 	httpClient          http.Client
 	httpClientNoTimeout http.Client
 	retryCount          int
+	auth                *clientAuth
 
 	log logger.Logger
 }
 
+// clientAuth holds credentials of a repository, they are only sent to the host of the repository.
+type clientAuth struct {
+	host              string
+	basicAuthUser     string
+	basicAuthPassword string
+	bearerToken       string
+}
+
 func newClient(skipTLSVerify bool, logger logger.Logger) *Client {
 	return &Client{
 		httpClient:          makeHttpClient(skipTLSVerify, 10*time.Second),
@@ -34,6 +43,23 @@ func newClient(skipTLSVerify bool, logger logger.Logger) *Client {
 	}
 }
 
+// newRepositoryClient creates a client which authenticates requests to the repository host.
+func newRepositoryClient(cfg RepositoryConfig, logger logger.Logger) *Client {
+	c := newClient(cfg.SkipTLSVerify, logger)
+	if cfg.BasicAuthUser == "" && cfg.BearerToken == "" {
+		return c
+	}
+	if u, err := url.Parse(cfg.URL); err == nil {
+		c.auth = &clientAuth{
+			host:              u.Host,
+			basicAuthUser:     cfg.BasicAuthUser,
+			basicAuthPassword: cfg.BasicAuthPassword,
+			bearerToken:       cfg.BearerToken,
+		}
+	}
+	return c
+}
+
 func (c *Client) download(_ context.Context, pluginZipURL, checksum string, compatOpts CompatOpts) (*PluginArchive, error) {
 	// Create temp file for downloading zip file
 	tmpFile, err := os.CreateTemp("", "*.zip")
@@ -194,6 +220,14 @@ func (c *Client) createReq(url *url.URL, compatOpts CompatOpts) (*http.Request,
 	req.Header.Set("grafana-arch", compatOpts.Arch)
 	req.Header.Set("User-Agent", "grafana "+compatOpts.GrafanaVersion)
 
+	if c.auth != nil && url.Host == c.auth.host {
+		if c.auth.bearerToken != "" {
+			req.Header.Set("Authorization", "Bearer "+c.auth.bearerToken)
+		} else {
+			req.SetBasicAuth(c.auth.basicAuthUser, c.auth.basicAuthPassword)
+		}
+	}
+
 	return req, err
 }
 
@@ -3,6 +3,7 @@ package repo
 import (
 	"archive/zip"
 	"fmt"
+	"strings"
 )
 
 type PluginArchive struct {
@@ -26,6 +27,8 @@ type Version struct {
 	URL     string              `json:"repoURL"`
 	Version string              `json:"version"`
 	Arch    map[string]ArchMeta `json:"arch"`
+	// DownloadURL is a location of the archive in an index repository, relative to the index.
+	DownloadURL string `json:"downloadUrl,omitempty"`
 }
 
 type ArchMeta struct {
@@ -72,3 +75,12 @@ type ErrVersionNotFound struct {
 func (e ErrVersionNotFound) Error() string {
 	return fmt.Sprintf("%s v%s either does not exist or is not supported on your system (%s)", e.PluginID, e.RequestedVersion, e.SystemInfo)
 }
+
+type ErrPluginNotFound struct {
+	PluginID     string
+	Repositories []string
+}
+
+func (e ErrPluginNotFound) Error() string {
+	return fmt.Sprintf("plugin %s not found in repositories: %s", e.PluginID, strings.Join(e.Repositories, ", "))
+}
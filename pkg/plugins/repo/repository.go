@@ -0,0 +1,244 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/plugins/logger"
+)
+
+const (
+	// DefaultRepositoryURL is the URL of the grafana.com plugin repository.
+	DefaultRepositoryURL = "https://grafana.com/api/plugins"
+
+	// indexFileName is the name of the index file in a directory repository.
+	indexFileName = "repo.json"
+)
+
+// RepositoryConfig configures a plugin repository.
+type RepositoryConfig struct {
+	Name string `yaml:"name"`
+	// URL is one of:
+	// * a base URL of an API with the same layout as https://grafana.com/api/plugins
+	// * a URL of a JSON index file in the same shape as https://grafana.com/api/plugins/repo
+	// * a path to a local JSON index file, or to a directory with a repo.json index file
+	URL               string `yaml:"url"`
+	BasicAuthUser     string `yaml:"basicAuthUser"`
+	BasicAuthPassword string `yaml:"basicAuthPassword"`
+	BearerToken       string `yaml:"bearerToken"`
+	SkipTLSVerify     bool   `yaml:"skipTlsVerify"`
+}
+
+type repositoriesConfig struct {
+	APIVersion   int64              `yaml:"apiVersion"`
+	Repositories []RepositoryConfig `yaml:"repositories"`
+}
+
+// ReadRepositoriesConfig reads the list of plugin repositories from a YAML file.
+func ReadRepositoriesConfig(path string) ([]RepositoryConfig, error) {
+	// nolint:gosec
+	// We can ignore the gosec G304 warning since the path comes from Grafana configuration or a command line flag.
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin repositories config: %w", err)
+	}
+	var cfg repositoriesConfig
+	if err := yaml.Unmarshal(b, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse plugin repositories config %s: %w", path, err)
+	}
+	if cfg.APIVersion != 1 {
+		return nil, fmt.Errorf("unsupported apiVersion %d in plugin repositories config %s", cfg.APIVersion, path)
+	}
+	if len(cfg.Repositories) == 0 {
+		return nil, fmt.Errorf("no plugin repositories in %s", path)
+	}
+	for i, r := range cfg.Repositories {
+		if r.URL == "" {
+			return nil, fmt.Errorf("plugin repository %d in %s: url required", i+1, path)
+		}
+		if r.Name == "" {
+			cfg.Repositories[i].Name = r.URL
+		}
+	}
+	return cfg.Repositories, nil
+}
+
+// repository is a source of plugin metadata and archives.
+type repository interface {
+	// name returns a name of the repository for logs and errors.
+	name() string
+	// plugin returns metadata of a plugin, false if the repository does not have the plugin.
+	plugin(ctx context.Context, pluginID string, compatOpts CompatOpts) (Plugin, bool, error)
+	// plugins returns metadata of all plugins in the repository.
+	plugins(ctx context.Context, compatOpts CompatOpts) ([]Plugin, error)
+	// downloadURL returns the URL or local path of a plugin version archive.
+	downloadURL(pluginID string, version Version) string
+	// client returns a client to download archives of the repository.
+	client() *Client
+}
+
+func newRepository(cfg RepositoryConfig, logger logger.Logger) repository {
+	c := newRepositoryClient(cfg, logger)
+	u, err := url.Parse(cfg.URL)
+	isHTTP := err == nil && (u.Scheme == "http" || u.Scheme == "https")
+	if isHTTP && !strings.HasSuffix(u.Path, ".json") {
+		return &apiRepository{cfg: cfg, c: c}
+	}
+	location := cfg.URL
+	if err == nil && u.Scheme == "file" {
+		location = u.Path
+	}
+	if !isHTTP {
+		if info, err := os.Stat(location); err == nil && info.IsDir() {
+			location = filepath.Join(location, indexFileName)
+		}
+	}
+	return &indexRepository{cfg: cfg, location: location, isHTTP: isHTTP, c: c}
+}
+
+// apiRepository is a repository with the grafana.com API layout.
+type apiRepository struct {
+	cfg RepositoryConfig
+	c   *Client
+}
+
+func (r *apiRepository) name() string {
+	return r.cfg.Name
+}
+
+func (r *apiRepository) client() *Client {
+	return r.c
+}
+
+func (r *apiRepository) plugin(_ context.Context, pluginID string, compatOpts CompatOpts) (Plugin, bool, error) {
+	body, err := r.get(compatOpts, "repo", pluginID)
+	if err != nil {
+		var respErr Response4xxError
+		if errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound {
+			return Plugin{}, false, nil
+		}
+		return Plugin{}, false, err
+	}
+
+	var data Plugin
+	if err := json.Unmarshal(body, &data); err != nil {
+		return Plugin{}, false, fmt.Errorf("failed to unmarshal plugin repo response: %w", err)
+	}
+	return data, true, nil
+}
+
+func (r *apiRepository) plugins(_ context.Context, compatOpts CompatOpts) ([]Plugin, error) {
+	body, err := r.get(compatOpts, "repo")
+	if err != nil {
+		return nil, err
+	}
+	var data PluginRepo
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal plugin repo response: %w", err)
+	}
+	return data.Plugins, nil
+}
+
+func (r *apiRepository) downloadURL(pluginID string, version Version) string {
+	return fmt.Sprintf("%s/%s/versions/%s/download", r.cfg.URL, pluginID, version.Version)
+}
+
+func (r *apiRepository) get(compatOpts CompatOpts, subPaths ...string) ([]byte, error) {
+	u, err := url.Parse(r.cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(append([]string{u.Path}, subPaths...)...)
+	return r.c.sendReq(u, compatOpts)
+}
+
+// indexRepository is a repository described by a single JSON index file, served
+// over HTTP or stored locally. Archives are expected next to the index as
+// <plugin id>-<version>.zip unless versions set downloadUrl.
+type indexRepository struct {
+	cfg      RepositoryConfig
+	location string
+	isHTTP   bool
+	c        *Client
+}
+
+func (r *indexRepository) name() string {
+	return r.cfg.Name
+}
+
+func (r *indexRepository) client() *Client {
+	return r.c
+}
+
+func (r *indexRepository) plugin(ctx context.Context, pluginID string, compatOpts CompatOpts) (Plugin, bool, error) {
+	plugins, err := r.plugins(ctx, compatOpts)
+	if err != nil {
+		return Plugin{}, false, err
+	}
+	for _, p := range plugins {
+		if p.ID == pluginID {
+			return p, true, nil
+		}
+	}
+	return Plugin{}, false, nil
+}
+
+func (r *indexRepository) plugins(_ context.Context, compatOpts CompatOpts) ([]Plugin, error) {
+	var body []byte
+	var err error
+	if r.isHTTP {
+		var u *url.URL
+		u, err = url.Parse(r.location)
+		if err != nil {
+			return nil, err
+		}
+		body, err = r.c.sendReq(u, compatOpts)
+	} else {
+		body, err = os.ReadFile(r.location)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin index %s: %w", r.location, err)
+	}
+
+	var data PluginRepo
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal plugin index %s: %w", r.location, err)
+	}
+	return data.Plugins, nil
+}
+
+func (r *indexRepository) downloadURL(pluginID string, version Version) string {
+	downloadURL := version.DownloadURL
+	if downloadURL == "" {
+		downloadURL = fmt.Sprintf("%s-%s.zip", pluginID, version.Version)
+	}
+	if r.isHTTP {
+		base, err := url.Parse(r.location)
+		if err != nil {
+			return downloadURL
+		}
+		ref, err := url.Parse(downloadURL)
+		if err != nil {
+			return downloadURL
+		}
+		return base.ResolveReference(ref).String()
+	}
+	if u, err := url.Parse(downloadURL); err == nil && u.Scheme != "" && u.Scheme != "file" {
+		return downloadURL
+	}
+	downloadURL = strings.TrimPrefix(downloadURL, "file://")
+	if filepath.IsAbs(downloadURL) {
+		return downloadURL
+	}
+	return filepath.Join(filepath.Dir(r.location), downloadURL)
+}
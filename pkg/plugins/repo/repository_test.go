@@ -0,0 +1,159 @@
+package repo
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadRepositoriesConfig(t *testing.T) {
+	writeConfig := func(t *testing.T, content string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "repositories.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+
+	t.Run("Should read repositories in order", func(t *testing.T) {
+		repositories, err := ReadRepositoriesConfig(writeConfig(t, `
+apiVersion: 1
+repositories:
+  - name: internal
+    url: https://plugins.example.com/api/plugins
+    basicAuthUser: grafana
+    basicAuthPassword: secret
+  - url: /var/lib/grafana/plugin-repo
+`))
+		require.NoError(t, err)
+		require.Equal(t, []RepositoryConfig{
+			{Name: "internal", URL: "https://plugins.example.com/api/plugins", BasicAuthUser: "grafana", BasicAuthPassword: "secret"},
+			{Name: "/var/lib/grafana/plugin-repo", URL: "/var/lib/grafana/plugin-repo"},
+		}, repositories)
+	})
+
+	t.Run("Should return error when url is missing", func(t *testing.T) {
+		_, err := ReadRepositoriesConfig(writeConfig(t, "apiVersion: 1\nrepositories:\n  - name: internal\n"))
+		require.ErrorContains(t, err, "url required")
+	})
+
+	t.Run("Should return error for unsupported apiVersion", func(t *testing.T) {
+		_, err := ReadRepositoriesConfig(writeConfig(t, "repositories:\n  - url: /tmp\n"))
+		require.ErrorContains(t, err, "unsupported apiVersion")
+	})
+}
+
+func TestManager_IndexRepository(t *testing.T) {
+	dir := t.TempDir()
+	writePluginZip(t, filepath.Join(dir, "test-panel-1.0.0.zip"))
+	writePluginZip(t, filepath.Join(dir, "archives", "test-datasource.zip"))
+	writeIndex(t, filepath.Join(dir, "repo.json"), PluginRepo{Plugins: []Plugin{
+		{ID: "test-panel", Versions: []Version{{Version: "1.0.0"}}},
+		{ID: "test-datasource", Versions: []Version{{Version: "2.0.0", DownloadURL: "archives/test-datasource.zip"}}},
+	}})
+	m := NewWithRepositories([]RepositoryConfig{{Name: "local", URL: dir}}, &fakeLogger{})
+
+	dlOpts, err := m.GetPluginDownloadOptions(context.Background(), "test-panel", "", CompatOpts{})
+	require.NoError(t, err)
+	require.Equal(t, "1.0.0", dlOpts.Version)
+	require.Equal(t, filepath.Join(dir, "test-panel-1.0.0.zip"), dlOpts.PluginZipURL)
+
+	archive, err := m.GetPluginArchive(context.Background(), "test-datasource", "2.0.0", CompatOpts{})
+	require.NoError(t, err)
+	require.NoError(t, archive.File.Close())
+
+	_, err = m.GetPluginArchive(context.Background(), "unknown", "", CompatOpts{})
+	require.ErrorAs(t, err, &ErrPluginNotFound{})
+}
+
+func TestManager_MultipleRepositories(t *testing.T) {
+	var privateRequests []string
+	private := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "grafana" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		privateRequests = append(privateRequests, r.URL.Path)
+		switch r.URL.Path {
+		case "/api/plugins/repo":
+			writeJSON(t, w, PluginRepo{Plugins: []Plugin{{ID: "private-panel", Versions: []Version{{Version: "1.0.0"}}}, {ID: "shared-panel", Versions: []Version{{Version: "3.0.0"}}}}})
+		case "/api/plugins/repo/private-panel":
+			writeJSON(t, w, Plugin{ID: "private-panel", Versions: []Version{{Version: "1.0.0"}}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(private.Close)
+
+	var publicAuthorization []string
+	public := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		publicAuthorization = append(publicAuthorization, r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/index.json":
+			writeJSON(t, w, PluginRepo{Plugins: []Plugin{{ID: "public-panel", Versions: []Version{{Version: "2.0.0"}}}, {ID: "shared-panel", Versions: []Version{{Version: "1.0.0"}}}}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(public.Close)
+
+	m := NewWithRepositories([]RepositoryConfig{
+		{Name: "private", URL: private.URL + "/api/plugins", BasicAuthUser: "grafana", BasicAuthPassword: "secret"},
+		{Name: "public", URL: public.URL + "/index.json"},
+	}, &fakeLogger{})
+
+	dlOpts, err := m.GetPluginDownloadOptions(context.Background(), "private-panel", "", CompatOpts{})
+	require.NoError(t, err)
+	require.Equal(t, private.URL+"/api/plugins/private-panel/versions/1.0.0/download", dlOpts.PluginZipURL)
+	require.Same(t, m.repositories[0].client(), m.clientForURL(dlOpts.PluginZipURL))
+
+	dlOpts, err = m.GetPluginDownloadOptions(context.Background(), "public-panel", "", CompatOpts{})
+	require.NoError(t, err)
+	require.Equal(t, public.URL+"/public-panel-2.0.0.zip", dlOpts.PluginZipURL)
+	require.Same(t, m.client, m.clientForURL(dlOpts.PluginZipURL))
+
+	plugins, err := m.ListPlugins(context.Background(), CompatOpts{})
+	require.NoError(t, err)
+	require.Len(t, plugins, 3)
+	require.Equal(t, "shared-panel", plugins[1].ID)
+	require.Equal(t, "3.0.0", plugins[1].Versions[0].Version)
+
+	require.Contains(t, privateRequests, "/api/plugins/repo/public-panel")
+	for _, authorization := range publicAuthorization {
+		require.Empty(t, authorization, "credentials must not be sent to other repositories")
+	}
+}
+
+func writePluginZip(t *testing.T, path string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0750))
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	w := zip.NewWriter(f)
+	pluginJSON, err := w.Create("test/plugin.json")
+	require.NoError(t, err)
+	_, err = pluginJSON.Write([]byte(`{"id": "test"}`))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, f.Close())
+}
+
+func writeIndex(t *testing.T, path string, index PluginRepo) {
+	t.Helper()
+	b, err := json.Marshal(index)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, b, 0600))
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, v interface{}) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	require.NoError(t, json.NewEncoder(w).Encode(v))
+}
@@ -2,60 +2,109 @@ package repo
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/url"
-	"path"
 	"strings"
 
+	"github.com/grafana/grafana/pkg/plugins/config"
 	"github.com/grafana/grafana/pkg/plugins/logger"
 )
 
 type Manager struct {
-	client  *Client
-	baseURL string
+	client       *Client
+	repositories []repository
 
 	log logger.Logger
 }
 
-func ProvideService() *Manager {
-	defaultBaseURL := "https://grafana.com/api/plugins"
-	return New(false, defaultBaseURL, logger.NewLogger("plugin.repository"))
+func ProvideService(cfg *config.Cfg) (*Manager, error) {
+	logger := logger.NewLogger("plugin.repository")
+	if cfg.PluginRepositoriesConfig == "" {
+		return New(false, DefaultRepositoryURL, logger), nil
+	}
+	repositories, err := ReadRepositoriesConfig(cfg.PluginRepositoriesConfig)
+	if err != nil {
+		return nil, err
+	}
+	return NewWithRepositories(repositories, logger), nil
 }
 
+// New creates a Manager for a single repository, baseURL can also be a location of an index file.
 func New(skipTLSVerify bool, baseURL string, logger logger.Logger) *Manager {
-	return &Manager{
-		client:  newClient(skipTLSVerify, logger),
-		baseURL: baseURL,
-		log:     logger,
+	return NewWithRepositories([]RepositoryConfig{{Name: baseURL, URL: baseURL, SkipTLSVerify: skipTLSVerify}}, logger)
+}
+
+// NewWithRepositories creates a Manager which looks up plugins in repositories in the given order.
+// The first repository with a plugin ID wins, so private repositories must come before public ones,
+// otherwise a public plugin with the same ID as a private one is installed instead.
+func NewWithRepositories(repositories []RepositoryConfig, logger logger.Logger) *Manager {
+	m := &Manager{
+		client: newClient(false, logger),
+		log:    logger,
+	}
+	for _, cfg := range repositories {
+		m.repositories = append(m.repositories, newRepository(cfg, logger))
 	}
+	return m
 }
 
 // GetPluginArchive fetches the requested plugin archive
 func (m *Manager) GetPluginArchive(ctx context.Context, pluginID, version string, compatOpts CompatOpts) (*PluginArchive, error) {
-	dlOpts, err := m.GetPluginDownloadOptions(ctx, pluginID, version, compatOpts)
+	dlOpts, r, err := m.downloadOptions(ctx, pluginID, version, compatOpts)
 	if err != nil {
 		return nil, err
 	}
 
-	return m.client.download(ctx, dlOpts.PluginZipURL, dlOpts.Checksum, compatOpts)
+	return r.client().download(ctx, dlOpts.PluginZipURL, dlOpts.Checksum, compatOpts)
 }
 
 // GetPluginArchiveByURL fetches the requested plugin archive from the provided `pluginZipURL`
 func (m *Manager) GetPluginArchiveByURL(ctx context.Context, pluginZipURL string, compatOpts CompatOpts) (*PluginArchive, error) {
-	return m.client.download(ctx, pluginZipURL, "", compatOpts)
+	return m.clientForURL(pluginZipURL).download(ctx, pluginZipURL, "", compatOpts)
 }
 
 // GetPluginDownloadOptions returns the options for downloading the requested plugin (with optional `version`)
-func (m *Manager) GetPluginDownloadOptions(_ context.Context, pluginID, version string, compatOpts CompatOpts) (*PluginDownloadOptions, error) {
-	plugin, err := m.pluginMetadata(pluginID, compatOpts)
+func (m *Manager) GetPluginDownloadOptions(ctx context.Context, pluginID, version string, compatOpts CompatOpts) (*PluginDownloadOptions, error) {
+	dlOpts, _, err := m.downloadOptions(ctx, pluginID, version, compatOpts)
+	return dlOpts, err
+}
+
+// GetPlugin returns metadata of the requested plugin from the first repository which has it.
+func (m *Manager) GetPlugin(ctx context.Context, pluginID string, compatOpts CompatOpts) (Plugin, error) {
+	plugin, _, err := m.pluginMetadata(ctx, pluginID, compatOpts)
+	return plugin, err
+}
+
+// ListPlugins returns metadata of plugins in all repositories. Plugins of a
+// repository hide plugins with the same ID in the following repositories.
+func (m *Manager) ListPlugins(ctx context.Context, compatOpts CompatOpts) ([]Plugin, error) {
+	seen := map[string]bool{}
+	var plugins []Plugin
+	for _, r := range m.repositories {
+		repoPlugins, err := r.plugins(ctx, compatOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list plugins of repository %s: %w", r.name(), err)
+		}
+		for _, p := range repoPlugins {
+			if seen[p.ID] {
+				continue
+			}
+			seen[p.ID] = true
+			plugins = append(plugins, p)
+		}
+	}
+	return plugins, nil
+}
+
+func (m *Manager) downloadOptions(ctx context.Context, pluginID, version string, compatOpts CompatOpts) (*PluginDownloadOptions, repository, error) {
+	plugin, r, err := m.pluginMetadata(ctx, pluginID, compatOpts)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	v, err := m.selectVersion(&plugin, version, compatOpts)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Plugins which are downloaded just as sourcecode zipball from GitHub do not have checksum
@@ -71,32 +120,44 @@ func (m *Manager) GetPluginDownloadOptions(_ context.Context, pluginID, version
 	return &PluginDownloadOptions{
 		Version:      v.Version,
 		Checksum:     checksum,
-		PluginZipURL: fmt.Sprintf("%s/%s/versions/%s/download", m.baseURL, pluginID, v.Version),
-	}, nil
+		PluginZipURL: r.downloadURL(pluginID, *v),
+	}, r, nil
 }
 
-func (m *Manager) pluginMetadata(pluginID string, compatOpts CompatOpts) (Plugin, error) {
-	m.log.Debugf("Fetching metadata for plugin \"%s\" from repo %s", pluginID, m.baseURL)
-
-	u, err := url.Parse(m.baseURL)
-	if err != nil {
-		return Plugin{}, err
+// pluginMetadata looks up the plugin in repositories in order. A failing
+// repository is not skipped, otherwise a plugin with the same ID could be
+// installed from the next repository.
+func (m *Manager) pluginMetadata(ctx context.Context, pluginID string, compatOpts CompatOpts) (Plugin, repository, error) {
+	names := make([]string, 0, len(m.repositories))
+	for _, r := range m.repositories {
+		m.log.Debugf("Fetching metadata for plugin \"%s\" from repo %s", pluginID, r.name())
+		plugin, found, err := r.plugin(ctx, pluginID, compatOpts)
+		if err != nil {
+			m.log.Error("Failed to fetch plugin metadata", "repository", r.name(), "err", err)
+			return Plugin{}, nil, err
+		}
+		if found {
+			return plugin, r, nil
+		}
+		names = append(names, r.name())
 	}
-	u.Path = path.Join(u.Path, "repo", pluginID)
+	return Plugin{}, nil, ErrPluginNotFound{PluginID: pluginID, Repositories: names}
+}
 
-	body, err := m.client.sendReq(u, compatOpts)
+// clientForURL returns the client of the repository the URL belongs to, so
+// that archives of private repositories are downloaded with credentials.
+func (m *Manager) clientForURL(pluginZipURL string) *Client {
+	u, err := url.Parse(pluginZipURL)
 	if err != nil {
-		return Plugin{}, err
+		return m.client
 	}
-
-	var data Plugin
-	err = json.Unmarshal(body, &data)
-	if err != nil {
-		m.log.Error("Failed to unmarshal plugin repo response error", err)
-		return Plugin{}, err
+	for _, r := range m.repositories {
+		c := r.client()
+		if c.auth != nil && c.auth.host == u.Host {
+			return c
+		}
 	}
-
-	return data, nil
+	return m.client
 }
 
 // selectVersion selects the most appropriate plugin version
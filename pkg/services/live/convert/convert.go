@@ -5,12 +5,26 @@ import (
 	"fmt"
 
 	"github.com/grafana/grafana/pkg/services/live/telemetry"
+	"github.com/grafana/grafana/pkg/services/live/telemetry/otlp"
+	"github.com/grafana/grafana/pkg/services/live/telemetry/prometheus"
 	"github.com/grafana/grafana/pkg/services/live/telemetry/telegraf"
 )
 
+const (
+	// InputFormatInflux is Influx line protocol, e.g. sent by Telegraf.
+	InputFormatInflux = "influx"
+	// InputFormatPrometheus is Prometheus text exposition format.
+	InputFormatPrometheus = "prometheus"
+	// InputFormatOTLP is an OTLP/HTTP metrics export request encoded in protobuf or JSON.
+	InputFormatOTLP = "otlp"
+)
+
 type Converter struct {
 	telegrafConverterWide         *telegraf.Converter
 	telegrafConverterLabelsColumn *telegraf.Converter
+
+	// converters of other input formats by input format and frame format.
+	converters map[string]map[string]telemetry.Converter
 }
 
 func NewConverter() *Converter {
@@ -22,11 +36,23 @@ func NewConverter() *Converter {
 			telegraf.WithUseLabelsColumn(true),
 			telegraf.WithFloat64Numbers(true),
 		),
+		converters: map[string]map[string]telemetry.Converter{
+			InputFormatPrometheus: {
+				"wide":          prometheus.NewConverter(telegraf.WithFloat64Numbers(true)),
+				"labels_column": prometheus.NewConverter(telegraf.WithUseLabelsColumn(true), telegraf.WithFloat64Numbers(true)),
+			},
+			InputFormatOTLP: {
+				"wide":          otlp.NewConverter(telegraf.WithFloat64Numbers(true)),
+				"labels_column": otlp.NewConverter(telegraf.WithUseLabelsColumn(true), telegraf.WithFloat64Numbers(true)),
+			},
+		},
 	}
 }
 
 var ErrUnsupportedFrameFormat = errors.New("unsupported frame format")
+var ErrUnsupportedInputFormat = errors.New("unsupported input format")
 
+// Convert converts data in Influx line protocol.
 func (c *Converter) Convert(data []byte, frameFormat string) ([]telemetry.FrameWrapper, error) {
 	var converter telemetry.Converter
 	switch frameFormat {
@@ -44,3 +70,32 @@ func (c *Converter) Convert(data []byte, frameFormat string) ([]telemetry.FrameW
 	}
 	return metricFrames, nil
 }
+
+// ConvertInput converts data in the given input format. The content type of the
+// request, if known, tells converters of formats with several encodings how data
+// is encoded.
+func (c *Converter) ConvertInput(data []byte, inputFormat string, frameFormat string, contentType string) ([]telemetry.FrameWrapper, error) {
+	if inputFormat == InputFormatInflux {
+		return c.Convert(data, frameFormat)
+	}
+	converters, ok := c.converters[inputFormat]
+	if !ok {
+		return nil, ErrUnsupportedInputFormat
+	}
+	converter, ok := converters[frameFormat]
+	if !ok {
+		return nil, ErrUnsupportedFrameFormat
+	}
+
+	var metricFrames []telemetry.FrameWrapper
+	var err error
+	if ctConverter, ok := converter.(telemetry.ContentTypeConverter); ok && contentType != "" {
+		metricFrames, err = ctConverter.ConvertContentType(data, contentType)
+	} else {
+		metricFrames, err = converter.Convert(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error converting metrics: %w", err)
+	}
+	return metricFrames, nil
+}
@@ -158,6 +158,10 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 
 	channelLocalPublisher := liveplugin.NewChannelLocalPublisher(node, nil)
 
+	historyConfig := managedstream.HistoryConfig{
+		MaxFrames: cfg.LiveManagedStreamHistorySize,
+		MaxAge:    cfg.LiveManagedStreamHistoryMaxAge,
+	}
 	var managedStreamRunner *managedstream.Runner
 	if g.IsHA() {
 		redisClient := redis.NewClient(&redis.Options{
@@ -170,13 +174,13 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 		managedStreamRunner = managedstream.NewRunner(
 			g.Publish,
 			channelLocalPublisher,
-			managedstream.NewRedisFrameCache(redisClient),
+			managedstream.NewRedisFrameCache(redisClient, historyConfig),
 		)
 	} else {
 		managedStreamRunner = managedstream.NewRunner(
 			g.Publish,
 			channelLocalPublisher,
-			managedstream.NewMemoryFrameCache(),
+			managedstream.NewMemoryFrameCache(historyConfig),
 		)
 	}
 
@@ -191,21 +195,30 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 				ChannelHandlerGetter: g,
 			}
 		} else {
-			storage := &pipeline.FileStorage{
+			var storage pipeline.Storage = &pipeline.FileStorage{
 				DataPath:       cfg.DataPath,
 				SecretsService: g.SecretsService,
 			}
+			if cfg.LivePipelineStorage == "database" {
+				storage = &pipeline.SQLStorage{
+					SQLStore:       sqlStore,
+					SecretsService: g.SecretsService,
+				}
+			}
 			g.pipelineStorage = storage
+			g.pipelineAggregationStorage = pipeline.NewAggregationStorage()
 			builder = &pipeline.StorageRuleBuilder{
 				Node:                 node,
 				ManagedStream:        g.ManagedStreamRunner,
 				FrameStorage:         pipeline.NewFrameStorage(),
+				AggregationStorage:   g.pipelineAggregationStorage,
 				Storage:              storage,
 				ChannelHandlerGetter: g,
 				SecretsService:       g.SecretsService,
 			}
 		}
-		channelRuleGetter := pipeline.NewCacheSegmentedTree(builder)
+		g.pipelineSources = pipeline.NewSourceRunner(builder)
+		channelRuleGetter := pipeline.NewCacheSegmentedTree(g.pipelineSources)
 
 		// Pre-build/validate channel rules for all organizations on start.
 		// This can be unreasonable to have in production scenario with many
@@ -428,6 +441,9 @@ type GrafanaLive struct {
 	ManagedStreamRunner *managedstream.Runner
 	Pipeline            *pipeline.Pipeline
 	pipelineStorage     pipeline.Storage
+	pipelineSources     *pipeline.SourceRunner
+
+	pipelineAggregationStorage *pipeline.AggregationStorage
 
 	contextGetter    *liveplugin.ContextGetter
 	runStreamManager *runstream.Manager
@@ -472,6 +488,18 @@ func (g *GrafanaLive) Run(ctx context.Context) error {
 		})
 	}
 
+	if g.pipelineSources != nil && g.Pipeline != nil {
+		eGroup.Go(func() error {
+			return g.pipelineSources.Run(eCtx, g.Pipeline)
+		})
+	}
+
+	if g.pipelineAggregationStorage != nil && g.Pipeline != nil {
+		eGroup.Go(func() error {
+			return g.pipelineAggregationStorage.Run(eCtx, g.Pipeline)
+		})
+	}
+
 	return eGroup.Wait()
 }
 
@@ -1066,8 +1094,12 @@ func (g *GrafanaLive) HandleChannelRulesListHTTP(c *models.ReqContext) response.
 	if err != nil {
 		return response.Error(http.StatusInternalServerError, "Failed to get channel rules", err)
 	}
+	rules := make([]pipeline.ChannelRule, 0, len(result))
+	for _, r := range result {
+		rules = append(rules, pipeline.ChannelRuleToDto(r))
+	}
 	return response.JSON(http.StatusOK, util.DynMap{
-		"rules": result,
+		"rules": rules,
 	})
 }
 
@@ -1182,7 +1214,7 @@ func (g *GrafanaLive) HandleChannelRulesPostHTTP(c *models.ReqContext) response.
 		return response.Error(http.StatusInternalServerError, "Failed to create channel rule", err)
 	}
 	return response.JSON(http.StatusOK, util.DynMap{
-		"rule": rule,
+		"rule": pipeline.ChannelRuleToDto(rule),
 	})
 }
 
@@ -1205,7 +1237,7 @@ func (g *GrafanaLive) HandleChannelRulesPutHTTP(c *models.ReqContext) response.R
 		return response.Error(http.StatusInternalServerError, "Failed to update channel rule", err)
 	}
 	return response.JSON(http.StatusOK, util.DynMap{
-		"rule": rule,
+		"rule": pipeline.ChannelRuleToDto(rule),
 	})
 }
 
@@ -1238,6 +1270,7 @@ func (g *GrafanaLive) HandlePipelineEntitiesListHTTP(_ *models.ReqContext) respo
 		"converters":      pipeline.ConvertersRegistry,
 		"frameProcessors": pipeline.FrameProcessorsRegistry,
 		"frameOutputs":    pipeline.FrameOutputsRegistry,
+		"sources":         pipeline.SourcesRegistry,
 	})
 }
 
@@ -3,6 +3,7 @@ package managedstream
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 )
@@ -13,6 +14,78 @@ type FrameCache interface {
 	GetActiveChannels(orgID int64) (map[string]json.RawMessage, error)
 	// GetFrame returns full JSON frame for a channel in org.
 	GetFrame(ctx context.Context, orgID int64, channel string) (json.RawMessage, bool, error)
+	// GetHistory returns full JSON frames kept for a channel in org, oldest first.
+	// Returns no frames if history is disabled.
+	GetHistory(ctx context.Context, orgID int64, channel string) ([]json.RawMessage, error)
 	// Update updates frame cache and returns true if schema changed.
 	Update(ctx context.Context, orgID int64, channel string, frameJson data.FrameJSONCache) (bool, error)
 }
+
+// historyEntry is a frame kept in history.
+type historyEntry struct {
+	// Time is a Unix time in milliseconds when the frame was pushed.
+	Time  int64           `json:"time"`
+	Frame json.RawMessage `json:"frame"`
+	// ID makes entries of equal frames pushed at the same time distinct
+	// members of the sorted set that keeps history in Redis.
+	ID string `json:"id,omitempty"`
+}
+
+// HistoryConfig bounds the history of frames kept per channel, the history is
+// replayed to new subscribers. History is enabled if any of the limits is set.
+type HistoryConfig struct {
+	// MaxFrames is a maximum number of frames kept per channel, zero means
+	// historyFramesLimit if MaxAge is set.
+	MaxFrames int
+	// MaxAge is a maximum age of kept frames, zero means no age limit.
+	MaxAge time.Duration
+}
+
+// historyFramesLimit bounds the number of frames kept per channel when history
+// is only limited by age.
+const historyFramesLimit = 10000
+
+// Enabled returns true if frames should be kept in history.
+func (c HistoryConfig) Enabled() bool {
+	return c.MaxFrames > 0 || c.MaxAge > 0
+}
+
+// maxFrames returns the maximum number of frames kept per channel.
+func (c HistoryConfig) maxFrames() int {
+	if c.MaxFrames > 0 {
+		return c.MaxFrames
+	}
+	return historyFramesLimit
+}
+
+// expired returns true if entry is older than MaxAge at the given time.
+func (c HistoryConfig) expired(e historyEntry, now time.Time) bool {
+	return c.MaxAge > 0 && e.Time < c.minTime(now)
+}
+
+// minTime returns the Unix time in milliseconds of the oldest entry which is
+// not expired at the given time.
+func (c HistoryConfig) minTime(now time.Time) int64 {
+	return now.Add(-c.MaxAge).UnixMilli()
+}
+
+// frameHistory keeps history entries of a channel, oldest first.
+type frameHistory struct {
+	entries []historyEntry
+}
+
+// push adds an entry and drops the oldest entries which are expired or exceed
+// the maximum number of frames.
+func (h *frameHistory) push(e historyEntry, config HistoryConfig) {
+	h.entries = append(h.entries, e)
+	drop := len(h.entries) - config.maxFrames()
+	if drop < 0 {
+		drop = 0
+	}
+	now := time.UnixMilli(e.Time)
+	for drop < len(h.entries) && config.expired(h.entries[drop], now) {
+		drop++
+	}
+	// The dropped entries are released when append reallocates the slice.
+	h.entries = h.entries[drop:]
+}
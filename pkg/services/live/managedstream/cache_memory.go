@@ -4,20 +4,25 @@ import (
 	"context"
 	"encoding/json"
 	"sync"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 )
 
 // MemoryFrameCache ...
 type MemoryFrameCache struct {
-	mu     sync.RWMutex
-	frames map[int64]map[string]data.FrameJSONCache
+	mu            sync.RWMutex
+	frames        map[int64]map[string]data.FrameJSONCache
+	historyConfig HistoryConfig
+	history       map[int64]map[string]*frameHistory
 }
 
 // NewMemoryFrameCache ...
-func NewMemoryFrameCache() *MemoryFrameCache {
+func NewMemoryFrameCache(historyConfig HistoryConfig) *MemoryFrameCache {
 	return &MemoryFrameCache{
-		frames: map[int64]map[string]data.FrameJSONCache{},
+		frames:        map[int64]map[string]data.FrameJSONCache{},
+		historyConfig: historyConfig,
+		history:       map[int64]map[string]*frameHistory{},
 	}
 }
 
@@ -42,6 +47,24 @@ func (c *MemoryFrameCache) GetFrame(ctx context.Context, orgID int64, channel st
 	return cachedFrame.Bytes(data.IncludeAll), ok, nil
 }
 
+func (c *MemoryFrameCache) GetHistory(_ context.Context, orgID int64, channel string) ([]json.RawMessage, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	h, ok := c.history[orgID][channel]
+	if !ok {
+		return nil, nil
+	}
+	now := time.Now()
+	var frames []json.RawMessage
+	for _, e := range h.entries {
+		if c.historyConfig.expired(e, now) {
+			continue
+		}
+		frames = append(frames, e.Frame)
+	}
+	return frames, nil
+}
+
 func (c *MemoryFrameCache) Update(ctx context.Context, orgID int64, channel string, jsonFrame data.FrameJSONCache) (bool, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -51,5 +74,16 @@ func (c *MemoryFrameCache) Update(ctx context.Context, orgID int64, channel stri
 	cachedJsonFrame, exists := c.frames[orgID][channel]
 	schemaUpdated := !exists || !cachedJsonFrame.SameSchema(&jsonFrame)
 	c.frames[orgID][channel] = jsonFrame
+	if c.historyConfig.Enabled() {
+		if _, ok := c.history[orgID]; !ok {
+			c.history[orgID] = map[string]*frameHistory{}
+		}
+		h, ok := c.history[orgID][channel]
+		if !ok {
+			h = &frameHistory{}
+			c.history[orgID][channel] = h
+		}
+		h.push(historyEntry{Time: time.Now().UnixMilli(), Frame: jsonFrame.Bytes(data.IncludeAll)}, c.historyConfig)
+	}
 	return schemaUpdated, nil
 }
@@ -3,7 +3,9 @@ package managedstream
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
@@ -63,8 +65,102 @@ func testFrameCache(t *testing.T, c FrameCache) {
 	require.NotEqual(t, string(channels["test"]), string(schema))
 }
 
+func testFrameCacheHistory(t *testing.T, c FrameCache) {
+	// Push more frames than history size to make sure only the latest frames are kept.
+	for i := 0; i < 5; i++ {
+		frame := data.NewFrame(fmt.Sprintf("frame%d", i), data.NewField("value", nil, []int64{int64(i)}))
+		frameJsonCache, err := data.FrameToJSONCache(frame)
+		require.NoError(t, err)
+		_, err = c.Update(context.Background(), 1, "history", frameJsonCache)
+		require.NoError(t, err)
+	}
+
+	history, err := c.GetHistory(context.Background(), 1, "history")
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	for i, frameJSON := range history {
+		var f data.Frame
+		require.NoError(t, json.Unmarshal(frameJSON, &f))
+		require.Equal(t, fmt.Sprintf("frame%d", i+2), f.Name)
+	}
+
+	// Other orgs and channels have their own history.
+	history, err = c.GetHistory(context.Background(), 2, "history")
+	require.NoError(t, err)
+	require.Empty(t, history)
+}
+
 func TestMemoryFrameCache(t *testing.T) {
-	c := NewMemoryFrameCache()
+	c := NewMemoryFrameCache(HistoryConfig{})
 	require.NotNil(t, c)
 	testFrameCache(t, c)
 }
+
+func TestMemoryFrameCache_History(t *testing.T) {
+	c := NewMemoryFrameCache(HistoryConfig{MaxFrames: 3})
+	testFrameCacheHistory(t, c)
+
+	// Disabled history keeps only the last frame.
+	c = NewMemoryFrameCache(HistoryConfig{})
+	frameJsonCache, err := data.FrameToJSONCache(data.NewFrame("hello"))
+	require.NoError(t, err)
+	_, err = c.Update(context.Background(), 1, "test", frameJsonCache)
+	require.NoError(t, err)
+	history, err := c.GetHistory(context.Background(), 1, "test")
+	require.NoError(t, err)
+	require.Empty(t, history)
+}
+
+func TestMemoryFrameCache_HistoryMaxAge(t *testing.T) {
+	c := NewMemoryFrameCache(HistoryConfig{MaxFrames: 3, MaxAge: time.Minute})
+	frameJsonCache, err := data.FrameToJSONCache(data.NewFrame("hello"))
+	require.NoError(t, err)
+	_, err = c.Update(context.Background(), 1, "test", frameJsonCache)
+	require.NoError(t, err)
+	_, err = c.Update(context.Background(), 1, "test", frameJsonCache)
+	require.NoError(t, err)
+
+	// Make the first frame expired.
+	c.history[1]["test"].entries[0].Time = time.Now().Add(-2 * time.Minute).UnixMilli()
+	history, err := c.GetHistory(context.Background(), 1, "test")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+
+	// Expired frames are dropped when new frames arrive.
+	_, err = c.Update(context.Background(), 1, "test", frameJsonCache)
+	require.NoError(t, err)
+	require.Len(t, c.history[1]["test"].entries, 2)
+}
+
+func TestMemoryFrameCache_HistoryMaxAgeOnly(t *testing.T) {
+	c := NewMemoryFrameCache(HistoryConfig{MaxAge: time.Minute})
+	frameJsonCache, err := data.FrameToJSONCache(data.NewFrame("hello"))
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		_, err = c.Update(context.Background(), 1, "test", frameJsonCache)
+		require.NoError(t, err)
+	}
+	history, err := c.GetHistory(context.Background(), 1, "test")
+	require.NoError(t, err)
+	require.Len(t, history, 5)
+
+	c.history[1]["test"].entries[0].Time = time.Now().Add(-2 * time.Minute).UnixMilli()
+	history, err = c.GetHistory(context.Background(), 1, "test")
+	require.NoError(t, err)
+	require.Len(t, history, 4)
+}
+
+func TestFrameHistory_Push(t *testing.T) {
+	base := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	h := &frameHistory{}
+	config := HistoryConfig{MaxFrames: 3, MaxAge: 10 * time.Second}
+	for i := 0; i < 5; i++ {
+		h.push(historyEntry{Time: base.Add(time.Duration(i) * time.Second).UnixMilli()}, config)
+	}
+	require.Len(t, h.entries, 3)
+	require.Equal(t, base.Add(2*time.Second).UnixMilli(), h.entries[0].Time)
+
+	h.push(historyEntry{Time: base.Add(13500 * time.Millisecond).UnixMilli()}, config)
+	require.Len(t, h.entries, 2)
+	require.Equal(t, base.Add(4*time.Second).UnixMilli(), h.entries[0].Time)
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/grafana/grafana/pkg/services/live/orgchannel"
+	"github.com/grafana/grafana/pkg/util"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
@@ -15,16 +17,18 @@ import (
 
 // RedisFrameCache ...
 type RedisFrameCache struct {
-	mu          sync.RWMutex
-	redisClient *redis.Client
-	frames      map[int64]map[string]data.FrameJSONCache
+	mu            sync.RWMutex
+	redisClient   *redis.Client
+	frames        map[int64]map[string]data.FrameJSONCache
+	historyConfig HistoryConfig
 }
 
 // NewRedisFrameCache ...
-func NewRedisFrameCache(redisClient *redis.Client) *RedisFrameCache {
+func NewRedisFrameCache(redisClient *redis.Client, historyConfig HistoryConfig) *RedisFrameCache {
 	return &RedisFrameCache{
-		frames:      map[int64]map[string]data.FrameJSONCache{},
-		redisClient: redisClient,
+		frames:        map[int64]map[string]data.FrameJSONCache{},
+		redisClient:   redisClient,
+		historyConfig: historyConfig,
 	}
 }
 
@@ -55,6 +59,30 @@ func (c *RedisFrameCache) GetFrame(ctx context.Context, orgID int64, channel str
 	return json.RawMessage(result["frame"]), true, nil
 }
 
+func (c *RedisFrameCache) GetHistory(ctx context.Context, orgID int64, channel string) ([]json.RawMessage, error) {
+	if !c.historyConfig.Enabled() {
+		return nil, nil
+	}
+	key := getHistoryKey(orgchannel.PrependOrgID(orgID, channel))
+	min := "-inf"
+	if c.historyConfig.MaxAge > 0 {
+		min = strconv.FormatInt(c.historyConfig.minTime(time.Now()), 10)
+	}
+	result, err := c.redisClient.ZRangeByScore(ctx, key, &redis.ZRangeBy{Min: min, Max: "+inf"}).Result()
+	if err != nil {
+		return nil, err
+	}
+	var frames []json.RawMessage
+	for _, item := range result {
+		var e historyEntry
+		if err := json.Unmarshal([]byte(item), &e); err != nil {
+			return nil, err
+		}
+		frames = append(frames, e.Frame)
+	}
+	return frames, nil
+}
+
 const (
 	frameCacheTTL = 7 * 24 * time.Hour
 )
@@ -81,6 +109,28 @@ func (c *RedisFrameCache) Update(ctx context.Context, orgID int64, channel strin
 	})
 	pipe.Expire(ctx, key, frameCacheTTL)
 
+	if c.historyConfig.Enabled() {
+		// History is a sorted set scored by the push time, so the same entries
+		// as in the memory cache are dropped when new frames arrive.
+		now := time.Now()
+		e := historyEntry{Time: now.UnixMilli(), Frame: jsonFrame.Bytes(data.IncludeAll), ID: util.GenerateShortUID()}
+		entry, err := json.Marshal(e)
+		if err != nil {
+			return false, err
+		}
+		historyKey := getHistoryKey(orgchannel.PrependOrgID(orgID, channel))
+		historyTTL := frameCacheTTL
+		if c.historyConfig.MaxAge > 0 && c.historyConfig.MaxAge < historyTTL {
+			historyTTL = c.historyConfig.MaxAge
+		}
+		pipe.ZAdd(ctx, historyKey, &redis.Z{Score: float64(e.Time), Member: entry})
+		if c.historyConfig.MaxAge > 0 {
+			pipe.ZRemRangeByScore(ctx, historyKey, "-inf", "("+strconv.FormatInt(c.historyConfig.minTime(now), 10))
+		}
+		pipe.ZRemRangeByRank(ctx, historyKey, 0, -int64(c.historyConfig.maxFrames())-1)
+		pipe.Expire(ctx, historyKey, historyTTL)
+	}
+
 	replies, err := pipe.Exec(ctx)
 	if err != nil {
 		return false, err
@@ -110,3 +160,7 @@ func (c *RedisFrameCache) Update(ctx context.Context, orgID int64, channel strin
 func getCacheKey(channelID string) string {
 	return "gf_live.managed_stream." + channelID
 }
+
+func getHistoryKey(channelID string) string {
+	return "gf_live.managed_stream_history." + channelID
+}
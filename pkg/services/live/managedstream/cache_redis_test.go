@@ -4,9 +4,13 @@
 package managedstream
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/go-redis/redis/v8"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
 )
 
@@ -14,7 +18,46 @@ func TestRedisCacheStorage(t *testing.T) {
 	redisClient := redis.NewClient(&redis.Options{
 		Addr: "localhost:6379",
 	})
-	c := NewRedisFrameCache(redisClient)
+	c := NewRedisFrameCache(redisClient, HistoryConfig{})
 	require.NotNil(t, c)
 	testFrameCache(t, c)
 }
+
+func TestRedisCacheStorage_History(t *testing.T) {
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: "localhost:6379",
+	})
+	require.NoError(t, redisClient.Del(context.Background(), getHistoryKey("1/history")).Err())
+	c := NewRedisFrameCache(redisClient, HistoryConfig{MaxFrames: 3})
+	testFrameCacheHistory(t, c)
+}
+
+func TestRedisCacheStorage_HistoryMaxAgeOnly(t *testing.T) {
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: "localhost:6379",
+	})
+	key := getHistoryKey("1/test")
+	require.NoError(t, redisClient.Del(context.Background(), key).Err())
+	c := NewRedisFrameCache(redisClient, HistoryConfig{MaxAge: time.Minute})
+
+	// Add an expired frame.
+	old := historyEntry{Time: time.Now().Add(-2 * time.Minute).UnixMilli(), Frame: json.RawMessage(`{}`), ID: "old"}
+	entry, err := json.Marshal(old)
+	require.NoError(t, err)
+	require.NoError(t, redisClient.ZAdd(context.Background(), key, &redis.Z{Score: float64(old.Time), Member: entry}).Err())
+
+	frameJsonCache, err := data.FrameToJSONCache(data.NewFrame("hello"))
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, err = c.Update(context.Background(), 1, "test", frameJsonCache)
+		require.NoError(t, err)
+	}
+
+	// Expired frames are dropped when new frames arrive, equal frames are all kept.
+	count, err := redisClient.ZCard(context.Background(), key).Result()
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+	history, err := c.GetHistory(context.Background(), 1, "test")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+}
@@ -0,0 +1,52 @@
+package managedstream
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// mergeHistory merges history frames into one frame. Only the latest frames
+// with the same schema as the last frame are merged. Returns false if there are
+// no frames to merge.
+//
+// All subscribers of a channel share one subscription in a browser, so the
+// history is not limited to a time range here. Panels skip rows out of their
+// time range.
+func mergeHistory(history []json.RawMessage) (json.RawMessage, bool, error) {
+	var frames []*data.Frame
+	var schema []byte
+	for i := len(history) - 1; i >= 0; i-- {
+		var frame data.Frame
+		if err := json.Unmarshal(history[i], &frame); err != nil {
+			return nil, false, err
+		}
+		frameSchema, err := data.FrameToJSON(&frame, data.IncludeSchemaOnly)
+		if err != nil {
+			return nil, false, err
+		}
+		if schema == nil {
+			schema = frameSchema
+		} else if !bytes.Equal(schema, frameSchema) {
+			break
+		}
+		frames = append(frames, &frame)
+	}
+	if len(frames) == 0 {
+		return nil, false, nil
+	}
+
+	merged := frames[0].EmptyCopy()
+	for i := len(frames) - 1; i >= 0; i-- {
+		frame := frames[i]
+		for rowIdx := 0; rowIdx < frame.Rows(); rowIdx++ {
+			merged.AppendRow(frame.RowCopy(rowIdx)...)
+		}
+	}
+	frameJSON, err := data.FrameToJSON(merged, data.IncludeAll)
+	if err != nil {
+		return nil, false, err
+	}
+	return frameJSON, true, nil
+}
@@ -241,6 +241,20 @@ func (s *NamespaceStream) GetHandlerForPath(_ string) (models.ChannelHandler, er
 
 func (s *NamespaceStream) OnSubscribe(ctx context.Context, u *user.SignedInUser, e models.SubscribeEvent) (models.SubscribeReply, backend.SubscribeStreamStatus, error) {
 	reply := models.SubscribeReply{}
+	history, err := s.frameCache.GetHistory(ctx, u.OrgID, e.Channel)
+	if err != nil {
+		return reply, 0, err
+	}
+	if len(history) > 0 {
+		frameJSON, ok, err := mergeHistory(history)
+		if err != nil {
+			return reply, 0, err
+		}
+		if ok {
+			reply.Data = frameJSON
+		}
+		return reply, backend.SubscribeStreamStatusOK, nil
+	}
 	frameJSON, ok, err := s.frameCache.GetFrame(ctx, u.OrgID, e.Channel)
 	if err != nil {
 		return reply, 0, err
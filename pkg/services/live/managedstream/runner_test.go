@@ -2,11 +2,16 @@ package managedstream
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/user"
 )
 
 type testPublisher struct {
@@ -19,13 +24,13 @@ func (p *testPublisher) publish(_ int64, _ string, _ []byte) error {
 
 func TestNewManagedStream(t *testing.T) {
 	publisher := &testPublisher{t: t}
-	c := NewNamespaceStream(1, "stream", "a", publisher.publish, nil, NewMemoryFrameCache())
+	c := NewNamespaceStream(1, "stream", "a", publisher.publish, nil, NewMemoryFrameCache(HistoryConfig{}))
 	require.NotNil(t, c)
 }
 
 func TestManagedStreamMinuteRate(t *testing.T) {
 	publisher := &testPublisher{t: t}
-	c := NewNamespaceStream(1, "stream", "a", publisher.publish, nil, NewMemoryFrameCache())
+	c := NewNamespaceStream(1, "stream", "a", publisher.publish, nil, NewMemoryFrameCache(HistoryConfig{}))
 	require.NotNil(t, c)
 
 	c.incRate("test1", time.Now().Unix())
@@ -47,7 +52,7 @@ func TestManagedStreamMinuteRate(t *testing.T) {
 
 func TestGetManagedStreams(t *testing.T) {
 	publisher := &testPublisher{t: t}
-	frameCache := NewMemoryFrameCache()
+	frameCache := NewMemoryFrameCache(HistoryConfig{})
 	runner := NewRunner(publisher.publish, nil, frameCache)
 	s1, err := runner.GetOrCreateStream(1, "stream", "test1")
 	require.NoError(t, err)
@@ -83,3 +88,42 @@ func TestGetManagedStreams(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, managedChannels, 7) // Not affected by other org.
 }
+
+func TestNamespaceStream_OnSubscribeHistory(t *testing.T) {
+	publisher := &testPublisher{t: t}
+	base := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	s := NewNamespaceStream(1, "stream", "sensors", publisher.publish, nil, NewMemoryFrameCache(HistoryConfig{MaxFrames: 10}))
+	u := &user.SignedInUser{OrgID: 1}
+
+	push := func(frame *data.Frame) {
+		require.NoError(t, s.Push(context.Background(), "room", frame))
+	}
+	subscribe := func(subscribeData string) *data.Frame {
+		reply, _, err := s.OnSubscribe(context.Background(), u, models.SubscribeEvent{
+			Channel: "stream/sensors/room",
+			Path:    "room",
+			Data:    json.RawMessage(subscribeData),
+		})
+		require.NoError(t, err)
+		var frame data.Frame
+		require.NoError(t, json.Unmarshal(reply.Data, &frame))
+		return &frame
+	}
+
+	// Frames with an outdated schema are not replayed.
+	push(data.NewFrame("room", data.NewField("time", nil, []time.Time{base}), data.NewField("old", nil, []float64{0})))
+	for i := 1; i <= 3; i++ {
+		push(data.NewFrame("room",
+			data.NewField("time", nil, []time.Time{base.Add(time.Duration(i) * time.Second)}),
+			data.NewField("value", nil, []float64{float64(i)}),
+		))
+	}
+
+	// The whole history is replayed, panels skip rows out of their time range.
+	from := base.Add(2 * time.Second).UnixMilli()
+	frame := subscribe(fmt.Sprintf(`{"from": %d}`, from))
+	require.Equal(t, 3, frame.Rows())
+	require.Equal(t, "value", frame.Fields[1].Name)
+	require.Equal(t, 1.0, frame.Fields[1].At(0))
+	require.Equal(t, 3.0, frame.Fields[1].At(2))
+}
@@ -25,6 +25,7 @@ type ChannelRuleSettings struct {
 	Converter       *ConverterConfig        `json:"converter,omitempty"`
 	FrameProcessors []*FrameProcessorConfig `json:"frameProcessors,omitempty"`
 	FrameOutputters []*FrameOutputterConfig `json:"frameOutputs,omitempty"`
+	Sources         []*SourceConfig         `json:"sources,omitempty"`
 }
 
 type ChannelRule struct {
@@ -34,11 +35,13 @@ type ChannelRule struct {
 }
 
 type ConverterConfig struct {
-	Type                      string                     `json:"type" ts_type:"Omit<keyof ConverterConfig, 'type'>"`
-	AutoJsonConverterConfig   *AutoJsonConverterConfig   `json:"jsonAuto,omitempty"`
-	ExactJsonConverterConfig  *ExactJsonConverterConfig  `json:"jsonExact,omitempty"`
-	AutoInfluxConverterConfig *AutoInfluxConverterConfig `json:"influxAuto,omitempty"`
-	JsonFrameConverterConfig  *JsonFrameConverterConfig  `json:"jsonFrame,omitempty"`
+	Type                          string                         `json:"type" ts_type:"Omit<keyof ConverterConfig, 'type'>"`
+	AutoJsonConverterConfig       *AutoJsonConverterConfig       `json:"jsonAuto,omitempty"`
+	ExactJsonConverterConfig      *ExactJsonConverterConfig      `json:"jsonExact,omitempty"`
+	AutoInfluxConverterConfig     *AutoInfluxConverterConfig     `json:"influxAuto,omitempty"`
+	JsonFrameConverterConfig      *JsonFrameConverterConfig      `json:"jsonFrame,omitempty"`
+	AutoPrometheusConverterConfig *AutoPrometheusConverterConfig `json:"prometheusAuto,omitempty"`
+	AutoOTLPConverterConfig       *AutoOTLPConverterConfig       `json:"otlpAuto,omitempty"`
 }
 
 type DropFieldsFrameProcessorConfig struct {
@@ -49,11 +52,40 @@ type KeepFieldsFrameProcessorConfig struct {
 	FieldNames []string `json:"fieldNames"`
 }
 
+// AggregateFrameProcessorConfig configures downsampling of frames to time windows.
+type AggregateFrameProcessorConfig struct {
+	// IntervalMilliseconds is the length of a time window.
+	IntervalMilliseconds int64 `json:"intervalMilliseconds"`
+	// Functions to apply to numeric fields: mean, min, max, last or count.
+	Functions []string `json:"functions"`
+	// GroupBy is a list of fields, e.g. a labels field, whose values identify a group.
+	GroupBy []string `json:"groupBy,omitempty"`
+	// TimeField is a name of the time field. The first time field is used by default.
+	TimeField string `json:"timeField,omitempty"`
+}
+
+// ExpressionField is a field computed by a JavaScript expression. Values of
+// the row are available in the expression as x.<field name>, time values as
+// Unix time in milliseconds. Labels of frame fields are available as labels.
+type ExpressionField struct {
+	Name       string            `json:"name"`
+	Type       data.FieldType    `json:"type,omitempty" ts_type:"string"` // float64, string or bool. Default is float64.
+	Expression string            `json:"expression"`
+	Config     *data.FieldConfig `json:"config,omitempty" ts_type:"FieldConfig"`
+}
+
+// ExpressionFrameProcessorConfig configures fields to add or overwrite, in order.
+type ExpressionFrameProcessorConfig struct {
+	Fields []ExpressionField `json:"fields"`
+}
+
 type FrameProcessorConfig struct {
 	Type                      string                          `json:"type" ts_type:"Omit<keyof FrameProcessorConfig, 'type'>"`
 	DropFieldsProcessorConfig *DropFieldsFrameProcessorConfig `json:"dropFields,omitempty"`
 	KeepFieldsProcessorConfig *KeepFieldsFrameProcessorConfig `json:"keepFields,omitempty"`
 	MultipleProcessorConfig   *MultipleFrameProcessorConfig   `json:"multiple,omitempty"`
+	AggregateProcessorConfig  *AggregateFrameProcessorConfig  `json:"aggregate,omitempty"`
+	ExpressionProcessorConfig *ExpressionFrameProcessorConfig `json:"expression,omitempty"`
 }
 
 type MultipleFrameProcessorConfig struct {
@@ -87,6 +119,55 @@ type SubscriberConfig struct {
 	MultipleSubscriberConfig *MultipleSubscriberConfig `json:"multiple,omitempty"`
 }
 
+// SourceTLSConfig enables TLS for connections to brokers of a source.
+type SourceTLSConfig struct {
+	// CACert is a PEM encoded certificate of the CA which signed the broker
+	// certificate. System certificates are used when empty.
+	CACert string `json:"caCert,omitempty"`
+	// ServerName to verify the broker certificate against, the broker host by default.
+	ServerName string `json:"serverName,omitempty"`
+	// InsecureSkipVerify disables verification of the broker certificate.
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// MQTTSourceConfig configures a subscription to an MQTT topic.
+type MQTTSourceConfig struct {
+	// URL of the broker, e.g. tcp://localhost:1883, or ssl://localhost:8883 for TLS.
+	URL string `json:"url"`
+	// TLS settings for ssl://, tls://, mqtts:// and wss:// broker URLs.
+	TLS *SourceTLSConfig `json:"tls,omitempty"`
+	// Topic to subscribe to, may contain MQTT wildcards.
+	Topic string `json:"topic"`
+	// QoS of the subscription: 0, 1 or 2.
+	QoS byte `json:"qos,omitempty"`
+	// ClientID is generated when empty. Must be unique per broker.
+	ClientID string `json:"clientId,omitempty"`
+}
+
+// KafkaSourceConfig configures consuming of a Kafka topic.
+type KafkaSourceConfig struct {
+	// Brokers to connect to, e.g. localhost:9092.
+	Brokers []string `json:"brokers"`
+	// TLS enables TLS for connections to the brokers.
+	TLS *SourceTLSConfig `json:"tls,omitempty"`
+	// Topic to consume.
+	Topic string `json:"topic"`
+	// GroupID of the consumer group used to commit offsets. Grafana instances
+	// with the same group split the partitions of the topic between them.
+	GroupID string `json:"groupId"`
+}
+
+type SourceConfig struct {
+	Type string `json:"type" ts_type:"Omit<keyof SourceConfig, 'type'>"`
+	// BasicAuth is used as MQTT username and password, or as Kafka SASL PLAIN
+	// credentials. Credentials are only sent over TLS. The password is encrypted
+	// into secure settings when the rule is saved.
+	BasicAuth         *BasicAuth         `json:"basicAuth,omitempty"`
+	SecureSettings    map[string][]byte  `json:"secureSettings,omitempty" ts_type:"{[key: string]: string}"`
+	MQTTSourceConfig  *MQTTSourceConfig  `json:"mqtt,omitempty"`
+	KafkaSourceConfig *KafkaSourceConfig `json:"kafka,omitempty"`
+}
+
 // RedirectDataOutputConfig ...
 type RedirectDataOutputConfig struct {
 	Channel string `json:"channel"`
@@ -151,4 +232,14 @@ type AutoInfluxConverterConfig struct {
 
 type JsonFrameConverterConfig struct{}
 
+// AutoPrometheusConverterConfig configures conversion of Prometheus text exposition format.
+type AutoPrometheusConverterConfig struct {
+	FrameFormat string `json:"frameFormat"`
+}
+
+// AutoOTLPConverterConfig configures conversion of OTLP/HTTP metrics.
+type AutoOTLPConverterConfig struct {
+	FrameFormat string `json:"frameFormat"`
+}
+
 type ManagedStreamOutputConfig struct{}
@@ -0,0 +1,399 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/live/orgchannel"
+)
+
+const (
+	AggregateFunctionMean  = "mean"
+	AggregateFunctionMin   = "min"
+	AggregateFunctionMax   = "max"
+	AggregateFunctionLast  = "last"
+	AggregateFunctionCount = "count"
+)
+
+// AggregateFrameProcessor buffers frames of a channel and emits a downsampled frame
+// for every completed time window. Numeric fields are reduced with the configured
+// functions, rows are grouped by the values of the configured group by fields.
+//
+// A window is completed when a frame with a newer time window arrives or when the
+// channel receives no frames for one interval, until then processing of the frame
+// stops at this processor, i.e. frame outputs of the rule are only called with
+// aggregated frames.
+//
+// To keep raw frames as well, e.g. to write them to a remote storage, the rule of
+// the raw channel can output to remoteWrite and redirect to a second channel which
+// has this processor:
+//
+//	stream/sensors/raw:        frameOutputs: remoteWrite, redirect to stream/sensors/aggregated
+//	stream/sensors/aggregated: frameProcessors: aggregate, frameOutputs: managedStream
+type AggregateFrameProcessor struct {
+	storage  *AggregationStorage
+	config   AggregateFrameProcessorConfig
+	interval time.Duration
+}
+
+// NewAggregateFrameProcessor creates new AggregateFrameProcessor. The state of open
+// windows is kept in the storage, so it survives rebuilding of the channel rules.
+func NewAggregateFrameProcessor(storage *AggregationStorage, config AggregateFrameProcessorConfig) (*AggregateFrameProcessor, error) {
+	if config.IntervalMilliseconds <= 0 {
+		return nil, errors.New("aggregation interval must be positive")
+	}
+	if len(config.Functions) == 0 {
+		return nil, errors.New("at least one aggregation function is required")
+	}
+	for _, fn := range config.Functions {
+		switch fn {
+		case AggregateFunctionMean, AggregateFunctionMin, AggregateFunctionMax, AggregateFunctionLast, AggregateFunctionCount:
+		default:
+			return nil, fmt.Errorf("unknown aggregation function: %s", fn)
+		}
+	}
+	return &AggregateFrameProcessor{
+		storage:  storage,
+		config:   config,
+		interval: time.Duration(config.IntervalMilliseconds) * time.Millisecond,
+	}, nil
+}
+
+const FrameProcessorTypeAggregate = "aggregate"
+
+func (p *AggregateFrameProcessor) Type() string {
+	return FrameProcessorTypeAggregate
+}
+
+func (p *AggregateFrameProcessor) ProcessFrame(_ context.Context, vars Vars, frame *data.Frame) (*data.Frame, error) {
+	timeField := p.timeField(frame)
+	groupByFields := make([]*data.Field, len(p.config.GroupBy))
+	for i, name := range p.config.GroupBy {
+		if field, idx := frame.FieldByName(name); idx >= 0 {
+			groupByFields[i] = field
+		}
+	}
+	var valueFields []*data.Field
+	for _, field := range frame.Fields {
+		if field == timeField || stringInSlice(field.Name, p.config.GroupBy) || !field.Type().Numeric() {
+			continue
+		}
+		valueFields = append(valueFields, field)
+	}
+
+	closed := p.storage.update(p, vars.OrgID, vars.Channel, frame.Name, func(w *aggregationWindow) (*aggregationWindow, []*aggregationWindow) {
+		var closed []*aggregationWindow
+		now := time.Now()
+		for row := 0; row < frame.Rows(); row++ {
+			t := now
+			if timeField != nil {
+				if v, ok := timeField.ConcreteAt(row); ok {
+					t = v.(time.Time)
+				}
+			}
+			start := t.Truncate(p.interval)
+			if w.start.IsZero() {
+				w.start = start
+			}
+			if start.Before(w.start) {
+				// The window of this row has been already emitted.
+				continue
+			}
+			if start.After(w.start) {
+				closed = append(closed, w)
+				w = newAggregationWindow(p.interval)
+				w.start = start
+			}
+			w.add(groupByFields, valueFields, row)
+		}
+		return w, closed
+	})
+	if len(closed) == 0 {
+		return nil, nil
+	}
+	return p.frame(frame.Name, closed), nil
+}
+
+// timeField returns the configured time field or the first time field of the frame.
+func (p *AggregateFrameProcessor) timeField(frame *data.Frame) *data.Field {
+	for _, field := range frame.Fields {
+		if !field.Type().Time() {
+			continue
+		}
+		if p.config.TimeField == "" || field.Name == p.config.TimeField {
+			return field
+		}
+	}
+	return nil
+}
+
+// frame builds a frame with a row for every group of every closed window. The
+// time of a row is the start of its window.
+func (p *AggregateFrameProcessor) frame(name string, windows []*aggregationWindow) *data.Frame {
+	var seriesOrder []string
+	seriesInfo := map[string]*aggregationSeries{}
+	rows := 0
+	for _, w := range windows {
+		for _, groupKey := range w.groupOrder {
+			g := w.groups[groupKey]
+			rows++
+			for _, key := range g.seriesOrder {
+				if _, ok := seriesInfo[key]; !ok {
+					seriesOrder = append(seriesOrder, key)
+					seriesInfo[key] = g.series[key]
+				}
+			}
+		}
+	}
+
+	timeField := data.NewField("time", nil, make([]time.Time, rows))
+	groupByFields := make([]*data.Field, len(p.config.GroupBy))
+	for i, groupBy := range p.config.GroupBy {
+		groupByFields[i] = data.NewField(groupBy, nil, make([]*string, rows))
+	}
+	valueFields := make(map[string][]*data.Field, len(seriesOrder))
+	for _, key := range seriesOrder {
+		s := seriesInfo[key]
+		for _, fn := range p.config.Functions {
+			valueFields[key] = append(valueFields[key], data.NewField(s.name+"_"+fn, s.labels.Copy(), make([]*float64, rows)))
+		}
+	}
+
+	row := 0
+	for _, w := range windows {
+		for _, groupKey := range w.groupOrder {
+			g := w.groups[groupKey]
+			timeField.Set(row, w.start)
+			for i, v := range g.values {
+				groupByFields[i].Set(row, v)
+			}
+			for key, s := range g.series {
+				for i, fn := range p.config.Functions {
+					v := s.value(fn)
+					valueFields[key][i].Set(row, &v)
+				}
+			}
+			row++
+		}
+	}
+
+	fields := append([]*data.Field{timeField}, groupByFields...)
+	for _, key := range seriesOrder {
+		fields = append(fields, valueFields[key]...)
+	}
+	return data.NewFrame(name, fields...)
+}
+
+// AggregatedFrameProcessor processes frames emitted by AggregationStorage when
+// a channel stops receiving frames, it's implemented by Pipeline.
+type AggregatedFrameProcessor interface {
+	ProcessAggregatedFrame(ctx context.Context, orgID int64, channelID string, frame *data.Frame) error
+}
+
+const aggregationFlushInterval = time.Second
+
+// AggregationStorage keeps open aggregation windows of channels in memory. Channels
+// are locked separately, so channels don't wait for each other. A channel which has
+// not received frames for its interval is flushed and removed from the storage by
+// Run. Not usable in HA setup.
+type AggregationStorage struct {
+	now func() time.Time
+
+	mu       sync.Mutex
+	channels map[string]*aggregationChannel
+}
+
+func NewAggregationStorage() *AggregationStorage {
+	return &AggregationStorage{
+		now:      time.Now,
+		channels: map[string]*aggregationChannel{},
+	}
+}
+
+type aggregationChannel struct {
+	mu        sync.Mutex
+	orgID     int64
+	channel   string
+	processor *AggregateFrameProcessor
+	frameName string
+	window    *aggregationWindow
+	updated   time.Time
+	evicted   bool
+}
+
+// update calls f with the open window of the channel and stores the window returned
+// by f as the new open window. It returns the windows closed by f in chronological order.
+func (s *AggregationStorage) update(p *AggregateFrameProcessor, orgID int64, channel string, frameName string, f func(w *aggregationWindow) (*aggregationWindow, []*aggregationWindow)) []*aggregationWindow {
+	key := orgchannel.PrependOrgID(orgID, channel)
+	for {
+		s.mu.Lock()
+		c, ok := s.channels[key]
+		if !ok {
+			c = &aggregationChannel{orgID: orgID, channel: channel}
+			s.channels[key] = c
+		}
+		s.mu.Unlock()
+
+		c.mu.Lock()
+		if c.evicted {
+			// Flushed by Run in the meantime, start over with a new channel.
+			c.mu.Unlock()
+			continue
+		}
+		if c.window == nil || c.window.interval != p.interval {
+			// Windows of a different interval can't be continued after the rule has changed.
+			c.window = newAggregationWindow(p.interval)
+		}
+		current, closed := f(c.window)
+		c.window = current
+		c.processor = p
+		c.frameName = frameName
+		c.updated = s.now()
+		c.mu.Unlock()
+		return closed
+	}
+}
+
+// Run flushes open windows of idle channels to the processor until ctx is done.
+func (s *AggregationStorage) Run(ctx context.Context, processor AggregatedFrameProcessor) error {
+	ticker := time.NewTicker(aggregationFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			s.flush(ctx, processor)
+		}
+	}
+}
+
+// flush removes channels which have not received frames for their interval and
+// passes the frames of their open windows to the processor.
+func (s *AggregationStorage) flush(ctx context.Context, processor AggregatedFrameProcessor) {
+	now := s.now()
+	s.mu.Lock()
+	var idle []*aggregationChannel
+	for key, c := range s.channels {
+		c.mu.Lock()
+		if c.window != nil && now.Sub(c.updated) < c.window.interval {
+			c.mu.Unlock()
+			continue
+		}
+		c.evicted = true
+		c.mu.Unlock()
+		delete(s.channels, key)
+		idle = append(idle, c)
+	}
+	s.mu.Unlock()
+
+	for _, c := range idle {
+		// Evicted channels are not updated anymore, so no lock is required.
+		if c.window == nil || c.window.start.IsZero() || c.processor == nil {
+			continue
+		}
+		frame := c.processor.frame(c.frameName, []*aggregationWindow{c.window})
+		if err := processor.ProcessAggregatedFrame(ctx, c.orgID, c.channel, frame); err != nil {
+			logger.Error("Error processing aggregated frame", "error", err, "channel", c.channel)
+		}
+	}
+}
+
+type aggregationWindow struct {
+	interval   time.Duration
+	start      time.Time
+	groups     map[string]*aggregationGroup
+	groupOrder []string
+}
+
+func newAggregationWindow(interval time.Duration) *aggregationWindow {
+	return &aggregationWindow{
+		interval: interval,
+		groups:   map[string]*aggregationGroup{},
+	}
+}
+
+func (w *aggregationWindow) add(groupByFields []*data.Field, valueFields []*data.Field, row int) {
+	values := make([]*string, len(groupByFields))
+	keyParts := make([]string, len(groupByFields))
+	for i, field := range groupByFields {
+		if field == nil {
+			continue
+		}
+		if v, ok := field.ConcreteAt(row); ok {
+			s := fmt.Sprint(v)
+			values[i] = &s
+			keyParts[i] = s
+		}
+	}
+	groupKey := strings.Join(keyParts, "\x00")
+	g, ok := w.groups[groupKey]
+	if !ok {
+		g = &aggregationGroup{values: values, series: map[string]*aggregationSeries{}}
+		w.groups[groupKey] = g
+		w.groupOrder = append(w.groupOrder, groupKey)
+	}
+	for _, field := range valueFields {
+		v, err := field.NullableFloatAt(row)
+		if err != nil || v == nil {
+			continue
+		}
+		g.add(field, *v)
+	}
+}
+
+type aggregationGroup struct {
+	values      []*string
+	series      map[string]*aggregationSeries
+	seriesOrder []string
+}
+
+func (g *aggregationGroup) add(field *data.Field, v float64) {
+	key := field.Name + field.Labels.String()
+	s, ok := g.series[key]
+	if !ok {
+		s = &aggregationSeries{name: field.Name, labels: field.Labels, min: v, max: v}
+		g.series[key] = s
+		g.seriesOrder = append(g.seriesOrder, key)
+	}
+	s.count++
+	s.sum += v
+	if v < s.min {
+		s.min = v
+	}
+	if v > s.max {
+		s.max = v
+	}
+	s.last = v
+}
+
+type aggregationSeries struct {
+	name   string
+	labels data.Labels
+	count  int
+	sum    float64
+	min    float64
+	max    float64
+	last   float64
+}
+
+func (s *aggregationSeries) value(fn string) float64 {
+	switch fn {
+	case AggregateFunctionMean:
+		return s.sum / float64(s.count)
+	case AggregateFunctionMin:
+		return s.min
+	case AggregateFunctionMax:
+		return s.max
+	case AggregateFunctionLast:
+		return s.last
+	default:
+		return float64(s.count)
+	}
+}
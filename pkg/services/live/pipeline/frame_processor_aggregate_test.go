@@ -0,0 +1,197 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAggregateFrameProcessor_Validation(t *testing.T) {
+	storage := NewAggregationStorage()
+	_, err := NewAggregateFrameProcessor(storage, AggregateFrameProcessorConfig{Functions: []string{"mean"}})
+	require.Error(t, err)
+	_, err = NewAggregateFrameProcessor(storage, AggregateFrameProcessorConfig{IntervalMilliseconds: 1000})
+	require.Error(t, err)
+	_, err = NewAggregateFrameProcessor(storage, AggregateFrameProcessorConfig{IntervalMilliseconds: 1000, Functions: []string{"median"}})
+	require.ErrorContains(t, err, "median")
+}
+
+func TestAggregateFrameProcessor_ProcessFrame(t *testing.T) {
+	base := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	at := func(ms int) time.Time { return base.Add(time.Duration(ms) * time.Millisecond) }
+	vars := Vars{OrgID: 1, Channel: "stream/sensors/room"}
+
+	processor, err := NewAggregateFrameProcessor(NewAggregationStorage(), AggregateFrameProcessorConfig{
+		IntervalMilliseconds: 1000,
+		Functions:            []string{AggregateFunctionMean, AggregateFunctionMin, AggregateFunctionMax, AggregateFunctionLast, AggregateFunctionCount},
+		GroupBy:              []string{"labels"},
+	})
+	require.NoError(t, err)
+
+	// First window, processing stops until the window is complete.
+	frame := data.NewFrame("sensors",
+		data.NewField("labels", nil, []string{"room=a", "room=b", "room=a"}),
+		data.NewField("time", nil, []time.Time{at(0), at(100), at(500)}),
+		data.NewField("value", nil, []*float64{fp(1), fp(10), fp(3)}),
+		data.NewField("status", nil, []string{"ok", "ok", "ok"}),
+	)
+	out, err := processor.ProcessFrame(context.Background(), vars, frame)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	// A rebuilt processor continues the window of the channel.
+	processor, err = NewAggregateFrameProcessor(processor.storage, processor.config)
+	require.NoError(t, err)
+
+	// The next window completes the first window.
+	frame = data.NewFrame("sensors",
+		data.NewField("labels", nil, []string{"room=a", "room=a"}),
+		data.NewField("time", nil, []time.Time{at(900), at(1200)}),
+		data.NewField("value", nil, []*float64{fp(5), fp(100)}),
+		data.NewField("status", nil, []string{"ok", "ok"}),
+	)
+	out, err = processor.ProcessFrame(context.Background(), vars, frame)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, "sensors", out.Name)
+	require.Equal(t, 2, out.Rows())
+
+	field := func(name string) *data.Field {
+		f, idx := out.FieldByName(name)
+		require.NotEqual(t, -1, idx, "field %s not found", name)
+		return f
+	}
+	require.Len(t, out.Fields, 7)
+	require.Equal(t, base, field("time").At(0))
+	require.Equal(t, "room=a", *field("labels").At(0).(*string))
+	require.Equal(t, "room=b", *field("labels").At(1).(*string))
+	require.Equal(t, 3.0, *field("value_mean").At(0).(*float64))
+	require.Equal(t, 1.0, *field("value_min").At(0).(*float64))
+	require.Equal(t, 5.0, *field("value_max").At(0).(*float64))
+	require.Equal(t, 5.0, *field("value_last").At(0).(*float64))
+	require.Equal(t, 3.0, *field("value_count").At(0).(*float64))
+	require.Equal(t, 10.0, *field("value_mean").At(1).(*float64))
+	require.Equal(t, 1.0, *field("value_count").At(1).(*float64))
+
+	// Late rows of an emitted window are dropped.
+	frame = data.NewFrame("sensors",
+		data.NewField("labels", nil, []string{"room=a", "room=a"}),
+		data.NewField("time", nil, []time.Time{at(800), at(3100)}),
+		data.NewField("value", nil, []*float64{fp(1000), fp(7)}),
+		data.NewField("status", nil, []string{"ok", "ok"}),
+	)
+	out, err = processor.ProcessFrame(context.Background(), vars, frame)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, 1, out.Rows())
+	require.Equal(t, at(1000), out.Fields[0].At(0))
+	require.Equal(t, 100.0, *out.Fields[2].At(0).(*float64))
+}
+
+func TestAggregateFrameProcessor_ProcessFrame_WideFrames(t *testing.T) {
+	base := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	vars := Vars{OrgID: 1, Channel: "stream/sensors/wide"}
+
+	processor, err := NewAggregateFrameProcessor(NewAggregationStorage(), AggregateFrameProcessorConfig{
+		IntervalMilliseconds: 10000,
+		Functions:            []string{AggregateFunctionMax},
+	})
+	require.NoError(t, err)
+
+	for i, v := range []float64{1, 4, 2} {
+		frame := data.NewFrame("cpu",
+			data.NewField("time", nil, []time.Time{base.Add(time.Duration(i) * time.Second)}),
+			data.NewField("usage", data.Labels{"host": "a"}, []float64{v}),
+			data.NewField("usage", data.Labels{"host": "b"}, []int64{int64(v * 10)}),
+		)
+		out, err := processor.ProcessFrame(context.Background(), vars, frame)
+		require.NoError(t, err)
+		require.Nil(t, out)
+	}
+
+	out, err := processor.ProcessFrame(context.Background(), vars, data.NewFrame("cpu",
+		data.NewField("time", nil, []time.Time{base.Add(10 * time.Second)}),
+		data.NewField("usage", data.Labels{"host": "a"}, []float64{0}),
+	))
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Len(t, out.Fields, 3)
+	require.Equal(t, "usage_max", out.Fields[1].Name)
+	require.Equal(t, data.Labels{"host": "a"}, out.Fields[1].Labels)
+	require.Equal(t, 4.0, *out.Fields[1].At(0).(*float64))
+	require.Equal(t, data.Labels{"host": "b"}, out.Fields[2].Labels)
+	require.Equal(t, 40.0, *out.Fields[2].At(0).(*float64))
+}
+
+type testAggregatedFrameProcessor struct {
+	channels []string
+	frames   []*data.Frame
+}
+
+func (t *testAggregatedFrameProcessor) ProcessAggregatedFrame(_ context.Context, _ int64, channelID string, frame *data.Frame) error {
+	t.channels = append(t.channels, channelID)
+	t.frames = append(t.frames, frame)
+	return nil
+}
+
+func TestAggregationStorage_Flush(t *testing.T) {
+	base := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	now := base
+	storage := NewAggregationStorage()
+	storage.now = func() time.Time { return now }
+
+	processor, err := NewAggregateFrameProcessor(storage, AggregateFrameProcessorConfig{
+		IntervalMilliseconds: 1000,
+		Functions:            []string{AggregateFunctionMax},
+	})
+	require.NoError(t, err)
+
+	for _, channel := range []string{"stream/sensors/a", "stream/sensors/b"} {
+		out, err := processor.ProcessFrame(context.Background(), Vars{OrgID: 1, Channel: channel}, data.NewFrame("sensors",
+			data.NewField("time", nil, []time.Time{base}),
+			data.NewField("value", nil, []float64{5}),
+		))
+		require.NoError(t, err)
+		require.Nil(t, out)
+	}
+
+	// Channels which receive frames are not flushed.
+	aggregated := &testAggregatedFrameProcessor{}
+	now = base.Add(500 * time.Millisecond)
+	storage.flush(context.Background(), aggregated)
+	require.Empty(t, aggregated.frames)
+
+	now = base.Add(800 * time.Millisecond)
+	_, err = processor.ProcessFrame(context.Background(), Vars{OrgID: 1, Channel: "stream/sensors/b"}, data.NewFrame("sensors",
+		data.NewField("time", nil, []time.Time{base.Add(800 * time.Millisecond)}),
+		data.NewField("value", nil, []float64{7}),
+	))
+	require.NoError(t, err)
+
+	// The open window of an idle channel is emitted and the channel is removed.
+	now = base.Add(1500 * time.Millisecond)
+	storage.flush(context.Background(), aggregated)
+	require.Equal(t, []string{"stream/sensors/a"}, aggregated.channels)
+	require.Equal(t, "sensors", aggregated.frames[0].Name)
+	require.Equal(t, base, aggregated.frames[0].Fields[0].At(0))
+	require.Equal(t, 5.0, *aggregated.frames[0].Fields[1].At(0).(*float64))
+	require.Len(t, storage.channels, 1)
+
+	now = base.Add(2 * time.Second)
+	storage.flush(context.Background(), aggregated)
+	require.Equal(t, []string{"stream/sensors/a", "stream/sensors/b"}, aggregated.channels)
+	require.Equal(t, 7.0, *aggregated.frames[1].Fields[1].At(0).(*float64))
+	require.Empty(t, storage.channels)
+
+	// Nothing is emitted twice.
+	now = base.Add(10 * time.Second)
+	storage.flush(context.Background(), aggregated)
+	require.Len(t, aggregated.frames, 2)
+}
+
+func fp(v float64) *float64 {
+	return &v
+}
@@ -0,0 +1,175 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dop251/goja"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// ExpressionFrameProcessor adds or overwrites fields with values computed by
+// JavaScript expressions over the other fields of each row.
+type ExpressionFrameProcessor struct {
+	config   ExpressionFrameProcessorConfig
+	programs []*goja.Program
+}
+
+// NewExpressionFrameProcessor validates the configuration and compiles expressions.
+func NewExpressionFrameProcessor(config ExpressionFrameProcessorConfig) (*ExpressionFrameProcessor, error) {
+	if len(config.Fields) == 0 {
+		return nil, errors.New("at least one field required")
+	}
+	programs := make([]*goja.Program, 0, len(config.Fields))
+	for _, f := range config.Fields {
+		if f.Name == "" {
+			return nil, errors.New("field name required")
+		}
+		switch expressionFieldType(f) {
+		case data.FieldTypeNullableFloat64, data.FieldTypeNullableString, data.FieldTypeNullableBool:
+		default:
+			return nil, fmt.Errorf("unsupported field type: %s (%s)", f.Type, f.Name)
+		}
+		program, err := compileScript(f.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expression for %s: %w", f.Name, err)
+		}
+		programs = append(programs, program)
+	}
+	return &ExpressionFrameProcessor{config: config, programs: programs}, nil
+}
+
+const FrameProcessorTypeExpression = "expression"
+
+func (p *ExpressionFrameProcessor) Type() string {
+	return FrameProcessorTypeExpression
+}
+
+// expressionFieldType returns a type of the computed field, float64 by default.
+func expressionFieldType(f ExpressionField) data.FieldType {
+	if f.Type == data.FieldTypeUnknown {
+		return data.FieldTypeNullableFloat64
+	}
+	return f.Type.NullableType()
+}
+
+// expressionFrameTimeout limits time of evaluating all expressions over a frame.
+const expressionFrameTimeout = time.Second
+
+func (p *ExpressionFrameProcessor) ProcessFrame(_ context.Context, _ Vars, frame *data.Frame) (*data.Frame, error) {
+	rows := frame.Rows()
+	computed := make([]*data.Field, len(p.config.Fields))
+	for i, f := range p.config.Fields {
+		fieldType := expressionFieldType(f)
+		// Overwritten fields keep their type, so non-nullable fields stay non-nullable.
+		if existing, idx := frame.FieldByName(f.Name); idx >= 0 && existing.Type().NullableType() == fieldType {
+			fieldType = existing.Type()
+		}
+		computed[i] = data.NewFieldFromFieldType(fieldType, rows)
+	}
+
+	labels := map[string]string{}
+	for _, field := range frame.Fields {
+		for k, v := range field.Labels {
+			if _, ok := labels[k]; !ok {
+				labels[k] = v
+			}
+		}
+	}
+
+	// Runtime is not safe for concurrent use, frames can be processed concurrently.
+	r := newRuntime()
+	if err := r.vm.Set("labels", labels); err != nil {
+		return nil, err
+	}
+	err := r.withTimeout(expressionFrameTimeout, func() error {
+		for rowIdx := 0; rowIdx < rows; rowIdx++ {
+			x := make(map[string]interface{}, len(frame.Fields)+len(computed))
+			for _, field := range frame.Fields {
+				if _, ok := x[field.Name]; ok {
+					continue
+				}
+				x[field.Name] = expressionValue(field, rowIdx)
+			}
+			if err := r.vm.Set("x", x); err != nil {
+				return err
+			}
+			for i, program := range p.programs {
+				name := p.config.Fields[i].Name
+				v, err := r.vm.RunProgram(program)
+				if err != nil {
+					return fmt.Errorf("error evaluating expression for %s: %w", name, err)
+				}
+				if goja.IsUndefined(v) || goja.IsNull(v) {
+					if !computed[i].Nullable() {
+						return fmt.Errorf("null value for non-nullable field %s", name)
+					}
+					x[name] = nil
+					continue
+				}
+				switch computed[i].Type().NullableType() {
+				case data.FieldTypeNullableFloat64:
+					switch val := v.Export().(type) {
+					case float64:
+						computed[i].SetConcrete(rowIdx, val)
+					case int64:
+						computed[i].SetConcrete(rowIdx, float64(val))
+					default:
+						return fmt.Errorf("unexpected return value for %s: %T", name, val)
+					}
+				case data.FieldTypeNullableString:
+					computed[i].SetConcrete(rowIdx, v.String())
+				case data.FieldTypeNullableBool:
+					computed[i].SetConcrete(rowIdx, v.ToBoolean())
+				}
+				// Later expressions can use values computed by previous ones.
+				x[name] = v.Export()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]*data.Field, len(frame.Fields))
+	copy(fields, frame.Fields)
+	for i, f := range p.config.Fields {
+		field := computed[i]
+		field.Name = f.Name
+		field.Config = f.Config
+		idx := -1
+		for j, existing := range fields {
+			if existing.Name == f.Name {
+				idx = j
+				break
+			}
+		}
+		if idx < 0 {
+			fields = append(fields, field)
+			continue
+		}
+		// Overwritten fields keep labels and config unless config is set.
+		field.Labels = fields[idx].Labels
+		if field.Config == nil {
+			field.Config = fields[idx].Config
+		}
+		fields[idx] = field
+	}
+	return data.NewFrame(frame.Name, fields...), nil
+}
+
+// expressionValue returns a field value at row to use in expressions,
+// time values are passed as Unix time in milliseconds.
+func expressionValue(field *data.Field, rowIdx int) interface{} {
+	v, ok := field.ConcreteAt(rowIdx)
+	if !ok {
+		return nil
+	}
+	if t, ok := v.(time.Time); ok {
+		return t.UnixMilli()
+	}
+	return v
+}
@@ -0,0 +1,108 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dop251/goja"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewExpressionFrameProcessor_Validation(t *testing.T) {
+	_, err := NewExpressionFrameProcessor(ExpressionFrameProcessorConfig{})
+	require.Error(t, err)
+	_, err = NewExpressionFrameProcessor(ExpressionFrameProcessorConfig{Fields: []ExpressionField{{Expression: "1"}}})
+	require.ErrorContains(t, err, "name required")
+	_, err = NewExpressionFrameProcessor(ExpressionFrameProcessorConfig{Fields: []ExpressionField{{Name: "a", Expression: "x.b +"}}})
+	require.ErrorContains(t, err, "invalid expression for a")
+	_, err = NewExpressionFrameProcessor(ExpressionFrameProcessorConfig{Fields: []ExpressionField{{Name: "a", Type: data.FieldTypeTime, Expression: "1"}}})
+	require.ErrorContains(t, err, "unsupported field type")
+}
+
+func TestExpressionFrameProcessor_ProcessFrame(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	processor, err := NewExpressionFrameProcessor(ExpressionFrameProcessorConfig{
+		Fields: []ExpressionField{
+			{Name: "temperature", Expression: "x.temperature * 1.8 + 32", Config: &data.FieldConfig{Unit: "fahrenheit"}},
+			{Name: "ratio", Expression: "x.used == null ? null : x.used / x.total"},
+			{Name: "title", Type: data.FieldTypeString, Expression: "labels.room + ': ' + x.temperature.toFixed(1)"},
+			{Name: "hot", Type: data.FieldTypeBool, Expression: "x.temperature > 80"},
+			{Name: "age", Expression: "x.time - 1664625600000"},
+		},
+	})
+	require.NoError(t, err)
+
+	frame := data.NewFrame("sensors",
+		data.NewField("time", nil, []time.Time{now, now.Add(time.Second)}),
+		data.NewField("temperature", data.Labels{"room": "kitchen"}, []float64{20, 30}),
+		data.NewField("used", nil, []*int64{i64p(5), nil}),
+		data.NewField("total", nil, []int64{10, 10}),
+	)
+	out, err := processor.ProcessFrame(context.Background(), Vars{}, frame)
+	require.NoError(t, err)
+	require.Equal(t, "sensors", out.Name)
+	require.Len(t, out.Fields, 8)
+
+	field := func(name string) *data.Field {
+		f, idx := out.FieldByName(name)
+		require.NotEqual(t, -1, idx, "field %s not found", name)
+		return f
+	}
+
+	// Overwritten field keeps its position, type and labels.
+	require.Equal(t, "temperature", out.Fields[1].Name)
+	require.Equal(t, data.FieldTypeFloat64, out.Fields[1].Type())
+	require.Equal(t, data.Labels{"room": "kitchen"}, out.Fields[1].Labels)
+	require.Equal(t, "fahrenheit", out.Fields[1].Config.Unit)
+	require.Equal(t, 68.0, field("temperature").At(0))
+	require.Equal(t, 86.0, field("temperature").At(1))
+
+	require.Equal(t, 0.5, *field("ratio").At(0).(*float64))
+	require.Nil(t, field("ratio").At(1))
+
+	// Later expressions see values computed by previous ones.
+	require.Equal(t, "kitchen: 68.0", *field("title").At(0).(*string))
+	require.False(t, *field("hot").At(0).(*bool))
+	require.True(t, *field("hot").At(1).(*bool))
+
+	require.Equal(t, 0.0, *field("age").At(0).(*float64))
+	require.Equal(t, 1000.0, *field("age").At(1).(*float64))
+}
+
+func TestExpressionFrameProcessor_ProcessFrame_Error(t *testing.T) {
+	processor, err := NewExpressionFrameProcessor(ExpressionFrameProcessorConfig{
+		Fields: []ExpressionField{{Name: "value", Expression: "x.name"}},
+	})
+	require.NoError(t, err)
+	_, err = processor.ProcessFrame(context.Background(), Vars{}, data.NewFrame("test",
+		data.NewField("name", nil, []string{"a"}),
+	))
+	require.ErrorContains(t, err, "unexpected return value for value")
+
+	processor, err = NewExpressionFrameProcessor(ExpressionFrameProcessorConfig{
+		Fields: []ExpressionField{{Name: "value", Expression: "null"}},
+	})
+	require.NoError(t, err)
+	_, err = processor.ProcessFrame(context.Background(), Vars{}, data.NewFrame("test",
+		data.NewField("value", nil, []float64{1}),
+	))
+	require.ErrorContains(t, err, "null value for non-nullable field value")
+}
+
+func TestExpressionFrameProcessor_ProcessFrame_Timeout(t *testing.T) {
+	processor, err := NewExpressionFrameProcessor(ExpressionFrameProcessorConfig{
+		Fields: []ExpressionField{{Name: "value", Expression: "while (true) {}"}},
+	})
+	require.NoError(t, err)
+	_, err = processor.ProcessFrame(context.Background(), Vars{}, data.NewFrame("test",
+		data.NewField("value", nil, []float64{1}),
+	))
+	var interrupted *goja.InterruptedError
+	require.ErrorAs(t, err, &interrupted)
+}
+
+func i64p(v int64) *int64 {
+	return &v
+}
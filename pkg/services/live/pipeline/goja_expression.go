@@ -10,10 +10,7 @@ import (
 )
 
 func getRuntime(payload []byte) (*gojaRuntime, error) {
-	vm := goja.New()
-	vm.SetMaxCallStackSize(64)
-	vm.SetParserOptions(parser.WithDisableSourceMaps)
-	r := &gojaRuntime{vm}
+	r := newRuntime()
 	err := r.init(payload)
 	if err != nil {
 		return nil, err
@@ -21,6 +18,19 @@ func getRuntime(payload []byte) (*gojaRuntime, error) {
 	return r, nil
 }
 
+func newRuntime() *gojaRuntime {
+	vm := goja.New()
+	vm.SetMaxCallStackSize(64)
+	vm.SetParserOptions(parser.WithDisableSourceMaps)
+	return &gojaRuntime{vm}
+}
+
+// compileScript compiles a script once, so it can be validated on rule build and
+// then run by many runtimes.
+func compileScript(script string) (*goja.Program, error) {
+	return goja.Compile("", script, true)
+}
+
 type gojaRuntime struct {
 	vm *goja.Runtime
 }
@@ -36,23 +46,42 @@ func (r *gojaRuntime) init(payload []byte) error {
 }
 
 func (r *gojaRuntime) runString(script string) (goja.Value, error) {
-	doneCh := make(chan struct{})
-	go func() {
-		select {
-		case <-doneCh:
-			return
-		case <-time.After(100 * time.Millisecond):
-			// Some ideas to prevent misuse of scripts:
-			// * parse/validate scripts on save
-			// * block scripts after several timeouts in a row
-			// * block scripts on malformed returned error
-			// * limit total quota of time for scripts
-			// * maybe allow only one statement, reject scripts with cycles and functions.
-			r.vm.Interrupt(errors.New("timeout"))
-		}
+	return r.run(func() (goja.Value, error) {
+		return r.vm.RunString(script)
+	})
+}
+
+func (r *gojaRuntime) run(fn func() (goja.Value, error)) (goja.Value, error) {
+	var v goja.Value
+	err := r.withTimeout(scriptTimeout, func() error {
+		var err error
+		v, err = fn()
+		return err
+	})
+	return v, err
+}
+
+// scriptTimeout limits time of a single script run.
+const scriptTimeout = 100 * time.Millisecond
+
+// withTimeout interrupts scripts run by fn once timeout passes, so that many
+// scripts can share one timer.
+func (r *gojaRuntime) withTimeout(timeout time.Duration, fn func() error) error {
+	// Some ideas to prevent misuse of scripts:
+	// * parse/validate scripts on save
+	// * block scripts after several timeouts in a row
+	// * block scripts on malformed returned error
+	// * limit total quota of time for scripts
+	// * maybe allow only one statement, reject scripts with cycles and functions.
+	timer := time.AfterFunc(timeout, func() {
+		r.vm.Interrupt(errors.New("timeout"))
+	})
+	defer func() {
+		timer.Stop()
+		// Timer could fire after fn returned, do not let it interrupt the next run.
+		r.vm.ClearInterrupt()
 	}()
-	defer close(doneCh)
-	return r.vm.RunString(script)
+	return fn()
 }
 
 func (r *gojaRuntime) getBool(script string) (bool, error) {
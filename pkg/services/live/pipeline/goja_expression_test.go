@@ -44,6 +44,10 @@ func TestGojaIInterrupt(t *testing.T) {
 	_, err = r.getBool("while (true) {}")
 	var interrupted *goja.InterruptedError
 	require.ErrorAs(t, err, &interrupted)
+	// Runtime can be used after interrupt.
+	val, err := r.getBool("true")
+	require.NoError(t, err)
+	require.True(t, val)
 }
 
 func TestGojaIMaxStack(t *testing.T) {
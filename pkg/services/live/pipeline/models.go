@@ -62,6 +62,30 @@ func WriteConfigToDto(b WriteConfig) WriteConfigDto {
 	}
 }
 
+// ChannelRuleToDto returns a copy of the rule without encrypted secrets of sources.
+// Keys of secure settings are kept with empty values, so clients know which
+// secrets are set.
+func ChannelRuleToDto(r ChannelRule) ChannelRule {
+	if len(r.Settings.Sources) == 0 {
+		return r
+	}
+	sources := make([]*SourceConfig, 0, len(r.Settings.Sources))
+	for _, source := range r.Settings.Sources {
+		if source == nil || len(source.SecureSettings) == 0 {
+			sources = append(sources, source)
+			continue
+		}
+		s := *source
+		s.SecureSettings = make(map[string][]byte, len(source.SecureSettings))
+		for k := range source.SecureSettings {
+			s.SecureSettings[k] = nil
+		}
+		sources = append(sources, &s)
+	}
+	r.Settings.Sources = sources
+	return r
+}
+
 type WriteConfigDto struct {
 	UID          string          `json:"uid"`
 	Settings     WriteSettings   `json:"settings"`
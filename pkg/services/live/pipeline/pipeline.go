@@ -111,6 +111,20 @@ type Subscriber interface {
 	Subscribe(ctx context.Context, vars Vars, data []byte) (models.SubscribeReply, backend.SubscribeStreamStatus, error)
 }
 
+// Source consumes messages from an external system, e.g. an MQTT broker. Each
+// message is processed as input data of the channel the source is defined for.
+type Source interface {
+	Type() string
+	// Key identifies the source configuration. A running source is restarted
+	// when the key of the source defined for a channel changes.
+	Key() string
+	// Run consumes messages until ctx is done.
+	Run(ctx context.Context, handle SourceMessageHandler) error
+}
+
+// SourceMessageHandler processes a message received by a Source.
+type SourceMessageHandler func(ctx context.Context, body []byte)
+
 // PublishAuthChecker checks whether current user can publish to a channel.
 type PublishAuthChecker interface {
 	CanPublish(ctx context.Context, u *user.SignedInUser) (bool, error)
@@ -162,6 +176,10 @@ type LiveChannelRule struct {
 	// can optionally return a slice of ChannelFrame to pass the control to a rule defined
 	// by ChannelFrame.Channel.
 	FrameOutputters []FrameOutputter
+	// Sources if set consume data from external systems, each message is then processed
+	// as input data of the channel - i.e. it's passed to DataOutputters or Converter.
+	// Sources are only allowed for rules with a pattern without parameters.
+	Sources []Source
 }
 
 // Label ...
@@ -404,9 +422,50 @@ func (p *Pipeline) processFrame(ctx context.Context, orgID int64, channelID stri
 		Namespace: ch.Namespace,
 		Path:      ch.Path,
 	}
+	return p.applyRule(ctx, rule, rule.FrameProcessors, vars, frame)
+}
+
+// ProcessAggregatedFrame processes a frame emitted by AggregationStorage for a
+// channel which stopped receiving frames. The frame continues after the aggregate
+// processor of the channel rule.
+func (p *Pipeline) ProcessAggregatedFrame(ctx context.Context, orgID int64, channelID string, frame *data.Frame) error {
+	rule, ok, err := p.ruleGetter.Get(orgID, channelID)
+	if err != nil || !ok {
+		return err
+	}
+	ch, err := live.ParseChannel(channelID)
+	if err != nil {
+		return err
+	}
+	vars := Vars{
+		OrgID:     orgID,
+		Channel:   channelID,
+		Scope:     ch.Scope,
+		Namespace: ch.Namespace,
+		Path:      ch.Path,
+	}
+	for i, proc := range rule.FrameProcessors {
+		if _, ok := proc.(*AggregateFrameProcessor); !ok {
+			continue
+		}
+		frames, err := p.applyRule(ctx, rule, rule.FrameProcessors[i+1:], vars, frame)
+		if err != nil {
+			return err
+		}
+		if len(frames) == 0 {
+			return nil
+		}
+		return p.processChannelFrames(ctx, orgID, channelID, frames, map[string]struct{}{channelID: {}})
+	}
+	// The rule has changed and does not aggregate anymore.
+	return nil
+}
 
-	if len(rule.FrameProcessors) > 0 {
-		for _, proc := range rule.FrameProcessors {
+// applyRule runs the frame through processors and frame outputs of the rule.
+func (p *Pipeline) applyRule(ctx context.Context, rule *LiveChannelRule, processors []FrameProcessor, vars Vars, frame *data.Frame) ([]*ChannelFrame, error) {
+	var err error
+	if len(processors) > 0 {
+		for _, proc := range processors {
 			frame, err = p.execProcessor(ctx, proc, vars, frame)
 			if err != nil {
 				logger.Error("Error processing frame", "error", err)
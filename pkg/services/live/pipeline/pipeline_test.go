@@ -5,6 +5,7 @@ import (
 	"errors"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
@@ -143,3 +144,50 @@ func TestPipeline_Recursion(t *testing.T) {
 	_, err = p.ProcessInput(context.Background(), 1, "stream/test/xxx", []byte(`{}`))
 	require.ErrorIs(t, err, errChannelRecursion)
 }
+
+func TestPipeline_AggregateRedirect(t *testing.T) {
+	base := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	storage := NewAggregationStorage()
+	aggregate, err := NewAggregateFrameProcessor(storage, AggregateFrameProcessorConfig{
+		IntervalMilliseconds: 1000,
+		Functions:            []string{AggregateFunctionMax},
+	})
+	require.NoError(t, err)
+	rawOutputter := &testOutputter{}
+	aggregatedOutputter := &testOutputter{}
+	p, err := New(&testRuleGetter{
+		rules: map[string]*LiveChannelRule{
+			"stream/sensors/raw": {
+				Converter: &testConverter{"", data.NewFrame("sensors",
+					data.NewField("time", nil, []time.Time{base}),
+					data.NewField("value", nil, []float64{5}),
+				)},
+				FrameOutputters: []FrameOutputter{
+					rawOutputter,
+					NewRedirectFrameOutput(RedirectOutputConfig{
+						Channel: "stream/sensors/aggregated",
+					}),
+				},
+			},
+			"stream/sensors/aggregated": {
+				FrameProcessors: []FrameProcessor{aggregate, &testProcessor{}},
+				FrameOutputters: []FrameOutputter{aggregatedOutputter},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	// Raw frames are output while the window of the redirected channel is open.
+	ok, err := p.ProcessInput(context.Background(), 1, "stream/sensors/raw", []byte(`{}`))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.NotNil(t, rawOutputter.frame)
+	require.Nil(t, aggregatedOutputter.frame)
+
+	// The window is flushed once the channel stops receiving frames.
+	storage.now = func() time.Time { return time.Now().Add(time.Minute) }
+	storage.flush(context.Background(), p)
+	require.NotNil(t, aggregatedOutputter.frame)
+	require.Equal(t, "value_max", aggregatedOutputter.frame.Fields[1].Name)
+	require.Equal(t, 5.0, *aggregatedOutputter.frame.Fields[1].At(0).(*float64))
+}
@@ -70,6 +70,20 @@ var ConvertersRegistry = []EntityInfo{
 		Type:        ConverterTypeJsonFrame,
 		Description: "JSON-encoded Grafana data frame",
 	},
+	{
+		Type:        ConverterTypePrometheusAuto,
+		Description: "accept prometheus text exposition format",
+		Example: AutoPrometheusConverterConfig{
+			FrameFormat: "labels_column",
+		},
+	},
+	{
+		Type:        ConverterTypeOTLPAuto,
+		Description: "accept OTLP/HTTP metrics encoded in protobuf or JSON",
+		Example: AutoOTLPConverterConfig{
+			FrameFormat: "labels_column",
+		},
+	},
 }
 
 var FrameProcessorsRegistry = []EntityInfo{
@@ -83,6 +97,24 @@ var FrameProcessorsRegistry = []EntityInfo{
 		Description: "list the fields that should be removed",
 		Example:     DropFieldsFrameProcessorConfig{},
 	},
+	{
+		Type:        FrameProcessorTypeAggregate,
+		Description: "downsample frames to time windows, grouped by field values",
+		Example: AggregateFrameProcessorConfig{
+			IntervalMilliseconds: 1000,
+			Functions:            []string{AggregateFunctionMean, AggregateFunctionMax},
+			GroupBy:              []string{"labels"},
+		},
+	},
+	{
+		Type:        FrameProcessorTypeExpression,
+		Description: "add or overwrite fields computed by JavaScript expressions over row values",
+		Example: ExpressionFrameProcessorConfig{
+			Fields: []ExpressionField{
+				{Name: "temperature_f", Expression: "x.temperature * 1.8 + 32"},
+			},
+		},
+	},
 }
 
 var DataOutputsRegistry = []EntityInfo{
@@ -99,3 +131,21 @@ var DataOutputsRegistry = []EntityInfo{
 		Description: "output data to Loki as logs",
 	},
 }
+
+var SourcesRegistry = []EntityInfo{
+	{
+		Type:        SourceTypeMQTT,
+		Description: "subscribe to an MQTT topic, uses write config endpoint as broker URL",
+		Example: MQTTSourceConfig{
+			Topic: "sensors/#",
+		},
+	},
+	{
+		Type:        SourceTypeKafka,
+		Description: "consume a Kafka topic, uses write config endpoint as comma separated list of brokers",
+		Example: KafkaSourceConfig{
+			Topic:   "sensors",
+			GroupID: "grafana-live",
+		},
+	},
+}
@@ -2,7 +2,10 @@ package pipeline
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
+	"strings"
 
 	"github.com/centrifugal/centrifuge"
 	"github.com/grafana/grafana/pkg/services/live/managedstream"
@@ -13,6 +16,7 @@ type StorageRuleBuilder struct {
 	Node                 *centrifuge.Node
 	ManagedStream        *managedstream.Runner
 	FrameStorage         *FrameStorage
+	AggregationStorage   *AggregationStorage
 	Storage              Storage
 	ChannelHandlerGetter ChannelHandlerGetter
 	SecretsService       secrets.Service
@@ -73,6 +77,16 @@ func (f *StorageRuleBuilder) extractConverter(config *ConverterConfig) (Converte
 			return nil, missingConfiguration
 		}
 		return NewAutoInfluxConverter(*config.AutoInfluxConverterConfig), nil
+	case ConverterTypePrometheusAuto:
+		if config.AutoPrometheusConverterConfig == nil {
+			config.AutoPrometheusConverterConfig = &AutoPrometheusConverterConfig{FrameFormat: "labels_column"}
+		}
+		return NewAutoPrometheusConverter(*config.AutoPrometheusConverterConfig), nil
+	case ConverterTypeOTLPAuto:
+		if config.AutoOTLPConverterConfig == nil {
+			config.AutoOTLPConverterConfig = &AutoOTLPConverterConfig{FrameFormat: "labels_column"}
+		}
+		return NewAutoOTLPConverter(*config.AutoOTLPConverterConfig), nil
 	default:
 		return nil, fmt.Errorf("unknown converter type: %s", config.Type)
 	}
@@ -108,6 +122,20 @@ func (f *StorageRuleBuilder) extractFrameProcessor(config *FrameProcessorConfig)
 			processors = append(processors, proc)
 		}
 		return NewMultipleFrameProcessor(processors...), nil
+	case FrameProcessorTypeAggregate:
+		if config.AggregateProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		storage := f.AggregationStorage
+		if storage == nil {
+			storage = NewAggregationStorage()
+		}
+		return NewAggregateFrameProcessor(storage, *config.AggregateProcessorConfig)
+	case FrameProcessorTypeExpression:
+		if config.ExpressionProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewExpressionFrameProcessor(*config.ExpressionProcessorConfig)
 	default:
 		return nil, fmt.Errorf("unknown processor type: %s", config.Type)
 	}
@@ -145,25 +173,27 @@ func (f *StorageRuleBuilder) extractFrameConditionChecker(config *FrameCondition
 }
 
 func (f *StorageRuleBuilder) constructBasicAuth(writeConfig WriteConfig) (*BasicAuth, error) {
-	if writeConfig.Settings.BasicAuth == nil {
+	return f.decryptBasicAuth(writeConfig.Settings.BasicAuth, writeConfig.SecureSettings)
+}
+
+func (f *StorageRuleBuilder) decryptBasicAuth(basicAuth *BasicAuth, secureSettings map[string][]byte) (*BasicAuth, error) {
+	if basicAuth == nil {
 		return nil, nil
 	}
 	var password string
-	hasSecurePassword := len(writeConfig.SecureSettings["basicAuthPassword"]) > 0
+	hasSecurePassword := len(secureSettings["basicAuthPassword"]) > 0
 	if hasSecurePassword {
-		passwordBytes, err := f.SecretsService.Decrypt(context.Background(), writeConfig.SecureSettings["basicAuthPassword"])
+		passwordBytes, err := f.SecretsService.Decrypt(context.Background(), secureSettings["basicAuthPassword"])
 		if err != nil {
 			return nil, fmt.Errorf("basicAuthPassword can't be decrypted: %w", err)
 		}
 		password = string(passwordBytes)
 	} else {
 		// Use plain text password (should be removed upon database integration).
-		if writeConfig.Settings.BasicAuth != nil {
-			password = writeConfig.Settings.BasicAuth.Password
-		}
+		password = basicAuth.Password
 	}
 	return &BasicAuth{
-		User:     writeConfig.Settings.BasicAuth.User,
+		User:     basicAuth.User,
 		Password: password,
 	}, nil
 }
@@ -294,6 +324,63 @@ func (f *StorageRuleBuilder) extractDataOutputter(config *DataOutputterConfig, w
 	}
 }
 
+func (f *StorageRuleBuilder) extractSource(config *SourceConfig) (Source, error) {
+	if config == nil {
+		return nil, nil
+	}
+	missingConfiguration := fmt.Errorf("missing configuration for %s", config.Type)
+	basicAuth, err := f.decryptBasicAuth(config.BasicAuth, config.SecureSettings)
+	if err != nil {
+		return nil, fmt.Errorf("error constructing basicAuth: %w", err)
+	}
+	switch config.Type {
+	case SourceTypeMQTT:
+		if config.MQTTSourceConfig == nil || config.MQTTSourceConfig.URL == "" || config.MQTTSourceConfig.Topic == "" {
+			return nil, missingConfiguration
+		}
+		brokerURL, err := url.Parse(config.MQTTSourceConfig.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mqtt broker url: %w", err)
+		}
+		secure := isMQTTSchemeSecure(brokerURL.Scheme)
+		if config.MQTTSourceConfig.TLS != nil && !secure {
+			return nil, fmt.Errorf("tls settings require a TLS broker url, e.g. ssl://, got %s://", brokerURL.Scheme)
+		}
+		if basicAuth != nil && !secure {
+			return nil, errors.New("mqtt credentials are only sent to a TLS broker url, e.g. ssl://")
+		}
+		tlsConfig, err := buildSourceTLSConfig(config.MQTTSourceConfig.TLS)
+		if err != nil {
+			return nil, err
+		}
+		return NewMQTTSource(basicAuth, tlsConfig, *config.MQTTSourceConfig), nil
+	case SourceTypeKafka:
+		if config.KafkaSourceConfig == nil || len(config.KafkaSourceConfig.Brokers) == 0 || config.KafkaSourceConfig.Topic == "" || config.KafkaSourceConfig.GroupID == "" {
+			return nil, missingConfiguration
+		}
+		if basicAuth != nil && config.KafkaSourceConfig.TLS == nil {
+			return nil, errors.New("kafka SASL PLAIN credentials require tls settings")
+		}
+		tlsConfig, err := buildSourceTLSConfig(config.KafkaSourceConfig.TLS)
+		if err != nil {
+			return nil, err
+		}
+		return NewKafkaSource(basicAuth, tlsConfig, *config.KafkaSourceConfig), nil
+	default:
+		return nil, fmt.Errorf("unknown source type: %s", config.Type)
+	}
+}
+
+// isMQTTSchemeSecure returns true for broker URL schemes connected to with TLS.
+func isMQTTSchemeSecure(scheme string) bool {
+	switch scheme {
+	case "ssl", "tls", "mqtts", "mqtt+ssl", "tcps", "wss":
+		return true
+	default:
+		return false
+	}
+}
+
 func (f *StorageRuleBuilder) getWriteConfig(uid string, writeConfigs []WriteConfig) (WriteConfig, bool) {
 	for _, rwb := range writeConfigs {
 		if rwb.UID == uid {
@@ -377,6 +464,19 @@ func (f *StorageRuleBuilder) BuildRules(ctx context.Context, orgID int64) ([]*Li
 		}
 		rule.Subscribers = subscribers
 
+		if len(ruleConfig.Settings.Sources) > 0 && strings.ContainsAny(rule.Pattern, ":*") {
+			return nil, fmt.Errorf("sources are not supported for %s: pattern must not contain parameters", rule.Pattern)
+		}
+		var sources []Source
+		for _, sourceConfig := range ruleConfig.Settings.Sources {
+			source, err := f.extractSource(sourceConfig)
+			if err != nil {
+				return nil, fmt.Errorf("error building source for %s: %w", rule.Pattern, err)
+			}
+			sources = append(sources, source)
+		}
+		rule.Sources = sources
+
 		rules = append(rules, rule)
 	}
 
@@ -0,0 +1,79 @@
+package pipeline
+
+import (
+	"context"
+	"crypto/tls"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+)
+
+// KafkaSource consumes a Kafka topic as a member of a consumer group, the value
+// of every message is processed as channel input data.
+type KafkaSource struct {
+	basicAuth *BasicAuth
+	tlsConfig *tls.Config
+	config    KafkaSourceConfig
+}
+
+// NewKafkaSource creates new KafkaSource. SASL PLAIN credentials are only sent
+// over TLS, so basicAuth requires a tlsConfig.
+func NewKafkaSource(basicAuth *BasicAuth, tlsConfig *tls.Config, config KafkaSourceConfig) *KafkaSource {
+	return &KafkaSource{
+		basicAuth: basicAuth,
+		tlsConfig: tlsConfig,
+		config:    config,
+	}
+}
+
+const SourceTypeKafka = "kafka"
+
+func (s *KafkaSource) Type() string {
+	return SourceTypeKafka
+}
+
+func (s *KafkaSource) Key() string {
+	return sourceKey(s.basicAuth, s.config)
+}
+
+func (s *KafkaSource) Run(ctx context.Context, handle SourceMessageHandler) error {
+	dialer := &kafka.Dialer{
+		Timeout:   10 * time.Second,
+		DualStack: true,
+		TLS:       s.tlsConfig,
+	}
+	if s.basicAuth != nil {
+		dialer.SASLMechanism = plain.Mechanism{
+			Username: s.basicAuth.User,
+			Password: s.basicAuth.Password,
+		}
+	}
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: s.config.Brokers,
+		Topic:   s.config.Topic,
+		GroupID: s.config.GroupID,
+		Dialer:  dialer,
+		MaxWait: time.Second,
+		// A new consumer group starts with new messages, there is no point
+		// to stream the history of a topic to live subscribers.
+		StartOffset: kafka.LastOffset,
+	})
+	defer func() {
+		if err := reader.Close(); err != nil {
+			logger.Error("Error closing Kafka reader", "error", err, "topic", s.config.Topic)
+		}
+	}()
+
+	for {
+		// ReadMessage commits the offset of the message for the consumer group.
+		msg, err := reader.ReadMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		handle(ctx, msg.Value)
+	}
+}
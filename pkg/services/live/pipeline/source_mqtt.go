@@ -0,0 +1,91 @@
+package pipeline
+
+import (
+	"context"
+	"crypto/tls"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const mqttSourceTimeout = 10 * time.Second
+
+// MQTTSource subscribes to an MQTT topic, the payload of every message
+// is processed as channel input data.
+type MQTTSource struct {
+	basicAuth *BasicAuth
+	tlsConfig *tls.Config
+	config    MQTTSourceConfig
+}
+
+// NewMQTTSource creates new MQTTSource. The tlsConfig is used for brokers
+// with a TLS URL, credentials are only sent to such brokers.
+func NewMQTTSource(basicAuth *BasicAuth, tlsConfig *tls.Config, config MQTTSourceConfig) *MQTTSource {
+	return &MQTTSource{
+		basicAuth: basicAuth,
+		tlsConfig: tlsConfig,
+		config:    config,
+	}
+}
+
+const SourceTypeMQTT = "mqtt"
+
+func (s *MQTTSource) Type() string {
+	return SourceTypeMQTT
+}
+
+func (s *MQTTSource) Key() string {
+	return sourceKey(s.basicAuth, s.config)
+}
+
+func (s *MQTTSource) Run(ctx context.Context, handle SourceMessageHandler) error {
+	clientID := s.config.ClientID
+	if clientID == "" {
+		// The client ID must be unique per broker, otherwise the broker disconnects the client that was connected first.
+		clientID = "grafana_live_" + util.GenerateShortUID()
+	}
+	opts := mqtt.NewClientOptions().
+		AddBroker(s.config.URL).
+		SetClientID(clientID).
+		SetCleanSession(true).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(mqttSourceTimeout).
+		SetConnectTimeout(mqttSourceTimeout)
+	if s.tlsConfig != nil {
+		opts.SetTLSConfig(s.tlsConfig)
+	}
+	if s.basicAuth != nil {
+		opts.SetUsername(s.basicAuth.User).SetPassword(s.basicAuth.Password)
+	}
+	// The broker forgets subscriptions of a clean session, so subscribe on every (re)connect.
+	// OnConnect is called in a separate goroutine, waiting for the token is fine here.
+	opts.SetOnConnectHandler(func(client mqtt.Client) {
+		token := client.Subscribe(s.config.Topic, s.config.QoS, func(_ mqtt.Client, msg mqtt.Message) {
+			handle(ctx, msg.Payload())
+		})
+		if !token.WaitTimeout(mqttSourceTimeout) {
+			logger.Error("Timed out subscribing to MQTT topic", "topic", s.config.Topic)
+			return
+		}
+		if err := token.Error(); err != nil {
+			logger.Error("Error subscribing to MQTT topic", "error", err, "topic", s.config.Topic)
+		}
+	})
+
+	client := mqtt.NewClient(opts)
+	token := client.Connect()
+	defer client.Disconnect(250)
+	select {
+	case <-token.Done():
+		if err := token.Error(); err != nil {
+			return err
+		}
+	case <-ctx.Done():
+		return nil
+	}
+	<-ctx.Done()
+	return nil
+}
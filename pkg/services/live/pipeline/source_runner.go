@@ -0,0 +1,182 @@
+package pipeline
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/live/orgchannel"
+)
+
+const sourceRestartInterval = 10 * time.Second
+
+// InputProcessor processes input data of a channel, it's implemented by Pipeline.
+type InputProcessor interface {
+	ProcessInput(ctx context.Context, orgID int64, channelID string, body []byte) (bool, error)
+}
+
+// SourceRunner runs Sources of channel rules. It wraps a RuleBuilder to be aware
+// of rules every time they are built. Channel rules are rebuilt periodically, a
+// source keeps running as long as its channel and configuration stay the same.
+// Not usable in HA setup unless the source itself splits the work between
+// Grafana instances, like Kafka consumer groups do.
+type SourceRunner struct {
+	ruleBuilder RuleBuilder
+
+	mu      sync.Mutex
+	sources map[int64]map[string]channelSource
+	changed chan struct{}
+}
+
+// NewSourceRunner creates new SourceRunner.
+func NewSourceRunner(ruleBuilder RuleBuilder) *SourceRunner {
+	return &SourceRunner{
+		ruleBuilder: ruleBuilder,
+		sources:     map[int64]map[string]channelSource{},
+		changed:     make(chan struct{}, 1),
+	}
+}
+
+type channelSource struct {
+	orgID   int64
+	channel string
+	source  Source
+}
+
+func (s channelSource) key() string {
+	return orgchannel.PrependOrgID(s.orgID, s.channel) + "/" + s.source.Type() + "/" + s.source.Key()
+}
+
+// BuildRules builds rules with the wrapped RuleBuilder and updates
+// the sources which should be running for the organization.
+func (r *SourceRunner) BuildRules(ctx context.Context, orgID int64) ([]*LiveChannelRule, error) {
+	rules, err := r.ruleBuilder.BuildRules(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	sources := map[string]channelSource{}
+	for _, rule := range rules {
+		for _, source := range rule.Sources {
+			if source == nil {
+				continue
+			}
+			s := channelSource{orgID: orgID, channel: rule.Pattern, source: source}
+			sources[s.key()] = s
+		}
+	}
+	r.mu.Lock()
+	r.sources[orgID] = sources
+	r.mu.Unlock()
+	select {
+	case r.changed <- struct{}{}:
+	default:
+	}
+	return rules, nil
+}
+
+// Run starts and stops sources according to the built rules until ctx is done.
+// Messages of sources are passed to the processor.
+func (r *SourceRunner) Run(ctx context.Context, processor InputProcessor) error {
+	var wg sync.WaitGroup
+	running := map[string]context.CancelFunc{}
+	defer func() {
+		for _, cancel := range running {
+			cancel()
+		}
+		wg.Wait()
+	}()
+
+	for {
+		desired := map[string]channelSource{}
+		r.mu.Lock()
+		for _, sources := range r.sources {
+			for key, s := range sources {
+				desired[key] = s
+			}
+		}
+		r.mu.Unlock()
+
+		for key, cancel := range running {
+			if _, ok := desired[key]; !ok {
+				cancel()
+				delete(running, key)
+			}
+		}
+		for key, s := range desired {
+			if _, ok := running[key]; ok {
+				continue
+			}
+			sourceCtx, cancel := context.WithCancel(ctx)
+			running[key] = cancel
+			wg.Add(1)
+			go func(s channelSource) {
+				defer wg.Done()
+				r.runSource(sourceCtx, processor, s)
+			}(s)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-r.changed:
+		}
+	}
+}
+
+func (r *SourceRunner) runSource(ctx context.Context, processor InputProcessor, s channelSource) {
+	handle := func(ctx context.Context, body []byte) {
+		_, err := processor.ProcessInput(ctx, s.orgID, s.channel, body)
+		if err != nil {
+			logger.Error("Error processing source message", "error", err, "type", s.source.Type(), "orgId", s.orgID, "channel", s.channel)
+		}
+	}
+	for {
+		logger.Info("Starting source", "type", s.source.Type(), "orgId", s.orgID, "channel", s.channel)
+		err := s.source.Run(ctx, handle)
+		if ctx.Err() != nil {
+			logger.Info("Source stopped", "type", s.source.Type(), "orgId", s.orgID, "channel", s.channel)
+			return
+		}
+		logger.Error("Source failed, restarting", "error", err, "type", s.source.Type(), "orgId", s.orgID, "channel", s.channel)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(sourceRestartInterval):
+		}
+	}
+}
+
+// sourceKey returns a hash of the source configuration, so that credentials
+// are not kept in keys.
+func sourceKey(config ...interface{}) string {
+	b, _ := json.Marshal(config)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// buildSourceTLSConfig returns the TLS configuration to connect to brokers,
+// or nil if TLS is not enabled.
+func buildSourceTLSConfig(config *SourceTLSConfig) (*tls.Config, error) {
+	if config == nil {
+		return nil, nil
+	}
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         config.ServerName,
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+	if config.CACert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(config.CACert)) {
+			return nil, errors.New("failed to parse the TLS CA certificate")
+		}
+		cfg.RootCAs = pool
+	}
+	return cfg, nil
+}
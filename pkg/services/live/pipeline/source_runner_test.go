@@ -0,0 +1,180 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testRuleBuilder struct {
+	rules []*LiveChannelRule
+}
+
+func (b *testRuleBuilder) BuildRules(_ context.Context, _ int64) ([]*LiveChannelRule, error) {
+	return b.rules, nil
+}
+
+type testSource struct {
+	key     string
+	message []byte
+	started chan struct{}
+	stopped chan struct{}
+}
+
+func newTestSource(key string, message string) *testSource {
+	return &testSource{
+		key:     key,
+		message: []byte(message),
+		started: make(chan struct{}, 10),
+		stopped: make(chan struct{}, 10),
+	}
+}
+
+func (s *testSource) Type() string {
+	return "test"
+}
+
+func (s *testSource) Key() string {
+	return s.key
+}
+
+func (s *testSource) Run(ctx context.Context, handle SourceMessageHandler) error {
+	s.started <- struct{}{}
+	handle(ctx, s.message)
+	<-ctx.Done()
+	s.stopped <- struct{}{}
+	return nil
+}
+
+type testInputProcessor struct {
+	inputs chan string
+}
+
+func (p *testInputProcessor) ProcessInput(_ context.Context, _ int64, channelID string, body []byte) (bool, error) {
+	p.inputs <- channelID + ":" + string(body)
+	return true, nil
+}
+
+func waitFor(t *testing.T, ch chan struct{}) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+}
+
+func TestSourceRunner(t *testing.T) {
+	source := newTestSource("a", "temperature=10")
+	builder := &testRuleBuilder{rules: []*LiveChannelRule{
+		{OrgId: 1, Pattern: "stream/sensors/room", Sources: []Source{source}},
+	}}
+	runner := NewSourceRunner(builder)
+	processor := &testInputProcessor{inputs: make(chan string, 10)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- runner.Run(ctx, processor)
+	}()
+
+	_, err := runner.BuildRules(context.Background(), 1)
+	require.NoError(t, err)
+	waitFor(t, source.started)
+	require.Equal(t, "stream/sensors/room:temperature=10", <-processor.inputs)
+
+	// Rebuilding rules with the same source configuration keeps the source running.
+	builder.rules = []*LiveChannelRule{
+		{OrgId: 1, Pattern: "stream/sensors/room", Sources: []Source{newTestSource("a", "ignored")}},
+	}
+	_, err = runner.BuildRules(context.Background(), 1)
+	require.NoError(t, err)
+
+	// Changed configuration restarts the source.
+	changed := newTestSource("b", "temperature=20")
+	builder.rules = []*LiveChannelRule{
+		{OrgId: 1, Pattern: "stream/sensors/room", Sources: []Source{changed}},
+	}
+	_, err = runner.BuildRules(context.Background(), 1)
+	require.NoError(t, err)
+	waitFor(t, source.stopped)
+	waitFor(t, changed.started)
+	require.Equal(t, "stream/sensors/room:temperature=20", <-processor.inputs)
+	require.Len(t, source.started, 0)
+
+	// Removed sources are stopped.
+	builder.rules = nil
+	_, err = runner.BuildRules(context.Background(), 1)
+	require.NoError(t, err)
+	waitFor(t, changed.stopped)
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestStorageRuleBuilder_ExtractSource(t *testing.T) {
+	builder := &StorageRuleBuilder{}
+	basicAuth := &BasicAuth{User: "grafana", Password: "secret"}
+
+	testCases := []struct {
+		name   string
+		config SourceConfig
+		err    string
+	}{
+		{
+			name:   "mqtt",
+			config: SourceConfig{Type: SourceTypeMQTT, MQTTSourceConfig: &MQTTSourceConfig{URL: "tcp://localhost:1883", Topic: "sensors/#"}},
+		},
+		{
+			name:   "mqtt credentials over tls",
+			config: SourceConfig{Type: SourceTypeMQTT, BasicAuth: basicAuth, MQTTSourceConfig: &MQTTSourceConfig{URL: "ssl://localhost:8883", Topic: "sensors/#", TLS: &SourceTLSConfig{ServerName: "mqtt"}}},
+		},
+		{
+			name:   "mqtt credentials without tls",
+			config: SourceConfig{Type: SourceTypeMQTT, BasicAuth: basicAuth, MQTTSourceConfig: &MQTTSourceConfig{URL: "tcp://localhost:1883", Topic: "sensors/#"}},
+			err:    "only sent to a TLS broker url",
+		},
+		{
+			name:   "mqtt tls settings without tls url",
+			config: SourceConfig{Type: SourceTypeMQTT, MQTTSourceConfig: &MQTTSourceConfig{URL: "tcp://localhost:1883", Topic: "sensors/#", TLS: &SourceTLSConfig{}}},
+			err:    "tls settings require a TLS broker url",
+		},
+		{
+			name:   "mqtt without url",
+			config: SourceConfig{Type: SourceTypeMQTT, MQTTSourceConfig: &MQTTSourceConfig{Topic: "sensors/#"}},
+			err:    "missing configuration",
+		},
+		{
+			name:   "kafka credentials over tls",
+			config: SourceConfig{Type: SourceTypeKafka, BasicAuth: basicAuth, KafkaSourceConfig: &KafkaSourceConfig{Brokers: []string{"localhost:9093"}, Topic: "sensors", GroupID: "grafana", TLS: &SourceTLSConfig{}}},
+		},
+		{
+			name:   "kafka credentials without tls",
+			config: SourceConfig{Type: SourceTypeKafka, BasicAuth: basicAuth, KafkaSourceConfig: &KafkaSourceConfig{Brokers: []string{"localhost:9092"}, Topic: "sensors", GroupID: "grafana"}},
+			err:    "require tls settings",
+		},
+		{
+			name:   "kafka invalid ca certificate",
+			config: SourceConfig{Type: SourceTypeKafka, KafkaSourceConfig: &KafkaSourceConfig{Brokers: []string{"localhost:9093"}, Topic: "sensors", GroupID: "grafana", TLS: &SourceTLSConfig{CACert: "invalid"}}},
+			err:    "CA certificate",
+		},
+		{
+			name:   "kafka without brokers",
+			config: SourceConfig{Type: SourceTypeKafka, KafkaSourceConfig: &KafkaSourceConfig{Topic: "sensors", GroupID: "grafana"}},
+			err:    "missing configuration",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			source, err := builder.extractSource(&tc.config)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.config.Type, source.Type())
+		})
+	}
+}
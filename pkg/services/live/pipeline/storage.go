@@ -1,6 +1,11 @@
 package pipeline
 
-import "context"
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/secrets"
+)
 
 // Storage describes all methods to manage Live pipeline persistent data.
 type Storage interface {
@@ -14,3 +19,42 @@ type Storage interface {
 	UpdateChannelRule(_ context.Context, orgID int64, cmd ChannelRuleUpdateCmd) (ChannelRule, error)
 	DeleteChannelRule(_ context.Context, orgID int64, cmd ChannelRuleDeleteCmd) error
 }
+
+// encryptSourceSecrets returns settings with plain text passwords of sources
+// encrypted into their secure settings. A source without a new password keeps
+// the secure settings of the source at the same position of the existing rule,
+// so clients don't have to send passwords again on every update.
+func encryptSourceSecrets(ctx context.Context, secretsService secrets.Service, settings ChannelRuleSettings, existing *ChannelRule) (ChannelRuleSettings, error) {
+	if len(settings.Sources) == 0 {
+		return settings, nil
+	}
+	sources := make([]*SourceConfig, 0, len(settings.Sources))
+	for i, source := range settings.Sources {
+		if source == nil {
+			sources = append(sources, nil)
+			continue
+		}
+		s := *source
+		// Secure settings are never taken from clients.
+		s.SecureSettings = nil
+		if s.BasicAuth != nil {
+			basicAuth := *s.BasicAuth
+			s.BasicAuth = &basicAuth
+			if basicAuth.Password != "" {
+				encrypted, err := secretsService.Encrypt(ctx, []byte(basicAuth.Password), secrets.WithoutScope())
+				if err != nil {
+					return settings, fmt.Errorf("error encrypting source password: %w", err)
+				}
+				s.SecureSettings = map[string][]byte{"basicAuthPassword": encrypted}
+				s.BasicAuth.Password = ""
+			} else if existing != nil && i < len(existing.Settings.Sources) {
+				if existingSource := existing.Settings.Sources[i]; existingSource != nil && existingSource.Type == s.Type {
+					s.SecureSettings = existingSource.SecureSettings
+				}
+			}
+		}
+		sources = append(sources, &s)
+	}
+	settings.Sources = sources
+	return settings, nil
+}
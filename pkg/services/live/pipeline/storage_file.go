@@ -158,16 +158,20 @@ func (f *FileStorage) ListChannelRules(_ context.Context, orgID int64) ([]Channe
 	return rules, nil
 }
 
-func (f *FileStorage) CreateChannelRule(_ context.Context, orgID int64, cmd ChannelRuleCreateCmd) (ChannelRule, error) {
+func (f *FileStorage) CreateChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleCreateCmd) (ChannelRule, error) {
 	channelRules, err := f.readRules()
 	if err != nil {
 		return ChannelRule{}, fmt.Errorf("can't read channel rules: %w", err)
 	}
 
+	settings, err := encryptSourceSecrets(ctx, f.SecretsService, cmd.Settings, nil)
+	if err != nil {
+		return ChannelRule{}, err
+	}
 	rule := ChannelRule{
 		OrgId:    orgID,
 		Pattern:  cmd.Pattern,
-		Settings: cmd.Settings,
+		Settings: settings,
 	}
 
 	ok, reason := rule.Valid()
@@ -198,31 +202,34 @@ func (f *FileStorage) UpdateChannelRule(ctx context.Context, orgID int64, cmd Ch
 		return ChannelRule{}, fmt.Errorf("can't read channel rules: %w", err)
 	}
 
-	rule := ChannelRule{
-		OrgId:    orgID,
-		Pattern:  cmd.Pattern,
-		Settings: cmd.Settings,
-	}
-
-	ok, reason := rule.Valid()
-	if !ok {
-		return rule, fmt.Errorf("invalid channel rule: %s", reason)
-	}
-
 	index := -1
 
 	for i, existingRule := range channelRules.Rules {
-		if patternMatch(orgID, rule.Pattern, existingRule) {
+		if patternMatch(orgID, cmd.Pattern, existingRule) {
 			index = i
 			break
 		}
 	}
-	if index > -1 {
-		channelRules.Rules[index] = rule
-	} else {
+	if index == -1 {
 		return f.CreateChannelRule(ctx, orgID, ChannelRuleCreateCmd(cmd))
 	}
 
+	settings, err := encryptSourceSecrets(ctx, f.SecretsService, cmd.Settings, &channelRules.Rules[index])
+	if err != nil {
+		return ChannelRule{}, err
+	}
+	rule := ChannelRule{
+		OrgId:    orgID,
+		Pattern:  cmd.Pattern,
+		Settings: settings,
+	}
+
+	ok, reason := rule.Valid()
+	if !ok {
+		return rule, fmt.Errorf("invalid channel rule: %s", reason)
+	}
+	channelRules.Rules[index] = rule
+
 	err = f.saveChannelRules(orgID, channelRules)
 	return rule, err
 }
@@ -0,0 +1,311 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/sqlstore/db"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// SQLStorage keeps channel rules and write configs in the Grafana database, so
+// all Grafana servers of an HA setup share the same pipeline configuration.
+type SQLStorage struct {
+	SQLStore       db.DB
+	SecretsService secrets.Service
+}
+
+type channelRuleRecord struct {
+	Id       int64
+	OrgId    int64
+	Pattern  string
+	Settings string
+	Created  time.Time
+	Updated  time.Time
+}
+
+func (channelRuleRecord) TableName() string {
+	return "live_pipeline_channel_rule"
+}
+
+type writeConfigRecord struct {
+	Id             int64
+	OrgId          int64
+	Uid            string
+	Settings       string
+	SecureSettings string
+	Created        time.Time
+	Updated        time.Time
+}
+
+func (writeConfigRecord) TableName() string {
+	return "live_pipeline_write_config"
+}
+
+func (r writeConfigRecord) toWriteConfig() (WriteConfig, error) {
+	writeConfig := WriteConfig{
+		OrgId: r.OrgId,
+		UID:   r.Uid,
+	}
+	if err := json.Unmarshal([]byte(r.Settings), &writeConfig.Settings); err != nil {
+		return WriteConfig{}, fmt.Errorf("can't unmarshal settings of write config %s: %w", r.Uid, err)
+	}
+	if err := json.Unmarshal([]byte(r.SecureSettings), &writeConfig.SecureSettings); err != nil {
+		return WriteConfig{}, fmt.Errorf("can't unmarshal secure settings of write config %s: %w", r.Uid, err)
+	}
+	return writeConfig, nil
+}
+
+func (s *SQLStorage) ListWriteConfigs(ctx context.Context, orgID int64) ([]WriteConfig, error) {
+	var records []writeConfigRecord
+	err := s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		return sess.Where("org_id = ?", orgID).Asc("uid").Find(&records)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't read write configs: %w", err)
+	}
+	writeConfigs := make([]WriteConfig, 0, len(records))
+	for _, r := range records {
+		writeConfig, err := r.toWriteConfig()
+		if err != nil {
+			return nil, err
+		}
+		writeConfigs = append(writeConfigs, writeConfig)
+	}
+	return writeConfigs, nil
+}
+
+func (s *SQLStorage) GetWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigGetCmd) (WriteConfig, bool, error) {
+	var record writeConfigRecord
+	var exists bool
+	err := s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		var err error
+		exists, err = sess.Where("org_id = ? AND uid = ?", orgID, cmd.UID).Get(&record)
+		return err
+	})
+	if err != nil {
+		return WriteConfig{}, false, fmt.Errorf("can't read write config: %w", err)
+	}
+	if !exists {
+		return WriteConfig{}, false, nil
+	}
+	writeConfig, err := record.toWriteConfig()
+	return writeConfig, err == nil, err
+}
+
+func (s *SQLStorage) CreateWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigCreateCmd) (WriteConfig, error) {
+	if cmd.UID == "" {
+		cmd.UID = util.GenerateShortUID()
+	}
+	return s.saveWriteConfig(ctx, orgID, cmd.UID, cmd.Settings, cmd.SecureSettings, false)
+}
+
+func (s *SQLStorage) UpdateWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigUpdateCmd) (WriteConfig, error) {
+	return s.saveWriteConfig(ctx, orgID, cmd.UID, cmd.Settings, cmd.SecureSettings, true)
+}
+
+// saveWriteConfig inserts a write config, or replaces an existing one if update is allowed.
+func (s *SQLStorage) saveWriteConfig(ctx context.Context, orgID int64, uid string, settings WriteSettings, secureSettings map[string]string, update bool) (WriteConfig, error) {
+	encryptedSettings, err := s.SecretsService.EncryptJsonData(ctx, secureSettings, secrets.WithoutScope())
+	if err != nil {
+		return WriteConfig{}, fmt.Errorf("error encrypting data: %w", err)
+	}
+
+	writeConfig := WriteConfig{
+		OrgId:          orgID,
+		UID:            uid,
+		Settings:       settings,
+		SecureSettings: encryptedSettings,
+	}
+	ok, reason := writeConfig.Valid()
+	if !ok {
+		return WriteConfig{}, fmt.Errorf("invalid write config: %s", reason)
+	}
+
+	settingsJSON, err := json.Marshal(writeConfig.Settings)
+	if err != nil {
+		return WriteConfig{}, err
+	}
+	secureSettingsJSON, err := json.Marshal(writeConfig.SecureSettings)
+	if err != nil {
+		return WriteConfig{}, err
+	}
+
+	err = s.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		var existing writeConfigRecord
+		exists, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Get(&existing)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		record := writeConfigRecord{
+			OrgId:          orgID,
+			Uid:            uid,
+			Settings:       string(settingsJSON),
+			SecureSettings: string(secureSettingsJSON),
+			Updated:        now,
+		}
+		if !exists {
+			record.Created = now
+			_, err = sess.Insert(&record)
+			return err
+		}
+		if !update {
+			return fmt.Errorf("backend already exists in org: %s", uid)
+		}
+		_, err = sess.ID(existing.Id).Cols("settings", "secure_settings", "updated").Update(&record)
+		return err
+	})
+	if err != nil {
+		return WriteConfig{}, err
+	}
+	return writeConfig, nil
+}
+
+func (s *SQLStorage) DeleteWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigDeleteCmd) error {
+	return s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		affected, err := sess.Where("org_id = ? AND uid = ?", orgID, cmd.UID).Delete(&writeConfigRecord{})
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return errors.New("write config not found")
+		}
+		return nil
+	})
+}
+
+func (s *SQLStorage) ListChannelRules(ctx context.Context, orgID int64) ([]ChannelRule, error) {
+	var rules []ChannelRule
+	err := s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		var err error
+		rules, err = listChannelRules(sess, orgID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't read channel rules: %w", err)
+	}
+	return rules, nil
+}
+
+func listChannelRules(sess *sqlstore.DBSession, orgID int64) ([]ChannelRule, error) {
+	var records []channelRuleRecord
+	if err := sess.Where("org_id = ?", orgID).Asc("pattern").Find(&records); err != nil {
+		return nil, err
+	}
+	rules := make([]ChannelRule, 0, len(records))
+	for _, r := range records {
+		rule := ChannelRule{
+			OrgId:   r.OrgId,
+			Pattern: r.Pattern,
+		}
+		if err := json.Unmarshal([]byte(r.Settings), &rule.Settings); err != nil {
+			return nil, fmt.Errorf("can't unmarshal settings of channel rule %s: %w", r.Pattern, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (s *SQLStorage) CreateChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleCreateCmd) (ChannelRule, error) {
+	return s.saveChannelRule(ctx, orgID, cmd.Pattern, cmd.Settings, false)
+}
+
+func (s *SQLStorage) UpdateChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleUpdateCmd) (ChannelRule, error) {
+	return s.saveChannelRule(ctx, orgID, cmd.Pattern, cmd.Settings, true)
+}
+
+// saveChannelRule inserts a channel rule, or replaces an existing one if update is allowed.
+func (s *SQLStorage) saveChannelRule(ctx context.Context, orgID int64, pattern string, settings ChannelRuleSettings, update bool) (ChannelRule, error) {
+	var existing *ChannelRule
+	if update && len(settings.Sources) > 0 {
+		// Secrets are encrypted outside of the transaction, encryption may use the database.
+		rules, err := s.ListChannelRules(ctx, orgID)
+		if err != nil {
+			return ChannelRule{}, err
+		}
+		for i := range rules {
+			if rules[i].Pattern == pattern {
+				existing = &rules[i]
+			}
+		}
+	}
+	settings, err := encryptSourceSecrets(ctx, s.SecretsService, settings, existing)
+	if err != nil {
+		return ChannelRule{}, err
+	}
+	rule := ChannelRule{
+		OrgId:    orgID,
+		Pattern:  pattern,
+		Settings: settings,
+	}
+	ok, reason := rule.Valid()
+	if !ok {
+		return rule, fmt.Errorf("invalid channel rule: %s", reason)
+	}
+	settingsJSON, err := json.Marshal(rule.Settings)
+	if err != nil {
+		return rule, err
+	}
+
+	err = s.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		rules, err := listChannelRules(sess, orgID)
+		if err != nil {
+			return err
+		}
+		exists := false
+		for _, existingRule := range rules {
+			if existingRule.Pattern == pattern {
+				exists = true
+				break
+			}
+		}
+		if exists && !update {
+			return fmt.Errorf("pattern already exists in org: %s", pattern)
+		}
+		if !exists {
+			// New patterns must not conflict with patterns of existing rules.
+			ok, reason := checkRulesValid(orgID, append(rules, rule))
+			if !ok {
+				return errors.New(reason)
+			}
+		}
+
+		now := time.Now()
+		record := channelRuleRecord{
+			OrgId:    orgID,
+			Pattern:  pattern,
+			Settings: string(settingsJSON),
+			Updated:  now,
+		}
+		if !exists {
+			record.Created = now
+			_, err = sess.Insert(&record)
+			return err
+		}
+		_, err = sess.Where("org_id = ? AND pattern = ?", orgID, pattern).Cols("settings", "updated").Update(&record)
+		return err
+	})
+	if err != nil {
+		return rule, err
+	}
+	return rule, nil
+}
+
+func (s *SQLStorage) DeleteChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleDeleteCmd) error {
+	return s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		affected, err := sess.Where("org_id = ? AND pattern = ?", orgID, cmd.Pattern).Delete(&channelRuleRecord{})
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return errors.New("rule not found")
+		}
+		return nil
+	})
+}
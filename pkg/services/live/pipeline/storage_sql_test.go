@@ -0,0 +1,176 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+func setupTestSQLStorage(t *testing.T) *SQLStorage {
+	t.Helper()
+	return &SQLStorage{
+		SQLStore:       sqlstore.InitTestDB(t),
+		SecretsService: secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore()),
+	}
+}
+
+func TestIntegrationSQLStorage_ChannelRules(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	storage := setupTestSQLStorage(t)
+
+	rules, err := storage.ListChannelRules(ctx, 1)
+	require.NoError(t, err)
+	require.Empty(t, rules)
+
+	settings := ChannelRuleSettings{
+		Converter: &ConverterConfig{Type: ConverterTypeJsonAuto},
+	}
+	rule, err := storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/sensors/:id", Settings: settings})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rule.OrgId)
+
+	_, err = storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/sensors/:id", Settings: settings})
+	require.ErrorContains(t, err, "pattern already exists")
+	_, err = storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/sensors/:name", Settings: settings})
+	require.Error(t, err, "conflicting patterns must be rejected")
+	_, err = storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/sensors/:id", Settings: ChannelRuleSettings{
+		Converter: &ConverterConfig{Type: "unknown"},
+	}})
+	require.ErrorContains(t, err, "invalid channel rule")
+
+	// Rules of other organizations are independent.
+	_, err = storage.CreateChannelRule(ctx, 2, ChannelRuleCreateCmd{Pattern: "stream/sensors/:name", Settings: settings})
+	require.NoError(t, err)
+
+	_, err = storage.UpdateChannelRule(ctx, 1, ChannelRuleUpdateCmd{Pattern: "stream/sensors/:id", Settings: ChannelRuleSettings{
+		Converter: &ConverterConfig{Type: ConverterTypeInfluxAuto, AutoInfluxConverterConfig: &AutoInfluxConverterConfig{FrameFormat: "wide"}},
+	}})
+	require.NoError(t, err)
+	// Update creates missing rules.
+	_, err = storage.UpdateChannelRule(ctx, 1, ChannelRuleUpdateCmd{Pattern: "stream/cpu", Settings: settings})
+	require.NoError(t, err)
+
+	rules, err = storage.ListChannelRules(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	require.Equal(t, "stream/cpu", rules[0].Pattern)
+	require.Equal(t, "stream/sensors/:id", rules[1].Pattern)
+	require.Equal(t, "wide", rules[1].Settings.Converter.AutoInfluxConverterConfig.FrameFormat)
+
+	require.NoError(t, storage.DeleteChannelRule(ctx, 1, ChannelRuleDeleteCmd{Pattern: "stream/cpu"}))
+	require.Error(t, storage.DeleteChannelRule(ctx, 1, ChannelRuleDeleteCmd{Pattern: "stream/cpu"}))
+	rules, err = storage.ListChannelRules(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+}
+
+func TestIntegrationSQLStorage_WriteConfigs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	storage := setupTestSQLStorage(t)
+
+	_, err := storage.CreateWriteConfig(ctx, 1, WriteConfigCreateCmd{UID: "loki"})
+	require.ErrorContains(t, err, "endpoint required")
+
+	created, err := storage.CreateWriteConfig(ctx, 1, WriteConfigCreateCmd{
+		UID:            "loki",
+		Settings:       WriteSettings{Endpoint: "http://localhost:3100", BasicAuth: &BasicAuth{User: "admin"}},
+		SecureSettings: map[string]string{"basicAuthPassword": "secret"},
+	})
+	require.NoError(t, err)
+	_, err = storage.CreateWriteConfig(ctx, 1, WriteConfigCreateCmd{UID: "loki", Settings: WriteSettings{Endpoint: "http://localhost:3100"}})
+	require.ErrorContains(t, err, "already exists")
+
+	writeConfig, ok, err := storage.GetWriteConfig(ctx, 1, WriteConfigGetCmd{UID: "loki"})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, created, writeConfig)
+	require.Equal(t, "admin", writeConfig.Settings.BasicAuth.User)
+	password, err := storage.SecretsService.Decrypt(ctx, writeConfig.SecureSettings["basicAuthPassword"])
+	require.NoError(t, err)
+	require.Equal(t, "secret", string(password))
+
+	_, ok, err = storage.GetWriteConfig(ctx, 2, WriteConfigGetCmd{UID: "loki"})
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, err = storage.UpdateWriteConfig(ctx, 1, WriteConfigUpdateCmd{UID: "loki", Settings: WriteSettings{Endpoint: "http://loki:3100"}})
+	require.NoError(t, err)
+	generated, err := storage.CreateWriteConfig(ctx, 1, WriteConfigCreateCmd{Settings: WriteSettings{Endpoint: "tcp://localhost:1883"}})
+	require.NoError(t, err)
+	require.NotEmpty(t, generated.UID)
+
+	writeConfigs, err := storage.ListWriteConfigs(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, writeConfigs, 2)
+	writeConfig, _, err = storage.GetWriteConfig(ctx, 1, WriteConfigGetCmd{UID: "loki"})
+	require.NoError(t, err)
+	require.Equal(t, "http://loki:3100", writeConfig.Settings.Endpoint)
+	require.Empty(t, writeConfig.SecureSettings)
+
+	require.NoError(t, storage.DeleteWriteConfig(ctx, 1, WriteConfigDeleteCmd{UID: "loki"}))
+	require.Error(t, storage.DeleteWriteConfig(ctx, 1, WriteConfigDeleteCmd{UID: "loki"}))
+	writeConfigs, err = storage.ListWriteConfigs(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, writeConfigs, 1)
+}
+
+func TestIntegrationSQLStorage_ChannelRuleSourceSecrets(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	storage := setupTestSQLStorage(t)
+
+	source := func(password string) ChannelRuleSettings {
+		return ChannelRuleSettings{Sources: []*SourceConfig{{
+			Type:             SourceTypeMQTT,
+			BasicAuth:        &BasicAuth{User: "grafana", Password: password},
+			SecureSettings:   map[string][]byte{"basicAuthPassword": []byte("not from clients")},
+			MQTTSourceConfig: &MQTTSourceConfig{URL: "ssl://localhost:8883", Topic: "sensors/#"},
+		}}}
+	}
+	passwordOf := func() string {
+		rules, err := storage.ListChannelRules(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+		s := rules[0].Settings.Sources[0]
+		require.Equal(t, "grafana", s.BasicAuth.User)
+		require.Empty(t, s.BasicAuth.Password)
+		if len(s.SecureSettings["basicAuthPassword"]) == 0 {
+			return ""
+		}
+		password, err := storage.SecretsService.Decrypt(ctx, s.SecureSettings["basicAuthPassword"])
+		require.NoError(t, err)
+		return string(password)
+	}
+
+	rule, err := storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/sensors/fleet", Settings: source("secret")})
+	require.NoError(t, err)
+	require.Empty(t, rule.Settings.Sources[0].BasicAuth.Password)
+	require.Equal(t, "secret", passwordOf())
+
+	// The stored password is kept when an update doesn't set it.
+	_, err = storage.UpdateChannelRule(ctx, 1, ChannelRuleUpdateCmd{Pattern: "stream/sensors/fleet", Settings: source("")})
+	require.NoError(t, err)
+	require.Equal(t, "secret", passwordOf())
+
+	_, err = storage.UpdateChannelRule(ctx, 1, ChannelRuleUpdateCmd{Pattern: "stream/sensors/fleet", Settings: source("changed")})
+	require.NoError(t, err)
+	require.Equal(t, "changed", passwordOf())
+
+	// Secure settings are not returned to clients.
+	dto := ChannelRuleToDto(rule)
+	require.Equal(t, map[string][]byte{"basicAuthPassword": nil}, dto.Settings.Sources[0].SecureSettings)
+	require.NotEmpty(t, rule.Settings.Sources[0].SecureSettings["basicAuthPassword"])
+}
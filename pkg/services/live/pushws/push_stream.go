@@ -76,17 +76,19 @@ func (s *Handler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 		// TODO Grafana 8: decide which formats to use or keep all.
 		urlValues := r.URL.Query()
 		frameFormat := pushurl.FrameFormatFromValues(urlValues)
+		inputFormat := pushurl.InputFormatFromValues(urlValues)
 
 		logger.Debug("Live Push request",
 			"protocol", "http",
 			"streamId", streamID,
 			"bodyLength", len(body),
 			"frameFormat", frameFormat,
+			"inputFormat", inputFormat,
 		)
 
-		metricFrames, err := s.converter.Convert(body, frameFormat)
+		metricFrames, err := s.converter.ConvertInput(body, inputFormat, frameFormat, r.Header.Get("Content-Type"))
 		if err != nil {
-			logger.Error("Error converting metrics", "error", err, "frameFormat", frameFormat)
+			logger.Error("Error converting metrics", "error", err, "frameFormat", frameFormat, "inputFormat", inputFormat)
 			continue
 		}
 
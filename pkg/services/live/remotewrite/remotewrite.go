@@ -3,6 +3,7 @@ package remotewrite
 import (
 	"fmt"
 	"hash/fnv"
+	"sort"
 	"strings"
 	"time"
 
@@ -190,6 +191,64 @@ func TimeSeriesFromFramesLabelsColumn(frames ...*data.Frame) []prompb.TimeSeries
 	return promTimeSeriesBatch
 }
 
+// TimeSeriesFromInstantFrames converts frames to slice of Prometheus TimeSeries with a single
+// sample at time t named metricName. The last non-null value of every numeric field is used,
+// so the function is suitable for results of expressions that reduce data to a single value.
+// Labels of a field are merged with extraLabels, the latter take precedence.
+func TimeSeriesFromInstantFrames(metricName string, t time.Time, extraLabels map[string]string, frames ...*data.Frame) []prompb.TimeSeries {
+	var promTimeSeriesBatch []prompb.TimeSeries
+
+	for _, frame := range frames {
+		for _, field := range frame.Fields {
+			if !field.Type().Numeric() {
+				continue
+			}
+			var value float64
+			var found bool
+			for i := field.Len() - 1; i >= 0; i-- {
+				val, ok := field.ConcreteAt(i)
+				if !ok {
+					continue
+				}
+				value, found = sampleValue(val)
+				if found {
+					break
+				}
+			}
+			if !found {
+				continue
+			}
+
+			fieldLabels := make(map[string]string, len(field.Labels)+len(extraLabels))
+			for k, v := range field.Labels {
+				fieldLabels[k] = v
+			}
+			for k, v := range extraLabels {
+				fieldLabels[k] = v
+			}
+			labels := createLabels(fieldLabels)
+			labels = append(labels, prompb.Label{
+				Name:  "__name__",
+				Value: metricName,
+			})
+			sort.Slice(labels, func(i, j int) bool {
+				return labels[i].Name < labels[j].Name
+			})
+
+			promTimeSeriesBatch = append(promTimeSeriesBatch, prompb.TimeSeries{
+				Labels: labels,
+				Samples: []prompb.Sample{{
+					// Timestamp is int milliseconds for remote write.
+					Timestamp: toSampleTime(t),
+					Value:     value,
+				}},
+			})
+		}
+	}
+
+	return promTimeSeriesBatch
+}
+
 func timeFieldIndex(frame *data.Frame) (int, bool) {
 	timeFieldIndex := -1
 	for i, field := range frame.Fields {
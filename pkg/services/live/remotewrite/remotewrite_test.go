@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/prompb"
 	"github.com/stretchr/testify/require"
 )
 
@@ -85,3 +86,35 @@ func TestSerialize(t *testing.T) {
 	_, err := Serialize(frame)
 	require.NoError(t, err)
 }
+
+func TestTsFromInstantFrames(t *testing.T) {
+	now := time.Now()
+	var nullValue *float64
+	last := 3.0
+	frame1 := data.NewFrame("",
+		data.NewField("B", map[string]string{"instance": "a"}, []*float64{&last, nullValue}),
+	)
+	frame2 := data.NewFrame("",
+		data.NewField("B", map[string]string{"instance": "b", "team": "x"}, []float64{5.0}),
+		data.NewField("text", nil, []string{"ignored"}),
+	)
+	ts := TimeSeriesFromInstantFrames("test_metric", now, map[string]string{"team": "y"}, frame1, frame2)
+	require.Len(t, ts, 2)
+
+	require.Len(t, ts[0].Samples, 1)
+	require.Equal(t, toSampleTime(now), ts[0].Samples[0].Timestamp)
+	require.Equal(t, 3.0, ts[0].Samples[0].Value)
+	require.Equal(t, []prompb.Label{
+		{Name: "__name__", Value: "test_metric"},
+		{Name: "instance", Value: "a"},
+		{Name: "team", Value: "y"},
+	}, ts[0].Labels)
+
+	require.Len(t, ts[1].Samples, 1)
+	require.Equal(t, 5.0, ts[1].Samples[0].Value)
+	require.Equal(t, []prompb.Label{
+		{Name: "__name__", Value: "test_metric"},
+		{Name: "instance", Value: "b"},
+		{Name: "team", Value: "y"},
+	}, ts[1].Labels)
+}
@@ -7,6 +7,11 @@ type Converter interface {
 	Convert(data []byte) ([]FrameWrapper, error)
 }
 
+// ContentTypeConverter can convert input encoded according to its content type.
+type ContentTypeConverter interface {
+	ConvertContentType(data []byte, contentType string) ([]FrameWrapper, error)
+}
+
 // FrameWrapper is a wrapper over data.Frame.
 type FrameWrapper interface {
 	// Key returns a key which describes Frame metrics.
@@ -0,0 +1,268 @@
+package otlp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"mime"
+	"strconv"
+	"time"
+
+	influx "github.com/influxdata/line-protocol"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	metricsv1 "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/grafana/grafana/pkg/services/live/telemetry"
+	"github.com/grafana/grafana/pkg/services/live/telemetry/telegraf"
+)
+
+var _ telemetry.Converter = (*Converter)(nil)
+var _ telemetry.ContentTypeConverter = (*Converter)(nil)
+
+// Converter converts OTLP metrics export requests to Grafana frames.
+type Converter struct {
+	converter *telegraf.Converter
+	now       func() time.Time
+}
+
+// maxDecompressedSize is the maximum size of a gzip compressed request once decompressed.
+const maxDecompressedSize = 32 << 20
+
+// NewConverter creates new Converter from OTLP/HTTP metrics to Grafana Data Frames.
+// Both protobuf and JSON encoded requests are accepted, and they can be gzip compressed
+// as senders do when they set Content-Encoding: gzip. Metrics are mapped the same
+// way Telegraf maps metrics in Prometheus format, so a gauge has a "gauge" field,
+// a monotonic sum has a "counter" field, and histograms and summaries have "count",
+// "sum" and a field per bucket upper bound or quantile.
+func NewConverter(opts ...telegraf.ConverterOption) *Converter {
+	return &Converter{
+		converter: telegraf.NewConverter(opts...),
+		now:       time.Now,
+	}
+}
+
+// Content types of OTLP/HTTP requests.
+const (
+	ContentTypeProtobuf = "application/x-protobuf"
+	ContentTypeJSON     = "application/json"
+)
+
+// Convert metrics. The encoding is detected from the body, use ConvertContentType
+// when the content type of the request is known.
+func (c *Converter) Convert(body []byte) ([]telemetry.FrameWrapper, error) {
+	return c.ConvertContentType(body, "")
+}
+
+// ConvertContentType converts metrics encoded as the content type of the request
+// tells, application/x-protobuf or application/json. The encoding is detected from
+// the body if the content type is empty or neither of them.
+func (c *Converter) ConvertContentType(body []byte, contentType string) ([]telemetry.FrameWrapper, error) {
+	if isGzip(body) {
+		decompressed, err := gunzip(body)
+		if err != nil {
+			return nil, fmt.Errorf("error decompressing metrics: %w", err)
+		}
+		body = decompressed
+	}
+
+	req := &collectormetrics.ExportMetricsServiceRequest{}
+	var err error
+	if decodeJSON(body, contentType) {
+		err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(body, req)
+	} else {
+		err = proto.Unmarshal(body, req)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing metrics: %w", err)
+	}
+
+	now := c.now()
+	var metrics []influx.Metric
+	for _, rm := range req.GetResourceMetrics() {
+		resourceTags := attributesToTags(nil, rm.GetResource().GetAttributes())
+		for _, sm := range rm.GetScopeMetrics() {
+			for _, m := range sm.GetMetrics() {
+				converted, err := convertMetric(m, resourceTags, now)
+				if err != nil {
+					return nil, err
+				}
+				metrics = append(metrics, converted...)
+			}
+		}
+		// Senders that still use OTLP before 0.15 send instrumentation library metrics instead of scope metrics.
+		for _, ilm := range rm.GetInstrumentationLibraryMetrics() { // nolint:staticcheck
+			for _, m := range ilm.GetMetrics() {
+				converted, err := convertMetric(m, resourceTags, now)
+				if err != nil {
+					return nil, err
+				}
+				metrics = append(metrics, converted...)
+			}
+		}
+	}
+	return c.converter.ConvertMetrics(metrics)
+}
+
+// isGzip reports whether the body is gzip compressed. Neither a protobuf nor a JSON
+// encoded request can start with the gzip magic number.
+func isGzip(body []byte) bool {
+	return len(body) >= 2 && body[0] == 0x1f && body[1] == 0x8b
+}
+
+func gunzip(body []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+	decompressed, err := io.ReadAll(io.LimitReader(r, maxDecompressedSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(decompressed) > maxDecompressedSize {
+		return nil, fmt.Errorf("decompressed request exceeds %d bytes", maxDecompressedSize)
+	}
+	return decompressed, nil
+}
+
+// decodeJSON reports whether the body must be decoded as JSON. Without a known
+// content type, the body is JSON if it starts with '{', as a protobuf encoded request
+// starts with the tag of the resource_metrics field. Leading whitespace is not
+// skipped, because the tag is '\n' and the next byte is the length of the message.
+func decodeJSON(body []byte, contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil {
+		switch mediaType {
+		case ContentTypeJSON:
+			return true
+		case ContentTypeProtobuf:
+			return false
+		}
+	}
+	return len(body) > 0 && body[0] == '{'
+}
+
+func convertMetric(m *metricsv1.Metric, resourceTags map[string]string, now time.Time) ([]influx.Metric, error) {
+	var result []influx.Metric
+	add := func(attributes []*commonv1.KeyValue, timeUnixNano uint64, fields map[string]interface{}) error {
+		t := now
+		if timeUnixNano != 0 {
+			t = time.Unix(0, int64(timeUnixNano))
+		}
+		metric, err := influx.New(m.GetName(), attributesToTags(resourceTags, attributes), fields, t)
+		if err != nil {
+			return err
+		}
+		result = append(result, metric)
+		return nil
+	}
+
+	switch data := m.GetData().(type) {
+	case *metricsv1.Metric_Gauge:
+		for _, dp := range data.Gauge.GetDataPoints() {
+			if err := add(dp.GetAttributes(), dp.GetTimeUnixNano(), map[string]interface{}{"gauge": numberValue(dp)}); err != nil {
+				return nil, err
+			}
+		}
+	case *metricsv1.Metric_Sum:
+		field := "gauge"
+		if data.Sum.GetIsMonotonic() {
+			field = "counter"
+		}
+		for _, dp := range data.Sum.GetDataPoints() {
+			if err := add(dp.GetAttributes(), dp.GetTimeUnixNano(), map[string]interface{}{field: numberValue(dp)}); err != nil {
+				return nil, err
+			}
+		}
+	case *metricsv1.Metric_Histogram:
+		for _, dp := range data.Histogram.GetDataPoints() {
+			fields := map[string]interface{}{
+				"count": float64(dp.GetCount()),
+			}
+			// Sum is optional for histograms, e.g. when observations can be negative.
+			if dp.Sum != nil {
+				fields["sum"] = dp.GetSum()
+			}
+			// OTLP bucket counts are not cumulative, unlike Prometheus buckets.
+			var cumulative uint64
+			bounds := dp.GetExplicitBounds()
+			for i, count := range dp.GetBucketCounts() {
+				cumulative += count
+				bound := "+Inf"
+				if i < len(bounds) {
+					bound = strconv.FormatFloat(bounds[i], 'g', -1, 64)
+				}
+				fields[bound] = float64(cumulative)
+			}
+			if err := add(dp.GetAttributes(), dp.GetTimeUnixNano(), fields); err != nil {
+				return nil, err
+			}
+		}
+	case *metricsv1.Metric_ExponentialHistogram:
+		// Exponential buckets do not have fixed upper bounds, only count and sum are kept.
+		for _, dp := range data.ExponentialHistogram.GetDataPoints() {
+			fields := map[string]interface{}{
+				"count": float64(dp.GetCount()),
+				"sum":   dp.GetSum(),
+			}
+			if err := add(dp.GetAttributes(), dp.GetTimeUnixNano(), fields); err != nil {
+				return nil, err
+			}
+		}
+	case *metricsv1.Metric_Summary:
+		for _, dp := range data.Summary.GetDataPoints() {
+			fields := map[string]interface{}{
+				"count": float64(dp.GetCount()),
+				"sum":   dp.GetSum(),
+			}
+			for _, q := range dp.GetQuantileValues() {
+				fields[strconv.FormatFloat(q.GetQuantile(), 'g', -1, 64)] = q.GetValue()
+			}
+			if err := add(dp.GetAttributes(), dp.GetTimeUnixNano(), fields); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return result, nil
+}
+
+func numberValue(dp *metricsv1.NumberDataPoint) float64 {
+	if v, ok := dp.GetValue().(*metricsv1.NumberDataPoint_AsInt); ok {
+		return float64(v.AsInt)
+	}
+	return dp.GetAsDouble()
+}
+
+// attributesToTags copies the base tags and adds the attributes to them. Attributes of
+// a data point take precedence over the attributes of the resource.
+func attributesToTags(base map[string]string, attributes []*commonv1.KeyValue) map[string]string {
+	tags := make(map[string]string, len(base)+len(attributes))
+	for k, v := range base {
+		tags[k] = v
+	}
+	for _, kv := range attributes {
+		if value, ok := attributeValueToString(kv.GetValue()); ok {
+			tags[kv.GetKey()] = value
+		}
+	}
+	return tags
+}
+
+func attributeValueToString(v *commonv1.AnyValue) (string, bool) {
+	switch value := v.GetValue().(type) {
+	case *commonv1.AnyValue_StringValue:
+		return value.StringValue, true
+	case *commonv1.AnyValue_BoolValue:
+		return strconv.FormatBool(value.BoolValue), true
+	case *commonv1.AnyValue_IntValue:
+		return strconv.FormatInt(value.IntValue, 10), true
+	case *commonv1.AnyValue_DoubleValue:
+		return strconv.FormatFloat(value.DoubleValue, 'g', -1, 64), true
+	}
+	// Arrays, maps and bytes can't be used as labels.
+	return "", false
+}
@@ -0,0 +1,246 @@
+package otlp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	metricsv1 "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcev1 "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/grafana/grafana/pkg/services/live/telemetry"
+	"github.com/grafana/grafana/pkg/services/live/telemetry/telegraf"
+)
+
+var ts = time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func stringAttribute(key, value string) *commonv1.KeyValue {
+	return &commonv1.KeyValue{Key: key, Value: &commonv1.AnyValue{Value: &commonv1.AnyValue_StringValue{StringValue: value}}}
+}
+
+func testRequest() *collectormetrics.ExportMetricsServiceRequest {
+	timeUnixNano := uint64(ts.UnixNano())
+	return &collectormetrics.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricsv1.ResourceMetrics{{
+			Resource: &resourcev1.Resource{Attributes: []*commonv1.KeyValue{
+				stringAttribute("service.name", "sensor"),
+				stringAttribute("room", "default"),
+			}},
+			ScopeMetrics: []*metricsv1.ScopeMetrics{{
+				Metrics: []*metricsv1.Metric{
+					{
+						Name: "temperature",
+						Data: &metricsv1.Metric_Gauge{Gauge: &metricsv1.Gauge{DataPoints: []*metricsv1.NumberDataPoint{{
+							Attributes: []*commonv1.KeyValue{
+								stringAttribute("room", "kitchen"),
+								{Key: "floor", Value: &commonv1.AnyValue{Value: &commonv1.AnyValue_IntValue{IntValue: 1}}},
+							},
+							TimeUnixNano: timeUnixNano,
+							Value:        &metricsv1.NumberDataPoint_AsDouble{AsDouble: 21.5},
+						}}}},
+					},
+					{
+						Name: "requests",
+						Data: &metricsv1.Metric_Sum{Sum: &metricsv1.Sum{IsMonotonic: true, DataPoints: []*metricsv1.NumberDataPoint{{
+							TimeUnixNano: timeUnixNano,
+							Value:        &metricsv1.NumberDataPoint_AsInt{AsInt: 1027},
+						}}}},
+					},
+					{
+						Name: "duration",
+						Data: &metricsv1.Metric_Histogram{Histogram: &metricsv1.Histogram{DataPoints: []*metricsv1.HistogramDataPoint{{
+							TimeUnixNano:   timeUnixNano,
+							Count:          6,
+							Sum:            proto.Float64(3.2),
+							BucketCounts:   []uint64{2, 3, 1},
+							ExplicitBounds: []float64{0.1, 1},
+						}}}},
+					},
+					{
+						Name: "rpc_duration",
+						Data: &metricsv1.Metric_Summary{Summary: &metricsv1.Summary{DataPoints: []*metricsv1.SummaryDataPoint{{
+							TimeUnixNano: timeUnixNano,
+							Count:        120,
+							Sum:          17,
+							QuantileValues: []*metricsv1.SummaryDataPoint_ValueAtQuantile{
+								{Quantile: 0.5, Value: 0.05},
+							},
+						}}}},
+					},
+				},
+			}},
+		}},
+	}
+}
+
+func fieldByName(t *testing.T, frame *data.Frame, name string) *data.Field {
+	t.Helper()
+	field, idx := frame.FieldByName(name)
+	require.NotEqual(t, -1, idx, "field %s not found", name)
+	return field
+}
+
+func framesByKey(frameWrappers []telemetry.FrameWrapper) map[string]*data.Frame {
+	frames := map[string]*data.Frame{}
+	for _, fw := range frameWrappers {
+		frames[fw.Key()] = fw.Frame()
+	}
+	return frames
+}
+
+func TestConverter_Convert_Protobuf(t *testing.T) {
+	body, err := proto.Marshal(testRequest())
+	require.NoError(t, err)
+
+	converter := NewConverter(telegraf.WithUseLabelsColumn(true), telegraf.WithFloat64Numbers(true))
+	frameWrappers, err := converter.Convert(body)
+	require.NoError(t, err)
+	frames := framesByKey(frameWrappers)
+	require.Len(t, frames, 4)
+
+	t.Run("gauge with data point attributes over resource attributes", func(t *testing.T) {
+		frame := frames["temperature"]
+		require.Equal(t, "floor=1, room=kitchen, service.name=sensor", fieldByName(t, frame, "labels").At(0))
+		require.Equal(t, ts, fieldByName(t, frame, "time").At(0).(time.Time).UTC())
+		require.Equal(t, 21.5, *fieldByName(t, frame, "gauge").At(0).(*float64))
+	})
+
+	t.Run("monotonic sum", func(t *testing.T) {
+		frame := frames["requests"]
+		require.Equal(t, 1027.0, *fieldByName(t, frame, "counter").At(0).(*float64))
+	})
+
+	t.Run("histogram with cumulative buckets", func(t *testing.T) {
+		frame := frames["duration"]
+		require.Equal(t, 6.0, *fieldByName(t, frame, "count").At(0).(*float64))
+		require.Equal(t, 3.2, *fieldByName(t, frame, "sum").At(0).(*float64))
+		require.Equal(t, 2.0, *fieldByName(t, frame, "0.1").At(0).(*float64))
+		require.Equal(t, 5.0, *fieldByName(t, frame, "1").At(0).(*float64))
+		require.Equal(t, 6.0, *fieldByName(t, frame, "+Inf").At(0).(*float64))
+	})
+
+	t.Run("summary", func(t *testing.T) {
+		frame := frames["rpc_duration"]
+		require.Equal(t, 120.0, *fieldByName(t, frame, "count").At(0).(*float64))
+		require.Equal(t, 0.05, *fieldByName(t, frame, "0.5").At(0).(*float64))
+	})
+}
+
+func TestConverter_Convert_JSON(t *testing.T) {
+	// As sent by OpenTelemetry exporters with OTLP/HTTP JSON encoding.
+	body := []byte(`{
+  "resourceMetrics": [{
+    "resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "sensor"}}]},
+    "scopeMetrics": [{
+      "scope": {"name": "meter"},
+      "metrics": [{
+        "name": "requests",
+        "unit": "1",
+        "sum": {
+          "aggregationTemporality": 2,
+          "isMonotonic": true,
+          "dataPoints": [{
+            "attributes": [{"key": "method", "value": {"stringValue": "post"}}],
+            "timeUnixNano": "1664625600000000000",
+            "asInt": "1027"
+          }]
+        }
+      }]
+    }]
+  }]
+}`)
+
+	frameWrappers, err := NewConverter(telegraf.WithUseLabelsColumn(true), telegraf.WithFloat64Numbers(true)).Convert(body)
+	require.NoError(t, err)
+	frames := framesByKey(frameWrappers)
+	require.Len(t, frames, 1)
+	frame := frames["requests"]
+	require.Equal(t, "method=post, service.name=sensor", fieldByName(t, frame, "labels").At(0))
+	require.Equal(t, ts, fieldByName(t, frame, "time").At(0).(time.Time).UTC())
+	require.Equal(t, 1027.0, *fieldByName(t, frame, "counter").At(0).(*float64))
+}
+
+func TestConverter_Convert_Gzip(t *testing.T) {
+	compress := func(t *testing.T, body []byte) []byte {
+		t.Helper()
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		_, err := w.Write(body)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		return buf.Bytes()
+	}
+
+	t.Run("protobuf", func(t *testing.T) {
+		body, err := proto.Marshal(testRequest())
+		require.NoError(t, err)
+
+		frameWrappers, err := NewConverter().Convert(compress(t, body))
+		require.NoError(t, err)
+		require.Len(t, framesByKey(frameWrappers), 4)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		body := []byte(`{"resourceMetrics": [{"scopeMetrics": [{"metrics": [{"name": "temperature", "gauge": {"dataPoints": [{"asDouble": 21.5}]}}]}]}]}`)
+
+		frameWrappers, err := NewConverter().Convert(compress(t, body))
+		require.NoError(t, err)
+		require.Len(t, framesByKey(frameWrappers), 1)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		body, err := proto.Marshal(testRequest())
+		require.NoError(t, err)
+		compressed := compress(t, body)
+
+		_, err = NewConverter().Convert(compressed[:len(compressed)/2])
+		require.Error(t, err)
+	})
+}
+
+func TestConverter_Convert_Invalid(t *testing.T) {
+	_, err := NewConverter().Convert([]byte(`{"resourceMetrics": 1}`))
+	require.Error(t, err)
+	_, err = NewConverter().Convert([]byte{0x0a, 0xff})
+	require.Error(t, err)
+}
+
+func TestConverter_ConvertContentType(t *testing.T) {
+	// A protobuf encoded request whose first resource metrics message is 123 bytes
+	// long starts with "\n{", as the length follows the field tag.
+	req := testRequest()
+	req.ResourceMetrics[0].ScopeMetrics = req.ResourceMetrics[0].ScopeMetrics[:1]
+	req.ResourceMetrics[0].ScopeMetrics[0].Metrics = req.ResourceMetrics[0].ScopeMetrics[0].Metrics[:1]
+	service := req.ResourceMetrics[0].Resource.Attributes[0].Value.Value.(*commonv1.AnyValue_StringValue)
+	for proto.Size(req.ResourceMetrics[0]) < 123 {
+		service.StringValue += "x"
+	}
+	require.Equal(t, 123, proto.Size(req.ResourceMetrics[0]))
+	body, err := proto.Marshal(req)
+	require.NoError(t, err)
+	require.Equal(t, []byte("\n{"), body[:2])
+
+	t.Run("protobuf starting with a brace", func(t *testing.T) {
+		frameWrappers, err := NewConverter().ConvertContentType(body, ContentTypeProtobuf)
+		require.NoError(t, err)
+		require.Len(t, framesByKey(frameWrappers), 1)
+
+		frameWrappers, err = NewConverter().Convert(body)
+		require.NoError(t, err)
+		require.Len(t, framesByKey(frameWrappers), 1)
+	})
+
+	t.Run("JSON with leading whitespace", func(t *testing.T) {
+		body := []byte("\n" + `{"resourceMetrics": [{"scopeMetrics": [{"metrics": [{"name": "temperature", "gauge": {"dataPoints": [{"asDouble": 21.5}]}}]}]}]}`)
+
+		frameWrappers, err := NewConverter().ConvertContentType(body, "application/json; charset=utf-8")
+		require.NoError(t, err)
+		require.Len(t, framesByKey(frameWrappers), 1)
+	})
+}
@@ -0,0 +1,107 @@
+package prometheus
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	influx "github.com/influxdata/line-protocol"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/grafana/grafana/pkg/services/live/telemetry"
+	"github.com/grafana/grafana/pkg/services/live/telemetry/telegraf"
+)
+
+var _ telemetry.Converter = (*Converter)(nil)
+
+// Converter converts metrics in Prometheus text exposition format to Grafana frames.
+type Converter struct {
+	converter *telegraf.Converter
+	now       func() time.Time
+}
+
+// NewConverter creates new Converter from Prometheus text exposition format to Grafana Data Frames.
+// Metrics are mapped the same way Telegraf maps them, so the frames are equal to the frames
+// produced from the same metrics collected by Telegraf and sent in Influx line protocol.
+func NewConverter(opts ...telegraf.ConverterOption) *Converter {
+	return &Converter{
+		converter: telegraf.NewConverter(opts...),
+		now:       time.Now,
+	}
+}
+
+// Convert metrics.
+func (c *Converter) Convert(body []byte) ([]telemetry.FrameWrapper, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing metrics: %w", err)
+	}
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	now := c.now()
+	var metrics []influx.Metric
+	for _, name := range names {
+		family := families[name]
+		for _, m := range family.GetMetric() {
+			metric, err := newMetric(name, family.GetType(), m, now)
+			if err != nil {
+				return nil, err
+			}
+			metrics = append(metrics, metric)
+		}
+	}
+	return c.converter.ConvertMetrics(metrics)
+}
+
+// newMetric creates a metric named after the metric family with a field per
+// value of the sample, e.g. "gauge" or "counter", or "count", "sum" and a field
+// per quantile or bucket upper bound for summaries and histograms.
+func newMetric(name string, metricType dto.MetricType, m *dto.Metric, now time.Time) (influx.Metric, error) {
+	tags := make(map[string]string, len(m.GetLabel()))
+	for _, l := range m.GetLabel() {
+		tags[l.GetName()] = l.GetValue()
+	}
+
+	fields := map[string]interface{}{}
+	switch metricType {
+	case dto.MetricType_COUNTER:
+		fields["counter"] = m.GetCounter().GetValue()
+	case dto.MetricType_GAUGE:
+		fields["gauge"] = m.GetGauge().GetValue()
+	case dto.MetricType_SUMMARY:
+		summary := m.GetSummary()
+		fields["count"] = float64(summary.GetSampleCount())
+		fields["sum"] = summary.GetSampleSum()
+		for _, q := range summary.GetQuantile() {
+			fields[formatFloat(q.GetQuantile())] = q.GetValue()
+		}
+	case dto.MetricType_HISTOGRAM:
+		histogram := m.GetHistogram()
+		fields["count"] = float64(histogram.GetSampleCount())
+		fields["sum"] = histogram.GetSampleSum()
+		for _, b := range histogram.GetBucket() {
+			fields[formatFloat(b.GetUpperBound())] = float64(b.GetCumulativeCount())
+		}
+	default:
+		fields["value"] = m.GetUntyped().GetValue()
+	}
+
+	t := now
+	if m.TimestampMs != nil {
+		t = time.UnixMilli(m.GetTimestampMs())
+	}
+	return influx.New(name, tags, fields, t)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,112 @@
+package prometheus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/live/telemetry/telegraf"
+)
+
+const exposition = `# HELP http_requests_total The total number of HTTP requests.
+# TYPE http_requests_total counter
+http_requests_total{method="post",code="200"} 1027 1395066363000
+http_requests_total{method="post",code="400"} 3 1395066363000
+# HELP temperature Current temperature.
+# TYPE temperature gauge
+temperature{room="kitchen"} 21.5
+# TYPE request_duration_seconds histogram
+request_duration_seconds_bucket{le="0.1"} 2
+request_duration_seconds_bucket{le="1"} 5
+request_duration_seconds_bucket{le="+Inf"} 6
+request_duration_seconds_sum 3.2
+request_duration_seconds_count 6
+# TYPE rpc_duration_seconds summary
+rpc_duration_seconds{quantile="0.5"} 0.05
+rpc_duration_seconds{quantile="0.99"} 0.3
+rpc_duration_seconds_sum 17
+rpc_duration_seconds_count 120
+untyped_metric 42
+`
+
+func fieldByName(t *testing.T, frame *data.Frame, name string) *data.Field {
+	t.Helper()
+	field, idx := frame.FieldByName(name)
+	require.NotEqual(t, -1, idx, "field %s not found", name)
+	return field
+}
+
+func TestConverter_Convert(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	converter := NewConverter(telegraf.WithUseLabelsColumn(true), telegraf.WithFloat64Numbers(true))
+	converter.now = func() time.Time { return now }
+
+	frameWrappers, err := converter.Convert([]byte(exposition))
+	require.NoError(t, err)
+
+	frames := map[string]*data.Frame{}
+	for _, fw := range frameWrappers {
+		frames[fw.Key()] = fw.Frame()
+	}
+	require.Len(t, frames, 5)
+
+	t.Run("counter", func(t *testing.T) {
+		frame := frames["http_requests_total"]
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, `code=200, method=post`, fieldByName(t, frame, "labels").At(0))
+		require.Equal(t, time.UnixMilli(1395066363000), fieldByName(t, frame, "time").At(0))
+		require.Equal(t, 1027.0, *fieldByName(t, frame, "counter").At(0).(*float64))
+		require.Equal(t, 3.0, *fieldByName(t, frame, "counter").At(1).(*float64))
+	})
+
+	t.Run("gauge without timestamp uses current time", func(t *testing.T) {
+		frame := frames["temperature"]
+		require.Equal(t, now, fieldByName(t, frame, "time").At(0))
+		require.Equal(t, 21.5, *fieldByName(t, frame, "gauge").At(0).(*float64))
+	})
+
+	t.Run("histogram", func(t *testing.T) {
+		frame := frames["request_duration_seconds"]
+		require.Equal(t, 6.0, *fieldByName(t, frame, "count").At(0).(*float64))
+		require.Equal(t, 3.2, *fieldByName(t, frame, "sum").At(0).(*float64))
+		require.Equal(t, 2.0, *fieldByName(t, frame, "0.1").At(0).(*float64))
+		require.Equal(t, 5.0, *fieldByName(t, frame, "1").At(0).(*float64))
+		require.Equal(t, 6.0, *fieldByName(t, frame, "+Inf").At(0).(*float64))
+	})
+
+	t.Run("summary", func(t *testing.T) {
+		frame := frames["rpc_duration_seconds"]
+		require.Equal(t, 120.0, *fieldByName(t, frame, "count").At(0).(*float64))
+		require.Equal(t, 17.0, *fieldByName(t, frame, "sum").At(0).(*float64))
+		require.Equal(t, 0.05, *fieldByName(t, frame, "0.5").At(0).(*float64))
+		require.Equal(t, 0.3, *fieldByName(t, frame, "0.99").At(0).(*float64))
+	})
+
+	t.Run("untyped", func(t *testing.T) {
+		frame := frames["untyped_metric"]
+		require.Equal(t, 42.0, *fieldByName(t, frame, "value").At(0).(*float64))
+	})
+}
+
+func TestConverter_Convert_SameAsInflux(t *testing.T) {
+	converter := NewConverter(telegraf.WithFloat64Numbers(true))
+	frameWrappers, err := converter.Convert([]byte(`# TYPE cpu gauge
+cpu{host="a",region="eu"} 0.5 1395066363000
+`))
+	require.NoError(t, err)
+
+	influxWrappers, err := telegraf.NewConverter(telegraf.WithFloat64Numbers(true)).Convert([]byte(`cpu,host=a,region=eu gauge=0.5 1395066363000000000`))
+	require.NoError(t, err)
+
+	require.Len(t, frameWrappers, 1)
+	require.Len(t, influxWrappers, 1)
+	require.Equal(t, influxWrappers[0].Key(), frameWrappers[0].Key())
+	require.Equal(t, influxWrappers[0].Frame(), frameWrappers[0].Frame())
+}
+
+func TestConverter_Convert_Invalid(t *testing.T) {
+	_, err := NewConverter().Convert([]byte("metric{label=\"value\" 1\n"))
+	require.Error(t, err)
+}
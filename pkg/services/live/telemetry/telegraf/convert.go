@@ -65,6 +65,12 @@ func (c *Converter) Convert(body []byte) ([]telemetry.FrameWrapper, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error parsing metrics: %w", err)
 	}
+	return c.ConvertMetrics(metrics)
+}
+
+// ConvertMetrics converts already parsed metrics. It allows converters of other
+// input formats to produce the same frames as Telegraf metrics.
+func (c *Converter) ConvertMetrics(metrics []influx.Metric) ([]telemetry.FrameWrapper, error) {
 	if !c.useLabelsColumn {
 		return c.convertWideFields(metrics)
 	}
@@ -171,6 +171,24 @@ var (
 		},
 		Grants: []string{string(org.RoleAdmin)},
 	}
+
+	alertingProvisioningSecretsReaderRole = accesscontrol.RoleRegistration{
+		Role: accesscontrol.RoleDTO{
+			Name:        accesscontrol.FixedRolePrefix + "alerting.provisioning.secrets:reader",
+			DisplayName: "Read secrets via the alert rules provisioning API",
+			Description: "Export contact points with decrypted secure settings via provisioning API.",
+			Group:       AlertRolesGroup,
+			Permissions: []accesscontrol.Permission{
+				{
+					Action: accesscontrol.ActionAlertingProvisioningRead, // organization scope
+				},
+				{
+					Action: accesscontrol.ActionAlertingProvisioningReadSecrets, // organization scope
+				},
+			},
+		},
+		Grants: []string{string(org.RoleAdmin)},
+	}
 )
 
 func DeclareFixedRoles(service accesscontrol.Service) error {
@@ -178,6 +196,6 @@ func DeclareFixedRoles(service accesscontrol.Service) error {
 		rulesReaderRole, rulesWriterRole,
 		instancesReaderRole, instancesWriterRole,
 		notificationsReaderRole, notificationsWriterRole,
-		alertingReaderRole, alertingWriterRole, alertingProvisionerRole,
+		alertingReaderRole, alertingWriterRole, alertingProvisionerRole, alertingProvisioningSecretsReaderRole,
 	)
 }
@@ -52,6 +52,9 @@ type Alertmanager interface {
 	// Receivers
 	GetReceivers(ctx context.Context) apimodels.Receivers
 	TestReceivers(ctx context.Context, c apimodels.TestReceiversConfigBodyParams) (*notifier.TestReceiversResult, error)
+
+	// Templates
+	TestTemplate(ctx context.Context, c apimodels.TestTemplatesConfigBodyParams) (*apimodels.TestTemplatesResults, error)
 }
 
 type AlertingStore interface {
@@ -82,6 +85,7 @@ type API struct {
 	MuteTimings          *provisioning.MuteTimingService
 	AlertRules           *provisioning.AlertRuleService
 	AlertsRouter         *sender.AlertsRouter
+	StateHistorian       StateHistorian
 }
 
 // RegisterAPIEndpoints registers API handlers
@@ -94,11 +98,17 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 
 	evaluator := eval.NewEvaluator(api.Cfg, log.New("ngalert.eval"), api.DatasourceCache, api.ExpressionService)
 
+	appURL, err := url.Parse(api.Cfg.AppURL)
+	if err != nil {
+		logger.Error("Failed to parse application URL. Continue without it.", "error", err)
+		appURL = nil
+	}
+
 	// Register endpoints for proxying to Alertmanager-compatible backends.
 	api.RegisterAlertmanagerApiEndpoints(NewForkingAM(
 		api.DatasourceCache,
 		NewLotexAM(proxy, logger),
-		&AlertmanagerSrv{crypto: api.MultiOrgAlertmanager.Crypto, log: logger, ac: api.AccessControl, mam: api.MultiOrgAlertmanager},
+		&AlertmanagerSrv{crypto: api.MultiOrgAlertmanager.Crypto, log: logger, ac: api.AccessControl, mam: api.MultiOrgAlertmanager, manager: api.StateManager, store: api.RuleStore, appURL: appURL},
 	), m)
 	// Register endpoints for proxying to Prometheus-compatible backends.
 	api.RegisterPrometheusApiEndpoints(NewForkingProm(
@@ -120,6 +130,7 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			log:                logger,
 			cfg:                &api.Cfg.UnifiedAlerting,
 			ac:                 api.AccessControl,
+			datasourceCache:    api.DatasourceCache,
 		},
 	), m)
 	api.RegisterTestingApiEndpoints(NewTestingApi(
@@ -141,10 +152,20 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 
 	api.RegisterProvisioningApiEndpoints(NewProvisioningApi(&ProvisioningSrv{
 		log:                 logger,
+		ac:                  api.AccessControl,
 		policies:            api.Policies,
 		contactPointService: api.ContactPointService,
 		templates:           api.Templates,
 		muteTimings:         api.MuteTimings,
 		alertRules:          api.AlertRules,
+		namespaces:          api.RuleStore,
+		conditionValidator:  evaluator,
+	}), m)
+
+	api.RegisterHistoryApiEndpoints(NewHistoryApi(&HistorySrv{
+		log:       logger,
+		historian: api.StateHistorian,
+		store:     api.RuleStore,
+		ac:        api.AccessControl,
 	}), m)
 }
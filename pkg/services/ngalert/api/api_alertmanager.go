@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -15,7 +17,10 @@ import (
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -23,13 +28,18 @@ import (
 const (
 	defaultTestReceiversTimeout = 15 * time.Second
 	maxTestReceiversTimeout     = 30 * time.Second
+
+	defaultNotificationDeliveriesLimit = 100
 )
 
 type AlertmanagerSrv struct {
-	log    log.Logger
-	ac     accesscontrol.AccessControl
-	mam    *notifier.MultiOrgAlertmanager
-	crypto notifier.Crypto
+	log     log.Logger
+	ac      accesscontrol.AccessControl
+	mam     *notifier.MultiOrgAlertmanager
+	crypto  notifier.Crypto
+	manager state.AlertInstanceManager
+	store   RuleStore
+	appURL  *url.URL
 }
 
 type UnknownReceiverError struct {
@@ -244,6 +254,109 @@ func (srv AlertmanagerSrv) RoutePostAlertingConfig(c *models.ReqContext, body ap
 	return ErrResp(http.StatusInternalServerError, err, "")
 }
 
+func (srv AlertmanagerSrv) RouteGetAlertingConfigHistory(c *models.ReqContext) response.Response {
+	limit := c.QueryInt("limit")
+	if limit < 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("limit must not be negative"), "")
+	}
+	history, err := srv.mam.GetAlertmanagerConfigurationHistory(c.Req.Context(), c.OrgID, limit)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, history)
+}
+
+func (srv AlertmanagerSrv) RouteGetAlertingConfigHistoryDiff(c *models.ReqContext, id string) response.Response {
+	configID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to parse id")
+	}
+	compareTo := c.QueryInt64("compareTo")
+	if compareTo < 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("compareTo must not be negative"), "")
+	}
+	diff, err := srv.mam.DiffAlertmanagerConfigurations(c.Req.Context(), c.OrgID, configID, compareTo)
+	if err != nil {
+		if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, apimodels.NewAlertingConfigDiff(configID, compareTo, diff))
+}
+
+func (srv AlertmanagerSrv) RoutePostAlertingConfigHistoryActivate(c *models.ReqContext, id string) response.Response {
+	configID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to parse id")
+	}
+	historicConfig, err := srv.mam.GetHistoricalAlertmanagerConfiguration(c.Req.Context(), c.OrgID, configID)
+	if err != nil {
+		if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	currentConfig, err := srv.mam.GetAlertmanagerConfiguration(c.Req.Context(), c.OrgID)
+	// Like when posting a config, the guard is bypassed if the current config is invalid.
+	if err == nil {
+		if err := srv.provenanceGuard(currentConfig, *historicConfig); err != nil {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+	}
+	err = srv.mam.ActivateHistoricalConfiguration(c.Req.Context(), c.OrgID, configID)
+	if err == nil {
+		return response.JSON(http.StatusAccepted, util.DynMap{"message": "configuration activated"})
+	}
+	if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	var configRejectedError notifier.AlertmanagerConfigRejectedError
+	if errors.As(err, &configRejectedError) {
+		return ErrResp(http.StatusBadRequest, configRejectedError, "")
+	}
+	if errors.Is(err, notifier.ErrNoAlertmanagerForOrg) {
+		return response.Error(http.StatusNotFound, err.Error(), err)
+	}
+	return ErrResp(http.StatusInternalServerError, err, "")
+}
+
+func (srv AlertmanagerSrv) RouteGetNotificationDeliveries(c *models.ReqContext) response.Response {
+	query := ngmodels.GetNotificationDeliveriesQuery{
+		OrgID:           c.OrgID,
+		Receiver:        c.Query("receiver"),
+		IntegrationType: c.Query("integration"),
+		GroupKey:        c.Query("groupKey"),
+		Status:          ngmodels.NotificationDeliveryStatus(c.Query("status")),
+		Limit:           defaultNotificationDeliveriesLimit,
+	}
+	switch query.Status {
+	case "", ngmodels.NotificationDeliverySuccess, ngmodels.NotificationDeliveryFailure:
+	default:
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid status %q, must be %q or %q", query.Status, ngmodels.NotificationDeliverySuccess, ngmodels.NotificationDeliveryFailure), "")
+	}
+	if from := c.QueryInt64("from"); from > 0 {
+		query.From = time.Unix(from, 0)
+	}
+	if to := c.QueryInt64("to"); to > 0 {
+		query.To = time.Unix(to, 0)
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && query.From.After(query.To) {
+		return ErrResp(http.StatusBadRequest, errors.New("the start of the time range must not be after its end"), "")
+	}
+	if limit := c.QueryInt("limit"); limit < 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("limit must not be negative"), "")
+	} else if limit > 0 {
+		query.Limit = limit
+	}
+
+	deliveries, err := srv.mam.GetNotificationDeliveries(c.Req.Context(), &query)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, deliveries)
+}
+
 func (srv AlertmanagerSrv) RoutePostAMAlerts(_ *models.ReqContext, _ apimodels.PostableAlerts) response.Response {
 	return NotImplementedResp
 }
@@ -297,6 +410,47 @@ func (srv AlertmanagerSrv) RoutePostTestReceivers(c *models.ReqContext, body api
 	return response.JSON(statusForTestReceivers(result.Receivers), newTestReceiversResult(result))
 }
 
+func (srv AlertmanagerSrv) RoutePostTestTemplates(c *models.ReqContext, body apimodels.TestTemplatesConfigBodyParams) response.Response {
+	if body.Name == "" {
+		return ErrResp(http.StatusBadRequest, errors.New("template must have a name"), "")
+	}
+	if body.Name != filepath.Base(filepath.Clean(body.Name)) {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("template name %q is not valid", body.Name), "")
+	}
+	if strings.TrimSpace(body.Template) == "" {
+		return ErrResp(http.StatusBadRequest, errors.New("template must have content"), "")
+	}
+
+	am, errResp := srv.AlertmanagerFor(c.OrgID)
+	if errResp != nil {
+		return errResp
+	}
+
+	if body.CurrentAlerts {
+		namespaceMap, err := srv.store.GetUserVisibleNamespaces(c.Req.Context(), c.OrgID, c.SignedInUser)
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to get namespaces visible to the user")
+		}
+		// only the alerts of rules in folders that the user can read are used
+		var states []*state.State
+		for _, s := range srv.manager.GetAll(c.OrgID) {
+			if _, ok := namespaceMap[s.Labels[ngmodels.NamespaceUIDLabel]]; ok {
+				states = append(states, s)
+			}
+		}
+		body.Alerts = schedule.FromAlertStatesToFiringAlerts(states, srv.appURL)
+	}
+
+	result, err := am.TestTemplate(c.Req.Context(), body)
+	if err != nil {
+		if errors.Is(err, notifier.ErrUnknownIntegrationType) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
 // contextWithTimeoutFromRequest returns a context with a deadline set from the
 // Request-Timeout header in the HTTP request. If the header is absent then the
 // context will use the default timeout. The timeout in the Request-Timeout
@@ -8,7 +8,6 @@ import (
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/util/cmputil"
-	amConfig "github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/alertmanager/pkg/labels"
 )
 
@@ -119,7 +118,7 @@ func checkContactPoints(currReceivers []*apimodels.GettableApiReceiver, newRecei
 }
 
 func checkMuteTimes(currentConfig apimodels.GettableUserConfig, newConfig apimodels.PostableUserConfig) error {
-	newMTs := make(map[string]amConfig.MuteTimeInterval)
+	newMTs := make(map[string]apimodels.MuteTimeIntervalConfig)
 	for _, newMuteTime := range newConfig.AlertmanagerConfig.MuteTimeIntervals {
 		newMTs[newMuteTime.Name] = newMuteTime
 	}
@@ -138,7 +137,8 @@ func checkMuteTimes(currentConfig apimodels.GettableUserConfig, newConfig apimod
 		reporter := cmputil.DiffReporter{}
 		options := []cmp.Option{cmp.Reporter(&reporter), cmpopts.EquateEmpty()}
 		timesEqual := cmp.Equal(muteTime.TimeIntervals, postedMT.TimeIntervals, options...)
-		if !timesEqual {
+		dateRangesEqual := cmp.Equal(muteTime.DateRanges, postedMT.DateRanges, options...)
+		if !timesEqual || !dateRangesEqual {
 			return fmt.Errorf("cannot save provisioned mute time '%s'", muteTime.Name)
 		}
 	}
@@ -507,6 +507,33 @@ func TestCheckMuteTimes(t *testing.T) {
 					},
 				}),
 		},
+		{
+			name:      "editing the date ranges of a provisioned object should fail",
+			shouldErr: true,
+			currentConfig: gettableMuteIntervals(t,
+				[]amConfig.MuteTimeInterval{
+					{
+						Name:          "test-1",
+						TimeIntervals: defaultInterval(t),
+					},
+				},
+				map[string]models.Provenance{
+					"test-1": models.ProvenanceFile,
+				}),
+			newConfig: func() definitions.PostableUserConfig {
+				cfg := postableMuteIntervals(t,
+					[]amConfig.MuteTimeInterval{
+						{
+							Name:          "test-1",
+							TimeIntervals: defaultInterval(t),
+						},
+					})
+				cfg.AlertmanagerConfig.MuteTimeIntervals[0].DateRanges = []definitions.DateRange{
+					{StartTime: "2022-10-20 22:00", EndTime: "2022-10-21 02:00"},
+				}
+				return cfg
+			}(),
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -525,7 +552,7 @@ func gettableMuteIntervals(t *testing.T, muteTimeIntervals []amConfig.MuteTimeIn
 		AlertmanagerConfig: definitions.GettableApiAlertingConfig{
 			MuteTimeProvenances: provenances,
 			Config: definitions.Config{
-				MuteTimeIntervals: muteTimeIntervals,
+				MuteTimeIntervals: muteIntervalConfigs(muteTimeIntervals),
 			},
 		},
 	}
@@ -536,12 +563,20 @@ func postableMuteIntervals(t *testing.T, muteTimeIntervals []amConfig.MuteTimeIn
 	return definitions.PostableUserConfig{
 		AlertmanagerConfig: definitions.PostableApiAlertingConfig{
 			Config: definitions.Config{
-				MuteTimeIntervals: muteTimeIntervals,
+				MuteTimeIntervals: muteIntervalConfigs(muteTimeIntervals),
 			},
 		},
 	}
 }
 
+func muteIntervalConfigs(muteTimeIntervals []amConfig.MuteTimeInterval) []definitions.MuteTimeIntervalConfig {
+	result := make([]definitions.MuteTimeIntervalConfig, 0, len(muteTimeIntervals))
+	for _, mt := range muteTimeIntervals {
+		result = append(result, definitions.MuteTimeIntervalConfig{MuteTimeInterval: mt})
+	}
+	return result
+}
+
 func defaultInterval(t *testing.T) []timeinterval.TimeInterval {
 	t.Helper()
 	return []timeinterval.TimeInterval{
@@ -3,8 +3,11 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"math/rand"
 	"net/http"
+	"net/url"
+	"strconv"
 	"testing"
 	"time"
 
@@ -19,10 +22,13 @@ import (
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	acMock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	ngfakes "github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/secrets/fakes"
 	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
@@ -290,6 +296,296 @@ func TestAlertmanagerConfig(t *testing.T) {
 	})
 }
 
+func TestAlertmanagerConfigHistory(t *testing.T) {
+	// createSutWithHistory returns a sut where org 1 has the initial config followed by a config with an additional template.
+	createSutWithHistory := func(t *testing.T) AlertmanagerSrv {
+		t.Helper()
+		sut := createSut(t, nil)
+		request := createAmConfigRequest(t)
+		request.TemplateFiles["b"] = "another template"
+		response := sut.RoutePostAlertingConfig(createRequestCtxWithQuery(1, ""), request)
+		require.Equal(t, 202, response.Status())
+		return sut
+	}
+
+	t.Run("assert configs are listed latest first", func(t *testing.T) {
+		sut := createSutWithHistory(t)
+
+		response := sut.RouteGetAlertingConfigHistory(createRequestCtxWithQuery(1, ""))
+
+		require.Equal(t, 200, response.Status())
+		history := asGettableHistoricUserConfigs(t, response)
+		require.Len(t, history, 2)
+		require.Greater(t, history[0].ID, history[1].ID)
+		require.Equal(t, map[string]string{"a": "template", "b": "another template"}, history[0].TemplateFiles)
+		require.Equal(t, map[string]string{"a": "template"}, history[1].TemplateFiles)
+		require.Equal(t, "grafana-default-email", history[1].AlertmanagerConfig.Route.Receiver)
+	})
+
+	t.Run("assert the number of configs is limited", func(t *testing.T) {
+		sut := createSutWithHistory(t)
+
+		response := sut.RouteGetAlertingConfigHistory(createRequestCtxWithQuery(1, "limit=1"))
+
+		require.Equal(t, 200, response.Status())
+		history := asGettableHistoricUserConfigs(t, response)
+		require.Len(t, history, 1)
+		require.Contains(t, history[0].TemplateFiles, "b")
+	})
+
+	t.Run("assert configs of other orgs are not listed", func(t *testing.T) {
+		sut := createSutWithHistory(t)
+
+		response := sut.RouteGetAlertingConfigHistory(createRequestCtxWithQuery(2, ""))
+
+		require.Equal(t, 200, response.Status())
+		require.Len(t, asGettableHistoricUserConfigs(t, response), 1)
+	})
+
+	t.Run("assert diff with the current config", func(t *testing.T) {
+		sut := createSutWithHistory(t)
+		history := asGettableHistoricUserConfigs(t, sut.RouteGetAlertingConfigHistory(createRequestCtxWithQuery(1, "")))
+		oldest := history[1].ID
+
+		response := sut.RouteGetAlertingConfigHistoryDiff(createRequestCtxWithQuery(1, ""), strconv.FormatInt(oldest, 10))
+
+		require.Equal(t, 200, response.Status())
+		diff := apimodels.AlertingConfigDiff{}
+		require.NoError(t, json.Unmarshal(response.Body(), &diff))
+		require.Equal(t, oldest, diff.ID)
+		require.Equal(t, int64(0), diff.CompareTo)
+		// the initial config does not have a UID for its contact point, saving the second config generated one.
+		require.Len(t, diff.Diffs, 2)
+		require.Equal(t, "[alertmanager_config][receivers][0][grafana_managed_receiver_configs][0][uid]", diff.Diffs[0].Path)
+		require.Equal(t, "", diff.Diffs[0].Left)
+		require.NotEmpty(t, diff.Diffs[0].Right)
+		require.Equal(t, "[template_files][b]", diff.Diffs[1].Path)
+		require.Nil(t, diff.Diffs[1].Left)
+		require.Equal(t, "another template", diff.Diffs[1].Right)
+	})
+
+	t.Run("assert diff of the same configs is empty", func(t *testing.T) {
+		sut := createSutWithHistory(t)
+		history := asGettableHistoricUserConfigs(t, sut.RouteGetAlertingConfigHistory(createRequestCtxWithQuery(1, "")))
+		latest := history[0].ID
+
+		response := sut.RouteGetAlertingConfigHistoryDiff(createRequestCtxWithQuery(1, fmt.Sprintf("compareTo=%d", latest)), strconv.FormatInt(latest, 10))
+
+		require.Equal(t, 200, response.Status())
+		diff := apimodels.AlertingConfigDiff{}
+		require.NoError(t, json.Unmarshal(response.Body(), &diff))
+		require.Empty(t, diff.Diffs)
+	})
+
+	t.Run("assert 404 Not Found when diffing unknown config", func(t *testing.T) {
+		sut := createSutWithHistory(t)
+
+		response := sut.RouteGetAlertingConfigHistoryDiff(createRequestCtxWithQuery(1, ""), "1000")
+
+		require.Equal(t, 404, response.Status())
+	})
+
+	t.Run("assert 400 Bad Request when id is not a number", func(t *testing.T) {
+		sut := createSutWithHistory(t)
+
+		require.Equal(t, 400, sut.RouteGetAlertingConfigHistoryDiff(createRequestCtxWithQuery(1, ""), "abc").Status())
+		require.Equal(t, 400, sut.RoutePostAlertingConfigHistoryActivate(createRequestCtxWithQuery(1, ""), "abc").Status())
+	})
+
+	t.Run("assert 202 when previous config is activated", func(t *testing.T) {
+		sut := createSutWithHistory(t)
+		history := asGettableHistoricUserConfigs(t, sut.RouteGetAlertingConfigHistory(createRequestCtxWithQuery(1, "")))
+		oldest := history[1].ID
+
+		response := sut.RoutePostAlertingConfigHistoryActivate(createRequestCtxWithQuery(1, ""), strconv.FormatInt(oldest, 10))
+
+		require.Equal(t, 202, response.Status())
+		current := asGettableUserConfig(t, sut.RouteGetAlertingConfig(createRequestCtxWithQuery(1, "")))
+		require.Equal(t, map[string]string{"a": "template"}, current.TemplateFiles)
+		// the activated config is saved as a new config.
+		history = asGettableHistoricUserConfigs(t, sut.RouteGetAlertingConfigHistory(createRequestCtxWithQuery(1, "")))
+		require.Len(t, history, 3)
+		require.Equal(t, map[string]string{"a": "template"}, history[0].TemplateFiles)
+	})
+
+	t.Run("assert 404 Not Found when activating config of another org", func(t *testing.T) {
+		sut := createSutWithHistory(t)
+		history := asGettableHistoricUserConfigs(t, sut.RouteGetAlertingConfigHistory(createRequestCtxWithQuery(2, "")))
+
+		response := sut.RoutePostAlertingConfigHistoryActivate(createRequestCtxWithQuery(1, ""), strconv.FormatInt(history[0].ID, 10))
+
+		require.Equal(t, 404, response.Status())
+	})
+
+	t.Run("assert 400 Bad Request when activating config that removes provisioned template", func(t *testing.T) {
+		sut := createSutWithHistory(t)
+		setTemplateProvenance(t, 1, "b", sut.mam.ProvStore)
+		history := asGettableHistoricUserConfigs(t, sut.RouteGetAlertingConfigHistory(createRequestCtxWithQuery(1, "")))
+
+		response := sut.RoutePostAlertingConfigHistoryActivate(createRequestCtxWithQuery(1, ""), strconv.FormatInt(history[1].ID, 10))
+
+		require.Equal(t, 400, response.Status())
+	})
+}
+
+func TestNotificationDeliveries(t *testing.T) {
+	createSutWithDeliveries := func(t *testing.T) AlertmanagerSrv {
+		t.Helper()
+		mam, configStore := createMultiOrgAlertmanagerAndConfigStore(t)
+		start := time.Unix(1000, 0).UTC()
+		deliveries := []*ngmodels.NotificationDelivery{
+			{OrgID: 1, Receiver: "ops", IntegrationType: "pagerduty", Status: ngmodels.NotificationDeliverySuccess, StatusCode: 202, CreatedAt: start},
+			{OrgID: 1, Receiver: "ops", IntegrationType: "slack", Status: ngmodels.NotificationDeliveryFailure, StatusCode: 500, Error: "request failed", CreatedAt: start.Add(time.Minute)},
+			{OrgID: 1, Receiver: "dev", IntegrationType: "email", Status: ngmodels.NotificationDeliverySuccess, CreatedAt: start.Add(2 * time.Minute)},
+			{OrgID: 2, Receiver: "ops", IntegrationType: "pagerduty", Status: ngmodels.NotificationDeliverySuccess, CreatedAt: start},
+		}
+		for _, d := range deliveries {
+			require.NoError(t, configStore.SaveNotificationDelivery(context.Background(), d))
+		}
+		return AlertmanagerSrv{
+			mam:    mam,
+			crypto: mam.Crypto,
+			ac:     acMock.New().WithDisabled(),
+			log:    log.NewNopLogger(),
+		}
+	}
+	asDeliveries := func(t *testing.T, r response.Response) apimodels.GettableNotificationDeliveries {
+		t.Helper()
+		body := apimodels.GettableNotificationDeliveries{}
+		require.NoError(t, json.Unmarshal(r.Body(), &body))
+		return body
+	}
+	integrations := func(deliveries apimodels.GettableNotificationDeliveries) []string {
+		result := make([]string, 0, len(deliveries))
+		for _, d := range deliveries {
+			result = append(result, d.IntegrationType)
+		}
+		return result
+	}
+
+	t.Run("assert deliveries of the org are listed latest first", func(t *testing.T) {
+		sut := createSutWithDeliveries(t)
+
+		response := sut.RouteGetNotificationDeliveries(createRequestCtxWithQuery(1, ""))
+
+		require.Equal(t, 200, response.Status())
+		deliveries := asDeliveries(t, response)
+		require.Equal(t, []string{"email", "slack", "pagerduty"}, integrations(deliveries))
+		require.Equal(t, "failure", deliveries[1].Status)
+		require.Equal(t, 500, deliveries[1].StatusCode)
+		require.Equal(t, "request failed", deliveries[1].Error)
+		require.True(t, time.Unix(1060, 0).Equal(deliveries[1].Timestamp))
+	})
+
+	t.Run("assert deliveries are filtered", func(t *testing.T) {
+		sut := createSutWithDeliveries(t)
+
+		testCases := map[string][]string{
+			"receiver=ops":             {"slack", "pagerduty"},
+			"integration=email":        {"email"},
+			"status=failure":           {"slack"},
+			"from=1030&to=1090":        {"slack"},
+			"receiver=ops&limit=1":     {"slack"},
+			"receiver=unknown":         {},
+			"status=success&from=1060": {"email"},
+		}
+		for query, expected := range testCases {
+			response := sut.RouteGetNotificationDeliveries(createRequestCtxWithQuery(1, query))
+			require.Equal(t, 200, response.Status(), query)
+			require.Equal(t, expected, integrations(asDeliveries(t, response)), query)
+		}
+	})
+
+	t.Run("assert 400 Bad Request when query is invalid", func(t *testing.T) {
+		sut := createSutWithDeliveries(t)
+
+		for _, query := range []string{"status=unknown", "limit=-1", "from=20&to=10"} {
+			response := sut.RouteGetNotificationDeliveries(createRequestCtxWithQuery(1, query))
+			require.Equal(t, 400, response.Status(), query)
+		}
+	})
+}
+
+func TestRoutePostTestTemplates(t *testing.T) {
+	createSutWithStates := func(t *testing.T) AlertmanagerSrv {
+		t.Helper()
+		sut := createSut(t, nil)
+		manager := NewFakeAlertInstanceManager(t)
+		manager.GenerateAlertInstances(1, util.GenerateShortUID(), 2, func(s *state.State) *state.State {
+			s.State = eval.Alerting
+			return s
+		})
+		manager.GenerateAlertInstances(1, util.GenerateShortUID(), 1)
+		// a firing alert of a rule in a folder that the user cannot read
+		manager.GenerateAlertInstances(1, util.GenerateShortUID(), 1, func(s *state.State) *state.State {
+			s.State = eval.Alerting
+			s.Labels[ngmodels.NamespaceUIDLabel] = "other_namespace_uid"
+			return s
+		})
+		sut.manager = manager
+		ruleStore := ngfakes.NewRuleStore(t)
+		rule := ngmodels.AlertRuleGen(ngmodels.WithOrgID(1))()
+		rule.NamespaceUID = "test_namespace_uid"
+		ruleStore.PutRule(context.Background(), rule)
+		sut.store = ruleStore
+		return sut
+	}
+	asResults := func(t *testing.T, r response.Response) apimodels.TestTemplatesResults {
+		t.Helper()
+		body := apimodels.TestTemplatesResults{}
+		require.NoError(t, json.Unmarshal(r.Body(), &body))
+		return body
+	}
+
+	t.Run("assert 200 and rendered settings", func(t *testing.T) {
+		sut := createSutWithStates(t)
+
+		response := sut.RoutePostTestTemplates(createRequestCtxInOrg(1), apimodels.TestTemplatesConfigBodyParams{
+			Name:     "slack",
+			Template: `{{ len .Alerts }} alerts`,
+			Integrations: []apimodels.TestTemplatesIntegration{
+				{Type: "slack", Settings: map[string]string{"title": `{{ template "slack" . }}`}},
+			},
+		})
+
+		require.Equal(t, 200, response.Status())
+		results := asResults(t, response)
+		require.Empty(t, results.Errors)
+		require.Equal(t, "1 alerts", results.Results[0].Settings["title"])
+	})
+
+	t.Run("assert current alerts are firing alerts of the state manager in folders the user can read", func(t *testing.T) {
+		sut := createSutWithStates(t)
+
+		response := sut.RoutePostTestTemplates(createRequestCtxInOrg(1), apimodels.TestTemplatesConfigBodyParams{
+			Name:          "slack",
+			Template:      `{{ len .Alerts.Firing }} alerts`,
+			CurrentAlerts: true,
+			Integrations: []apimodels.TestTemplatesIntegration{
+				{Type: "slack", Settings: map[string]string{"title": `{{ template "slack" . }}`}},
+			},
+		})
+
+		require.Equal(t, 200, response.Status())
+		require.Equal(t, "2 alerts", asResults(t, response).Results[0].Settings["title"])
+	})
+
+	t.Run("assert 400 Bad Request when request is invalid", func(t *testing.T) {
+		sut := createSutWithStates(t)
+
+		testCases := map[string]apimodels.TestTemplatesConfigBodyParams{
+			"missing name":             {Template: "text"},
+			"name with path":           {Name: "../slack", Template: "text"},
+			"missing template":         {Name: "slack", Template: " "},
+			"unknown integration type": {Name: "slack", Template: "text", Integrations: []apimodels.TestTemplatesIntegration{{Type: "unknown"}}},
+		}
+		for name, body := range testCases {
+			response := sut.RoutePostTestTemplates(createRequestCtxInOrg(1), body)
+			require.Equal(t, 400, response.Status(), name)
+		}
+	})
+}
+
 func TestSilenceCreate(t *testing.T) {
 	makeSilence := func(comment string, createdBy string,
 		startsAt, endsAt strfmt.DateTime, matchers amv2.Matchers) amv2.Silence {
@@ -510,6 +806,12 @@ func createAmConfigRequest(t *testing.T) apimodels.PostableUserConfig {
 
 func createMultiOrgAlertmanager(t *testing.T) *notifier.MultiOrgAlertmanager {
 	t.Helper()
+	mam, _ := createMultiOrgAlertmanagerAndConfigStore(t)
+	return mam
+}
+
+func createMultiOrgAlertmanagerAndConfigStore(t *testing.T) (*notifier.MultiOrgAlertmanager, *notifier.FakeConfigStore) {
+	t.Helper()
 
 	configs := map[int64]*ngmodels.AlertConfiguration{
 		1: {AlertmanagerConfiguration: validConfig, OrgID: 1},
@@ -538,7 +840,7 @@ func createMultiOrgAlertmanager(t *testing.T) *notifier.MultiOrgAlertmanager {
 	require.NoError(t, err)
 	err = mam.LoadAndSyncAlertmanagersForOrgs(context.Background())
 	require.NoError(t, err)
-	return mam
+	return mam, &configStore
 }
 
 var validConfig = `{
@@ -653,6 +955,20 @@ func setTemplateProvenance(t *testing.T, orgID int64, name string, ps provisioni
 	require.NoError(t, err)
 }
 
+func createRequestCtxWithQuery(org int64, query string) *models.ReqContext {
+	rc := createRequestCtxInOrg(org)
+	rc.Req = &http.Request{URL: &url.URL{RawQuery: query}}
+	return rc
+}
+
+func asGettableHistoricUserConfigs(t *testing.T, r response.Response) apimodels.GettableHistoricUserConfigs {
+	t.Helper()
+	body := apimodels.GettableHistoricUserConfigs{}
+	err := json.Unmarshal(r.Body(), &body)
+	require.NoError(t, err)
+	return body
+}
+
 func asGettableUserConfig(t *testing.T, r response.Response) *apimodels.GettableUserConfig {
 	t.Helper()
 	body := &apimodels.GettableUserConfig{}
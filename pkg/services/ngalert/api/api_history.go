@@ -0,0 +1,129 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	// labelQueryParamPrefix is the prefix of query parameters that specify the labels of the alert instances to return the history of.
+	labelQueryParamPrefix = "labels_"
+	// defaultHistoryRange is the time range that is queried if the request does not specify the start of the range.
+	defaultHistoryRange = time.Hour
+)
+
+// StateHistorian queries the history of alert state transitions.
+type StateHistorian interface {
+	QueryStates(ctx context.Context, query ngmodels.HistoryQuery) (*data.Frame, error)
+}
+
+type HistorySrv struct {
+	log       log.Logger
+	historian StateHistorian
+	store     RuleStore
+	ac        accesscontrol.AccessControl
+}
+
+func (srv HistorySrv) RouteGetStateHistory(c *models.ReqContext) response.Response {
+	query := ngmodels.HistoryQuery{
+		OrgID:        c.OrgID,
+		RuleUID:      c.Query("ruleUID"),
+		Labels:       make(map[string]string),
+		SignedInUser: c.SignedInUser,
+	}
+	for key, values := range c.Req.URL.Query() {
+		name := strings.TrimPrefix(key, labelQueryParamPrefix)
+		if name == key || name == "" || len(values) == 0 {
+			continue
+		}
+		query.Labels[name] = values[0]
+	}
+	if query.RuleUID == "" && len(query.Labels) == 0 {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("either ruleUID or at least one label must be specified"), "")
+	}
+
+	query.To = timeNow()
+	if to := c.QueryInt64("to"); to > 0 {
+		query.To = time.Unix(to, 0)
+	}
+	query.From = query.To.Add(-defaultHistoryRange)
+	if from := c.QueryInt64("from"); from > 0 {
+		query.From = time.Unix(from, 0)
+	}
+	if query.From.After(query.To) {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("the start of the time range must not be after its end"), "")
+	}
+	query.Limit = c.QueryInt("limit")
+	if query.Limit < 0 {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("limit must not be negative"), "")
+	}
+
+	namespaceUIDs, ruleUIDs, err := srv.visibleRules(c)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get alert rules visible to the user")
+	}
+	if query.RuleUID != "" {
+		if _, ok := ruleUIDs[query.RuleUID]; !ok {
+			return ErrResp(http.StatusNotFound, ngmodels.ErrAlertRuleNotFound, "")
+		}
+	}
+	// the historian returns only transitions of rules the user can see, so that the limit applies to them.
+	query.NamespaceUIDs = namespaceUIDs
+	query.RuleUIDs = make([]string, 0, len(ruleUIDs))
+	for uid := range ruleUIDs {
+		query.RuleUIDs = append(query.RuleUIDs, uid)
+	}
+
+	frame, err := srv.historian.QueryStates(c.Req.Context(), query)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to query state history")
+	}
+	return response.JSON(http.StatusOK, apimodels.StateHistory{Results: frame})
+}
+
+// visibleRules returns the UIDs of the folders the user can see and the UIDs of the rules that are stored in these folders
+// and query data sources the user can query.
+func (srv HistorySrv) visibleRules(c *models.ReqContext) ([]string, map[string]struct{}, error) {
+	result := make(map[string]struct{})
+	namespaceMap, err := srv.store.GetUserVisibleNamespaces(c.Req.Context(), c.OrgID, c.SignedInUser)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(namespaceMap) == 0 {
+		return nil, result, nil
+	}
+	namespaceUIDs := make([]string, 0, len(namespaceMap))
+	for k := range namespaceMap {
+		namespaceUIDs = append(namespaceUIDs, k)
+	}
+
+	q := ngmodels.ListAlertRulesQuery{
+		OrgID:         c.OrgID,
+		NamespaceUIDs: namespaceUIDs,
+	}
+	if err := srv.store.ListAlertRules(c.Req.Context(), &q); err != nil {
+		return nil, nil, err
+	}
+	hasAccess := func(evaluator accesscontrol.Evaluator) bool {
+		return accesscontrol.HasAccess(srv.ac, c)(accesscontrol.ReqViewer, evaluator)
+	}
+	for _, rule := range q.Result {
+		if !authorizeDatasourceAccessForRule(rule, hasAccess) {
+			continue
+		}
+		result[rule.UID] = struct{}{}
+	}
+	return namespaceUIDs, result, nil
+}
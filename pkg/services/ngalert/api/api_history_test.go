@@ -0,0 +1,167 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	acmock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestRouteGetStateHistory(t *testing.T) {
+	orgID := int64(1)
+
+	t.Run("should return 400 if neither rule UID nor labels are specified", func(t *testing.T) {
+		srv, historian, _ := createHistorySrv(t)
+
+		response := srv.RouteGetStateHistory(createHistoryRequestCtx(orgID, url.Values{"from": {"10"}}))
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+		require.Nil(t, historian.query)
+	})
+
+	t.Run("should return 400 if time range is invalid", func(t *testing.T) {
+		srv, _, _ := createHistorySrv(t)
+
+		response := srv.RouteGetStateHistory(createHistoryRequestCtx(orgID, url.Values{"labels_team": {"a"}, "from": {"20"}, "to": {"10"}}))
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("should return 404 if rule is not visible to the user", func(t *testing.T) {
+		srv, historian, _ := createHistorySrv(t)
+
+		response := srv.RouteGetStateHistory(createHistoryRequestCtx(orgID, url.Values{"ruleUID": {"unknown"}}))
+
+		require.Equal(t, http.StatusNotFound, response.Status())
+		require.Nil(t, historian.query)
+	})
+
+	t.Run("should query historian and return the frame", func(t *testing.T) {
+		srv, historian, ruleStore := createHistorySrv(t)
+		rule := ngmodels.AlertRuleGen(withOrgID(orgID))()
+		ruleStore.PutRule(context.Background(), rule)
+		historian.frame = newTestHistoryFrame(rule.UID)
+
+		response := srv.RouteGetStateHistory(createHistoryRequestCtx(orgID, url.Values{
+			"ruleUID":     {rule.UID},
+			"labels_team": {"a"},
+			"from":        {"10"},
+			"to":          {"20"},
+			"limit":       {"5"},
+		}))
+
+		require.Equal(t, http.StatusOK, response.Status())
+		require.NotNil(t, historian.query)
+		require.Equal(t, orgID, historian.query.OrgID)
+		require.Equal(t, rule.UID, historian.query.RuleUID)
+		require.Equal(t, map[string]string{"team": "a"}, historian.query.Labels)
+		require.Equal(t, time.Unix(10, 0), historian.query.From)
+		require.Equal(t, time.Unix(20, 0), historian.query.To)
+		require.Equal(t, 5, historian.query.Limit)
+
+		var result struct {
+			Results *data.Frame `json:"results"`
+		}
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Equal(t, 1, result.Results.Rows())
+	})
+
+	t.Run("should default to last hour", func(t *testing.T) {
+		srv, historian, _ := createHistorySrv(t)
+		now := time.Unix(10000, 0)
+		timeNow = func() time.Time { return now }
+		t.Cleanup(func() { timeNow = time.Now })
+
+		response := srv.RouteGetStateHistory(createHistoryRequestCtx(orgID, url.Values{"labels_team": {"a"}}))
+
+		require.Equal(t, http.StatusOK, response.Status())
+		require.Equal(t, now, historian.query.To)
+		require.Equal(t, now.Add(-time.Hour), historian.query.From)
+	})
+
+	t.Run("should query only transitions of rules visible to the user", func(t *testing.T) {
+		srv, historian, ruleStore := createHistorySrv(t)
+		rule := ngmodels.AlertRuleGen(withOrgID(orgID))()
+		ruleStore.PutRule(context.Background(), rule)
+		hidden := ngmodels.AlertRuleGen(withOrgID(orgID))()
+		ruleStore.PutRule(context.Background(), hidden)
+		ruleStore.Folders[orgID] = ruleStore.Folders[orgID][:1]
+
+		response := srv.RouteGetStateHistory(createHistoryRequestCtx(orgID, url.Values{"labels_team": {"a"}}))
+
+		require.Equal(t, http.StatusOK, response.Status())
+		require.Equal(t, []string{rule.UID}, historian.query.RuleUIDs)
+		require.Equal(t, []string{rule.NamespaceUID}, historian.query.NamespaceUIDs)
+	})
+
+	t.Run("should query no rules if the user cannot see any folder", func(t *testing.T) {
+		srv, historian, _ := createHistorySrv(t)
+
+		response := srv.RouteGetStateHistory(createHistoryRequestCtx(orgID, url.Values{"labels_team": {"a"}}))
+
+		require.Equal(t, http.StatusOK, response.Status())
+		require.NotNil(t, historian.query.RuleUIDs)
+		require.Empty(t, historian.query.RuleUIDs)
+	})
+}
+
+type fakeStateHistorian struct {
+	query *ngmodels.HistoryQuery
+	frame *data.Frame
+}
+
+func (f *fakeStateHistorian) QueryStates(_ context.Context, query ngmodels.HistoryQuery) (*data.Frame, error) {
+	f.query = &query
+	if f.frame == nil {
+		return newTestHistoryFrame(), nil
+	}
+	return f.frame, nil
+}
+
+func newTestHistoryFrame(ruleUIDs ...string) *data.Frame {
+	times := make([]time.Time, 0, len(ruleUIDs))
+	states := make([]string, 0, len(ruleUIDs))
+	for i := range ruleUIDs {
+		times = append(times, time.Unix(int64(i), 0))
+		states = append(states, "Alerting")
+	}
+	return data.NewFrame("states",
+		data.NewField("time", nil, times),
+		data.NewField("ruleUID", nil, append([]string{}, ruleUIDs...)),
+		data.NewField("current", nil, states),
+	)
+}
+
+func createHistorySrv(t *testing.T) (HistorySrv, *fakeStateHistorian, *fakes.RuleStore) {
+	t.Helper()
+	historian := &fakeStateHistorian{}
+	ruleStore := fakes.NewRuleStore(t)
+	return HistorySrv{
+		log:       log.NewNopLogger(),
+		historian: historian,
+		store:     ruleStore,
+		ac:        acmock.New().WithDisabled(),
+	}, historian, ruleStore
+}
+
+func createHistoryRequestCtx(orgID int64, values url.Values) *models.ReqContext {
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/ngalert/history?"+values.Encode(), nil)
+	return &models.ReqContext{
+		Context:      &web.Context{Req: req},
+		SignedInUser: &user.SignedInUser{OrgID: orgID, OrgRole: org.RoleViewer},
+	}
+}
@@ -193,11 +193,12 @@ func (srv PrometheusSrv) toRuleGroup(groupName string, folder *models.Folder, ru
 	ngmodels.RulesGroup(rules).SortByGroupIndex()
 	for _, rule := range rules {
 		alertingRule := apimodels.AlertingRule{
-			State:       "inactive",
-			Name:        rule.Title,
-			Query:       ruleToQuery(srv.log, rule),
-			Duration:    rule.For.Seconds(),
-			Annotations: rule.Annotations,
+			State:         "inactive",
+			Name:          rule.Title,
+			Query:         ruleToQuery(srv.log, rule),
+			Duration:      rule.For.Seconds(),
+			KeepFiringFor: rule.KeepFiringFor.Seconds(),
+			Annotations:   rule.Annotations,
 		}
 
 		newRule := apimodels.Rule{
@@ -206,6 +207,19 @@ func (srv PrometheusSrv) toRuleGroup(groupName string, folder *models.Folder, ru
 			Health:         "ok",
 			Type:           apiv1.RuleTypeAlerting,
 			LastEvaluation: time.Time{},
+			IsPaused:       rule.IsPaused,
+		}
+
+		if rule.Type() == ngmodels.RuleTypeRecording {
+			// recording rules do not produce alert instances, and therefore, they do not have alerting state.
+			alertingRule.State = ""
+			alertingRule.Duration = 0
+			alertingRule.KeepFiringFor = 0
+			newRule.Type = apiv1.RuleTypeRecording
+			alertingRule.Rule = newRule
+			newGroup.Rules = append(newGroup.Rules, alertingRule)
+			newGroup.Interval = float64(rule.IntervalSeconds)
+			continue
 		}
 
 		for _, alertState := range srv.manager.GetStatesForRuleUID(rule.OrgID, rule.UID) {
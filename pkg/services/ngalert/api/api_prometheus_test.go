@@ -464,6 +464,33 @@ func TestRouteGetRuleStatuses(t *testing.T) {
 		})
 	})
 
+	t.Run("with a paused rule", func(t *testing.T) {
+		ruleStore := fakes.NewRuleStore(t)
+		fakeAIM := NewFakeAlertInstanceManager(t)
+		rule := ngmodels.AlertRuleGen(ngmodels.WithOrgID(orgID), func(rule *ngmodels.AlertRule) {
+			rule.IsPaused = true
+		})()
+		ruleStore.PutRule(context.Background(), rule)
+
+		api := PrometheusSrv{
+			log:     log.NewNopLogger(),
+			manager: fakeAIM,
+			store:   ruleStore,
+			ac:      acmock.New().WithDisabled(),
+		}
+
+		response := api.RouteGetRuleStatuses(c)
+		require.Equal(t, http.StatusOK, response.Status())
+		result := &apimodels.RuleResponse{}
+		require.NoError(t, json.Unmarshal(response.Body(), result))
+
+		require.Len(t, result.Data.RuleGroups, 1)
+		group := result.Data.RuleGroups[0]
+		require.Len(t, group.Rules, 1)
+		require.Equal(t, rule.Title, group.Rules[0].Name)
+		require.True(t, group.Rules[0].IsPaused)
+	})
+
 	t.Run("when fine-grained access is enabled", func(t *testing.T) {
 		t.Run("should return only rules if the user can query all data sources", func(t *testing.T) {
 			ruleStore := fakes.NewRuleStore(t)
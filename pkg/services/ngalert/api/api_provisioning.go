@@ -1,27 +1,39 @@
 package api
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	alerting_models "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/util/cmputil"
+	"gopkg.in/yaml.v3"
 )
 
 type ProvisioningSrv struct {
 	log                 log.Logger
+	ac                  accesscontrol.AccessControl
 	policies            NotificationPolicyService
 	contactPointService ContactPointService
 	templates           TemplateService
 	muteTimings         MuteTimingService
 	alertRules          AlertRuleService
+	namespaces          NamespaceService
+	conditionValidator  ConditionValidator
 }
 
 type ContactPointService interface {
@@ -50,13 +62,24 @@ type MuteTimingService interface {
 	DeleteMuteTiming(ctx context.Context, name string, orgID int64) error
 }
 
+// NamespaceService returns the folders of alert rules that a user can see.
+type NamespaceService interface {
+	GetUserVisibleNamespaces(ctx context.Context, orgID int64, user *user.SignedInUser) (map[string]*models.Folder, error)
+}
+
 type AlertRuleService interface {
 	GetAlertRule(ctx context.Context, orgID int64, ruleUID string) (alerting_models.AlertRule, alerting_models.Provenance, error)
 	CreateAlertRule(ctx context.Context, rule alerting_models.AlertRule, provenance alerting_models.Provenance, userID int64) (alerting_models.AlertRule, error)
 	UpdateAlertRule(ctx context.Context, rule alerting_models.AlertRule, provenance alerting_models.Provenance) (alerting_models.AlertRule, error)
 	DeleteAlertRule(ctx context.Context, orgID int64, ruleUID string, provenance alerting_models.Provenance) error
 	GetRuleGroup(ctx context.Context, orgID int64, folder, group string) (alerting_models.AlertRuleGroup, error)
+	GetRuleGroups(ctx context.Context, orgID int64, folderUIDs []string, group string) ([]alerting_models.AlertRuleGroup, error)
 	ReplaceRuleGroup(ctx context.Context, orgID int64, group alerting_models.AlertRuleGroup, userID int64, provenance alerting_models.Provenance) error
+	SetRuleGroupPaused(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string, isPaused bool, provenance alerting_models.Provenance) (alerting_models.AlertRuleGroup, error)
+	GetAlertRuleVersions(ctx context.Context, orgID int64, ruleUID string) ([]*alerting_models.AlertRuleVersion, error)
+	GetAlertRuleVersion(ctx context.Context, orgID int64, ruleUID string, version int64) (*alerting_models.AlertRuleVersion, error)
+	DiffAlertRuleVersions(ctx context.Context, orgID int64, ruleUID string, version int64, compareTo int64) (cmputil.DiffReport, error)
+	RestoreAlertRuleVersion(ctx context.Context, orgID int64, ruleUID string, version int64, provenance alerting_models.Provenance) (alerting_models.AlertRule, error)
 }
 
 func (srv *ProvisioningSrv) RouteGetPolicyTree(c *models.ReqContext) response.Response {
@@ -310,6 +333,109 @@ func (srv *ProvisioningSrv) RouteDeleteAlertRule(c *models.ReqContext, UID strin
 	return response.JSON(http.StatusNoContent, "")
 }
 
+func (srv *ProvisioningSrv) RouteGetAlertRuleVersions(c *models.ReqContext, UID string) response.Response {
+	rule, provenance, err := srv.alertRules.GetAlertRule(c.Req.Context(), c.OrgID, UID)
+	if err != nil {
+		if errors.Is(err, alerting_models.ErrAlertRuleNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	versions, err := srv.alertRules.GetAlertRuleVersions(c.Req.Context(), c.OrgID, rule.UID)
+	if err != nil {
+		if errors.Is(err, alerting_models.ErrAlertRuleNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	result, err := definitions.NewAlertRuleVersions(versions, provenance)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRuleVersion(c *models.ReqContext, UID string, version string) response.Response {
+	v, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to parse version")
+	}
+	_, provenance, err := srv.alertRules.GetAlertRule(c.Req.Context(), c.OrgID, UID)
+	if err != nil {
+		if errors.Is(err, alerting_models.ErrAlertRuleNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	ruleVersion, err := srv.alertRules.GetAlertRuleVersion(c.Req.Context(), c.OrgID, UID, v)
+	if err != nil {
+		if errors.Is(err, alerting_models.ErrAlertRuleVersionNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	result, err := definitions.NewAlertRuleVersion(*ruleVersion, provenance)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRuleVersionDiff(c *models.ReqContext, UID string, version string) response.Response {
+	v, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to parse version")
+	}
+	compareTo := c.QueryInt64("compareTo")
+	if compareTo < 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("compareTo must not be negative"), "")
+	}
+	diff, err := srv.alertRules.DiffAlertRuleVersions(c.Req.Context(), c.OrgID, UID, v, compareTo)
+	if err != nil {
+		if errors.Is(err, alerting_models.ErrAlertRuleNotFound) || errors.Is(err, alerting_models.ErrAlertRuleVersionNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, definitions.NewAlertRuleVersionDiff(v, compareTo, diff))
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertRuleVersionRestore(c *models.ReqContext, UID string, version string) response.Response {
+	v, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to parse version")
+	}
+	// the version could have been valid when it was stored but refer to data sources or queries that are no longer valid
+	ruleVersion, err := srv.alertRules.GetAlertRuleVersion(c.Req.Context(), c.OrgID, UID, v)
+	if err != nil {
+		if errors.Is(err, alerting_models.ErrAlertRuleNotFound) || errors.Is(err, alerting_models.ErrAlertRuleVersionNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	versionRule, err := ruleVersion.ToAlertRule()
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	if err := srv.conditionValidator.Validate(c.Req.Context(), c.SignedInUser, versionRule.GetEvalCondition()); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to validate condition of the rule version")
+	}
+	restored, err := srv.alertRules.RestoreAlertRuleVersion(c.Req.Context(), c.OrgID, UID, v, alerting_models.ProvenanceAPI)
+	if err != nil {
+		if errors.Is(err, alerting_models.ErrAlertRuleNotFound) || errors.Is(err, alerting_models.ErrAlertRuleVersionNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		if errors.Is(err, store.ErrOptimisticLock) {
+			return ErrResp(http.StatusConflict, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, definitions.NewAlertRule(restored, alerting_models.ProvenanceAPI))
+}
+
 func (srv *ProvisioningSrv) RouteGetAlertRuleGroup(c *models.ReqContext, folder string, group string) response.Response {
 	g, err := srv.alertRules.GetRuleGroup(c.Req.Context(), c.OrgID, folder, group)
 	if err != nil {
@@ -340,3 +466,176 @@ func (srv *ProvisioningSrv) RoutePutAlertRuleGroup(c *models.ReqContext, ag defi
 	}
 	return response.JSON(http.StatusOK, ag)
 }
+
+func (srv *ProvisioningSrv) RoutePostAlertRuleGroupPause(c *models.ReqContext, folderUID string, group string) response.Response {
+	return srv.setAlertRuleGroupPaused(c, folderUID, group, true)
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertRuleGroupResume(c *models.ReqContext, folderUID string, group string) response.Response {
+	return srv.setAlertRuleGroupPaused(c, folderUID, group, false)
+}
+
+func (srv *ProvisioningSrv) setAlertRuleGroupPaused(c *models.ReqContext, folderUID string, group string, isPaused bool) response.Response {
+	g, err := srv.alertRules.SetRuleGroupPaused(c.Req.Context(), c.OrgID, folderUID, group, isPaused, alerting_models.ProvenanceAPI)
+	if err != nil {
+		if errors.Is(err, store.ErrAlertRuleGroupNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, definitions.NewAlertRuleGroupFromModel(g))
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRulesExport(c *models.ReqContext) response.Response {
+	folderUID := c.Query("folderUid")
+	group := c.Query("group")
+	if group != "" && folderUID == "" {
+		return ErrResp(http.StatusBadRequest, errors.New("group requires folderUid to be set"), "")
+	}
+	return srv.exportAlertRuleGroups(c, folderUID, group)
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRuleGroupExport(c *models.ReqContext, folderUID string, group string) response.Response {
+	return srv.exportAlertRuleGroups(c, folderUID, group)
+}
+
+// exportAlertRuleGroups exports the rule groups of the folders the user can see. If folderUID is set, only the rule
+// groups of the folder are exported, and if group is set as well, only the rule group with this title.
+func (srv *ProvisioningSrv) exportAlertRuleGroups(c *models.ReqContext, folderUID string, group string) response.Response {
+	namespaces, err := srv.namespaces.GetUserVisibleNamespaces(c.Req.Context(), c.OrgID, c.SignedInUser)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get folders")
+	}
+	folderUIDs := make([]string, 0, len(namespaces))
+	if folderUID != "" {
+		if _, ok := namespaces[folderUID]; !ok {
+			return ErrResp(http.StatusNotFound, fmt.Errorf("folder with UID '%s' not found", folderUID), "")
+		}
+		folderUIDs = append(folderUIDs, folderUID)
+	} else {
+		for uid := range namespaces {
+			folderUIDs = append(folderUIDs, uid)
+		}
+	}
+
+	groups, err := srv.alertRules.GetRuleGroups(c.Req.Context(), c.OrgID, folderUIDs, group)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	if group != "" && len(groups) == 0 {
+		return ErrResp(http.StatusNotFound, store.ErrAlertRuleGroupNotFound, "")
+	}
+
+	file := definitions.NewAlertingFileExport()
+	for _, g := range groups {
+		exported, err := definitions.NewAlertRuleGroupExport(c.OrgID, namespaces[g.FolderUID].Title, g)
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "")
+		}
+		file.Groups = append(file.Groups, exported)
+	}
+	return exportResponse(c, file)
+}
+
+func (srv *ProvisioningSrv) RouteGetContactPointsExport(c *models.ReqContext) response.Response {
+	q := provisioning.ContactPointQuery{
+		Name:    c.Query("name"),
+		OrgID:   c.OrgID,
+		Decrypt: c.QueryBool("decrypt"),
+	}
+	if q.Decrypt {
+		hasAccess := accesscontrol.HasAccess(srv.ac, c)
+		if !hasAccess(accesscontrol.ReqOrgAdmin, accesscontrol.EvalPermission(accesscontrol.ActionAlertingProvisioningReadSecrets)) {
+			return ErrResp(http.StatusForbidden, errors.New("permission to read secrets is required to decrypt contact points"), "")
+		}
+	}
+	cps, err := srv.contactPointService.GetContactPoints(c.Req.Context(), q)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	file := definitions.NewAlertingFileExport()
+	file.ContactPoints, err = definitions.NewContactPointExports(c.OrgID, cps)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return exportResponse(c, file)
+}
+
+func (srv *ProvisioningSrv) RouteGetPolicyTreeExport(c *models.ReqContext) response.Response {
+	tree, err := srv.policies.GetPolicyTree(c.Req.Context(), c.OrgID)
+	if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	file := definitions.NewAlertingFileExport()
+	file.Policies = []definitions.NotificationPolicyExport{definitions.NewNotificationPolicyExport(c.OrgID, tree)}
+	return exportResponse(c, file)
+}
+
+func (srv *ProvisioningSrv) RouteGetMuteTimingsExport(c *models.ReqContext) response.Response {
+	timings, err := srv.muteTimings.GetMuteTimings(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	file := definitions.NewAlertingFileExport()
+	for _, timing := range timings {
+		file.MuteTimes = append(file.MuteTimes, definitions.NewMuteTimeIntervalExport(c.OrgID, timing))
+	}
+	return exportResponse(c, file)
+}
+
+func (srv *ProvisioningSrv) RouteGetTemplatesExport(c *models.ReqContext) response.Response {
+	templates, err := srv.templates.GetTemplates(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	file := definitions.NewAlertingFileExport()
+	for _, name := range names {
+		tmpl := definitions.MessageTemplate{Name: name, Template: templates[name]}
+		file.Templates = append(file.Templates, definitions.NewMessageTemplateExport(c.OrgID, tmpl))
+	}
+	return exportResponse(c, file)
+}
+
+// exportResponse returns the file in the format of the format query parameter, YAML by default. If the download query
+// parameter is set, the file is returned as an attachment.
+func exportResponse(c *models.ReqContext, file definitions.AlertingFileExport) response.Response {
+	format := c.Query("format")
+	if format == "" {
+		format = "yaml"
+	}
+
+	var body []byte
+	var contentType string
+	switch format {
+	case "yaml":
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(file); err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to marshal export")
+		}
+		body, contentType = buf.Bytes(), "application/yaml"
+	case "json":
+		b, err := json.MarshalIndent(file, "", "  ")
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to marshal export")
+		}
+		body, contentType = b, "application/json"
+	default:
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("unsupported format '%s', must be yaml or json", format), "")
+	}
+
+	resp := response.Respond(http.StatusOK, body).SetHeader("Content-Type", contentType)
+	if c.QueryBool("download") {
+		resp.SetHeader("Content-Disposition", fmt.Sprintf(`attachment;filename=export.%s`, format))
+	}
+	return resp
+}
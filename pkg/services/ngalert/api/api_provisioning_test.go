@@ -3,23 +3,31 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"testing"
 	"time"
 
 	prometheus "github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 
+	gfresponse "github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
 	gfcore "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	acMock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	secrets_fakes "github.com/grafana/grafana/pkg/services/secrets/fakes"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
@@ -314,6 +322,155 @@ func TestProvisioningApi(t *testing.T) {
 		})
 	})
 
+	t.Run("alert rule versions", func(t *testing.T) {
+		t.Run("are present, GET returns 200 with latest version first", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rule := createTestAlertRule("rule", 1)
+			rule.UID = t.Name()
+			insertRule(t, sut, rule)
+			rule.Title = "updated rule"
+			require.Equal(t, 200, sut.RoutePutAlertRule(&rc, rule, rule.UID).Status())
+
+			response := sut.RouteGetAlertRuleVersions(&rc, rule.UID)
+
+			require.Equal(t, 200, response.Status())
+			var versions definitions.ProvisionedAlertRuleVersions
+			require.NoError(t, json.Unmarshal(response.Body(), &versions))
+			require.Len(t, versions, 2)
+			require.Equal(t, int64(2), versions[0].Version)
+			require.Equal(t, int64(1), versions[0].ParentVersion)
+			require.Equal(t, "updated rule", versions[0].Rule.Title)
+			require.Equal(t, int64(1), versions[1].Version)
+			require.Equal(t, "rule", versions[1].Rule.Title)
+		})
+
+		t.Run("rule is missing, GET returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetAlertRuleVersions(&rc, "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("version is missing, GET returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rule := createTestAlertRule("rule", 1)
+			rule.UID = t.Name()
+			insertRule(t, sut, rule)
+
+			response := sut.RouteGetAlertRuleVersion(&rc, rule.UID, "10")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("version is not a number, GET returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetAlertRuleVersion(&rc, "rule", "latest")
+
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("diff with current rule returns changed fields", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rule := createTestAlertRule("rule", 1)
+			rule.UID = t.Name()
+			insertRule(t, sut, rule)
+			rule.Title = "updated rule"
+			require.Equal(t, 200, sut.RoutePutAlertRule(&rc, rule, rule.UID).Status())
+
+			response := sut.RouteGetAlertRuleVersionDiff(&rc, rule.UID, "1")
+
+			require.Equal(t, 200, response.Status())
+			var diff definitions.AlertRuleVersionDiff
+			require.NoError(t, json.Unmarshal(response.Body(), &diff))
+			require.Equal(t, int64(1), diff.Version)
+			require.Equal(t, int64(0), diff.CompareTo)
+			require.Len(t, diff.Diffs, 1)
+			require.Equal(t, "Title", diff.Diffs[0].Path)
+			require.Equal(t, "rule", diff.Diffs[0].Left)
+			require.Equal(t, "updated rule", diff.Diffs[0].Right)
+		})
+
+		t.Run("diff of same versions returns no changes", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.Form = url.Values{"compareTo": []string{"1"}}
+			rule := createTestAlertRule("rule", 1)
+			rule.UID = t.Name()
+			insertRule(t, sut, rule)
+
+			response := sut.RouteGetAlertRuleVersionDiff(&rc, rule.UID, "1")
+
+			require.Equal(t, 200, response.Status())
+			var diff definitions.AlertRuleVersionDiff
+			require.NoError(t, json.Unmarshal(response.Body(), &diff))
+			require.Equal(t, int64(1), diff.CompareTo)
+			require.Empty(t, diff.Diffs)
+		})
+
+		t.Run("restore returns restored rule", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rule := createTestAlertRule("rule", 1)
+			rule.UID = t.Name()
+			// the restored version is validated again, and therefore, the time range must survive serialization of the version.
+			rule.Data[0].RelativeTimeRange.From = models.Duration(time.Minute)
+			insertRule(t, sut, rule)
+			rule.Title = "updated rule"
+			require.Equal(t, 200, sut.RoutePutAlertRule(&rc, rule, rule.UID).Status())
+
+			response := sut.RoutePostAlertRuleVersionRestore(&rc, rule.UID, "1")
+
+			require.Equal(t, 200, response.Status())
+			restored := deserializeRule(t, response.Body())
+			require.Equal(t, "rule", restored.Title)
+
+			response = sut.RouteGetAlertRuleVersion(&rc, rule.UID, "3")
+			require.Equal(t, 200, response.Status())
+			var version definitions.ProvisionedAlertRuleVersion
+			require.NoError(t, json.Unmarshal(response.Body(), &version))
+			require.Equal(t, int64(1), version.RestoredFrom)
+			require.Equal(t, int64(2), version.ParentVersion)
+			require.Equal(t, "rule", version.Rule.Title)
+		})
+
+		t.Run("restore of version with invalid condition returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			sut.conditionValidator = fakeConditionValidator{err: errors.New("data source not found")}
+			rc := createTestRequestCtx()
+			rule := createTestAlertRule("rule", 1)
+			rule.UID = t.Name()
+			insertRule(t, sut, rule)
+			rule.Title = "updated rule"
+			require.Equal(t, 200, sut.RoutePutAlertRule(&rc, rule, rule.UID).Status())
+
+			response := sut.RoutePostAlertRuleVersionRestore(&rc, rule.UID, "1")
+
+			require.Equal(t, 400, response.Status())
+			response = sut.RouteRouteGetAlertRule(&rc, rule.UID)
+			require.Equal(t, 200, response.Status())
+			require.Equal(t, "updated rule", deserializeRule(t, response.Body()).Title)
+		})
+
+		t.Run("restore of missing version returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rule := createTestAlertRule("rule", 1)
+			rule.UID = t.Name()
+			insertRule(t, sut, rule)
+
+			response := sut.RoutePostAlertRuleVersionRestore(&rc, rule.UID, "10")
+
+			require.Equal(t, 404, response.Status())
+		})
+	})
+
 	t.Run("alert rule groups", func(t *testing.T) {
 		t.Run("are present, GET returns 200", func(t *testing.T) {
 			sut := createProvisioningSrvSut(t)
@@ -335,6 +492,37 @@ func TestProvisioningApi(t *testing.T) {
 			require.Equal(t, 404, response.Status())
 		})
 
+		t.Run("are paused and resumed, POST returns 200", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rule := createTestAlertRule("rule", 1)
+			rule.Data[0].RelativeTimeRange.From = models.Duration(time.Minute)
+			insertRule(t, sut, rule)
+
+			response := sut.RoutePostAlertRuleGroupPause(&rc, "folder-uid", "my-cool-group")
+
+			require.Equal(t, 200, response.Status())
+			group := definitions.AlertRuleGroup{}
+			require.NoError(t, json.Unmarshal(response.Body(), &group))
+			require.Len(t, group.Rules, 1)
+			require.True(t, group.Rules[0].IsPaused)
+
+			response = sut.RoutePostAlertRuleGroupResume(&rc, "folder-uid", "my-cool-group")
+
+			require.Equal(t, 200, response.Status())
+			require.NoError(t, json.Unmarshal(response.Body(), &group))
+			require.False(t, group.Rules[0].IsPaused)
+		})
+
+		t.Run("are missing, pause returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostAlertRuleGroupPause(&rc, "folder-uid", "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
 		t.Run("are invalid at group level", func(t *testing.T) {
 			t.Run("PUT returns 400", func(t *testing.T) {
 				sut := createProvisioningSrvSut(t)
@@ -366,6 +554,167 @@ func TestProvisioningApi(t *testing.T) {
 			})
 		})
 	})
+
+	t.Run("exports", func(t *testing.T) {
+		t.Run("alert rules are exported as YAML by default", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.Form = url.Values{}
+			rule := createTestAlertRule("rule", 1)
+			rule.Labels = map[string]string{"team": "$team"}
+			insertRule(t, sut, rule)
+
+			response := sut.RouteGetAlertRulesExport(&rc)
+
+			require.Equal(t, 200, response.Status())
+			require.Equal(t, "application/yaml", response.(*gfresponse.NormalResponse).Header().Get("Content-Type"))
+			var file definitions.AlertingFileExport
+			require.NoError(t, yaml.Unmarshal(response.Body(), &file))
+			require.Equal(t, int64(1), file.APIVersion)
+			require.Len(t, file.Groups, 1)
+			require.Equal(t, "Folder Title", file.Groups[0].Folder)
+			require.Equal(t, "my-cool-group", file.Groups[0].Name)
+			require.Equal(t, "1m0s", file.Groups[0].Interval)
+			require.Len(t, file.Groups[0].Rules, 1)
+			require.Equal(t, "rule", file.Groups[0].Rules[0].Title)
+			require.Equal(t, map[string]string{"team": "$$team"}, file.Groups[0].Rules[0].Labels)
+		})
+
+		t.Run("alert rules are exported as JSON", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.Form = url.Values{"format": []string{"json"}, "download": []string{"true"}}
+			insertRule(t, sut, createTestAlertRule("rule", 1))
+
+			response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
+
+			require.Equal(t, 200, response.Status())
+			header := response.(*gfresponse.NormalResponse).Header()
+			require.Equal(t, "application/json", header.Get("Content-Type"))
+			require.Equal(t, "attachment;filename=export.json", header.Get("Content-Disposition"))
+			var file definitions.AlertingFileExport
+			require.NoError(t, json.Unmarshal(response.Body(), &file))
+			require.Len(t, file.Groups, 1)
+			require.Equal(t, "A", file.Groups[0].Rules[0].Data[0].RefID)
+		})
+
+		t.Run("alert rules of a folder that is not visible return 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.Form = url.Values{"folderUid": []string{"unknown"}}
+
+			response := sut.RouteGetAlertRulesExport(&rc)
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("missing rule group returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.Form = url.Values{}
+			insertRule(t, sut, createTestAlertRule("rule", 1))
+
+			response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("rule group without folder returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.Form = url.Values{"group": []string{"my-cool-group"}}
+
+			response := sut.RouteGetAlertRulesExport(&rc)
+
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("unsupported format returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.Form = url.Values{"format": []string{"hcl"}}
+
+			response := sut.RouteGetMuteTimingsExport(&rc)
+
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("contact points are grouped by name", func(t *testing.T) {
+			env := createTestEnv(t)
+			env.prov.(*provisioning.MockProvisioningStore).EXPECT().
+				GetProvenances(mock.Anything, mock.Anything, mock.Anything).
+				Return(map[string]models.Provenance{}, nil)
+			sut := createProvisioningSrvSutFromEnv(t, &env)
+			rc := createTestRequestCtx()
+			rc.Req.Form = url.Values{"format": []string{"json"}}
+
+			response := sut.RouteGetContactPointsExport(&rc)
+
+			require.Equal(t, 200, response.Status())
+			var file definitions.AlertingFileExport
+			require.NoError(t, json.Unmarshal(response.Body(), &file))
+			require.Len(t, file.ContactPoints, 1)
+			require.Equal(t, "email receiver", file.ContactPoints[0].Name)
+			require.Len(t, file.ContactPoints[0].Receivers, 1)
+			require.Equal(t, "email-uid", file.ContactPoints[0].Receivers[0].UID)
+			require.Equal(t, "<example@email.com>", file.ContactPoints[0].Receivers[0].Settings["addresses"])
+		})
+
+		t.Run("decrypted contact points require permission to read secrets", func(t *testing.T) {
+			testCases := map[string]struct {
+				ac     *acMock.Mock
+				role   org.RoleType
+				status int
+			}{
+				"provisioning reader":                       {ac: acMock.New().WithPermissions([]accesscontrol.Permission{{Action: accesscontrol.ActionAlertingProvisioningRead}}), status: 403},
+				"provisioning secrets reader":               {ac: acMock.New().WithPermissions([]accesscontrol.Permission{{Action: accesscontrol.ActionAlertingProvisioningRead}, {Action: accesscontrol.ActionAlertingProvisioningReadSecrets}}), status: 200},
+				"editor without access control":             {ac: acMock.New().WithDisabled(), role: org.RoleEditor, status: 403},
+				"organization admin without access control": {ac: acMock.New().WithDisabled(), role: org.RoleAdmin, status: 200},
+			}
+			for name, tc := range testCases {
+				t.Run(name, func(t *testing.T) {
+					env := createTestEnv(t)
+					env.prov.(*provisioning.MockProvisioningStore).EXPECT().
+						GetProvenances(mock.Anything, mock.Anything, mock.Anything).
+						Return(map[string]models.Provenance{}, nil).Maybe()
+					sut := createProvisioningSrvSutFromEnv(t, &env)
+					sut.ac = tc.ac
+					rc := createTestRequestCtx()
+					rc.OrgRole = tc.role
+					rc.Req.Form = url.Values{"format": []string{"json"}, "decrypt": []string{"true"}}
+
+					response := sut.RouteGetContactPointsExport(&rc)
+
+					require.Equal(t, tc.status, response.Status())
+				})
+			}
+		})
+
+		t.Run("notification policies, mute timings and templates are exported", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Req.Form = url.Values{"format": []string{"json"}}
+
+			var file definitions.AlertingFileExport
+			response := sut.RouteGetPolicyTreeExport(&rc)
+			require.Equal(t, 200, response.Status())
+			require.NoError(t, json.Unmarshal(response.Body(), &file))
+			require.Len(t, file.Policies, 1)
+			require.Equal(t, int64(1), file.Policies[0].OrgID)
+			require.Equal(t, "some-receiver", file.Policies[0].Receiver)
+
+			response = sut.RouteGetMuteTimingsExport(&rc)
+			require.Equal(t, 200, response.Status())
+			require.NoError(t, json.Unmarshal(response.Body(), &file))
+			require.Len(t, file.MuteTimes, 1)
+			require.Equal(t, "interval", file.MuteTimes[0].Name)
+
+			response = sut.RouteGetTemplatesExport(&rc)
+			require.Equal(t, 200, response.Status())
+			require.NoError(t, json.Unmarshal(response.Body(), &file))
+			require.Equal(t, []definitions.MessageTemplateExport{{OrgID: 1, Name: "a", Template: "template"}}, file.Templates)
+		})
+	})
 }
 
 // testEnvironment binds together common dependencies for testing alerting APIs.
@@ -426,14 +775,25 @@ func createProvisioningSrvSutFromEnv(t *testing.T, env *testEnvironment) Provisi
 
 	return ProvisioningSrv{
 		log:                 env.log,
+		ac:                  acMock.New().WithDisabled(),
 		policies:            newFakeNotificationPolicyService(),
 		contactPointService: provisioning.NewContactPointService(env.configs, env.secrets, env.prov, env.xact, env.log),
 		templates:           provisioning.NewTemplateService(env.configs, env.prov, env.xact, env.log),
 		muteTimings:         provisioning.NewMuteTimingService(env.configs, env.prov, env.xact, env.log),
 		alertRules:          provisioning.NewAlertRuleService(env.store, env.prov, env.quotas, env.xact, 60, 10, env.log),
+		namespaces: fakeNamespaceService{
+			"folder-uid": {Uid: "folder-uid", Title: "Folder Title"},
+		},
+		conditionValidator: fakeConditionValidator{},
 	}
 }
 
+type fakeNamespaceService map[string]*gfcore.Folder
+
+func (f fakeNamespaceService) GetUserVisibleNamespaces(_ context.Context, _ int64, _ *user.SignedInUser) (map[string]*gfcore.Folder, error) {
+	return f, nil
+}
+
 func createTestRequestCtx() gfcore.ReqContext {
 	return gfcore.ReqContext{
 		Context: &web.Context{
@@ -5,11 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/quota"
@@ -43,6 +45,7 @@ type RulerSrv struct {
 	cfg                *setting.UnifiedAlertingSettings
 	ac                 accesscontrol.AccessControl
 	conditionValidator ConditionValidator
+	datasourceCache    datasources.CacheService
 }
 
 var (
@@ -301,6 +304,228 @@ func (srv RulerSrv) RouteGetRulesConfig(c *models.ReqContext) response.Response
 	return response.JSON(http.StatusOK, result)
 }
 
+// RouteGetRuleVersionsByUID returns the versions of the rule, latest first. Versions that use data sources
+// the user does not have access to are omitted.
+func (srv RulerSrv) RouteGetRuleVersionsByUID(c *models.ReqContext, ruleUID string) response.Response {
+	rule, _, folder, err := srv.getRuleWithGroup(c, ruleUID)
+	if err != nil {
+		return toRuleVersionErrorResponse(err)
+	}
+	q := ngmodels.ListAlertRuleVersionsQuery{
+		OrgID: c.SignedInUser.OrgID,
+		UID:   rule.UID,
+	}
+	if err := srv.store.ListAlertRuleVersions(c.Req.Context(), &q); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get rule versions")
+	}
+	provenanceRecords, err := srv.provenanceStore.GetProvenances(c.Req.Context(), c.SignedInUser.OrgID, rule.ResourceType())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get rule versions")
+	}
+	hasAccess := func(evaluator accesscontrol.Evaluator) bool {
+		return accesscontrol.HasAccess(srv.ac, c)(accesscontrol.ReqViewer, evaluator)
+	}
+
+	result := make(apimodels.GettableRuleVersions, 0, len(q.Result))
+	for _, v := range q.Result {
+		versionRule, err := v.ToAlertRule()
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to get rule versions")
+		}
+		if !authorizeDatasourceAccessForRule(&versionRule, hasAccess) {
+			continue
+		}
+		result = append(result, apimodels.GettableRuleVersion{
+			Version:       v.Version,
+			ParentVersion: v.ParentVersion,
+			RestoredFrom:  v.RestoredFrom,
+			Created:       v.Created,
+			Rule:          toGettableExtendedRuleNode(versionRule, folder.Id, provenanceRecords),
+		})
+	}
+	return response.JSON(http.StatusAccepted, result)
+}
+
+// RouteGetRuleVersionDiff returns the differences between the version of the rule and the version set by the
+// compareTo query parameter, or the current rule if it is not set.
+func (srv RulerSrv) RouteGetRuleVersionDiff(c *models.ReqContext, ruleUID string, version string) response.Response {
+	v, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to parse version")
+	}
+	compareTo := c.QueryInt64("compareTo")
+	if compareTo < 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("compareTo must not be negative"), "")
+	}
+	rule, _, _, err := srv.getRuleWithGroup(c, ruleUID)
+	if err != nil {
+		return toRuleVersionErrorResponse(err)
+	}
+	from, err := srv.getRuleVersion(c, rule.UID, v)
+	if err != nil {
+		return toRuleVersionErrorResponse(err)
+	}
+	to := *rule
+	if compareTo != 0 {
+		to, err = srv.getRuleVersion(c, rule.UID, compareTo)
+		if err != nil {
+			return toRuleVersionErrorResponse(err)
+		}
+	}
+	diff := from.Diff(&to, store.AlertRuleFieldsToIgnoreInDiff[:]...)
+	return response.JSON(http.StatusAccepted, apimodels.NewAlertRuleVersionDiff(v, compareTo, diff))
+}
+
+// RoutePostRuleVersionRestore replaces the definition of the rule with the one of the version. The rule keeps its
+// folder, group and evaluation interval. The restored rule is stored as a new version marked as restored from the version.
+func (srv RulerSrv) RoutePostRuleVersionRestore(c *models.ReqContext, ruleUID string, version string) response.Response {
+	v, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to parse version")
+	}
+	rule, group, folder, err := srv.getRuleWithGroup(c, ruleUID)
+	if err != nil {
+		return toRuleVersionErrorResponse(err)
+	}
+	q := ngmodels.GetAlertRuleVersionQuery{
+		OrgID:   c.SignedInUser.OrgID,
+		UID:     rule.UID,
+		Version: v,
+	}
+	if err := srv.store.GetAlertRuleVersion(c.Req.Context(), &q); err != nil {
+		return toRuleVersionErrorResponse(err)
+	}
+	restored, err := q.Result.Restore(*rule)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to restore rule version")
+	}
+
+	diff := rule.Diff(&restored, store.AlertRuleFieldsToIgnoreInDiff[:]...)
+	if len(diff) == 0 {
+		return response.JSON(http.StatusAccepted, util.DynMap{"message": "no changes detected in the rule"})
+	}
+	restored.Updated = time.Now()
+	changes := &store.GroupDelta{
+		GroupKey: rule.GetGroupKey(),
+		AffectedGroups: map[ngmodels.AlertRuleGroupKey]ngmodels.RulesGroup{
+			rule.GetGroupKey(): group,
+		},
+		Update: []store.RuleDelta{
+			{
+				Existing: rule,
+				New:      &restored,
+				Diff:     diff,
+			},
+		},
+	}
+	// if RBAC is disabled the permission are limited to folder access
+	if srv.ac.IsDisabled() {
+		if _, err := srv.store.GetNamespaceByTitle(c.Req.Context(), folder.Title, c.SignedInUser.OrgID, c.SignedInUser, true); err != nil {
+			return toNamespaceErrorResponse(err)
+		}
+	} else {
+		hasAccess := accesscontrol.HasAccess(srv.ac, c)
+		err = authorizeRuleChanges(changes, func(evaluator accesscontrol.Evaluator) bool {
+			return hasAccess(accesscontrol.ReqOrgAdminOrEditor, evaluator)
+		})
+		if err != nil {
+			return toRuleGroupErrorResponse(err)
+		}
+	}
+	if err := verifyProvisionedRulesNotAffected(c.Req.Context(), srv.provenanceStore, c.OrgID, changes); err != nil {
+		return toRuleGroupErrorResponse(err)
+	}
+	// the version could have been valid when it was stored but refer to data sources or queries that are no longer valid
+	if err := srv.conditionValidator.Validate(c.Req.Context(), c.SignedInUser, restored.GetEvalCondition()); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to validate condition of the rule version")
+	}
+
+	err = srv.xactManager.InTransaction(c.Req.Context(), func(tranCtx context.Context) error {
+		return srv.store.UpdateAlertRules(tranCtx, []ngmodels.UpdateRule{
+			{
+				Existing:     rule,
+				New:          restored,
+				RestoredFrom: v,
+			},
+		})
+	})
+	if err != nil {
+		return toRuleGroupErrorResponse(err)
+	}
+	srv.notifyScheduler(c, changes)
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": "rule restored successfully"})
+}
+
+// getRuleWithGroup returns the rule, all rules of its group and its folder. It returns ngmodels.ErrAlertRuleNotFound if
+// the rule does not exist or the user cannot see its folder, and ErrAuthorization if the user cannot access the group.
+func (srv RulerSrv) getRuleWithGroup(c *models.ReqContext, ruleUID string) (*ngmodels.AlertRule, ngmodels.RulesGroup, *models.Folder, error) {
+	q := ngmodels.GetAlertRulesGroupByRuleUIDQuery{
+		OrgID: c.SignedInUser.OrgID,
+		UID:   ruleUID,
+	}
+	if err := srv.store.GetAlertRulesGroupByRuleUID(c.Req.Context(), &q); err != nil {
+		return nil, nil, nil, err
+	}
+	var rule *ngmodels.AlertRule
+	for _, r := range q.Result {
+		if r.UID == ruleUID {
+			rule = r
+			break
+		}
+	}
+	if rule == nil {
+		return nil, nil, nil, ngmodels.ErrAlertRuleNotFound
+	}
+	namespaces, err := srv.store.GetUserVisibleNamespaces(c.Req.Context(), c.OrgID, c.SignedInUser)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	folder, ok := namespaces[rule.NamespaceUID]
+	if !ok {
+		return nil, nil, nil, ngmodels.ErrAlertRuleNotFound
+	}
+	hasAccess := func(evaluator accesscontrol.Evaluator) bool {
+		return accesscontrol.HasAccess(srv.ac, c)(accesscontrol.ReqViewer, evaluator)
+	}
+	if !authorizeAccessToRuleGroup(q.Result, hasAccess) {
+		return nil, nil, nil, fmt.Errorf("%w to access the rule because it does not have access to one or many data sources one or many rules in the group use", ErrAuthorization)
+	}
+	return rule, q.Result, folder, nil
+}
+
+// getRuleVersion returns the rule as it was defined at the version if the user can access the data sources it used.
+func (srv RulerSrv) getRuleVersion(c *models.ReqContext, ruleUID string, version int64) (ngmodels.AlertRule, error) {
+	q := ngmodels.GetAlertRuleVersionQuery{
+		OrgID:   c.SignedInUser.OrgID,
+		UID:     ruleUID,
+		Version: version,
+	}
+	if err := srv.store.GetAlertRuleVersion(c.Req.Context(), &q); err != nil {
+		return ngmodels.AlertRule{}, err
+	}
+	rule, err := q.Result.ToAlertRule()
+	if err != nil {
+		return ngmodels.AlertRule{}, err
+	}
+	hasAccess := func(evaluator accesscontrol.Evaluator) bool {
+		return accesscontrol.HasAccess(srv.ac, c)(accesscontrol.ReqViewer, evaluator)
+	}
+	if !authorizeDatasourceAccessForRule(&rule, hasAccess) {
+		return ngmodels.AlertRule{}, fmt.Errorf("%w to access version %d of the rule because it does not have access to one or many data sources the version uses", ErrAuthorization, version)
+	}
+	return rule, nil
+}
+
+// toRuleVersionErrorResponse converts the error of reading versions of a rule to a response.
+func toRuleVersionErrorResponse(err error) response.Response {
+	if errors.Is(err, ngmodels.ErrAlertRuleNotFound) || errors.Is(err, ngmodels.ErrAlertRuleVersionNotFound) {
+		return ErrResp(http.StatusNotFound, err, "")
+	} else if errors.Is(err, ErrAuthorization) {
+		return ErrResp(http.StatusUnauthorized, err, "")
+	}
+	return ErrResp(http.StatusInternalServerError, err, "failed to get rule version")
+}
+
 func (srv RulerSrv) RoutePostNameRulesConfig(c *models.ReqContext, ruleGroupConfig apimodels.PostableRuleGroupConfig, namespaceTitle string) response.Response {
 	namespace, err := srv.store.GetNamespaceByTitle(c.Req.Context(), namespaceTitle, c.SignedInUser.OrgID, c.SignedInUser, true)
 	if err != nil {
@@ -323,125 +548,392 @@ func (srv RulerSrv) RoutePostNameRulesConfig(c *models.ReqContext, ruleGroupConf
 	return srv.updateAlertRulesInGroup(c, groupKey, rules)
 }
 
-// updateAlertRulesInGroup calculates changes (rules to add,update,delete), verifies that the user is authorized to do the calculated changes and updates database.
-// All operations are performed in a single transaction
-func (srv RulerSrv) updateAlertRulesInGroup(c *models.ReqContext, groupKey ngmodels.AlertRuleGroupKey, rules []*ngmodels.AlertRule) response.Response {
-	var finalChanges *store.GroupDelta
-	hasAccess := accesscontrol.HasAccess(srv.ac, c)
-	err := srv.xactManager.InTransaction(c.Req.Context(), func(tranCtx context.Context) error {
-		logger := srv.log.New("namespace_uid", groupKey.NamespaceUID, "group", groupKey.RuleGroup, "org_id", groupKey.OrgID, "user_id", c.UserID)
-		groupChanges, err := store.CalculateChanges(tranCtx, srv.store, groupKey, rules)
-		if err != nil {
-			return err
-		}
+// RoutePostPauseRuleGroup pauses or resumes evaluation of all rules in the group. The rules are updated as if the whole
+// group was submitted, therefore the user must be authorized to update every rule in the group and none of the rules can be provisioned.
+func (srv RulerSrv) RoutePostPauseRuleGroup(c *models.ReqContext, namespaceTitle string, ruleGroup string, isPaused bool) response.Response {
+	namespace, err := srv.store.GetNamespaceByTitle(c.Req.Context(), namespaceTitle, c.SignedInUser.OrgID, c.SignedInUser, true)
+	if err != nil {
+		return toNamespaceErrorResponse(err)
+	}
 
-		if groupChanges.IsEmpty() {
-			finalChanges = groupChanges
-			logger.Info("no changes detected in the request. Do nothing")
-			return nil
+	q := ngmodels.ListAlertRulesQuery{
+		OrgID:         c.SignedInUser.OrgID,
+		NamespaceUIDs: []string{namespace.Uid},
+		RuleGroup:     ruleGroup,
+	}
+	if err := srv.store.ListAlertRules(c.Req.Context(), &q); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get group alert rules")
+	}
+	if len(q.Result) == 0 {
+		return ErrResp(http.StatusNotFound, store.ErrAlertRuleGroupNotFound, "")
+	}
+
+	rules := make([]*ngmodels.AlertRule, 0, len(q.Result))
+	for _, r := range q.Result {
+		rule := *r
+		rule.IsPaused = isPaused
+		rules = append(rules, &rule)
+	}
+
+	groupKey := ngmodels.AlertRuleGroupKey{
+		OrgID:        c.SignedInUser.OrgID,
+		NamespaceUID: namespace.Uid,
+		RuleGroup:    ruleGroup,
+	}
+	return srv.updateAlertRulesInGroup(c, groupKey, rules)
+}
+
+// RoutePostPrometheusRulesImport converts the rule groups of a Prometheus rule file to Grafana managed rules that query the
+// data source, and replaces the rule groups with the same names in the namespace. Rules that cannot be converted are skipped
+// and returned in the response. All rule groups are updated in a single transaction.
+func (srv RulerSrv) RoutePostPrometheusRulesImport(c *models.ReqContext, body apimodels.PrometheusRulesImportBody, namespaceTitle string) response.Response {
+	namespace, err := srv.store.GetNamespaceByTitle(c.Req.Context(), namespaceTitle, c.SignedInUser.OrgID, c.SignedInUser, true)
+	if err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+
+	if body.DatasourceUID == "" {
+		return ErrResp(http.StatusBadRequest, errors.New("data source UID must be specified"), "")
+	}
+	ds, err := srv.datasourceCache.GetDatasourceByUID(c.Req.Context(), body.DatasourceUID, c.SignedInUser, c.SkipCache)
+	if err != nil {
+		if errors.Is(err, datasources.ErrDataSourceNotFound) {
+			return ErrResp(http.StatusBadRequest, err, "")
 		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to get data source")
+	}
+	converter, err := newPrometheusRuleConverter(ds)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
 
-		// if RBAC is disabled the permission are limited to folder access that is done upstream
-		if !srv.ac.IsDisabled() {
-			err = authorizeRuleChanges(groupChanges, func(evaluator accesscontrol.Evaluator) bool {
-				return hasAccess(accesscontrol.ReqOrgAdminOrEditor, evaluator)
-			})
+	q := ngmodels.ListAlertRulesQuery{
+		OrgID:         c.SignedInUser.OrgID,
+		NamespaceUIDs: []string{namespace.Uid},
+	}
+	if err := srv.store.ListAlertRules(c.Req.Context(), &q); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get alert rules")
+	}
+
+	result, groups := srv.convertPrometheusRuleGroups(c, converter, namespace, body.Groups, q.Result)
+	if body.DryRun || len(groups) == 0 {
+		return response.JSON(http.StatusOK, result)
+	}
+
+	changes := make([]*store.GroupDelta, 0, len(groups))
+	err = srv.xactManager.InTransaction(c.Req.Context(), func(tranCtx context.Context) error {
+		for _, group := range groups {
+			delta, err := srv.applyAlertRulesInGroup(tranCtx, c, group.key, group.rules)
 			if err != nil {
-				return err
+				return fmt.Errorf("failed to import rule group %s: %w", group.key.RuleGroup, err)
 			}
+			changes = append(changes, delta)
 		}
+		return nil
+	})
+	if err != nil {
+		return toRuleGroupErrorResponse(err)
+	}
 
-		if err := verifyProvisionedRulesNotAffected(c.Req.Context(), srv.provenanceStore, c.OrgID, groupChanges); err != nil {
-			return err
+	for _, delta := range changes {
+		srv.notifyScheduler(c, delta)
+	}
+	return response.JSON(http.StatusAccepted, result)
+}
+
+type importedRuleGroup struct {
+	key   ngmodels.AlertRuleGroupKey
+	rules []*ngmodels.AlertRule
+}
+
+// convertPrometheusRuleGroups converts and validates the rules of the Prometheus rule groups. Rules of existing rule groups
+// with the same names are updated rather than replaced by new rules. Rules are skipped if they cannot be converted or if
+// another rule of the namespace has the same title, which must be unique. A rule group is imported only if none of its
+// rules are skipped, so that an existing rule group is never left without some of its rules.
+func (srv RulerSrv) convertPrometheusRuleGroups(c *models.ReqContext, converter *prometheusRuleConverter, namespace *models.Folder, groups []apimodels.PrometheusRuleGroup, existing []*ngmodels.AlertRule) (apimodels.PrometheusRulesImportResult, []importedRuleGroup) {
+	result := apimodels.PrometheusRulesImportResult{Groups: []apimodels.PostableRuleGroupConfig{}}
+	skip := func(group, rule string, reason error) {
+		result.Skipped = append(result.Skipped, apimodels.SkippedPrometheusRule{Group: group, Rule: rule, Reason: reason.Error()})
+	}
+	ruleName := func(rule apimodels.ApiRuleNode) string {
+		if rule.Alert != "" {
+			return rule.Alert
 		}
+		return rule.Record
+	}
 
-		finalChanges = store.UpdateCalculatedRuleFields(groupChanges)
-		logger.Debug("updating database with the authorized changes", "add", len(finalChanges.New), "update", len(finalChanges.New), "delete", len(finalChanges.Delete))
+	importedGroups := make(map[string]struct{}, len(groups))
+	for _, group := range groups {
+		importedGroups[group.Name] = struct{}{}
+	}
+	// titles maps the titles of the rules of the namespace to the names of their groups.
+	titles := make(map[string]string, len(existing))
+	existingUIDs := make(map[string]map[string]string)
+	for _, rule := range existing {
+		if _, ok := importedGroups[rule.RuleGroup]; ok {
+			if existingUIDs[rule.RuleGroup] == nil {
+				existingUIDs[rule.RuleGroup] = make(map[string]string)
+			}
+			existingUIDs[rule.RuleGroup][rule.Title] = rule.UID
+			continue
+		}
+		titles[rule.Title] = rule.RuleGroup
+	}
 
-		if len(finalChanges.Update) > 0 || len(finalChanges.New) > 0 {
-			updates := make([]ngmodels.UpdateRule, 0, len(finalChanges.Update))
-			inserts := make([]ngmodels.AlertRule, 0, len(finalChanges.New))
-			for _, update := range finalChanges.Update {
-				logger.Debug("updating rule", "rule_uid", update.New.UID, "diff", update.Diff.String())
-				updates = append(updates, ngmodels.UpdateRule{
-					Existing: update.Existing,
-					New:      *update.New,
-				})
+	conditionValidator := func(condition ngmodels.Condition) error {
+		return srv.conditionValidator.Validate(c.Req.Context(), c.SignedInUser, condition)
+	}
+
+	var imported []importedRuleGroup
+	convertedGroups := make(map[string]struct{}, len(groups))
+	for _, group := range groups {
+		groupConfig := apimodels.PostableRuleGroupConfig{Name: group.Name, Interval: group.Interval}
+		err := func() error {
+			if _, ok := convertedGroups[group.Name]; ok {
+				return fmt.Errorf("rule group %s is defined more than once", group.Name)
 			}
-			for _, rule := range finalChanges.New {
-				inserts = append(inserts, *rule)
+			// validates the rule group without rules
+			_, err := validateRuleGroup(&groupConfig, c.SignedInUser.OrgID, namespace, conditionValidator, srv.cfg)
+			return err
+		}()
+		if err != nil {
+			for _, rule := range group.Rules {
+				skip(group.Name, ruleName(rule), err)
+			}
+			continue
+		}
+		convertedGroups[group.Name] = struct{}{}
+
+		interval := time.Duration(group.Interval)
+		if interval == 0 {
+			interval = srv.cfg.DefaultRuleEvaluationInterval
+		}
+		var rules []*ngmodels.AlertRule
+		var converted []string
+		skipped := 0
+		for _, rule := range group.Rules {
+			name := ruleName(rule)
+			if other, ok := titles[name]; ok {
+				skip(group.Name, name, fmt.Errorf("rule group %s of the folder has a rule with the same name, and names of rules must be unique in a folder", other))
+				skipped++
+				continue
 			}
-			_, err = srv.store.InsertAlertRules(tranCtx, inserts)
+			node, err := converter.convertRule(rule)
 			if err != nil {
-				return fmt.Errorf("failed to add rules: %w", err)
+				skip(group.Name, name, err)
+				skipped++
+				continue
 			}
-			err = srv.store.UpdateAlertRules(tranCtx, updates)
+			node.GrafanaManagedAlert.UID = existingUIDs[group.Name][name]
+			alertRule, err := validateRuleNode(node, group.Name, interval, c.SignedInUser.OrgID, namespace, conditionValidator, srv.cfg)
 			if err != nil {
-				return fmt.Errorf("failed to update rules: %w", err)
+				skip(group.Name, name, err)
+				skipped++
+				continue
 			}
+			titles[name] = group.Name
+			converted = append(converted, name)
+			alertRule.RuleGroupIndex = len(rules) + 1
+			rules = append(rules, alertRule)
+			groupConfig.Rules = append(groupConfig.Rules, *node)
 		}
-
-		if len(finalChanges.Delete) > 0 {
-			UIDs := make([]string, 0, len(finalChanges.Delete))
-			for _, rule := range finalChanges.Delete {
-				UIDs = append(UIDs, rule.UID)
+		if skipped > 0 {
+			// the rule group would replace the existing rule group without the skipped rules
+			for _, name := range converted {
+				skip(group.Name, name, fmt.Errorf("rule group %s has rules that cannot be imported", group.Name))
 			}
+			continue
+		}
+		if len(rules) == 0 {
+			continue
+		}
 
-			if err = srv.store.DeleteAlertRulesByUID(tranCtx, c.SignedInUser.OrgID, UIDs...); err != nil {
-				return fmt.Errorf("failed to delete rules: %w", err)
+		result.Groups = append(result.Groups, groupConfig)
+		imported = append(imported, importedRuleGroup{
+			key: ngmodels.AlertRuleGroupKey{
+				OrgID:        c.SignedInUser.OrgID,
+				NamespaceUID: namespace.Uid,
+				RuleGroup:    group.Name,
+			},
+			rules: rules,
+		})
+	}
+
+	// rule groups that are not imported keep their existing rules, whose titles cannot be used by the imported rule groups
+	for {
+		importedNames := make(map[string]struct{}, len(imported))
+		for _, group := range imported {
+			importedNames[group.key.RuleGroup] = struct{}{}
+		}
+		kept := make(map[string]string)
+		for _, rule := range existing {
+			if _, ok := importedGroups[rule.RuleGroup]; !ok {
+				continue
+			}
+			if _, ok := importedNames[rule.RuleGroup]; !ok {
+				kept[rule.Title] = rule.RuleGroup
 			}
 		}
-
-		if len(finalChanges.New) > 0 {
-			limitReached, err := srv.QuotaService.CheckQuotaReached(tranCtx, "alert_rule", &quota.ScopeParameters{
-				OrgID:  c.OrgID,
-				UserID: c.UserID,
-			}) // alert rule is table name
-			if err != nil {
-				return fmt.Errorf("failed to get alert rules quota: %w", err)
+		idx, conflict := -1, ""
+		for i, group := range imported {
+			for _, rule := range group.rules {
+				if other, ok := kept[rule.Title]; ok {
+					idx, conflict = i, other
+					break
+				}
 			}
-			if limitReached {
-				return ngmodels.ErrQuotaReached
+			if idx >= 0 {
+				break
 			}
 		}
-		return nil
+		if idx < 0 {
+			break
+		}
+		for _, rule := range imported[idx].rules {
+			skip(imported[idx].key.RuleGroup, rule.Title, fmt.Errorf("rule group %s of the folder is not imported and keeps a rule with the same name as a rule of this group", conflict))
+		}
+		imported = append(imported[:idx], imported[idx+1:]...)
+		result.Groups = append(result.Groups[:idx], result.Groups[idx+1:]...)
+	}
+	return result, imported
+}
+
+// updateAlertRulesInGroup calculates changes (rules to add,update,delete), verifies that the user is authorized to do the calculated changes and updates database.
+// All operations are performed in a single transaction
+func (srv RulerSrv) updateAlertRulesInGroup(c *models.ReqContext, groupKey ngmodels.AlertRuleGroupKey, rules []*ngmodels.AlertRule) response.Response {
+	var finalChanges *store.GroupDelta
+	err := srv.xactManager.InTransaction(c.Req.Context(), func(tranCtx context.Context) error {
+		var err error
+		finalChanges, err = srv.applyAlertRulesInGroup(tranCtx, c, groupKey, rules)
+		return err
 	})
 
 	if err != nil {
-		if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
-			return ErrResp(http.StatusNotFound, err, "failed to update rule group")
-		} else if errors.Is(err, ngmodels.ErrAlertRuleFailedValidation) || errors.Is(err, errProvisionedResource) {
-			return ErrResp(http.StatusBadRequest, err, "failed to update rule group")
-		} else if errors.Is(err, ngmodels.ErrQuotaReached) {
-			return ErrResp(http.StatusForbidden, err, "")
-		} else if errors.Is(err, ErrAuthorization) {
-			return ErrResp(http.StatusUnauthorized, err, "")
-		} else if errors.Is(err, store.ErrOptimisticLock) {
-			return ErrResp(http.StatusConflict, err, "")
+		return toRuleGroupErrorResponse(err)
+	}
+
+	srv.notifyScheduler(c, finalChanges)
+
+	if finalChanges.IsEmpty() {
+		return response.JSON(http.StatusAccepted, util.DynMap{"message": "no changes detected in the rule group"})
+	}
+
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": "rule group updated successfully"})
+}
+
+// applyAlertRulesInGroup calculates changes (rules to add,update,delete) of the group, verifies that the user is authorized to do the calculated changes and updates database.
+// It must be called in a transaction. Returns the changes that were applied.
+func (srv RulerSrv) applyAlertRulesInGroup(tranCtx context.Context, c *models.ReqContext, groupKey ngmodels.AlertRuleGroupKey, rules []*ngmodels.AlertRule) (*store.GroupDelta, error) {
+	logger := srv.log.New("namespace_uid", groupKey.NamespaceUID, "group", groupKey.RuleGroup, "org_id", groupKey.OrgID, "user_id", c.UserID)
+	groupChanges, err := store.CalculateChanges(tranCtx, srv.store, groupKey, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	if groupChanges.IsEmpty() {
+		logger.Info("no changes detected in the request. Do nothing")
+		return groupChanges, nil
+	}
+
+	// if RBAC is disabled the permission are limited to folder access that is done upstream
+	if !srv.ac.IsDisabled() {
+		hasAccess := accesscontrol.HasAccess(srv.ac, c)
+		err = authorizeRuleChanges(groupChanges, func(evaluator accesscontrol.Evaluator) bool {
+			return hasAccess(accesscontrol.ReqOrgAdminOrEditor, evaluator)
+		})
+		if err != nil {
+			return nil, err
 		}
-		return ErrResp(http.StatusInternalServerError, err, "failed to update rule group")
 	}
 
-	for _, rule := range finalChanges.Update {
+	if err := verifyProvisionedRulesNotAffected(c.Req.Context(), srv.provenanceStore, c.OrgID, groupChanges); err != nil {
+		return nil, err
+	}
+
+	finalChanges := store.UpdateCalculatedRuleFields(groupChanges)
+	logger.Debug("updating database with the authorized changes", "add", len(finalChanges.New), "update", len(finalChanges.New), "delete", len(finalChanges.Delete))
+
+	if len(finalChanges.Update) > 0 || len(finalChanges.New) > 0 {
+		updates := make([]ngmodels.UpdateRule, 0, len(finalChanges.Update))
+		inserts := make([]ngmodels.AlertRule, 0, len(finalChanges.New))
+		for _, update := range finalChanges.Update {
+			logger.Debug("updating rule", "rule_uid", update.New.UID, "diff", update.Diff.String())
+			updates = append(updates, ngmodels.UpdateRule{
+				Existing: update.Existing,
+				New:      *update.New,
+			})
+		}
+		for _, rule := range finalChanges.New {
+			inserts = append(inserts, *rule)
+		}
+		_, err = srv.store.InsertAlertRules(tranCtx, inserts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add rules: %w", err)
+		}
+		err = srv.store.UpdateAlertRules(tranCtx, updates)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update rules: %w", err)
+		}
+	}
+
+	if len(finalChanges.Delete) > 0 {
+		UIDs := make([]string, 0, len(finalChanges.Delete))
+		for _, rule := range finalChanges.Delete {
+			UIDs = append(UIDs, rule.UID)
+		}
+
+		if err = srv.store.DeleteAlertRulesByUID(tranCtx, c.SignedInUser.OrgID, UIDs...); err != nil {
+			return nil, fmt.Errorf("failed to delete rules: %w", err)
+		}
+	}
+
+	if len(finalChanges.New) > 0 {
+		limitReached, err := srv.QuotaService.CheckQuotaReached(tranCtx, "alert_rule", &quota.ScopeParameters{
+			OrgID:  c.OrgID,
+			UserID: c.UserID,
+		}) // alert rule is table name
+		if err != nil {
+			return nil, fmt.Errorf("failed to get alert rules quota: %w", err)
+		}
+		if limitReached {
+			return nil, ngmodels.ErrQuotaReached
+		}
+	}
+	return finalChanges, nil
+}
+
+// notifyScheduler notifies the scheduler about the rules that were updated or deleted.
+func (srv RulerSrv) notifyScheduler(c *models.ReqContext, changes *store.GroupDelta) {
+	for _, rule := range changes.Update {
 		srv.scheduleService.UpdateAlertRule(ngmodels.AlertRuleKey{
 			OrgID: c.SignedInUser.OrgID,
 			UID:   rule.Existing.UID,
 		}, rule.Existing.Version+1)
 	}
 
-	if len(finalChanges.Delete) > 0 {
-		keys := make([]ngmodels.AlertRuleKey, 0, len(finalChanges.Delete))
-		for _, rule := range finalChanges.Delete {
+	if len(changes.Delete) > 0 {
+		keys := make([]ngmodels.AlertRuleKey, 0, len(changes.Delete))
+		for _, rule := range changes.Delete {
 			keys = append(keys, rule.GetKey())
 		}
 		srv.scheduleService.DeleteAlertRule(keys...)
 	}
+}
 
-	if finalChanges.IsEmpty() {
-		return response.JSON(http.StatusAccepted, util.DynMap{"message": "no changes detected in the rule group"})
+// toRuleGroupErrorResponse converts the error of updating a rule group to a response.
+func toRuleGroupErrorResponse(err error) response.Response {
+	if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+		return ErrResp(http.StatusNotFound, err, "failed to update rule group")
+	} else if errors.Is(err, ngmodels.ErrAlertRuleFailedValidation) || errors.Is(err, errProvisionedResource) {
+		return ErrResp(http.StatusBadRequest, err, "failed to update rule group")
+	} else if errors.Is(err, ngmodels.ErrQuotaReached) {
+		return ErrResp(http.StatusForbidden, err, "")
+	} else if errors.Is(err, ErrAuthorization) {
+		return ErrResp(http.StatusUnauthorized, err, "")
+	} else if errors.Is(err, store.ErrOptimisticLock) {
+		return ErrResp(http.StatusConflict, err, "")
 	}
-
-	return response.JSON(http.StatusAccepted, util.DynMap{"message": "rule group updated successfully"})
+	return ErrResp(http.StatusInternalServerError, err, "failed to update rule group")
 }
 
 func toGettableRuleGroupConfig(groupName string, rules ngmodels.RulesGroup, namespaceID int64, provenanceRecords map[string]ngmodels.Provenance) apimodels.GettableRuleGroupConfig {
@@ -483,6 +975,8 @@ func toGettableExtendedRuleNode(r ngmodels.AlertRule, namespaceID int64, provena
 			NoDataState:     apimodels.NoDataState(r.NoDataState),
 			ExecErrState:    apimodels.ExecutionErrorState(r.ExecErrState),
 			Provenance:      provenance,
+			Record:          apimodels.NewRecord(r.Record),
+			IsPaused:        r.IsPaused,
 		},
 	}
 	forDuration := model.Duration(r.For)
@@ -491,6 +985,10 @@ func toGettableExtendedRuleNode(r ngmodels.AlertRule, namespaceID int64, provena
 		Annotations: r.Annotations,
 		Labels:      r.Labels,
 	}
+	if r.KeepFiringFor > 0 {
+		keepFiringFor := model.Duration(r.KeepFiringFor)
+		gettableExtendedRuleNode.ApiRuleNode.KeepFiringFor = &keepFiringFor
+	}
 	return gettableExtendedRuleNode
 }
 
@@ -3,11 +3,13 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"net/http"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -17,6 +19,7 @@ import (
 	models2 "github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	acMock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -26,6 +29,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -637,6 +641,283 @@ func TestVerifyProvisionedRulesNotAffected(t *testing.T) {
 	})
 }
 
+func TestRoutePostNameRulesConfig(t *testing.T) {
+	orgID := rand.Int63()
+	folder := randFolder()
+	groupKey := models.GenerateGroupKey(orgID)
+	groupKey.NamespaceUID = folder.Uid
+
+	setup := func(t *testing.T) (*RulerSrv, *fakes.RuleStore, *models.AlertRule) {
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+		rule := models.AlertRuleGen(withGroupKey(groupKey), func(rule *models.AlertRule) {
+			rule.IsPaused = true
+		})()
+		ruleStore.PutRule(context.Background(), rule)
+		scheduler := &schedule.FakeScheduleService{}
+		scheduler.On("UpdateAlertRule", mock.Anything, mock.Anything)
+		svc := createService(acMock.New().WithDisabled(), ruleStore, scheduler)
+		svc.conditionValidator = fakeConditionValidator{}
+		svc.cfg = &setting.UnifiedAlertingSettings{BaseInterval: 10 * time.Second, DefaultRuleEvaluationInterval: time.Minute}
+		return svc, ruleStore, rule
+	}
+	postableGroup := func(rule *models.AlertRule, isPaused *bool) apimodels.PostableRuleGroupConfig {
+		return apimodels.PostableRuleGroupConfig{
+			Name: rule.RuleGroup,
+			Rules: []apimodels.PostableExtendedRuleNode{
+				{
+					ApiRuleNode: &apimodels.ApiRuleNode{Labels: rule.Labels},
+					GrafanaManagedAlert: &apimodels.PostableGrafanaRule{
+						UID:       rule.UID,
+						Title:     "updated title",
+						Condition: rule.Condition,
+						Data:      rule.Data,
+						IsPaused:  isPaused,
+					},
+				},
+			},
+		}
+	}
+	updatedRule := func(t *testing.T, ruleStore *fakes.RuleStore) models.AlertRule {
+		t.Helper()
+		updates := ruleStore.GetRecordedCommands(func(cmd interface{}) (interface{}, bool) {
+			c, ok := cmd.([]models.UpdateRule)
+			return c, ok
+		})
+		require.Len(t, updates, 1)
+		update := updates[0].([]models.UpdateRule)
+		require.Len(t, update, 1)
+		return update[0].New
+	}
+
+	t.Run("should keep the rule paused if is_paused is not specified", func(t *testing.T) {
+		svc, ruleStore, rule := setup(t)
+
+		response := svc.RoutePostNameRulesConfig(createRequestContext(orgID, "", nil), postableGroup(rule, nil), folder.Title)
+
+		require.Equalf(t, http.StatusAccepted, response.Status(), string(response.Body()))
+		updated := updatedRule(t, ruleStore)
+		require.Equal(t, "updated title", updated.Title)
+		require.True(t, updated.IsPaused)
+	})
+
+	t.Run("should resume the rule if is_paused is false", func(t *testing.T) {
+		svc, ruleStore, rule := setup(t)
+		isPaused := false
+
+		response := svc.RoutePostNameRulesConfig(createRequestContext(orgID, "", nil), postableGroup(rule, &isPaused), folder.Title)
+
+		require.Equalf(t, http.StatusAccepted, response.Status(), string(response.Body()))
+		require.False(t, updatedRule(t, ruleStore).IsPaused)
+	})
+}
+
+func TestRoutePostPauseRuleGroup(t *testing.T) {
+	orgID := rand.Int63()
+	folder := randFolder()
+	groupKey := models.GenerateGroupKey(orgID)
+	groupKey.NamespaceUID = folder.Uid
+
+	setup := func(t *testing.T) (*fakes.RuleStore, []*models.AlertRule) {
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+		rules := models.GenerateAlertRules(rand.Intn(3)+2, models.AlertRuleGen(withGroupKey(groupKey)))
+		ruleStore.PutRule(context.Background(), rules...)
+		return ruleStore, rules
+	}
+	updatePermissions := func(rules []*models.AlertRule) []accesscontrol.Permission {
+		return append(createPermissionsForRules(rules), accesscontrol.Permission{
+			Action: accesscontrol.ActionAlertingRuleUpdate, Scope: dashboards.ScopeFoldersProvider.GetResourceScopeUID(folder.Uid),
+		})
+	}
+
+	t.Run("should pause all rules in the group and notify the scheduler", func(t *testing.T) {
+		ruleStore, rules := setup(t)
+		scheduler := &schedule.FakeScheduleService{}
+		scheduler.On("UpdateAlertRule", mock.Anything, mock.Anything)
+		ac := acMock.New().WithPermissions(updatePermissions(rules))
+
+		response := createService(ac, ruleStore, scheduler).RoutePostPauseRuleGroup(createRequestContext(orgID, "", nil), folder.Title, groupKey.RuleGroup, true)
+
+		require.Equal(t, http.StatusAccepted, response.Status())
+		updates := ruleStore.GetRecordedCommands(func(cmd interface{}) (interface{}, bool) {
+			c, ok := cmd.([]models.UpdateRule)
+			return c, ok
+		})
+		require.Len(t, updates, 1)
+		update := updates[0].([]models.UpdateRule)
+		require.Len(t, update, len(rules))
+		for _, u := range update {
+			require.True(t, u.New.IsPaused)
+			require.Equal(t, u.Existing.Data, u.New.Data)
+		}
+		scheduler.AssertNumberOfCalls(t, "UpdateAlertRule", len(rules))
+	})
+
+	t.Run("should return 401 if user is not authorized to update rules in the folder", func(t *testing.T) {
+		ruleStore, rules := setup(t)
+		ac := acMock.New().WithPermissions(createPermissionsForRules(rules))
+
+		response := createService(ac, ruleStore, nil).RoutePostPauseRuleGroup(createRequestContext(orgID, "", nil), folder.Title, groupKey.RuleGroup, true)
+
+		require.Equal(t, http.StatusUnauthorized, response.Status())
+	})
+
+	t.Run("should return 404 if the group does not exist", func(t *testing.T) {
+		ruleStore, rules := setup(t)
+		ac := acMock.New().WithPermissions(updatePermissions(rules))
+
+		response := createService(ac, ruleStore, nil).RoutePostPauseRuleGroup(createRequestContext(orgID, "", nil), folder.Title, util.GenerateShortUID(), true)
+
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+}
+
+func TestRouteGetRuleVersionsByUID(t *testing.T) {
+	orgID := rand.Int63()
+	folder := randFolder()
+	ruleStore := fakes.NewRuleStore(t)
+	ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+	rule := models.AlertRuleGen(withOrgID(orgID), withNamespace(folder))()
+	ruleStore.PutRule(context.Background(), rule)
+
+	// the previous version used another data source
+	previous := fakes.VersionOfRule(rule)
+	previous.Version = rule.Version - 1
+	previous.Data = models.AlertRuleGen()().Data
+	ruleStore.Versions = map[string][]*models.AlertRuleVersion{
+		rule.UID: {fakes.VersionOfRule(rule), previous},
+	}
+	request := createRequestContext(orgID, "", nil)
+
+	t.Run("should omit versions that use data sources the user does not have access to", func(t *testing.T) {
+		ac := acMock.New().WithPermissions(createPermissionsForRules([]*models.AlertRule{rule}))
+		response := createService(ac, ruleStore, nil).RouteGetRuleVersionsByUID(request, rule.UID)
+
+		require.Equal(t, http.StatusAccepted, response.Status())
+		var result apimodels.GettableRuleVersions
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result, 1)
+		require.Equal(t, rule.Version, result[0].Version)
+		require.Equal(t, rule.Title, result[0].Rule.GrafanaManagedAlert.Title)
+	})
+
+	t.Run("should return 401 if user does not have access to the rule", func(t *testing.T) {
+		ac := acMock.New()
+		response := createService(ac, ruleStore, nil).RouteGetRuleVersionsByUID(request, rule.UID)
+		require.Equal(t, http.StatusUnauthorized, response.Status())
+	})
+
+	t.Run("should return 404 if the rule does not exist", func(t *testing.T) {
+		ac := acMock.New()
+		response := createService(ac, ruleStore, nil).RouteGetRuleVersionsByUID(request, util.GenerateShortUID())
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+}
+
+func TestRoutePostRuleVersionRestore(t *testing.T) {
+	orgID := rand.Int63()
+	folder := randFolder()
+
+	setup := func(t *testing.T) (*fakes.RuleStore, *models.AlertRule, *models.AlertRuleVersion) {
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+		rule := models.AlertRuleGen(withOrgID(orgID), withNamespace(folder))()
+		ruleStore.PutRule(context.Background(), rule)
+
+		previous := fakes.VersionOfRule(rule)
+		previous.Version = rule.Version - 1
+		previous.Title = "previous title"
+		previous.Labels = map[string]string{"team": "previous"}
+		ruleStore.Versions = map[string][]*models.AlertRuleVersion{
+			rule.UID: {fakes.VersionOfRule(rule), previous},
+		}
+		return ruleStore, rule, previous
+	}
+
+	t.Run("should store the definition of the version and notify the scheduler", func(t *testing.T) {
+		ruleStore, rule, previous := setup(t)
+		scheduler := &schedule.FakeScheduleService{}
+		scheduler.On("UpdateAlertRule", mock.Anything, mock.Anything)
+		ac := acMock.New().WithPermissions(append(createPermissionsForRules([]*models.AlertRule{rule}), accesscontrol.Permission{
+			Action: accesscontrol.ActionAlertingRuleUpdate, Scope: dashboards.ScopeFoldersProvider.GetResourceScopeUID(folder.Uid),
+		}))
+
+		svc := createService(ac, ruleStore, scheduler)
+		svc.conditionValidator = fakeConditionValidator{}
+
+		response := svc.RoutePostRuleVersionRestore(createRequestContext(orgID, "", nil), rule.UID, fmt.Sprint(previous.Version))
+
+		require.Equalf(t, http.StatusAccepted, response.Status(), string(response.Body()))
+		updates := ruleStore.GetRecordedCommands(func(cmd interface{}) (interface{}, bool) {
+			c, ok := cmd.([]models.UpdateRule)
+			return c, ok
+		})
+		require.Len(t, updates, 1)
+		update := updates[0].([]models.UpdateRule)
+		require.Len(t, update, 1)
+		require.Equal(t, previous.Version, update[0].RestoredFrom)
+		require.Equal(t, "previous title", update[0].New.Title)
+		require.Equal(t, previous.Labels, update[0].New.Labels)
+		require.Equal(t, rule.RuleGroup, update[0].New.RuleGroup)
+		scheduler.AssertCalled(t, "UpdateAlertRule", rule.GetKey(), rule.Version+1)
+	})
+
+	t.Run("should return 401 if user is not authorized to update rules in the folder", func(t *testing.T) {
+		ruleStore, rule, previous := setup(t)
+		ac := acMock.New().WithPermissions(createPermissionsForRules([]*models.AlertRule{rule}))
+
+		response := createService(ac, ruleStore, nil).RoutePostRuleVersionRestore(createRequestContext(orgID, "", nil), rule.UID, fmt.Sprint(previous.Version))
+
+		require.Equal(t, http.StatusUnauthorized, response.Status())
+		require.Empty(t, ruleStore.GetRecordedCommands(func(cmd interface{}) (interface{}, bool) {
+			c, ok := cmd.([]models.UpdateRule)
+			return c, ok
+		}))
+	})
+
+	t.Run("should return 400 if the rule is provisioned", func(t *testing.T) {
+		ruleStore, rule, previous := setup(t)
+		provisioningStore := provisioning.NewFakeProvisioningStore()
+		require.NoError(t, provisioningStore.SetProvenance(context.Background(), rule, orgID, models.ProvenanceAPI))
+		ac := acMock.New().WithPermissions(append(createPermissionsForRules([]*models.AlertRule{rule}), accesscontrol.Permission{
+			Action: accesscontrol.ActionAlertingRuleUpdate, Scope: dashboards.ScopeFoldersProvider.GetResourceScopeUID(folder.Uid),
+		}))
+
+		response := createServiceWithProvenanceStore(ac, ruleStore, nil, provisioningStore).RoutePostRuleVersionRestore(createRequestContext(orgID, "", nil), rule.UID, fmt.Sprint(previous.Version))
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("should return 400 if the condition of the version is not valid", func(t *testing.T) {
+		ruleStore, rule, previous := setup(t)
+		scheduler := &schedule.FakeScheduleService{}
+		ac := acMock.New().WithPermissions(append(createPermissionsForRules([]*models.AlertRule{rule}), accesscontrol.Permission{
+			Action: accesscontrol.ActionAlertingRuleUpdate, Scope: dashboards.ScopeFoldersProvider.GetResourceScopeUID(folder.Uid),
+		}))
+		svc := createService(ac, ruleStore, scheduler)
+		svc.conditionValidator = fakeConditionValidator{err: errors.New("data source not found")}
+
+		response := svc.RoutePostRuleVersionRestore(createRequestContext(orgID, "", nil), rule.UID, fmt.Sprint(previous.Version))
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+		require.Empty(t, ruleStore.GetRecordedCommands(func(cmd interface{}) (interface{}, bool) {
+			c, ok := cmd.([]models.UpdateRule)
+			return c, ok
+		}))
+		scheduler.AssertNotCalled(t, "UpdateAlertRule", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return 404 if the version does not exist", func(t *testing.T) {
+		ruleStore, rule, _ := setup(t)
+		ac := acMock.New().WithPermissions(createPermissionsForRules([]*models.AlertRule{rule}))
+
+		response := createService(ac, ruleStore, nil).RoutePostRuleVersionRestore(createRequestContext(orgID, "", nil), rule.UID, fmt.Sprint(rule.Version+10))
+
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+}
+
 func createServiceWithProvenanceStore(ac *acMock.Mock, store *fakes.RuleStore, scheduler schedule.ScheduleService, provenanceStore provisioning.ProvisioningStore) *RulerSrv {
 	svc := createService(ac, store, scheduler)
 	svc.provenanceStore = provenanceStore
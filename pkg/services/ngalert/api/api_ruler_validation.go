@@ -75,9 +75,19 @@ func validateRuleNode(
 		}
 	}
 
+	condition := ruleNode.GrafanaManagedAlert.Condition
+	record := ruleNode.GrafanaManagedAlert.Record.ToModel()
+	if record != nil {
+		if err := record.Validate(); err != nil {
+			return nil, err
+		}
+		// recording rules do not have a condition. Instead, the node the data is recorded from is evaluated.
+		condition = record.From
+	}
+
 	if len(ruleNode.GrafanaManagedAlert.Data) == 0 {
 		if canPatch {
-			if ruleNode.GrafanaManagedAlert.Condition != "" {
+			if condition != "" {
 				return nil, fmt.Errorf("%w: query is not specified by condition is. You must specify both query and condition to update existing alert rule", ngmodels.ErrAlertRuleFailedValidation)
 			}
 		} else {
@@ -87,7 +97,7 @@ func validateRuleNode(
 
 	if len(ruleNode.GrafanaManagedAlert.Data) != 0 {
 		cond := ngmodels.Condition{
-			Condition: ruleNode.GrafanaManagedAlert.Condition,
+			Condition: condition,
 			Data:      ruleNode.GrafanaManagedAlert.Data,
 		}
 		if err := conditionValidator(cond); err != nil {
@@ -98,7 +108,7 @@ func validateRuleNode(
 	newAlertRule := ngmodels.AlertRule{
 		OrgID:           orgId,
 		Title:           ruleNode.GrafanaManagedAlert.Title,
-		Condition:       ruleNode.GrafanaManagedAlert.Condition,
+		Condition:       condition,
 		Data:            ruleNode.GrafanaManagedAlert.Data,
 		UID:             ruleNode.GrafanaManagedAlert.UID,
 		IntervalSeconds: intervalSeconds,
@@ -106,6 +116,12 @@ func validateRuleNode(
 		RuleGroup:       groupName,
 		NoDataState:     noDataState,
 		ExecErrState:    errorState,
+		Record:          record,
+		KeepIsPaused:    ruleNode.GrafanaManagedAlert.IsPaused == nil,
+	}
+
+	if ruleNode.GrafanaManagedAlert.IsPaused != nil {
+		newAlertRule.IsPaused = *ruleNode.GrafanaManagedAlert.IsPaused
 	}
 
 	var err error
@@ -113,6 +129,10 @@ func validateRuleNode(
 	if err != nil {
 		return nil, err
 	}
+	newAlertRule.KeepFiringFor, err = validateKeepFiringFor(ruleNode)
+	if err != nil {
+		return nil, err
+	}
 
 	if ruleNode.ApiRuleNode != nil {
 		newAlertRule.Annotations = ruleNode.ApiRuleNode.Annotations
@@ -141,6 +161,21 @@ func validateForInterval(ruleNode *apimodels.PostableExtendedRuleNode) (time.Dur
 	return duration, nil
 }
 
+// validateKeepFiringFor validates ApiRuleNode.KeepFiringFor and converts it to time.Duration. If the field is not specified returns 0 if GrafanaManagedAlert.UID is empty and -1 if it is not.
+func validateKeepFiringFor(ruleNode *apimodels.PostableExtendedRuleNode) (time.Duration, error) {
+	if ruleNode.ApiRuleNode == nil || ruleNode.ApiRuleNode.KeepFiringFor == nil {
+		if ruleNode.GrafanaManagedAlert.UID != "" {
+			return -1, nil // will be patched later with the real value of the current version of the rule
+		}
+		return 0, nil
+	}
+	duration := time.Duration(*ruleNode.ApiRuleNode.KeepFiringFor)
+	if duration < 0 {
+		return 0, fmt.Errorf("field `keep_firing_for` cannot be negative [%v]. 0 or any positive duration are allowed", *ruleNode.ApiRuleNode.KeepFiringFor)
+	}
+	return duration, nil
+}
+
 // validateRuleGroup validates API model (definitions.PostableRuleGroupConfig) and converts it to a collection of models.AlertRule.
 // Returns a slice that contains all rules described by API model or error if either group specification or an alert definition is not valid.
 func validateRuleGroup(
@@ -242,8 +242,11 @@ func TestValidateRuleNode_NoUID(t *testing.T) {
 				require.Equal(t, models.NoDataState(api.GrafanaManagedAlert.NoDataState), alert.NoDataState)
 				require.Equal(t, models.ExecutionErrorState(api.GrafanaManagedAlert.ExecErrState), alert.ExecErrState)
 				require.Equal(t, time.Duration(*api.ApiRuleNode.For), alert.For)
+				require.Equal(t, time.Duration(0), alert.KeepFiringFor)
 				require.Equal(t, api.ApiRuleNode.Annotations, alert.Annotations)
 				require.Equal(t, api.ApiRuleNode.Labels, alert.Labels)
+				require.False(t, alert.IsPaused)
+				require.True(t, alert.KeepIsPaused)
 			},
 		},
 		{
@@ -298,6 +301,48 @@ func TestValidateRuleNode_NoUID(t *testing.T) {
 				require.Equal(t, int64(panelId), *alert.PanelID)
 			},
 		},
+		{
+			name: "converts keep_firing_for",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				keepFiringFor := model.Duration(time.Duration(rand.Int63n(1000)+1) * time.Second)
+				r.ApiRuleNode.KeepFiringFor = &keepFiringFor
+				return &r
+			},
+			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
+				require.Equal(t, time.Duration(*api.ApiRuleNode.KeepFiringFor), alert.KeepFiringFor)
+			},
+		},
+		{
+			name: "keeps the rule paused",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				isPaused := true
+				r.GrafanaManagedAlert.IsPaused = &isPaused
+				return &r
+			},
+			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
+				require.True(t, alert.IsPaused)
+				require.False(t, alert.KeepIsPaused)
+			},
+		},
+		{
+			name: "uses the node a recording rule records from as condition",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.GrafanaManagedAlert.Condition = ""
+				r.GrafanaManagedAlert.Record = &apimodels.Record{
+					Metric: "test_metric",
+					From:   "A",
+				}
+				return &r
+			},
+			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
+				require.Equal(t, "A", alert.Condition)
+				require.Equal(t, &models.Record{Metric: "test_metric", From: "A"}, alert.Record)
+				require.Equal(t, models.RuleTypeRecording, alert.Type())
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -438,6 +483,36 @@ func TestValidateRuleNodeFailures_NoUID(t *testing.T) {
 				return &r
 			},
 		},
+		{
+			name: "fail if metric of recording rule is not valid",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.GrafanaManagedAlert.Record = &apimodels.Record{
+					Metric: "invalid metric",
+					From:   "A",
+				}
+				return &r
+			},
+		},
+		{
+			name: "fail if recording rule does not specify the node to record from",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.GrafanaManagedAlert.Record = &apimodels.Record{
+					Metric: "test_metric",
+				}
+				return &r
+			},
+		},
+		{
+			name: "fail if keep_firing_for is negative",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				keepFiringFor := model.Duration(-time.Duration(rand.Int63n(1000)+1) * time.Second)
+				r.ApiRuleNode.KeepFiringFor = &keepFiringFor
+				return &r
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -510,6 +585,17 @@ func TestValidateRuleNode_UID(t *testing.T) {
 				require.Equal(t, models.ExecutionErrorState(""), alert.ExecErrState)
 			},
 		},
+		{
+			name: "use -1 if KeepFiringFor is not specified",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.ApiRuleNode.KeepFiringFor = nil
+				return &r
+			},
+			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
+				require.Equal(t, time.Duration(-1), alert.KeepFiringFor)
+			},
+		},
 		{
 			name: "use empty Condition and Data if they are empty",
 			rule: func() *apimodels.PostableExtendedRuleNode {
@@ -168,6 +168,22 @@ func (f *AlertmanagerApiHandler) handleRouteGetGrafanaAlertingConfig(ctx *models
 	return f.GrafanaSvc.RouteGetAlertingConfig(ctx)
 }
 
+func (f *AlertmanagerApiHandler) handleRouteGetGrafanaAlertingConfigHistory(ctx *models.ReqContext) response.Response {
+	return f.GrafanaSvc.RouteGetAlertingConfigHistory(ctx)
+}
+
+func (f *AlertmanagerApiHandler) handleRouteGetGrafanaAlertingConfigHistoryDiff(ctx *models.ReqContext, id string) response.Response {
+	return f.GrafanaSvc.RouteGetAlertingConfigHistoryDiff(ctx, id)
+}
+
+func (f *AlertmanagerApiHandler) handleRoutePostGrafanaAlertingConfigHistoryActivate(ctx *models.ReqContext, id string) response.Response {
+	return f.GrafanaSvc.RoutePostAlertingConfigHistoryActivate(ctx, id)
+}
+
+func (f *AlertmanagerApiHandler) handleRouteGetGrafanaNotificationDeliveries(ctx *models.ReqContext) response.Response {
+	return f.GrafanaSvc.RouteGetNotificationDeliveries(ctx)
+}
+
 func (f *AlertmanagerApiHandler) handleRouteGetGrafanaSilence(ctx *models.ReqContext, id string) response.Response {
 	return f.GrafanaSvc.RouteGetSilence(ctx, id)
 }
@@ -194,3 +210,7 @@ func (f *AlertmanagerApiHandler) handleRouteGetGrafanaReceivers(ctx *models.ReqC
 func (f *AlertmanagerApiHandler) handleRoutePostTestGrafanaReceivers(ctx *models.ReqContext, conf apimodels.TestReceiversConfigBodyParams) response.Response {
 	return f.GrafanaSvc.RoutePostTestReceivers(ctx, conf)
 }
+
+func (f *AlertmanagerApiHandler) handleRoutePostTestGrafanaTemplates(ctx *models.ReqContext, conf apimodels.TestTemplatesConfigBodyParams) response.Response {
+	return f.GrafanaSvc.RoutePostTestTemplates(ctx, conf)
+}
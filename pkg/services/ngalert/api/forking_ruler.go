@@ -81,6 +81,14 @@ func (f *RulerApiHandler) handleRouteDeleteGrafanaRuleGroupConfig(ctx *models.Re
 	return f.GrafanaRuler.RouteDeleteAlertRules(ctx, namespace, groupName)
 }
 
+func (f *RulerApiHandler) handleRoutePostPauseGrafanaRuleGroup(ctx *models.ReqContext, namespace, groupName string) response.Response {
+	return f.GrafanaRuler.RoutePostPauseRuleGroup(ctx, namespace, groupName, true)
+}
+
+func (f *RulerApiHandler) handleRoutePostResumeGrafanaRuleGroup(ctx *models.ReqContext, namespace, groupName string) response.Response {
+	return f.GrafanaRuler.RoutePostPauseRuleGroup(ctx, namespace, groupName, false)
+}
+
 func (f *RulerApiHandler) handleRouteGetNamespaceGrafanaRulesConfig(ctx *models.ReqContext, namespace string) response.Response {
 	return f.GrafanaRuler.RouteGetNamespaceRulesConfig(ctx, namespace)
 }
@@ -101,6 +109,22 @@ func (f *RulerApiHandler) handleRoutePostNameGrafanaRulesConfig(ctx *models.ReqC
 	return f.GrafanaRuler.RoutePostNameRulesConfig(ctx, conf, namespace)
 }
 
+func (f *RulerApiHandler) handleRoutePostPrometheusRulesImport(ctx *models.ReqContext, conf apimodels.PrometheusRulesImportBody, namespace string) response.Response {
+	return f.GrafanaRuler.RoutePostPrometheusRulesImport(ctx, conf, namespace)
+}
+
+func (f *RulerApiHandler) handleRouteGetRuleVersionsByUID(ctx *models.ReqContext, ruleUID string) response.Response {
+	return f.GrafanaRuler.RouteGetRuleVersionsByUID(ctx, ruleUID)
+}
+
+func (f *RulerApiHandler) handleRouteGetRuleVersionDiff(ctx *models.ReqContext, ruleUID, version string) response.Response {
+	return f.GrafanaRuler.RouteGetRuleVersionDiff(ctx, ruleUID, version)
+}
+
+func (f *RulerApiHandler) handleRoutePostRuleVersionRestore(ctx *models.ReqContext, ruleUID, version string) response.Response {
+	return f.GrafanaRuler.RoutePostRuleVersionRestore(ctx, ruleUID, version)
+}
+
 func (f *RulerApiHandler) getService(ctx *models.ReqContext) (*LotexRuler, error) {
 	_, err := getDatasourceByUID(ctx, f.DatasourceCache, apimodels.LoTexRulerBackend)
 	if err != nil {
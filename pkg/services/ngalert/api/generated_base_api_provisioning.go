@@ -25,13 +25,25 @@ type ProvisioningApi interface {
 	RouteDeleteTemplate(*models.ReqContext) response.Response
 	RouteGetAlertRule(*models.ReqContext) response.Response
 	RouteGetAlertRuleGroup(*models.ReqContext) response.Response
+	RouteGetAlertRuleGroupExport(*models.ReqContext) response.Response
+	RouteGetAlertRuleVersion(*models.ReqContext) response.Response
+	RouteGetAlertRuleVersionDiff(*models.ReqContext) response.Response
+	RouteGetAlertRuleVersions(*models.ReqContext) response.Response
+	RouteGetAlertRulesExport(*models.ReqContext) response.Response
 	RouteGetContactpoints(*models.ReqContext) response.Response
+	RouteGetContactpointsExport(*models.ReqContext) response.Response
 	RouteGetMuteTiming(*models.ReqContext) response.Response
 	RouteGetMuteTimings(*models.ReqContext) response.Response
+	RouteGetMuteTimingsExport(*models.ReqContext) response.Response
 	RouteGetPolicyTree(*models.ReqContext) response.Response
+	RouteGetPolicyTreeExport(*models.ReqContext) response.Response
 	RouteGetTemplate(*models.ReqContext) response.Response
 	RouteGetTemplates(*models.ReqContext) response.Response
+	RouteGetTemplatesExport(*models.ReqContext) response.Response
 	RoutePostAlertRule(*models.ReqContext) response.Response
+	RoutePostAlertRuleGroupPause(*models.ReqContext) response.Response
+	RoutePostAlertRuleGroupResume(*models.ReqContext) response.Response
+	RoutePostAlertRuleVersionRestore(*models.ReqContext) response.Response
 	RoutePostContactpoints(*models.ReqContext) response.Response
 	RoutePostMuteTiming(*models.ReqContext) response.Response
 	RoutePutAlertRule(*models.ReqContext) response.Response
@@ -74,9 +86,38 @@ func (f *ProvisioningApiHandler) RouteGetAlertRuleGroup(ctx *models.ReqContext)
 	groupParam := web.Params(ctx.Req)[":Group"]
 	return f.handleRouteGetAlertRuleGroup(ctx, folderUIDParam, groupParam)
 }
+func (f *ProvisioningApiHandler) RouteGetAlertRuleGroupExport(ctx *models.ReqContext) response.Response {
+	// Parse Path Parameters
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	groupParam := web.Params(ctx.Req)[":Group"]
+	return f.handleRouteGetAlertRuleGroupExport(ctx, folderUIDParam, groupParam)
+}
+func (f *ProvisioningApiHandler) RouteGetAlertRuleVersion(ctx *models.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	versionParam := web.Params(ctx.Req)[":Version"]
+	return f.handleRouteGetAlertRuleVersion(ctx, uIDParam, versionParam)
+}
+func (f *ProvisioningApiHandler) RouteGetAlertRuleVersionDiff(ctx *models.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	versionParam := web.Params(ctx.Req)[":Version"]
+	return f.handleRouteGetAlertRuleVersionDiff(ctx, uIDParam, versionParam)
+}
+func (f *ProvisioningApiHandler) RouteGetAlertRuleVersions(ctx *models.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteGetAlertRuleVersions(ctx, uIDParam)
+}
+func (f *ProvisioningApiHandler) RouteGetAlertRulesExport(ctx *models.ReqContext) response.Response {
+	return f.handleRouteGetAlertRulesExport(ctx)
+}
 func (f *ProvisioningApiHandler) RouteGetContactpoints(ctx *models.ReqContext) response.Response {
 	return f.handleRouteGetContactpoints(ctx)
 }
+func (f *ProvisioningApiHandler) RouteGetContactpointsExport(ctx *models.ReqContext) response.Response {
+	return f.handleRouteGetContactpointsExport(ctx)
+}
 func (f *ProvisioningApiHandler) RouteGetMuteTiming(ctx *models.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
@@ -85,9 +126,15 @@ func (f *ProvisioningApiHandler) RouteGetMuteTiming(ctx *models.ReqContext) resp
 func (f *ProvisioningApiHandler) RouteGetMuteTimings(ctx *models.ReqContext) response.Response {
 	return f.handleRouteGetMuteTimings(ctx)
 }
+func (f *ProvisioningApiHandler) RouteGetMuteTimingsExport(ctx *models.ReqContext) response.Response {
+	return f.handleRouteGetMuteTimingsExport(ctx)
+}
 func (f *ProvisioningApiHandler) RouteGetPolicyTree(ctx *models.ReqContext) response.Response {
 	return f.handleRouteGetPolicyTree(ctx)
 }
+func (f *ProvisioningApiHandler) RouteGetPolicyTreeExport(ctx *models.ReqContext) response.Response {
+	return f.handleRouteGetPolicyTreeExport(ctx)
+}
 func (f *ProvisioningApiHandler) RouteGetTemplate(ctx *models.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
@@ -96,6 +143,9 @@ func (f *ProvisioningApiHandler) RouteGetTemplate(ctx *models.ReqContext) respon
 func (f *ProvisioningApiHandler) RouteGetTemplates(ctx *models.ReqContext) response.Response {
 	return f.handleRouteGetTemplates(ctx)
 }
+func (f *ProvisioningApiHandler) RouteGetTemplatesExport(ctx *models.ReqContext) response.Response {
+	return f.handleRouteGetTemplatesExport(ctx)
+}
 func (f *ProvisioningApiHandler) RoutePostAlertRule(ctx *models.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.ProvisionedAlertRule{}
@@ -104,6 +154,24 @@ func (f *ProvisioningApiHandler) RoutePostAlertRule(ctx *models.ReqContext) resp
 	}
 	return f.handleRoutePostAlertRule(ctx, conf)
 }
+func (f *ProvisioningApiHandler) RoutePostAlertRuleGroupPause(ctx *models.ReqContext) response.Response {
+	// Parse Path Parameters
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	groupParam := web.Params(ctx.Req)[":Group"]
+	return f.handleRoutePostAlertRuleGroupPause(ctx, folderUIDParam, groupParam)
+}
+func (f *ProvisioningApiHandler) RoutePostAlertRuleGroupResume(ctx *models.ReqContext) response.Response {
+	// Parse Path Parameters
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	groupParam := web.Params(ctx.Req)[":Group"]
+	return f.handleRoutePostAlertRuleGroupResume(ctx, folderUIDParam, groupParam)
+}
+func (f *ProvisioningApiHandler) RoutePostAlertRuleVersionRestore(ctx *models.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	versionParam := web.Params(ctx.Req)[":Version"]
+	return f.handleRoutePostAlertRuleVersionRestore(ctx, uIDParam, versionParam)
+}
 func (f *ProvisioningApiHandler) RoutePostContactpoints(ctx *models.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.EmbeddedContactPoint{}
@@ -245,6 +313,56 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/export"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/export"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/export",
+				srv.RouteGetAlertRuleGroupExport,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/alert-rules/{UID}/versions/{Version}"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rules/{UID}/versions/{Version}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/alert-rules/{UID}/versions/{Version}",
+				srv.RouteGetAlertRuleVersion,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/alert-rules/{UID}/versions/{Version}/diff"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rules/{UID}/versions/{Version}/diff"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/alert-rules/{UID}/versions/{Version}/diff",
+				srv.RouteGetAlertRuleVersionDiff,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/alert-rules/{UID}/versions"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rules/{UID}/versions"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/alert-rules/{UID}/versions",
+				srv.RouteGetAlertRuleVersions,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/alert-rules/export"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rules/export"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/alert-rules/export",
+				srv.RouteGetAlertRulesExport,
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/provisioning/contact-points"),
 			api.authorize(http.MethodGet, "/api/v1/provisioning/contact-points"),
@@ -255,6 +373,16 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/contact-points/export"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/contact-points/export"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/contact-points/export",
+				srv.RouteGetContactpointsExport,
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/provisioning/mute-timings/{name}"),
 			api.authorize(http.MethodGet, "/api/v1/provisioning/mute-timings/{name}"),
@@ -275,6 +403,16 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/mute-timings/export"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/mute-timings/export"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/mute-timings/export",
+				srv.RouteGetMuteTimingsExport,
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/provisioning/policies"),
 			api.authorize(http.MethodGet, "/api/v1/provisioning/policies"),
@@ -285,6 +423,16 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/policies/export"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/policies/export"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/policies/export",
+				srv.RouteGetPolicyTreeExport,
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/provisioning/templates/{name}"),
 			api.authorize(http.MethodGet, "/api/v1/provisioning/templates/{name}"),
@@ -305,6 +453,16 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/templates/export"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/templates/export"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/templates/export",
+				srv.RouteGetTemplatesExport,
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/provisioning/alert-rules"),
 			api.authorize(http.MethodPost, "/api/v1/provisioning/alert-rules"),
@@ -315,6 +473,36 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/pause"),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/pause"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/pause",
+				srv.RoutePostAlertRuleGroupPause,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/resume"),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/resume"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/resume",
+				srv.RoutePostAlertRuleGroupResume,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/alert-rules/{UID}/versions/{Version}/restore"),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/alert-rules/{UID}/versions/{Version}/restore"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/alert-rules/{UID}/versions/{Version}/restore",
+				srv.RoutePostAlertRuleVersionRestore,
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/provisioning/contact-points"),
 			api.authorize(http.MethodPost, "/api/v1/provisioning/contact-points"),
@@ -27,10 +27,16 @@ type RulerApi interface {
 	RouteGetGrafanaRulesConfig(*models.ReqContext) response.Response
 	RouteGetNamespaceGrafanaRulesConfig(*models.ReqContext) response.Response
 	RouteGetNamespaceRulesConfig(*models.ReqContext) response.Response
+	RouteGetRuleVersionDiff(*models.ReqContext) response.Response
+	RouteGetRuleVersionsByUID(*models.ReqContext) response.Response
 	RouteGetRulegGroupConfig(*models.ReqContext) response.Response
 	RouteGetRulesConfig(*models.ReqContext) response.Response
 	RoutePostNameGrafanaRulesConfig(*models.ReqContext) response.Response
 	RoutePostNameRulesConfig(*models.ReqContext) response.Response
+	RoutePostPauseGrafanaRuleGroup(*models.ReqContext) response.Response
+	RoutePostPrometheusRulesImport(*models.ReqContext) response.Response
+	RoutePostResumeGrafanaRuleGroup(*models.ReqContext) response.Response
+	RoutePostRuleVersionRestore(*models.ReqContext) response.Response
 }
 
 func (f *RulerApiHandler) RouteDeleteGrafanaRuleGroupConfig(ctx *models.ReqContext) response.Response {
@@ -77,6 +83,17 @@ func (f *RulerApiHandler) RouteGetNamespaceRulesConfig(ctx *models.ReqContext) r
 	namespaceParam := web.Params(ctx.Req)[":Namespace"]
 	return f.handleRouteGetNamespaceRulesConfig(ctx, datasourceUIDParam, namespaceParam)
 }
+func (f *RulerApiHandler) RouteGetRuleVersionDiff(ctx *models.ReqContext) response.Response {
+	// Parse Path Parameters
+	ruleUIDParam := web.Params(ctx.Req)[":RuleUID"]
+	versionParam := web.Params(ctx.Req)[":Version"]
+	return f.handleRouteGetRuleVersionDiff(ctx, ruleUIDParam, versionParam)
+}
+func (f *RulerApiHandler) RouteGetRuleVersionsByUID(ctx *models.ReqContext) response.Response {
+	// Parse Path Parameters
+	ruleUIDParam := web.Params(ctx.Req)[":RuleUID"]
+	return f.handleRouteGetRuleVersionsByUID(ctx, ruleUIDParam)
+}
 func (f *RulerApiHandler) RouteGetRulegGroupConfig(ctx *models.ReqContext) response.Response {
 	// Parse Path Parameters
 	datasourceUIDParam := web.Params(ctx.Req)[":DatasourceUID"]
@@ -110,6 +127,34 @@ func (f *RulerApiHandler) RoutePostNameRulesConfig(ctx *models.ReqContext) respo
 	}
 	return f.handleRoutePostNameRulesConfig(ctx, conf, datasourceUIDParam, namespaceParam)
 }
+func (f *RulerApiHandler) RoutePostPauseGrafanaRuleGroup(ctx *models.ReqContext) response.Response {
+	// Parse Path Parameters
+	namespaceParam := web.Params(ctx.Req)[":Namespace"]
+	groupnameParam := web.Params(ctx.Req)[":Groupname"]
+	return f.handleRoutePostPauseGrafanaRuleGroup(ctx, namespaceParam, groupnameParam)
+}
+func (f *RulerApiHandler) RoutePostPrometheusRulesImport(ctx *models.ReqContext) response.Response {
+	// Parse Path Parameters
+	namespaceParam := web.Params(ctx.Req)[":Namespace"]
+	// Parse Request Body
+	conf := apimodels.PrometheusRulesImportBody{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostPrometheusRulesImport(ctx, conf, namespaceParam)
+}
+func (f *RulerApiHandler) RoutePostResumeGrafanaRuleGroup(ctx *models.ReqContext) response.Response {
+	// Parse Path Parameters
+	namespaceParam := web.Params(ctx.Req)[":Namespace"]
+	groupnameParam := web.Params(ctx.Req)[":Groupname"]
+	return f.handleRoutePostResumeGrafanaRuleGroup(ctx, namespaceParam, groupnameParam)
+}
+func (f *RulerApiHandler) RoutePostRuleVersionRestore(ctx *models.ReqContext) response.Response {
+	// Parse Path Parameters
+	ruleUIDParam := web.Params(ctx.Req)[":RuleUID"]
+	versionParam := web.Params(ctx.Req)[":Version"]
+	return f.handleRoutePostRuleVersionRestore(ctx, ruleUIDParam, versionParam)
+}
 
 func (api *API) RegisterRulerApiEndpoints(srv RulerApi, m *metrics.API) {
 	api.RouteRegister.Group("", func(group routing.RouteRegister) {
@@ -193,6 +238,26 @@ func (api *API) RegisterRulerApiEndpoints(srv RulerApi, m *metrics.API) {
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}/diff"),
+			api.authorize(http.MethodGet, "/api/ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}/diff"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}/diff",
+				srv.RouteGetRuleVersionDiff,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/ruler/grafana/api/v1/rule/{RuleUID}/versions"),
+			api.authorize(http.MethodGet, "/api/ruler/grafana/api/v1/rule/{RuleUID}/versions"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/ruler/grafana/api/v1/rule/{RuleUID}/versions",
+				srv.RouteGetRuleVersionsByUID,
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/ruler/{DatasourceUID}/api/v1/rules/{Namespace}/{Groupname}"),
 			api.authorize(http.MethodGet, "/api/ruler/{DatasourceUID}/api/v1/rules/{Namespace}/{Groupname}"),
@@ -233,5 +298,45 @@ func (api *API) RegisterRulerApiEndpoints(srv RulerApi, m *metrics.API) {
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/ruler/grafana/api/v1/rules/{Namespace}/{Groupname}/pause"),
+			api.authorize(http.MethodPost, "/api/ruler/grafana/api/v1/rules/{Namespace}/{Groupname}/pause"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/ruler/grafana/api/v1/rules/{Namespace}/{Groupname}/pause",
+				srv.RoutePostPauseGrafanaRuleGroup,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/ruler/grafana/api/v1/import/prometheus/{Namespace}"),
+			api.authorize(http.MethodPost, "/api/ruler/grafana/api/v1/import/prometheus/{Namespace}"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/ruler/grafana/api/v1/import/prometheus/{Namespace}",
+				srv.RoutePostPrometheusRulesImport,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/ruler/grafana/api/v1/rules/{Namespace}/{Groupname}/resume"),
+			api.authorize(http.MethodPost, "/api/ruler/grafana/api/v1/rules/{Namespace}/{Groupname}/resume"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/ruler/grafana/api/v1/rules/{Namespace}/{Groupname}/resume",
+				srv.RoutePostResumeGrafanaRuleGroup,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}/restore"),
+			api.authorize(http.MethodPost, "/api/ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}/restore"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}/restore",
+				srv.RoutePostRuleVersionRestore,
+				m,
+			),
+		)
 	}, middleware.ReqSignedIn)
 }
@@ -0,0 +1,242 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	// prometheusImportQueryRange is the relative time range of the queries of imported rules.
+	prometheusImportQueryRange = 10 * time.Minute
+
+	prometheusImportQueryRefID     = "A"
+	prometheusImportReduceRefID    = "B"
+	prometheusImportConditionRefID = "C"
+)
+
+// prometheusTemplateValueRegexp matches $value in the templates of Prometheus rules, but not $values.
+var prometheusTemplateValueRegexp = regexp.MustCompile(`\$value\b`)
+
+// prometheusRuleConverter converts the rules of Prometheus rule files to Grafana managed rules that query a
+// Prometheus or Loki data source.
+//
+// Alerting rules query the expression of the rule and fire for every series that is returned, the same way as
+// in Prometheus. If the expression compares a vector with a number, such as `rate(errors_total[5m]) > 0.5`, the
+// vector is queried and the comparison is converted to a threshold expression instead, so that the threshold can be
+// changed in Grafana. Recording rules record the result of the query.
+type prometheusRuleConverter struct {
+	datasourceUID  string
+	datasourceType string
+}
+
+func newPrometheusRuleConverter(ds *datasources.DataSource) (*prometheusRuleConverter, error) {
+	if ds.Type != datasources.DS_PROMETHEUS && ds.Type != datasources.DS_LOKI {
+		return nil, fmt.Errorf("data source %s of type %s is neither a Prometheus nor a Loki data source", ds.Uid, ds.Type)
+	}
+	return &prometheusRuleConverter{datasourceUID: ds.Uid, datasourceType: ds.Type}, nil
+}
+
+// convertRule converts a rule of a Prometheus rule file to a Grafana managed rule.
+func (c *prometheusRuleConverter) convertRule(rule apimodels.ApiRuleNode) (*apimodels.PostableExtendedRuleNode, error) {
+	if rule.Alert == "" && rule.Record == "" {
+		return nil, errors.New("rule is neither an alerting nor a recording rule")
+	}
+	if rule.Alert != "" && rule.Record != "" {
+		return nil, errors.New("rule cannot be both an alerting and a recording rule")
+	}
+	if rule.Expr == "" {
+		return nil, errors.New("expression of the rule is empty")
+	}
+
+	queryExpr, threshold, err := c.splitThreshold(rule.Expr)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &apimodels.PostableExtendedRuleNode{
+		ApiRuleNode: &apimodels.ApiRuleNode{
+			Labels: rule.Labels,
+		},
+		GrafanaManagedAlert: &apimodels.PostableGrafanaRule{
+			NoDataState:  apimodels.OK,
+			ExecErrState: apimodels.ErrorErrState,
+		},
+	}
+
+	if rule.Record != "" {
+		if rule.For != nil || rule.KeepFiringFor != nil || len(rule.Annotations) > 0 {
+			return nil, errors.New("recording rules cannot have a for or keep_firing_for duration or annotations")
+		}
+		query, err := c.query(rule.Expr)
+		if err != nil {
+			return nil, err
+		}
+		result.GrafanaManagedAlert.Title = rule.Record
+		result.GrafanaManagedAlert.Data = []ngmodels.AlertQuery{query}
+		result.GrafanaManagedAlert.Record = &apimodels.Record{Metric: rule.Record, From: prometheusImportQueryRefID}
+		return result, nil
+	}
+
+	query, err := c.query(queryExpr)
+	if err != nil {
+		return nil, err
+	}
+	reduce, err := expressionQuery(prometheusImportReduceRefID, map[string]interface{}{
+		"type":       "reduce",
+		"expression": prometheusImportQueryRefID,
+		"reducer":    "last",
+	})
+	if err != nil {
+		return nil, err
+	}
+	var condition ngmodels.AlertQuery
+	if threshold != nil {
+		condition, err = expressionQuery(prometheusImportConditionRefID, map[string]interface{}{
+			"type":       "threshold",
+			"expression": prometheusImportReduceRefID,
+			"conditions": []expr.ThresholdConditionJSON{{Evaluator: *threshold}},
+		})
+	} else {
+		// the alert fires for every series that is returned by the expression, regardless of its value
+		condition, err = expressionQuery(prometheusImportConditionRefID, map[string]interface{}{
+			"type":       "math",
+			"expression": fmt.Sprintf("is_number($%[1]s) || is_nan($%[1]s) || is_inf($%[1]s)", prometheusImportReduceRefID),
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.ApiRuleNode.For = rule.For
+	result.ApiRuleNode.KeepFiringFor = rule.KeepFiringFor
+	result.ApiRuleNode.Annotations = convertPrometheusTemplates(rule.Annotations)
+	result.ApiRuleNode.Labels = convertPrometheusTemplates(rule.Labels)
+	result.GrafanaManagedAlert.Title = rule.Alert
+	result.GrafanaManagedAlert.Condition = prometheusImportConditionRefID
+	result.GrafanaManagedAlert.Data = []ngmodels.AlertQuery{query, reduce, condition}
+	return result, nil
+}
+
+// splitThreshold returns the vector expression and the threshold of an expression that compares a vector with a
+// number, such as `rate(errors_total[5m]) > 0.5`. If the expression cannot be split, it is returned with a nil
+// threshold. Only PromQL expressions are split.
+func (c *prometheusRuleConverter) splitThreshold(e string) (string, *expr.ConditionEvalJSON, error) {
+	if c.datasourceType != datasources.DS_PROMETHEUS {
+		return e, nil, nil
+	}
+	parsed, err := parser.ParseExpr(e)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid PromQL expression: %w", err)
+	}
+	for {
+		paren, ok := parsed.(*parser.ParenExpr)
+		if !ok {
+			break
+		}
+		parsed = paren.Expr
+	}
+	binary, ok := parsed.(*parser.BinaryExpr)
+	if !ok || binary.ReturnBool {
+		return e, nil, nil
+	}
+
+	op := binary.Op
+	vector, number := binary.LHS, binary.RHS
+	if _, ok := vector.(*parser.NumberLiteral); ok {
+		// the number is on the left side of the comparison, such as `0.5 < rate(errors_total[5m])`
+		vector, number = number, vector
+		switch op {
+		case parser.GTR:
+			op = parser.LSS
+		case parser.LSS:
+			op = parser.GTR
+		}
+	}
+	literal, ok := number.(*parser.NumberLiteral)
+	if !ok || vector.Type() != parser.ValueTypeVector {
+		return e, nil, nil
+	}
+
+	var evaluator string
+	switch op {
+	case parser.GTR:
+		evaluator = expr.ThresholdIsAbove
+	case parser.LSS:
+		evaluator = expr.ThresholdIsBelow
+	default:
+		return e, nil, nil
+	}
+	pos := vector.PositionRange()
+	return e[pos.Start:pos.End], &expr.ConditionEvalJSON{Type: evaluator, Params: []float64{literal.Val}}, nil
+}
+
+// query returns the query of the expression against the data source.
+func (c *prometheusRuleConverter) query(e string) (ngmodels.AlertQuery, error) {
+	model := map[string]interface{}{
+		"refId": prometheusImportQueryRefID,
+		"expr":  e,
+		"datasource": map[string]string{
+			"type": c.datasourceType,
+			"uid":  c.datasourceUID,
+		},
+	}
+	switch c.datasourceType {
+	case datasources.DS_PROMETHEUS:
+		model["instant"] = true
+		model["range"] = false
+	case datasources.DS_LOKI:
+		model["queryType"] = "instant"
+	}
+	raw, err := json.Marshal(model)
+	if err != nil {
+		return ngmodels.AlertQuery{}, err
+	}
+	return ngmodels.AlertQuery{
+		RefID:             prometheusImportQueryRefID,
+		DatasourceUID:     c.datasourceUID,
+		RelativeTimeRange: ngmodels.RelativeTimeRange{From: ngmodels.Duration(prometheusImportQueryRange)},
+		Model:             raw,
+	}, nil
+}
+
+// expressionQuery returns the server side expression of the model.
+func expressionQuery(refID string, model map[string]interface{}) (ngmodels.AlertQuery, error) {
+	model["refId"] = refID
+	model["datasource"] = map[string]string{
+		"type": expr.DatasourceType,
+		"uid":  expr.DatasourceUID,
+	}
+	raw, err := json.Marshal(model)
+	if err != nil {
+		return ngmodels.AlertQuery{}, err
+	}
+	return ngmodels.AlertQuery{
+		RefID:         refID,
+		QueryType:     expr.DatasourceType,
+		DatasourceUID: expr.DatasourceUID,
+		Model:         raw,
+	}, nil
+}
+
+// convertPrometheusTemplates replaces $value in the templates of Prometheus rules with the value of the reduced
+// expression, because $value of Grafana contains the values of all queries and expressions.
+func convertPrometheusTemplates(templates map[string]string) map[string]string {
+	if templates == nil {
+		return nil
+	}
+	result := make(map[string]string, len(templates))
+	for k, v := range templates {
+		result[k] = prometheusTemplateValueRegexp.ReplaceAllString(v, "$$values."+prometheusImportReduceRefID+".Value")
+	}
+	return result
+}
@@ -0,0 +1,380 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	models2 "github.com/grafana/grafana/pkg/models"
+	acMock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestPrometheusRuleConverter(t *testing.T) {
+	prometheus, err := newPrometheusRuleConverter(&datasources.DataSource{Uid: "prom", Type: datasources.DS_PROMETHEUS})
+	require.NoError(t, err)
+	loki, err := newPrometheusRuleConverter(&datasources.DataSource{Uid: "loki", Type: datasources.DS_LOKI})
+	require.NoError(t, err)
+
+	queryModel := func(t *testing.T, q models.AlertQuery) map[string]interface{} {
+		t.Helper()
+		m := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(q.Model, &m))
+		return m
+	}
+
+	t.Run("should fail for data sources that are not Prometheus or Loki", func(t *testing.T) {
+		_, err := newPrometheusRuleConverter(&datasources.DataSource{Uid: "graphite", Type: datasources.DS_GRAPHITE})
+		require.ErrorContains(t, err, "neither a Prometheus nor a Loki data source")
+	})
+
+	t.Run("should convert comparisons with numbers to threshold expressions", func(t *testing.T) {
+		testCases := []struct {
+			expr      string
+			expQuery  string
+			expType   string
+			expParams []float64
+		}{
+			{expr: `rate(errors_total{job="api"}[5m]) > 0.5`, expQuery: `rate(errors_total{job="api"}[5m])`, expType: "gt", expParams: []float64{0.5}},
+			{expr: `up < 1`, expQuery: `up`, expType: "lt", expParams: []float64{1}},
+			{expr: `10 < sum by (job) (up)`, expQuery: `sum by (job) (up)`, expType: "gt", expParams: []float64{10}},
+			{expr: `(node_load1 > -1)`, expQuery: `node_load1`, expType: "gt", expParams: []float64{-1}},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.expr, func(t *testing.T) {
+				node, err := prometheus.convertRule(apimodels.ApiRuleNode{Alert: "Test", Expr: tc.expr})
+				require.NoError(t, err)
+				data := node.GrafanaManagedAlert.Data
+				require.Len(t, data, 3)
+				require.Equal(t, "C", node.GrafanaManagedAlert.Condition)
+
+				require.Equal(t, "prom", data[0].DatasourceUID)
+				query := queryModel(t, data[0])
+				require.Equal(t, tc.expQuery, query["expr"])
+				require.Equal(t, true, query["instant"])
+
+				reduce := queryModel(t, data[1])
+				require.Equal(t, "reduce", reduce["type"])
+				require.Equal(t, "A", reduce["expression"])
+
+				threshold := queryModel(t, data[2])
+				require.Equal(t, "threshold", threshold["type"])
+				require.Equal(t, "B", threshold["expression"])
+				conditions := threshold["conditions"].([]interface{})
+				evaluator := conditions[0].(map[string]interface{})["evaluator"].(map[string]interface{})
+				require.Equal(t, tc.expType, evaluator["type"])
+				params := make([]float64, 0, len(tc.expParams))
+				for _, p := range evaluator["params"].([]interface{}) {
+					params = append(params, p.(float64))
+				}
+				require.Equal(t, tc.expParams, params)
+			})
+		}
+	})
+
+	t.Run("should fire for every series of other expressions", func(t *testing.T) {
+		testCases := []string{
+			`up == 0`,
+			`up >= 1`,
+			`up > bool 1`,
+			`up > on (job) other`,
+			`absent(up{job="api"})`,
+			`up > 1 and other`,
+		}
+		for _, expr := range testCases {
+			t.Run(expr, func(t *testing.T) {
+				node, err := prometheus.convertRule(apimodels.ApiRuleNode{Alert: "Test", Expr: expr})
+				require.NoError(t, err)
+				data := node.GrafanaManagedAlert.Data
+				require.Len(t, data, 3)
+				require.Equal(t, expr, queryModel(t, data[0])["expr"])
+				condition := queryModel(t, data[2])
+				require.Equal(t, "math", condition["type"])
+				require.Equal(t, "is_number($B) || is_nan($B) || is_inf($B)", condition["expression"])
+			})
+		}
+	})
+
+	t.Run("should not split Loki expressions", func(t *testing.T) {
+		expr := `sum by (app) (rate({app="api"} |= "error" [5m])) > 10`
+		node, err := loki.convertRule(apimodels.ApiRuleNode{Alert: "Test", Expr: expr})
+		require.NoError(t, err)
+		query := queryModel(t, node.GrafanaManagedAlert.Data[0])
+		require.Equal(t, expr, query["expr"])
+		require.Equal(t, "instant", query["queryType"])
+		require.Equal(t, "math", queryModel(t, node.GrafanaManagedAlert.Data[2])["type"])
+	})
+
+	t.Run("should convert alerting rules", func(t *testing.T) {
+		forDuration := model.Duration(5 * time.Minute)
+		node, err := prometheus.convertRule(apimodels.ApiRuleNode{
+			Alert:       "HighErrorRate",
+			Expr:        `rate(errors_total[5m]) > 0.5`,
+			For:         &forDuration,
+			Labels:      map[string]string{"severity": "critical"},
+			Annotations: map[string]string{"summary": "Error rate is {{ $value | humanize }} on {{ $labels.instance }}", "values": "{{ $values.B }}"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "HighErrorRate", node.GrafanaManagedAlert.Title)
+		require.Equal(t, &forDuration, node.ApiRuleNode.For)
+		require.Equal(t, map[string]string{"severity": "critical"}, node.ApiRuleNode.Labels)
+		require.Equal(t, "Error rate is {{ $values.B.Value | humanize }} on {{ $labels.instance }}", node.ApiRuleNode.Annotations["summary"])
+		require.Equal(t, "{{ $values.B }}", node.ApiRuleNode.Annotations["values"])
+		require.Equal(t, apimodels.OK, node.GrafanaManagedAlert.NoDataState)
+		require.Equal(t, apimodels.ErrorErrState, node.GrafanaManagedAlert.ExecErrState)
+		require.Empty(t, node.ApiRuleNode.Expr)
+	})
+
+	t.Run("should convert recording rules", func(t *testing.T) {
+		node, err := prometheus.convertRule(apimodels.ApiRuleNode{
+			Record: "job:errors:rate5m",
+			Expr:   `sum by (job) (rate(errors_total[5m])) > 0`,
+			Labels: map[string]string{"team": "api"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "job:errors:rate5m", node.GrafanaManagedAlert.Title)
+		require.Equal(t, &apimodels.Record{Metric: "job:errors:rate5m", From: "A"}, node.GrafanaManagedAlert.Record)
+		require.Len(t, node.GrafanaManagedAlert.Data, 1)
+		require.Equal(t, `sum by (job) (rate(errors_total[5m])) > 0`, queryModel(t, node.GrafanaManagedAlert.Data[0])["expr"])
+		require.Equal(t, map[string]string{"team": "api"}, node.ApiRuleNode.Labels)
+	})
+
+	t.Run("should fail for invalid rules", func(t *testing.T) {
+		forDuration := model.Duration(time.Minute)
+		testCases := map[string]struct {
+			rule   apimodels.ApiRuleNode
+			expErr string
+		}{
+			"missing name":            {rule: apimodels.ApiRuleNode{Expr: "up"}, expErr: "neither an alerting nor a recording rule"},
+			"alert and record":        {rule: apimodels.ApiRuleNode{Alert: "a", Record: "b", Expr: "up"}, expErr: "both an alerting and a recording rule"},
+			"missing expression":      {rule: apimodels.ApiRuleNode{Alert: "a"}, expErr: "expression of the rule is empty"},
+			"invalid PromQL":          {rule: apimodels.ApiRuleNode{Alert: "a", Expr: "rate(up[5m]"}, expErr: "invalid PromQL expression"},
+			"recording rule with for": {rule: apimodels.ApiRuleNode{Record: "b", Expr: "up", For: &forDuration}, expErr: "recording rules cannot have"},
+		}
+		for name, tc := range testCases {
+			t.Run(name, func(t *testing.T) {
+				_, err := prometheus.convertRule(tc.rule)
+				require.ErrorContains(t, err, tc.expErr)
+			})
+		}
+	})
+}
+
+type fakeConditionValidator struct {
+	err error
+}
+
+func (f fakeConditionValidator) Validate(context.Context, *user.SignedInUser, models.Condition) error {
+	return f.err
+}
+
+func TestRoutePostPrometheusRulesImport(t *testing.T) {
+	orgID := int64(1)
+	createImportService := func(t *testing.T) (*RulerSrv, *fakes.RuleStore, *models2.Folder) {
+		t.Helper()
+		folder := randFolder()
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+		scheduler := &schedule.FakeScheduleService{}
+		scheduler.On("UpdateAlertRule", mock.Anything, mock.Anything)
+		scheduler.On("DeleteAlertRule", mock.Anything)
+		svc := createService(acMock.New().WithDisabled(), ruleStore, scheduler)
+		svc.QuotaService = quotatest.NewQuotaServiceFake()
+		svc.conditionValidator = fakeConditionValidator{}
+		svc.cfg = &setting.UnifiedAlertingSettings{BaseInterval: 10 * time.Second, DefaultRuleEvaluationInterval: time.Minute}
+		svc.datasourceCache = &fakeDatasources.FakeCacheService{DataSources: []*datasources.DataSource{
+			{Uid: "prom", Type: datasources.DS_PROMETHEUS},
+			{Uid: "graphite", Type: datasources.DS_GRAPHITE},
+		}}
+		return svc, ruleStore, folder
+	}
+	body := func(dryRun bool) apimodels.PrometheusRulesImportBody {
+		return apimodels.PrometheusRulesImportBody{
+			DatasourceUID: "prom",
+			DryRun:        dryRun,
+			Groups: []apimodels.PrometheusRuleGroup{
+				{
+					Name:     "api",
+					Interval: model.Duration(30 * time.Second),
+					Rules: []apimodels.ApiRuleNode{
+						{Alert: "HighErrorRate", Expr: `rate(errors_total[5m]) > 0.5`},
+						{Record: "job:up:sum", Expr: `sum by (job) (up)`},
+					},
+				},
+				{
+					Name: "broken",
+					Rules: []apimodels.ApiRuleNode{
+						{Alert: "Healthy", Expr: `up == 1`},
+						{Alert: "Broken", Expr: `rate(errors_total[5m]`},
+						{Alert: "HighErrorRate", Expr: `rate(errors_total[5m]) > 1`},
+					},
+				},
+				{
+					Name:     "invalid-interval",
+					Interval: model.Duration(15 * time.Second),
+					Rules: []apimodels.ApiRuleNode{
+						{Alert: "Down", Expr: `up == 0`},
+					},
+				},
+			},
+		}
+	}
+	asResult := func(t *testing.T, r []byte) apimodels.PrometheusRulesImportResult {
+		t.Helper()
+		result := apimodels.PrometheusRulesImportResult{}
+		require.NoError(t, json.Unmarshal(r, &result))
+		return result
+	}
+	insertedRules := func(ruleStore *fakes.RuleStore) []models.AlertRule {
+		var inserted []models.AlertRule
+		for _, cmd := range ruleStore.GetRecordedCommands(func(cmd interface{}) (interface{}, bool) {
+			rules, ok := cmd.([]models.AlertRule)
+			return rules, ok
+		}) {
+			inserted = append(inserted, cmd.([]models.AlertRule)...)
+		}
+		return inserted
+	}
+	writtenRules := func(ruleStore *fakes.RuleStore) []interface{} {
+		return ruleStore.GetRecordedCommands(func(cmd interface{}) (interface{}, bool) {
+			switch c := cmd.(type) {
+			case []models.AlertRule, []models.UpdateRule:
+				return c, true
+			case fakes.GenericRecordedQuery:
+				return c, c.Name == "DeleteAlertRulesByUID"
+			}
+			return nil, false
+		})
+	}
+	skippedRules := func(result apimodels.PrometheusRulesImportResult) []string {
+		var names []string
+		for _, s := range result.Skipped {
+			names = append(names, s.Group+"/"+s.Rule)
+		}
+		return names
+	}
+
+	t.Run("should convert rules without saving them in dry run", func(t *testing.T) {
+		svc, ruleStore, folder := createImportService(t)
+
+		response := svc.RoutePostPrometheusRulesImport(createRequestContext(orgID, org.RoleEditor, nil), body(true), folder.Title)
+
+		require.Equal(t, http.StatusOK, response.Status())
+		result := asResult(t, response.Body())
+		require.Len(t, result.Groups, 1)
+		require.Equal(t, "api", result.Groups[0].Name)
+		require.Len(t, result.Groups[0].Rules, 2)
+		require.ElementsMatch(t, []string{"broken/Healthy", "broken/Broken", "broken/HighErrorRate", "invalid-interval/Down"}, skippedRules(result))
+		require.Empty(t, insertedRules(ruleStore))
+	})
+
+	t.Run("should save converted rules", func(t *testing.T) {
+		svc, ruleStore, folder := createImportService(t)
+
+		response := svc.RoutePostPrometheusRulesImport(createRequestContext(orgID, org.RoleEditor, nil), body(false), folder.Title)
+
+		require.Equal(t, http.StatusAccepted, response.Status())
+		inserted := insertedRules(ruleStore)
+		require.Len(t, inserted, 2)
+		for _, rule := range inserted {
+			require.Equal(t, folder.Uid, rule.NamespaceUID)
+			require.Equal(t, "api", rule.RuleGroup)
+			require.Equal(t, int64(30), rule.IntervalSeconds)
+		}
+	})
+
+	t.Run("should update rules of existing groups", func(t *testing.T) {
+		svc, ruleStore, folder := createImportService(t)
+		existing := models.AlertRuleGen(withOrgID(orgID), withNamespace(folder), withGroup("api"), func(rule *models.AlertRule) {
+			rule.Title = "HighErrorRate"
+		})()
+		ruleStore.PutRule(context.Background(), existing)
+
+		response := svc.RoutePostPrometheusRulesImport(createRequestContext(orgID, org.RoleEditor, nil), body(true), folder.Title)
+
+		require.Equal(t, http.StatusOK, response.Status())
+		result := asResult(t, response.Body())
+		require.Len(t, result.Groups, 1)
+		require.Len(t, result.Groups[0].Rules, 2)
+		require.Equal(t, existing.UID, result.Groups[0].Rules[0].GrafanaManagedAlert.UID)
+	})
+
+	t.Run("should keep existing groups if some of their rules are skipped", func(t *testing.T) {
+		svc, ruleStore, folder := createImportService(t)
+		existing := models.AlertRuleGen(withOrgID(orgID), withNamespace(folder), withGroup("api"), func(rule *models.AlertRule) {
+			rule.Title = "HighErrorRate"
+		})()
+		other := models.AlertRuleGen(withOrgID(orgID), withNamespace(folder), withGroup("other"), func(rule *models.AlertRule) {
+			rule.Title = "job:up:sum"
+		})()
+		ruleStore.PutRule(context.Background(), existing, other)
+
+		response := svc.RoutePostPrometheusRulesImport(createRequestContext(orgID, org.RoleEditor, nil), body(false), folder.Title)
+
+		require.Equal(t, http.StatusOK, response.Status())
+		result := asResult(t, response.Body())
+		require.Empty(t, result.Groups)
+		require.Subset(t, skippedRules(result), []string{"api/HighErrorRate", "api/job:up:sum"})
+		require.Empty(t, writtenRules(ruleStore))
+	})
+
+	t.Run("should keep existing groups if all of their rules are skipped", func(t *testing.T) {
+		svc, ruleStore, folder := createImportService(t)
+		existing := models.AlertRuleGen(withOrgID(orgID), withNamespace(folder), withGroup("invalid-interval"), func(rule *models.AlertRule) {
+			rule.Title = "Down"
+		})()
+		ruleStore.PutRule(context.Background(), existing)
+		b := body(false)
+		b.Groups = b.Groups[2:]
+
+		response := svc.RoutePostPrometheusRulesImport(createRequestContext(orgID, org.RoleEditor, nil), b, folder.Title)
+
+		require.Equal(t, http.StatusOK, response.Status())
+		result := asResult(t, response.Body())
+		require.Empty(t, result.Groups)
+		require.Equal(t, []string{"invalid-interval/Down"}, skippedRules(result))
+		require.Empty(t, writtenRules(ruleStore))
+	})
+
+	t.Run("should skip groups with rules of the same name as the rules of existing groups that are kept", func(t *testing.T) {
+		svc, ruleStore, folder := createImportService(t)
+		existing := models.AlertRuleGen(withOrgID(orgID), withNamespace(folder), withGroup("broken"), func(rule *models.AlertRule) {
+			rule.Title = "job:up:sum"
+		})()
+		ruleStore.PutRule(context.Background(), existing)
+
+		response := svc.RoutePostPrometheusRulesImport(createRequestContext(orgID, org.RoleEditor, nil), body(false), folder.Title)
+
+		require.Equal(t, http.StatusOK, response.Status())
+		result := asResult(t, response.Body())
+		require.Empty(t, result.Groups)
+		require.Subset(t, skippedRules(result), []string{"api/HighErrorRate", "api/job:up:sum"})
+		require.Empty(t, writtenRules(ruleStore))
+	})
+
+	t.Run("should fail if data source is not supported", func(t *testing.T) {
+		svc, _, folder := createImportService(t)
+
+		for _, uid := range []string{"", "unknown", "graphite"} {
+			b := body(true)
+			b.DatasourceUID = uid
+			response := svc.RoutePostPrometheusRulesImport(createRequestContext(orgID, org.RoleEditor, nil), b, folder.Title)
+			require.Equal(t, http.StatusBadRequest, response.Status(), uid)
+		}
+	})
+}
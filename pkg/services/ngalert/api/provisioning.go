@@ -92,6 +92,22 @@ func (f *ProvisioningApiHandler) handleRouteDeleteAlertRule(ctx *models.ReqConte
 	return f.svc.RouteDeleteAlertRule(ctx, UID)
 }
 
+func (f *ProvisioningApiHandler) handleRouteGetAlertRuleVersions(ctx *models.ReqContext, UID string) response.Response {
+	return f.svc.RouteGetAlertRuleVersions(ctx, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetAlertRuleVersion(ctx *models.ReqContext, UID string, version string) response.Response {
+	return f.svc.RouteGetAlertRuleVersion(ctx, UID, version)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetAlertRuleVersionDiff(ctx *models.ReqContext, UID string, version string) response.Response {
+	return f.svc.RouteGetAlertRuleVersionDiff(ctx, UID, version)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostAlertRuleVersionRestore(ctx *models.ReqContext, UID string, version string) response.Response {
+	return f.svc.RoutePostAlertRuleVersionRestore(ctx, UID, version)
+}
+
 func (f *ProvisioningApiHandler) handleRouteResetPolicyTree(ctx *models.ReqContext) response.Response {
 	return f.svc.RouteResetPolicyTree(ctx)
 }
@@ -103,3 +119,35 @@ func (f *ProvisioningApiHandler) handleRouteGetAlertRuleGroup(ctx *models.ReqCon
 func (f *ProvisioningApiHandler) handleRoutePutAlertRuleGroup(ctx *models.ReqContext, ag apimodels.AlertRuleGroup, folder, group string) response.Response {
 	return f.svc.RoutePutAlertRuleGroup(ctx, ag, folder, group)
 }
+
+func (f *ProvisioningApiHandler) handleRoutePostAlertRuleGroupPause(ctx *models.ReqContext, folder, group string) response.Response {
+	return f.svc.RoutePostAlertRuleGroupPause(ctx, folder, group)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostAlertRuleGroupResume(ctx *models.ReqContext, folder, group string) response.Response {
+	return f.svc.RoutePostAlertRuleGroupResume(ctx, folder, group)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetAlertRulesExport(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteGetAlertRulesExport(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetAlertRuleGroupExport(ctx *models.ReqContext, folder, group string) response.Response {
+	return f.svc.RouteGetAlertRuleGroupExport(ctx, folder, group)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetContactpointsExport(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteGetContactPointsExport(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetPolicyTreeExport(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteGetPolicyTreeExport(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetMuteTimingsExport(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteGetMuteTimingsExport(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetTemplatesExport(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteGetTemplatesExport(ctx)
+}
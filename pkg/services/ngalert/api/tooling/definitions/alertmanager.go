@@ -21,6 +21,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/util/cmputil"
 )
 
 // swagger:route POST /api/alertmanager/grafana/config/api/v1/alerts alertmanager RoutePostGrafanaAlertingConfig
@@ -74,6 +75,31 @@ import (
 //       400: ValidationError
 //       404: NotFound
 
+// swagger:route GET /api/alertmanager/grafana/config/history alertmanager RouteGetGrafanaAlertingConfigHistory
+//
+// gets the previous Alerting configs of the Grafana Alertmanager, latest first
+//
+//     Responses:
+//       200: GettableHistoricUserConfigs
+
+// swagger:route GET /api/alertmanager/grafana/config/history/{id}/diff alertmanager RouteGetGrafanaAlertingConfigHistoryDiff
+//
+// gets the difference between a previous Alerting config of the Grafana Alertmanager and another previous or the current config
+//
+//     Responses:
+//       200: AlertingConfigDiff
+//       400: ValidationError
+//       404: NotFound
+
+// swagger:route POST /api/alertmanager/grafana/config/history/{id}/_activate alertmanager RoutePostGrafanaAlertingConfigHistoryActivate
+//
+// applies a previous Alerting config of the Grafana Alertmanager
+//
+//     Responses:
+//       202: Ack
+//       400: ValidationError
+//       404: NotFound
+
 // swagger:route GET /api/alertmanager/grafana/api/v2/status alertmanager RouteGetGrafanaAMStatus
 //
 // get alertmanager status and configuration
@@ -149,6 +175,14 @@ import (
 //     Responses:
 //       200: receivers
 
+// swagger:route GET /api/alertmanager/grafana/notifications/deliveries alertmanager RouteGetGrafanaNotificationDeliveries
+//
+// Get the attempts of the Grafana managed contact points to deliver notifications, latest first.
+//
+//     Responses:
+//       200: GettableNotificationDeliveries
+//       400: ValidationError
+
 // swagger:route POST /api/alertmanager/grafana/config/api/v1/receivers/test alertmanager RoutePostTestGrafanaReceivers
 //
 // Test Grafana managed receivers without saving them.
@@ -177,6 +211,17 @@ import (
 //       408: Failure
 //       409: AlertManagerNotReady
 
+// swagger:route POST /api/alertmanager/grafana/config/api/v1/templates/test alertmanager RoutePostTestGrafanaTemplates
+//
+// Render a notification template against sample or current alerts without saving it.
+//
+//     Responses:
+//
+//       200: TestTemplatesResults
+//       400: ValidationError
+//       403: PermissionDenied
+//       409: AlertManagerNotReady
+
 // swagger:route GET /api/alertmanager/grafana/api/v2/silences alertmanager RouteGetGrafanaSilences
 //
 // get silences
@@ -295,6 +340,67 @@ type TestReceiverConfigResult struct {
 	Error  string `json:"error,omitempty"`
 }
 
+// swagger:parameters RoutePostTestGrafanaTemplates
+type TestTemplatesConfigParams struct {
+	// in:body
+	Body TestTemplatesConfigBodyParams
+}
+
+type TestTemplatesConfigBodyParams struct {
+	// Name of the template. A saved template with the same name is replaced by the tested template.
+	Name string `json:"name"`
+	// Template is the content of the template. It is defined with the name of the template if it does not define any template.
+	Template string `json:"template"`
+	// Alerts to render the template against. A sample alert is used if there are no alerts.
+	Alerts []*amv2.PostableAlert `json:"alerts,omitempty"`
+	// CurrentAlerts renders the template against the alerts that are currently firing in the organization instead of Alerts.
+	CurrentAlerts bool `json:"currentAlerts,omitempty"`
+	// Integrations are the contact point types to render the templated settings of. All the contact point types with
+	// templated settings are rendered if there are no integrations.
+	Integrations []TestTemplatesIntegration `json:"integrations,omitempty"`
+}
+
+type TestTemplatesIntegration struct {
+	Type string `json:"type"`
+	// Settings are the templated settings to render. The default templates of the contact point type are rendered
+	// for the settings that are not set.
+	Settings map[string]string `json:"settings,omitempty"`
+}
+
+// swagger:model
+type TestTemplatesResults struct {
+	Results []TestTemplatesResult      `json:"results"`
+	Errors  []TestTemplatesErrorResult `json:"errors,omitempty"`
+}
+
+// swagger:model
+type TestTemplatesResult struct {
+	// Type of the contact point.
+	Type string `json:"type"`
+	// Settings are the rendered templated settings of the contact point.
+	Settings map[string]string `json:"settings"`
+}
+
+const (
+	// TestTemplatesErrorInvalidTemplate is the kind of error of a template that cannot be parsed.
+	TestTemplatesErrorInvalidTemplate = "invalid_template"
+	// TestTemplatesErrorExecution is the kind of error of a setting that cannot be rendered.
+	TestTemplatesErrorExecution = "execution_error"
+)
+
+// swagger:model
+type TestTemplatesErrorResult struct {
+	// Kind is either invalid_template or execution_error.
+	Kind string `json:"kind"`
+	// Type and Setting are the contact point type and the setting that failed to render, if any.
+	Type    string `json:"type,omitempty"`
+	Setting string `json:"setting,omitempty"`
+	// Template and Line are the name of the template and the line in the template where the error occurred, if any.
+	Template string `json:"template,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Message  string `json:"message"`
+}
+
 // swagger:parameters RouteCreateSilence RouteCreateGrafanaSilence
 type CreateSilenceParams struct {
 	// in:body
@@ -460,6 +566,62 @@ type BodyAlertingConfig struct {
 	Body PostableUserConfig
 }
 
+// swagger:parameters RouteGetGrafanaAlertingConfigHistory
+type AlertingConfigHistoryParams struct {
+	// Maximum number of configs to return. All stored configs are returned if not set.
+	// in:query
+	// required:false
+	Limit int `json:"limit"`
+}
+
+// swagger:parameters RouteGetGrafanaAlertingConfigHistoryDiff RoutePostGrafanaAlertingConfigHistoryActivate
+type AlertingConfigHistoryReference struct {
+	// ID of the previous Alerting config
+	// in:path
+	ID int64 `json:"id"`
+}
+
+// swagger:parameters RouteGetGrafanaAlertingConfigHistoryDiff
+type AlertingConfigDiffParams struct {
+	// ID of the previous Alerting config to compare with. If not set, the config is compared with the current config.
+	// in:query
+	// required:false
+	CompareTo int64 `json:"compareTo"`
+}
+
+// swagger:parameters RouteGetGrafanaNotificationDeliveries
+type NotificationDeliveriesParams struct {
+	// Only return the deliveries of the contact point with this name.
+	// in:query
+	// required:false
+	Receiver string `json:"receiver"`
+	// Only return the deliveries of the integrations of this type, e.g. pagerduty.
+	// in:query
+	// required:false
+	Integration string `json:"integration"`
+	// Only return the deliveries of the notifications of this alert group.
+	// in:query
+	// required:false
+	GroupKey string `json:"groupKey"`
+	// Only return the deliveries with this status.
+	// in:query
+	// required:false
+	// enum: success,failure
+	Status string `json:"status"`
+	// Only return the deliveries attempted at or after this time, in seconds since epoch.
+	// in:query
+	// required:false
+	From int64 `json:"from"`
+	// Only return the deliveries attempted at or before this time, in seconds since epoch.
+	// in:query
+	// required:false
+	To int64 `json:"to"`
+	// Maximum number of deliveries to return. Defaults to 100.
+	// in:query
+	// required:false
+	Limit int `json:"limit"`
+}
+
 // alertmanager routes
 // swagger:parameters RoutePostAlertingConfig RouteGetAlertingConfig RouteDeleteAlertingConfig RouteGetAMStatus RouteGetAMAlerts RoutePostAMAlerts RouteGetAMAlertGroups RouteGetSilences RouteCreateSilence RouteGetSilence RouteDeleteSilence RoutePostAlertingConfig RoutePostTestReceivers
 // testing routes
@@ -593,6 +755,83 @@ type GettableUserConfig struct {
 	amSimple map[string]interface{} `yaml:"-" json:"-"`
 }
 
+// swagger:model
+type GettableHistoricUserConfigs []GettableHistoricUserConfig
+
+// GettableHistoricUserConfig is a previous version of the Alerting config of the Grafana Alertmanager.
+// swagger:model
+type GettableHistoricUserConfig struct {
+	ID int64 `json:"id"`
+	// The time the config was saved.
+	CreatedAt time.Time `json:"created_at"`
+	// True if the config is the default config that was applied when the Alerting config was reset.
+	Default            bool                      `json:"default"`
+	TemplateFiles      map[string]string         `json:"template_files"`
+	AlertmanagerConfig GettableApiAlertingConfig `json:"alertmanager_config"`
+}
+
+// swagger:model
+type AlertingConfigDiff struct {
+	ID int64 `json:"id"`
+	// The ID of the config the config is compared with. 0 means the current config.
+	CompareTo int64                     `json:"compareTo"`
+	Diffs     []AlertingConfigFieldDiff `json:"diffs"`
+}
+
+type AlertingConfigFieldDiff struct {
+	// Path to the field that differs, e.g. [alertmanager_config][route][group_wait]
+	Path string `json:"path"`
+	// The value of the field in the config. Absent if the field was added.
+	Left interface{} `json:"left,omitempty"`
+	// The value of the field in the config compared with. Absent if the field was removed.
+	Right interface{} `json:"right,omitempty"`
+}
+
+// swagger:model
+type GettableNotificationDeliveries []GettableNotificationDelivery
+
+// GettableNotificationDelivery is an attempt of an integration of a Grafana managed contact point to deliver a notification.
+// swagger:model
+type GettableNotificationDelivery struct {
+	ID int64 `json:"id"`
+	// The time the delivery was attempted.
+	Timestamp time.Time `json:"timestamp"`
+	// The name of the contact point.
+	Receiver         string `json:"receiver"`
+	IntegrationUID   string `json:"integrationUid"`
+	IntegrationName  string `json:"integrationName"`
+	IntegrationType  string `json:"integrationType"`
+	IntegrationIndex int    `json:"integrationIndex"`
+	GroupKey         string `json:"groupKey"`
+	// The number of alerts in the notification.
+	Alerts int `json:"alerts"`
+	// enum: success,failure
+	Status string `json:"status"`
+	// The HTTP status code of the response of the receiving service. Absent if the integration does not use HTTP
+	// or no response was received.
+	StatusCode int    `json:"statusCode,omitempty"`
+	Error      string `json:"error,omitempty"`
+	// True if the delivery failed and is attempted again.
+	Retry      bool  `json:"retry"`
+	DurationMs int64 `json:"durationMs"`
+}
+
+func NewAlertingConfigDiff(id, compareTo int64, report cmputil.DiffReport) AlertingConfigDiff {
+	diffs := make([]AlertingConfigFieldDiff, 0, len(report))
+	for _, d := range report {
+		diffs = append(diffs, AlertingConfigFieldDiff{
+			Path:  d.Path,
+			Left:  reflectValueToInterface(d.Left),
+			Right: reflectValueToInterface(d.Right),
+		})
+	}
+	return AlertingConfigDiff{
+		ID:        id,
+		CompareTo: compareTo,
+		Diffs:     diffs,
+	}
+}
+
 func (c *GettableUserConfig) UnmarshalYAML(value *yaml.Node) error {
 	// cortex/loki actually pass the AM config as a string.
 	type cortexGettableUserConfig struct {
@@ -707,11 +946,11 @@ func (c *GettableApiAlertingConfig) validate() error {
 
 // Config is the top-level configuration for Alertmanager's config files.
 type Config struct {
-	Global            *config.GlobalConfig      `yaml:"global,omitempty" json:"global,omitempty"`
-	Route             *Route                    `yaml:"route,omitempty" json:"route,omitempty"`
-	InhibitRules      []*config.InhibitRule     `yaml:"inhibit_rules,omitempty" json:"inhibit_rules,omitempty"`
-	MuteTimeIntervals []config.MuteTimeInterval `yaml:"mute_time_intervals,omitempty" json:"mute_time_intervals,omitempty"`
-	Templates         []string                  `yaml:"templates" json:"templates"`
+	Global            *config.GlobalConfig     `yaml:"global,omitempty" json:"global,omitempty"`
+	Route             *Route                   `yaml:"route,omitempty" json:"route,omitempty"`
+	InhibitRules      []*config.InhibitRule    `yaml:"inhibit_rules,omitempty" json:"inhibit_rules,omitempty"`
+	MuteTimeIntervals []MuteTimeIntervalConfig `yaml:"mute_time_intervals,omitempty" json:"mute_time_intervals,omitempty"`
+	Templates         []string                 `yaml:"templates" json:"templates"`
 }
 
 // A Route is a node that contains definitions of how to handle alerts. This is modified
@@ -133,12 +133,15 @@ func (r *Route) ValidateMuteTimes(muteTimes map[string]struct{}) error {
 }
 
 func (mt *MuteTimeInterval) Validate() error {
-	s, err := yaml.Marshal(mt.MuteTimeInterval)
+	cfg := mt.Config()
+	s, err := yaml.Marshal(cfg)
 	if err != nil {
 		return err
 	}
-	if err = yaml.Unmarshal(s, &(mt.MuteTimeInterval)); err != nil {
+	if err = yaml.Unmarshal(s, &cfg); err != nil {
 		return err
 	}
+	mt.MuteTimeInterval = cfg.MuteTimeInterval
+	mt.DateRanges = cfg.DateRanges
 	return nil
 }
@@ -315,6 +315,20 @@ func TestValidateMuteTimeInterval(t *testing.T) {
 					},
 				},
 			},
+			{
+				desc: "date ranges",
+				mti: MuteTimeInterval{
+					MuteTimeInterval: config.MuteTimeInterval{
+						Name: "interval",
+					},
+					DateRanges: []DateRange{
+						{
+							StartTime: "2022-10-20 22:00",
+							EndTime:   "2022-10-21T02:00:00+02:00",
+						},
+					},
+				},
+			},
 		}
 
 		for _, c := range cases {
@@ -354,6 +368,21 @@ func TestValidateMuteTimeInterval(t *testing.T) {
 				},
 				expMsg: "unable to convert -1 into weekday",
 			},
+			{
+				desc: "date range ending before it starts",
+				mti: MuteTimeInterval{
+					MuteTimeInterval: config.MuteTimeInterval{
+						Name: "interval",
+					},
+					DateRanges: []DateRange{
+						{
+							StartTime: "2022-10-21 02:00",
+							EndTime:   "2022-10-20 22:00",
+						},
+					},
+				},
+				expMsg: "must be before end time",
+			},
 		}
 
 		for _, c := range cases {
@@ -128,6 +128,52 @@ import (
 //       202: Ack
 //       404: NotFound
 
+// swagger:route POST /api/ruler/grafana/api/v1/rules/{Namespace}/{Groupname}/pause ruler RoutePostPauseGrafanaRuleGroup
+//
+// Pause evaluation of all rules in the rule group
+//
+//     Responses:
+//       202: Ack
+//       404: NotFound
+
+// swagger:route POST /api/ruler/grafana/api/v1/rules/{Namespace}/{Groupname}/resume ruler RoutePostResumeGrafanaRuleGroup
+//
+// Resume evaluation of all rules in the rule group
+//
+//     Responses:
+//       202: Ack
+//       404: NotFound
+
+// swagger:route Get /api/ruler/grafana/api/v1/rule/{RuleUID}/versions ruler RouteGetRuleVersionsByUID
+//
+// List versions of a rule, latest first
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       202: GettableRuleVersions
+//       404: NotFound
+
+// swagger:route Get /api/ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}/diff ruler RouteGetRuleVersionDiff
+//
+// Get the differences between a version of a rule and the current rule or another version
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       202: AlertRuleVersionDiff
+//       404: NotFound
+
+// swagger:route POST /api/ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}/restore ruler RoutePostRuleVersionRestore
+//
+// Restore a version of a rule. The rule keeps its folder, group and evaluation interval.
+//
+//     Responses:
+//       202: Ack
+//       404: NotFound
+
 // swagger:parameters RoutePostNameRulesConfig RoutePostNameGrafanaRulesConfig
 type NamespaceConfig struct {
 	// in:path
@@ -142,7 +188,7 @@ type PathNamespaceConfig struct {
 	Namespace string
 }
 
-// swagger:parameters RouteGetRulegGroupConfig RouteDeleteRuleGroupConfig RouteGetGrafanaRuleGroupConfig RouteDeleteGrafanaRuleGroupConfig
+// swagger:parameters RouteGetRulegGroupConfig RouteDeleteRuleGroupConfig RouteGetGrafanaRuleGroupConfig RouteDeleteGrafanaRuleGroupConfig RoutePostPauseGrafanaRuleGroup RoutePostResumeGrafanaRuleGroup
 type PathRouleGroupConfig struct {
 	// in: path
 	Namespace string
@@ -150,6 +196,12 @@ type PathRouleGroupConfig struct {
 	Groupname string
 }
 
+// swagger:parameters RouteGetRuleVersionsByUID RouteGetRuleVersionDiff RoutePostRuleVersionRestore
+type PathRuleUID struct {
+	// in: path
+	RuleUID string
+}
+
 // swagger:parameters RouteGetRulesConfig RouteGetGrafanaRulesConfig
 type PathGetRulesParams struct {
 	// in: query
@@ -166,6 +218,18 @@ type RuleGroupConfigResponse struct {
 // swagger:model
 type NamespaceConfigResponse map[string][]GettableRuleGroupConfig
 
+// swagger:model
+type GettableRuleVersions []GettableRuleVersion
+
+type GettableRuleVersion struct {
+	Version       int64 `json:"version" yaml:"version"`
+	ParentVersion int64 `json:"parent_version" yaml:"parent_version"`
+	// The version this version was restored from, if any.
+	RestoredFrom int64                    `json:"restored_from,omitempty" yaml:"restored_from,omitempty"`
+	Created      time.Time                `json:"created" yaml:"created"`
+	Rule         GettableExtendedRuleNode `json:"rule" yaml:"rule"`
+}
+
 // swagger:model
 type PostableRuleGroupConfig struct {
 	Name     string                     `yaml:"name" json:"name"`
@@ -260,12 +324,13 @@ func (c *GettableRuleGroupConfig) validate() error {
 }
 
 type ApiRuleNode struct {
-	Record      string            `yaml:"record,omitempty" json:"record,omitempty"`
-	Alert       string            `yaml:"alert,omitempty" json:"alert,omitempty"`
-	Expr        string            `yaml:"expr" json:"expr"`
-	For         *model.Duration   `yaml:"for,omitempty" json:"for,omitempty"`
-	Labels      map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
-	Annotations map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+	Record        string            `yaml:"record,omitempty" json:"record,omitempty"`
+	Alert         string            `yaml:"alert,omitempty" json:"alert,omitempty"`
+	Expr          string            `yaml:"expr" json:"expr"`
+	For           *model.Duration   `yaml:"for,omitempty" json:"for,omitempty"`
+	KeepFiringFor *model.Duration   `yaml:"keep_firing_for,omitempty" json:"keep_firing_for,omitempty"`
+	Labels        map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Annotations   map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
 }
 
 type RuleType int
@@ -366,6 +431,41 @@ const (
 	ErrorErrState    ExecutionErrorState = "Error"
 )
 
+// Record defines how the result of a recording rule is written.
+// swagger:model
+type Record struct {
+	// Name of the recorded metric.
+	// required: true
+	// example: grafana_alerts_ratio
+	Metric string `json:"metric" yaml:"metric"`
+	// RefID of the query or expression that is used as the input for the recorded metric.
+	// required: true
+	// example: A
+	From string `json:"from" yaml:"from"`
+}
+
+// ToModel converts the API model to the storage model.
+func (r *Record) ToModel() *models.Record {
+	if r == nil {
+		return nil
+	}
+	return &models.Record{
+		Metric: r.Metric,
+		From:   r.From,
+	}
+}
+
+// NewRecord converts the storage model to the API model. Returns nil if record is nil.
+func NewRecord(r *models.Record) *Record {
+	if r == nil {
+		return nil
+	}
+	return &Record{
+		Metric: r.Metric,
+		From:   r.From,
+	}
+}
+
 // swagger:model
 type PostableGrafanaRule struct {
 	Title        string              `json:"title" yaml:"title"`
@@ -374,6 +474,9 @@ type PostableGrafanaRule struct {
 	UID          string              `json:"uid" yaml:"uid"`
 	NoDataState  NoDataState         `json:"no_data_state" yaml:"no_data_state"`
 	ExecErrState ExecutionErrorState `json:"exec_err_state" yaml:"exec_err_state"`
+	Record       *Record             `json:"record,omitempty" yaml:"record,omitempty"`
+	// IsPaused pauses or resumes the rule. If it is not specified, an existing rule keeps its paused state.
+	IsPaused *bool `json:"is_paused,omitempty" yaml:"is_paused,omitempty"`
 }
 
 // swagger:model
@@ -393,4 +496,6 @@ type GettableGrafanaRule struct {
 	NoDataState     NoDataState         `json:"no_data_state" yaml:"no_data_state"`
 	ExecErrState    ExecutionErrorState `json:"exec_err_state" yaml:"exec_err_state"`
 	Provenance      models.Provenance   `json:"provenance,omitempty" yaml:"provenance,omitempty"`
+	Record          *Record             `json:"record,omitempty" yaml:"record,omitempty"`
+	IsPaused        bool                `json:"is_paused" yaml:"is_paused"`
 }
@@ -0,0 +1,48 @@
+package definitions
+
+import (
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// swagger:route GET /api/v1/ngalert/history history RouteGetStateHistory
+//
+// Get the history of alert state transitions of an alert rule or of alert instances that match labels.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: StateHistory
+//       400: ValidationError
+//       404: NotFound
+
+// swagger:parameters RouteGetStateHistory
+type StateHistoryParams struct {
+	// UID of the alert rule. Either the rule UID or at least one label must be specified.
+	// in:query
+	// required:false
+	RuleUID string `json:"ruleUID"`
+	// Start of the time range in Unix seconds. Defaults to one hour before the end of the time range.
+	// in:query
+	// required:false
+	From int64 `json:"from"`
+	// End of the time range in Unix seconds. Defaults to now.
+	// in:query
+	// required:false
+	To int64 `json:"to"`
+	// Maximum number of state transitions to return.
+	// in:query
+	// required:false
+	Limit int64 `json:"limit"`
+	// Labels the alert instances must have are specified as query parameters with the prefix "labels_", e.g. labels_team=sre.
+	// in:query
+	// required:false
+	Labels string `json:"labels_{name}"`
+}
+
+// swagger:model
+type StateHistory struct {
+	// Results is a data frame with one row per state transition, oldest first, with the fields
+	// time, ruleUID, previous, current, labels and values.
+	Results *data.Frame `json:"results"`
+}
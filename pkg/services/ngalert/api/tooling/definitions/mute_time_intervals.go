@@ -0,0 +1,138 @@
+package definitions
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/timeinterval"
+)
+
+// dateRangeLayouts are the accepted layouts of the start and end of a date range. Layouts without a time zone offset
+// are interpreted in the location of the date range.
+var dateRangeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// MuteTimeIntervalConfig is a mute time interval of the Alertmanager configuration. In addition to the recurring time
+// intervals of the upstream Alertmanager, it supports absolute date ranges such as maintenance windows.
+type MuteTimeIntervalConfig struct {
+	config.MuteTimeInterval `yaml:",inline" json:",inline"`
+	DateRanges              []DateRange `yaml:"date_ranges,omitempty" json:"date_ranges,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for MuteTimeIntervalConfig. It is required because
+// config.MuteTimeInterval implements it as well, which would ignore the date ranges.
+func (mt *MuteTimeIntervalConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&mt.MuteTimeInterval); err != nil {
+		return err
+	}
+	var dateRanges struct {
+		DateRanges []DateRange `yaml:"date_ranges,omitempty"`
+	}
+	if err := unmarshal(&dateRanges); err != nil {
+		return err
+	}
+	mt.DateRanges = dateRanges.DateRanges
+	return nil
+}
+
+// ContainsTime returns true if the time is within one of the time intervals or date ranges of the mute time interval.
+func (mt MuteTimeIntervalConfig) ContainsTime(t time.Time) bool {
+	for _, ti := range mt.TimeIntervals {
+		if ti.ContainsTime(t.UTC()) {
+			return true
+		}
+	}
+	for _, dr := range mt.DateRanges {
+		if dr.ContainsTime(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// DateRange is an absolute range of time, such as a planned maintenance window. The start is inclusive and the end
+// is exclusive.
+type DateRange struct {
+	// StartTime and EndTime are either in RFC3339 format or without a time zone offset, such as "2022-10-20 22:00",
+	// in which case they are interpreted in Location.
+	StartTime string `yaml:"start_time" json:"start_time"`
+	EndTime   string `yaml:"end_time" json:"end_time"`
+	// Location is the IANA time zone of the date range. It defaults to UTC.
+	Location *timeinterval.Location `yaml:"location,omitempty" json:"location,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for DateRange.
+func (dr *DateRange) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain DateRange
+	if err := unmarshal((*plain)(dr)); err != nil {
+		return err
+	}
+	return dr.Validate()
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for DateRange.
+func (dr *DateRange) UnmarshalJSON(b []byte) error {
+	type plain DateRange
+	if err := json.Unmarshal(b, (*plain)(dr)); err != nil {
+		return err
+	}
+	return dr.Validate()
+}
+
+// Validate returns an error if the start or the end of the date range cannot be parsed or the start is not before the end.
+func (dr DateRange) Validate() error {
+	start, end, err := dr.Bounds()
+	if err != nil {
+		return err
+	}
+	if !start.Before(end) {
+		return fmt.Errorf("start time %q of date range must be before end time %q", dr.StartTime, dr.EndTime)
+	}
+	return nil
+}
+
+// Bounds returns the start and the end of the date range.
+func (dr DateRange) Bounds() (time.Time, time.Time, error) {
+	loc := time.UTC
+	if dr.Location != nil && dr.Location.Location != nil {
+		loc = dr.Location.Location
+	}
+	start, err := parseDateRangeTime(dr.StartTime, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start time of date range: %w", err)
+	}
+	end, err := parseDateRangeTime(dr.EndTime, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end time of date range: %w", err)
+	}
+	return start, end, nil
+}
+
+// ContainsTime returns true if the time is within the date range.
+func (dr DateRange) ContainsTime(t time.Time) bool {
+	start, end, err := dr.Bounds()
+	if err != nil {
+		return false
+	}
+	return !t.Before(start) && t.Before(end)
+}
+
+func parseDateRangeTime(s string, loc *time.Location) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, fmt.Errorf("time is missing")
+	}
+	for _, layout := range dateRangeLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is neither in RFC3339 format nor in the format YYYY-MM-DD HH:MM", s)
+}
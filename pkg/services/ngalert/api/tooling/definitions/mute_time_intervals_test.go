@@ -0,0 +1,134 @@
+package definitions
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMuteTimeIntervalConfigUnmarshaling(t *testing.T) {
+	t.Run("should unmarshal date ranges from YAML", func(t *testing.T) {
+		in := `
+name: maintenance
+time_intervals:
+  - weekdays: ['saturday', 'sunday']
+date_ranges:
+  - start_time: '2022-10-20 22:00'
+    end_time: '2022-10-21 02:00'
+    location: 'Europe/Berlin'
+`
+		var mt MuteTimeIntervalConfig
+		require.NoError(t, yaml.Unmarshal([]byte(in), &mt))
+		require.Equal(t, "maintenance", mt.Name)
+		require.Len(t, mt.TimeIntervals, 1)
+		require.Len(t, mt.DateRanges, 1)
+		require.Equal(t, "Europe/Berlin", mt.DateRanges[0].Location.String())
+	})
+
+	t.Run("should unmarshal date ranges from JSON", func(t *testing.T) {
+		in := `{"name": "maintenance", "time_intervals": [], "date_ranges": [{"start_time": "2022-10-20T22:00:00Z", "end_time": "2022-10-21T02:00:00Z"}]}`
+		var mt MuteTimeIntervalConfig
+		require.NoError(t, json.Unmarshal([]byte(in), &mt))
+		require.Equal(t, "maintenance", mt.Name)
+		require.Equal(t, []DateRange{{StartTime: "2022-10-20T22:00:00Z", EndTime: "2022-10-21T02:00:00Z"}}, mt.DateRanges)
+
+		out, err := json.Marshal(mt)
+		require.NoError(t, err)
+		require.JSONEq(t, in, string(out))
+	})
+
+	t.Run("should fail on invalid date ranges", func(t *testing.T) {
+		testCases := []struct {
+			name   string
+			in     string
+			expErr string
+		}{
+			{
+				name:   "missing end",
+				in:     `{"name": "maintenance", "date_ranges": [{"start_time": "2022-10-20 22:00"}]}`,
+				expErr: "invalid end time of date range: time is missing",
+			},
+			{
+				name:   "invalid start",
+				in:     `{"name": "maintenance", "date_ranges": [{"start_time": "tomorrow", "end_time": "2022-10-21 02:00"}]}`,
+				expErr: "invalid start time of date range",
+			},
+			{
+				name:   "end before start",
+				in:     `{"name": "maintenance", "date_ranges": [{"start_time": "2022-10-21 02:00", "end_time": "2022-10-20 22:00"}]}`,
+				expErr: "must be before end time",
+			},
+			{
+				name:   "unknown location",
+				in:     `{"name": "maintenance", "date_ranges": [{"start_time": "2022-10-20 22:00", "end_time": "2022-10-21 02:00", "location": "Mars/Olympus_Mons"}]}`,
+				expErr: "unknown time zone Mars/Olympus_Mons",
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				var mt MuteTimeIntervalConfig
+				require.ErrorContains(t, json.Unmarshal([]byte(tc.in), &mt), tc.expErr)
+			})
+		}
+	})
+}
+
+func TestMuteTimeIntervalConfigContainsTime(t *testing.T) {
+	var mt MuteTimeIntervalConfig
+	require.NoError(t, yaml.Unmarshal([]byte(`
+name: maintenance
+time_intervals:
+  - weekdays: ['sunday']
+date_ranges:
+  - start_time: '2022-10-20 22:00'
+    end_time: '2022-10-21 02:00'
+    location: 'Europe/Berlin'
+  - start_time: '2022-11-01T10:00:00Z'
+    end_time: '2022-11-01T11:00:00Z'
+`), &mt))
+
+	testCases := []struct {
+		name     string
+		t        time.Time
+		expected bool
+	}{
+		{
+			name:     "before the date range in its location",
+			t:        time.Date(2022, 10, 20, 19, 59, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			name:     "start of the date range in its location",
+			t:        time.Date(2022, 10, 20, 20, 0, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name:     "end of the date range is exclusive",
+			t:        time.Date(2022, 10, 21, 0, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			name:     "date range with a time zone offset",
+			t:        time.Date(2022, 11, 1, 10, 30, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name:     "time interval",
+			t:        time.Date(2022, 10, 23, 12, 0, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name:     "outside of time intervals and date ranges",
+			t:        time.Date(2022, 10, 24, 12, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, mt.ContainsTime(tc.t))
+		})
+	}
+}
@@ -101,8 +101,9 @@ type AlertingRule struct {
 	// required: true
 	Name string `json:"name,omitempty"`
 	// required: true
-	Query    string  `json:"query,omitempty"`
-	Duration float64 `json:"duration,omitempty"`
+	Query         string  `json:"query,omitempty"`
+	Duration      float64 `json:"duration,omitempty"`
+	KeepFiringFor float64 `json:"keepFiringFor,omitempty"`
 	// required: true
 	Annotations overrideLabels `json:"annotations,omitempty"`
 	// required: true
@@ -125,6 +126,8 @@ type Rule struct {
 	Type           v1.RuleType `json:"type"`
 	LastEvaluation time.Time   `json:"lastEvaluation"`
 	EvaluationTime float64     `json:"evaluationTime"`
+	// IsPaused is true if the rule is paused and therefore is not evaluated.
+	IsPaused bool `json:"isPaused,omitempty"`
 }
 
 // Alert has info for an alert.
@@ -1,9 +1,11 @@
 package definitions
 
 import (
+	"reflect"
 	"time"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util/cmputil"
 	"github.com/prometheus/common/model"
 )
 
@@ -44,7 +46,41 @@ import (
 //     Responses:
 //       204: description: The alert rule was deleted successfully.
 
-// swagger:parameters RouteGetAlertRule RoutePutAlertRule RouteDeleteAlertRule
+// swagger:route GET /api/v1/provisioning/alert-rules/{UID}/versions provisioning stable RouteGetAlertRuleVersions
+//
+// Get all versions of an alert rule, latest first.
+//
+//     Responses:
+//       200: ProvisionedAlertRuleVersions
+//       404: description: Not found.
+
+// swagger:route GET /api/v1/provisioning/alert-rules/{UID}/versions/{Version} provisioning stable RouteGetAlertRuleVersion
+//
+// Get a specific version of an alert rule.
+//
+//     Responses:
+//       200: ProvisionedAlertRuleVersion
+//       404: description: Not found.
+
+// swagger:route GET /api/v1/provisioning/alert-rules/{UID}/versions/{Version}/diff provisioning stable RouteGetAlertRuleVersionDiff
+//
+// Get the difference between a version of an alert rule and another version or the current alert rule.
+//
+//     Responses:
+//       200: AlertRuleVersionDiff
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:route POST /api/v1/provisioning/alert-rules/{UID}/versions/{Version}/restore provisioning stable RoutePostAlertRuleVersionRestore
+//
+// Restore an alert rule to a specific version.
+//
+//     Responses:
+//       200: ProvisionedAlertRule
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:parameters RouteGetAlertRule RoutePutAlertRule RouteDeleteAlertRule RouteGetAlertRuleVersions RouteGetAlertRuleVersion RouteGetAlertRuleVersionDiff RoutePostAlertRuleVersionRestore
 type AlertRuleUIDReference struct {
 	// Alert rule UID
 	// in:path
@@ -89,12 +125,18 @@ type ProvisionedAlertRule struct {
 	ExecErrState models.ExecutionErrorState `json:"execErrState"`
 	// required: true
 	For model.Duration `json:"for"`
+	// The duration the alerts keep firing after the condition of the rule stopped being true.
+	KeepFiringFor model.Duration `json:"keepFiringFor"`
 	// example: {"runbook_url": "https://supercoolrunbook.com/page/13"}
 	Annotations map[string]string `json:"annotations,omitempty"`
 	// example: {"team": "sre-team-1"}
 	Labels map[string]string `json:"labels,omitempty"`
 	// readonly: true
 	Provenance models.Provenance `json:"provenance,omitempty"`
+	// If set, the rule is a recording rule and its result is written to the metric instead of producing alerts.
+	Record *Record `json:"record,omitempty"`
+	// If set, the rule is not evaluated and all its alerts are resolved.
+	IsPaused bool `json:"isPaused"`
 }
 
 func (a *ProvisionedAlertRule) UpstreamModel() (models.AlertRule, error) {
@@ -102,44 +144,152 @@ func (a *ProvisionedAlertRule) UpstreamModel() (models.AlertRule, error) {
 	if err != nil {
 		return models.AlertRule{}, err
 	}
-	return models.AlertRule{
-		ID:           a.ID,
-		UID:          a.UID,
-		OrgID:        a.OrgID,
-		NamespaceUID: a.FolderUID,
-		RuleGroup:    a.RuleGroup,
-		Title:        a.Title,
-		Condition:    a.Condition,
-		Data:         a.Data,
-		Updated:      a.Updated,
-		NoDataState:  a.NoDataState,
-		ExecErrState: a.ExecErrState,
-		For:          forDur,
-		Annotations:  a.Annotations,
-		Labels:       a.Labels,
-	}, nil
+	rule := models.AlertRule{
+		ID:            a.ID,
+		UID:           a.UID,
+		OrgID:         a.OrgID,
+		NamespaceUID:  a.FolderUID,
+		RuleGroup:     a.RuleGroup,
+		Title:         a.Title,
+		Condition:     a.Condition,
+		Data:          a.Data,
+		Updated:       a.Updated,
+		NoDataState:   a.NoDataState,
+		ExecErrState:  a.ExecErrState,
+		For:           forDur,
+		KeepFiringFor: time.Duration(a.KeepFiringFor),
+		Annotations:   a.Annotations,
+		Labels:        a.Labels,
+		IsPaused:      a.IsPaused,
+	}
+	if a.Record != nil {
+		rule.Record = a.Record.ToModel()
+		// recording rules do not have a condition, and therefore, the condition is the node the data is recorded from.
+		if rule.Condition == "" {
+			rule.Condition = rule.Record.From
+		}
+	}
+	return rule, nil
 }
 
 func NewAlertRule(rule models.AlertRule, provenance models.Provenance) ProvisionedAlertRule {
 	return ProvisionedAlertRule{
-		ID:           rule.ID,
-		UID:          rule.UID,
-		OrgID:        rule.OrgID,
-		FolderUID:    rule.NamespaceUID,
-		RuleGroup:    rule.RuleGroup,
-		Title:        rule.Title,
-		For:          model.Duration(rule.For),
-		Condition:    rule.Condition,
-		Data:         rule.Data,
-		Updated:      rule.Updated,
-		NoDataState:  rule.NoDataState,
-		ExecErrState: rule.ExecErrState,
-		Annotations:  rule.Annotations,
-		Labels:       rule.Labels,
-		Provenance:   provenance,
+		ID:            rule.ID,
+		UID:           rule.UID,
+		OrgID:         rule.OrgID,
+		FolderUID:     rule.NamespaceUID,
+		RuleGroup:     rule.RuleGroup,
+		Title:         rule.Title,
+		For:           model.Duration(rule.For),
+		KeepFiringFor: model.Duration(rule.KeepFiringFor),
+		Condition:     rule.Condition,
+		Data:          rule.Data,
+		Updated:       rule.Updated,
+		NoDataState:   rule.NoDataState,
+		ExecErrState:  rule.ExecErrState,
+		Annotations:   rule.Annotations,
+		Labels:        rule.Labels,
+		Provenance:    provenance,
+		Record:        NewRecord(rule.Record),
+		IsPaused:      rule.IsPaused,
 	}
 }
 
+// swagger:parameters RouteGetAlertRuleVersion RouteGetAlertRuleVersionDiff RoutePostAlertRuleVersionRestore RouteGetRuleVersionDiff RoutePostRuleVersionRestore
+type AlertRuleVersionReference struct {
+	// Version of the alert rule
+	// in:path
+	Version int64
+}
+
+// swagger:parameters RouteGetAlertRuleVersionDiff RouteGetRuleVersionDiff
+type AlertRuleVersionDiffParams struct {
+	// Version of the alert rule to compare with. If not set, the version is compared with the current alert rule.
+	// in:query
+	// required:false
+	CompareTo int64 `json:"compareTo"`
+}
+
+// swagger:model
+type ProvisionedAlertRuleVersions []ProvisionedAlertRuleVersion
+
+// swagger:model
+type ProvisionedAlertRuleVersion struct {
+	Version       int64 `json:"version"`
+	ParentVersion int64 `json:"parentVersion"`
+	// The version this version was restored from, if any.
+	RestoredFrom int64                `json:"restoredFrom,omitempty"`
+	Created      time.Time            `json:"created"`
+	Rule         ProvisionedAlertRule `json:"rule"`
+}
+
+func NewAlertRuleVersion(version models.AlertRuleVersion, provenance models.Provenance) (ProvisionedAlertRuleVersion, error) {
+	rule, err := version.ToAlertRule()
+	if err != nil {
+		return ProvisionedAlertRuleVersion{}, err
+	}
+	return ProvisionedAlertRuleVersion{
+		Version:       version.Version,
+		ParentVersion: version.ParentVersion,
+		RestoredFrom:  version.RestoredFrom,
+		Created:       version.Created,
+		Rule:          NewAlertRule(rule, provenance),
+	}, nil
+}
+
+func NewAlertRuleVersions(versions []*models.AlertRuleVersion, provenance models.Provenance) (ProvisionedAlertRuleVersions, error) {
+	result := make(ProvisionedAlertRuleVersions, 0, len(versions))
+	for _, v := range versions {
+		version, err := NewAlertRuleVersion(*v, provenance)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, version)
+	}
+	return result, nil
+}
+
+// swagger:model
+type AlertRuleVersionDiff struct {
+	Version int64 `json:"version"`
+	// The version the alert rule is compared with. 0 means the current alert rule.
+	CompareTo int64                `json:"compareTo"`
+	Diffs     []AlertRuleFieldDiff `json:"diffs"`
+}
+
+type AlertRuleFieldDiff struct {
+	// Path to the field that differs, e.g. Labels[team] or Data[0].Model
+	Path string `json:"path"`
+	// The value of the field in the version. Absent if the field was added.
+	Left interface{} `json:"left,omitempty"`
+	// The value of the field in the version compared with. Absent if the field was removed.
+	Right interface{} `json:"right,omitempty"`
+}
+
+func NewAlertRuleVersionDiff(version, compareTo int64, report cmputil.DiffReport) AlertRuleVersionDiff {
+	diffs := make([]AlertRuleFieldDiff, 0, len(report))
+	for _, d := range report {
+		diffs = append(diffs, AlertRuleFieldDiff{
+			Path:  d.Path,
+			Left:  reflectValueToInterface(d.Left),
+			Right: reflectValueToInterface(d.Right),
+		})
+	}
+	return AlertRuleVersionDiff{
+		Version:   version,
+		CompareTo: compareTo,
+		Diffs:     diffs,
+	}
+}
+
+func reflectValueToInterface(v reflect.Value) interface{} {
+	// invalid value means that the element is missing in the collection
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
+
 // swagger:route GET /api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group} provisioning stable RouteGetAlertRuleGroup
 //
 // Get a rule group.
@@ -159,13 +309,29 @@ func NewAlertRule(rule models.AlertRule, provenance models.Provenance) Provision
 //       200: AlertRuleGroup
 //       400: ValidationError
 
-// swagger:parameters RouteGetAlertRuleGroup RoutePutAlertRuleGroup
+// swagger:route POST /api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/pause provisioning stable RoutePostAlertRuleGroupPause
+//
+// Pause evaluation of all rules in a rule group.
+//
+//     Responses:
+//       200: AlertRuleGroup
+//       404: description: Not found.
+
+// swagger:route POST /api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/resume provisioning stable RoutePostAlertRuleGroupResume
+//
+// Resume evaluation of all rules in a rule group.
+//
+//     Responses:
+//       200: AlertRuleGroup
+//       404: description: Not found.
+
+// swagger:parameters RouteGetAlertRuleGroup RoutePutAlertRuleGroup RoutePostAlertRuleGroupPause RoutePostAlertRuleGroupResume
 type FolderUIDPathParam struct {
 	// in:path
 	FolderUID string `json:"FolderUID"`
 }
 
-// swagger:parameters RouteGetAlertRuleGroup RoutePutAlertRuleGroup
+// swagger:parameters RouteGetAlertRuleGroup RoutePutAlertRuleGroup RoutePostAlertRuleGroupPause RoutePostAlertRuleGroupResume
 type RuleGroupPathParam struct {
 	// in:path
 	Group string `json:"Group"`
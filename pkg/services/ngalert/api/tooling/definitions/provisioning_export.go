@@ -0,0 +1,383 @@
+package definitions
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/alertmanager/config"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// swagger:route GET /api/v1/provisioning/alert-rules/export provisioning stable RouteGetAlertRulesExport
+//
+// Export the alert rules of the organization, a folder or a rule group in the provisioning file format.
+//
+//     Produces:
+//     - application/json
+//     - application/yaml
+//
+//     Responses:
+//       200: AlertingFileExport
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:route GET /api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/export provisioning stable RouteGetAlertRuleGroupExport
+//
+// Export a rule group in the provisioning file format.
+//
+//     Produces:
+//     - application/json
+//     - application/yaml
+//
+//     Responses:
+//       200: AlertingFileExport
+//       404: description: Not found.
+
+// swagger:route GET /api/v1/provisioning/contact-points/export provisioning stable RouteGetContactpointsExport
+//
+// Export all contact points in the provisioning file format.
+//
+//     Produces:
+//     - application/json
+//     - application/yaml
+//
+//     Responses:
+//       200: AlertingFileExport
+
+// swagger:route GET /api/v1/provisioning/policies/export provisioning stable RouteGetPolicyTreeExport
+//
+// Export the notification policy tree in the provisioning file format.
+//
+//     Produces:
+//     - application/json
+//     - application/yaml
+//
+//     Responses:
+//       200: AlertingFileExport
+//       404: description: Not found.
+
+// swagger:route GET /api/v1/provisioning/mute-timings/export provisioning stable RouteGetMuteTimingsExport
+//
+// Export all mute timings in the provisioning file format.
+//
+//     Produces:
+//     - application/json
+//     - application/yaml
+//
+//     Responses:
+//       200: AlertingFileExport
+
+// swagger:route GET /api/v1/provisioning/templates/export provisioning stable RouteGetTemplatesExport
+//
+// Export all message templates in the provisioning file format.
+//
+//     Produces:
+//     - application/json
+//     - application/yaml
+//
+//     Responses:
+//       200: AlertingFileExport
+
+// swagger:parameters RouteGetAlertRulesExport RouteGetAlertRuleGroupExport RouteGetContactpointsExport RouteGetPolicyTreeExport RouteGetMuteTimingsExport RouteGetTemplatesExport
+type ExportParams struct {
+	// Format of the exported file, either yaml or json.
+	// in:query
+	// required:false
+	// default:yaml
+	Format string `json:"format"`
+	// Whether the file should be downloaded as an attachment.
+	// in:query
+	// required:false
+	// default:false
+	Download bool `json:"download"`
+}
+
+// swagger:parameters RouteGetAlertRulesExport
+type AlertRulesExportParams struct {
+	// UID of the folder to export the rules of. If not set, the rules of all folders are exported.
+	// in:query
+	// required:false
+	FolderUID string `json:"folderUid"`
+	// Name of the rule group to export. Requires folderUid.
+	// in:query
+	// required:false
+	Group string `json:"group"`
+}
+
+// swagger:parameters RouteGetAlertRuleGroupExport
+type AlertRuleGroupExportParams struct {
+	// in:path
+	FolderUID string `json:"FolderUID"`
+	// in:path
+	Group string `json:"Group"`
+}
+
+// swagger:parameters RouteGetContactpointsExport
+type ContactPointsExportParams struct {
+	// Filter by name
+	// in:query
+	// required:false
+	Name string `json:"name"`
+	// Whether the secure settings of the contact points are exported. If not set, they are redacted.
+	// in:query
+	// required:false
+	// default:false
+	Decrypt bool `json:"decrypt"`
+}
+
+// AlertingFileExport is a file of alerting resources in the format that is read by file provisioning.
+// The JSON keys are the same as the YAML keys, because JSON files are read as YAML.
+// swagger:model
+type AlertingFileExport struct {
+	APIVersion    int64                      `json:"apiVersion" yaml:"apiVersion"`
+	Groups        []AlertRuleGroupExport     `json:"groups,omitempty" yaml:"groups,omitempty"`
+	ContactPoints []ContactPointExport       `json:"contactPoints,omitempty" yaml:"contactPoints,omitempty"`
+	Policies      []NotificationPolicyExport `json:"policies,omitempty" yaml:"policies,omitempty"`
+	MuteTimes     []MuteTimeIntervalExport   `json:"muteTimes,omitempty" yaml:"muteTimes,omitempty"`
+	Templates     []MessageTemplateExport    `json:"templates,omitempty" yaml:"templates,omitempty"`
+}
+
+// NewAlertingFileExport returns an empty file of the current version of the provisioning file format.
+func NewAlertingFileExport() AlertingFileExport {
+	return AlertingFileExport{APIVersion: 1}
+}
+
+// AlertRuleGroupExport is a rule group in the provisioning file format.
+type AlertRuleGroupExport struct {
+	OrgID    int64             `json:"orgId" yaml:"orgId"`
+	Name     string            `json:"name" yaml:"name"`
+	Folder   string            `json:"folder" yaml:"folder"`
+	Interval string            `json:"interval" yaml:"interval"`
+	Rules    []AlertRuleExport `json:"rules" yaml:"rules"`
+}
+
+// AlertRuleExport is an alert rule in the provisioning file format.
+type AlertRuleExport struct {
+	UID           string             `json:"uid" yaml:"uid"`
+	Title         string             `json:"title" yaml:"title"`
+	Condition     string             `json:"condition" yaml:"condition"`
+	Data          []AlertQueryExport `json:"data" yaml:"data"`
+	DashboardUID  string             `json:"dashboardUid,omitempty" yaml:"dashboardUid,omitempty"`
+	PanelID       int64              `json:"panelId,omitempty" yaml:"panelId,omitempty"`
+	NoDataState   string             `json:"noDataState" yaml:"noDataState"`
+	ExecErrState  string             `json:"execErrState" yaml:"execErrState"`
+	For           string             `json:"for" yaml:"for"`
+	KeepFiringFor string             `json:"keepFiringFor,omitempty" yaml:"keepFiringFor,omitempty"`
+	Annotations   map[string]string  `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	Labels        map[string]string  `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Record        *RecordExport      `json:"record,omitempty" yaml:"record,omitempty"`
+	IsPaused      bool               `json:"isPaused,omitempty" yaml:"isPaused,omitempty"`
+}
+
+// RecordExport is the recording of a recording rule in the provisioning file format.
+type RecordExport struct {
+	Metric string `json:"metric" yaml:"metric"`
+	From   string `json:"from" yaml:"from"`
+}
+
+// AlertQueryExport is a query of an alert rule in the provisioning file format.
+type AlertQueryExport struct {
+	RefID             string                   `json:"refId" yaml:"refId"`
+	QueryType         string                   `json:"queryType,omitempty" yaml:"queryType,omitempty"`
+	RelativeTimeRange models.RelativeTimeRange `json:"relativeTimeRange" yaml:"relativeTimeRange"`
+	DatasourceUID     string                   `json:"datasourceUid" yaml:"datasourceUid"`
+	Model             map[string]interface{}   `json:"model" yaml:"model"`
+}
+
+// ContactPointExport is a contact point in the provisioning file format.
+type ContactPointExport struct {
+	OrgID     int64            `json:"orgId" yaml:"orgId"`
+	Name      string           `json:"name" yaml:"name"`
+	Receivers []ReceiverExport `json:"receivers" yaml:"receivers"`
+}
+
+// ReceiverExport is an integration of a contact point in the provisioning file format.
+type ReceiverExport struct {
+	UID                   string                 `json:"uid" yaml:"uid"`
+	Type                  string                 `json:"type" yaml:"type"`
+	Settings              map[string]interface{} `json:"settings" yaml:"settings"`
+	DisableResolveMessage bool                   `json:"disableResolveMessage,omitempty" yaml:"disableResolveMessage,omitempty"`
+}
+
+// NotificationPolicyExport is the notification policy tree of an organization in the provisioning file format.
+type NotificationPolicyExport struct {
+	OrgID int64 `json:"orgId" yaml:"orgId"`
+	Route `json:",inline" yaml:",inline"`
+}
+
+// MuteTimeIntervalExport is a mute timing in the provisioning file format.
+type MuteTimeIntervalExport struct {
+	OrgID                   int64 `json:"orgId" yaml:"orgId"`
+	config.MuteTimeInterval `json:",inline" yaml:",inline"`
+	DateRanges              []DateRange `json:"date_ranges,omitempty" yaml:"date_ranges,omitempty"`
+}
+
+// MessageTemplateExport is a message template in the provisioning file format.
+type MessageTemplateExport struct {
+	OrgID    int64  `json:"orgId" yaml:"orgId"`
+	Name     string `json:"name" yaml:"name"`
+	Template string `json:"template" yaml:"template"`
+}
+
+// NewAlertRuleGroupExport converts a rule group to the provisioning file format. Rule groups of provisioning files
+// refer to their folder by title.
+func NewAlertRuleGroupExport(orgID int64, folderTitle string, group models.AlertRuleGroup) (AlertRuleGroupExport, error) {
+	rules := make([]AlertRuleExport, 0, len(group.Rules))
+	for _, rule := range group.Rules {
+		exported, err := newAlertRuleExport(rule)
+		if err != nil {
+			return AlertRuleGroupExport{}, fmt.Errorf("failed to export rule '%s': %w", rule.Title, err)
+		}
+		rules = append(rules, exported)
+	}
+	return AlertRuleGroupExport{
+		OrgID:    orgID,
+		Name:     escapeProvisioningValue(group.Title),
+		Folder:   escapeProvisioningValue(folderTitle),
+		Interval: (time.Duration(group.Interval) * time.Second).String(),
+		Rules:    rules,
+	}, nil
+}
+
+func newAlertRuleExport(rule models.AlertRule) (AlertRuleExport, error) {
+	data := make([]AlertQueryExport, 0, len(rule.Data))
+	for _, query := range rule.Data {
+		var model map[string]interface{}
+		if err := json.Unmarshal(query.Model, &model); err != nil {
+			return AlertRuleExport{}, fmt.Errorf("failed to parse model of query '%s': %w", query.RefID, err)
+		}
+		data = append(data, AlertQueryExport{
+			RefID:             query.RefID,
+			QueryType:         query.QueryType,
+			RelativeTimeRange: query.RelativeTimeRange,
+			DatasourceUID:     query.DatasourceUID,
+			Model:             model,
+		})
+	}
+	result := AlertRuleExport{
+		UID:          rule.UID,
+		Title:        escapeProvisioningValue(rule.Title),
+		Condition:    rule.Condition,
+		Data:         data,
+		NoDataState:  string(rule.NoDataState),
+		ExecErrState: string(rule.ExecErrState),
+		For:          rule.For.String(),
+		// annotations are not interpolated by file provisioning
+		Annotations: rule.Annotations,
+		IsPaused:    rule.IsPaused,
+	}
+	if rule.KeepFiringFor > 0 {
+		result.KeepFiringFor = rule.KeepFiringFor.String()
+	}
+	if rule.DashboardUID != nil {
+		result.DashboardUID = *rule.DashboardUID
+	}
+	if rule.PanelID != nil {
+		result.PanelID = *rule.PanelID
+	}
+	if len(rule.Labels) > 0 {
+		result.Labels = make(map[string]string, len(rule.Labels))
+		for k, v := range rule.Labels {
+			result.Labels[k] = escapeProvisioningValue(v)
+		}
+	}
+	if rule.Record != nil {
+		result.Record = &RecordExport{Metric: rule.Record.Metric, From: rule.Record.From}
+	}
+	return result, nil
+}
+
+// NewContactPointExports converts contact points to the provisioning file format, in which the integrations with the
+// same name are the receivers of a single contact point.
+func NewContactPointExports(orgID int64, contactPoints []EmbeddedContactPoint) ([]ContactPointExport, error) {
+	result := make([]ContactPointExport, 0, len(contactPoints))
+	byName := make(map[string]int, len(contactPoints))
+	for _, cp := range contactPoints {
+		settings := map[string]interface{}{}
+		if cp.Settings != nil {
+			m, err := cp.Settings.Map()
+			if err != nil {
+				return nil, fmt.Errorf("failed to export settings of contact point '%s': %w", cp.Name, err)
+			}
+			settings = escapeProvisioningSettings(m)
+		}
+		receiver := ReceiverExport{
+			UID:                   cp.UID,
+			Type:                  cp.Type,
+			Settings:              settings,
+			DisableResolveMessage: cp.DisableResolveMessage,
+		}
+		idx, ok := byName[cp.Name]
+		if !ok {
+			idx = len(result)
+			byName[cp.Name] = idx
+			result = append(result, ContactPointExport{OrgID: orgID, Name: escapeProvisioningValue(cp.Name)})
+		}
+		result[idx].Receivers = append(result[idx].Receivers, receiver)
+	}
+	return result, nil
+}
+
+// NewNotificationPolicyExport converts the notification policy tree to the provisioning file format.
+func NewNotificationPolicyExport(orgID int64, tree Route) NotificationPolicyExport {
+	clearProvenance(&tree)
+	return NotificationPolicyExport{OrgID: orgID, Route: tree}
+}
+
+func clearProvenance(r *Route) {
+	r.Provenance = ""
+	for _, child := range r.Routes {
+		clearProvenance(child)
+	}
+}
+
+// NewMuteTimeIntervalExport converts a mute timing to the provisioning file format.
+func NewMuteTimeIntervalExport(orgID int64, mt MuteTimeInterval) MuteTimeIntervalExport {
+	return MuteTimeIntervalExport{
+		OrgID:            orgID,
+		MuteTimeInterval: mt.MuteTimeInterval,
+		DateRanges:       mt.DateRanges,
+	}
+}
+
+// NewMessageTemplateExport converts a message template to the provisioning file format.
+func NewMessageTemplateExport(orgID int64, tmpl MessageTemplate) MessageTemplateExport {
+	return MessageTemplateExport{
+		OrgID:    orgID,
+		Name:     tmpl.Name,
+		Template: tmpl.Template,
+	}
+}
+
+// escapeProvisioningValue escapes the $ of a value that file provisioning interpolates with environment variables.
+func escapeProvisioningValue(v string) string {
+	return strings.ReplaceAll(v, "$", "$$")
+}
+
+func escapeProvisioningSettings(settings map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(settings))
+	for k, v := range settings {
+		result[k] = escapeProvisioningSetting(v)
+	}
+	return result
+}
+
+func escapeProvisioningSetting(v interface{}) interface{} {
+	switch t := v.(type) {
+	case string:
+		return escapeProvisioningValue(t)
+	case map[string]interface{}:
+		return escapeProvisioningSettings(t)
+	case []interface{}:
+		result := make([]interface{}, 0, len(t))
+		for _, e := range t {
+			result = append(result, escapeProvisioningSetting(e))
+		}
+		return result
+	default:
+		return v
+	}
+}
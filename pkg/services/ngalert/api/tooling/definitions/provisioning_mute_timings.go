@@ -70,6 +70,7 @@ type MuteTimingPayload struct {
 // swagger:model
 type MuteTimeInterval struct {
 	config.MuteTimeInterval `json:",inline" yaml:",inline"`
+	DateRanges              []DateRange       `json:"date_ranges,omitempty" yaml:"date_ranges,omitempty"`
 	Provenance              models.Provenance `json:"provenance,omitempty"`
 }
 
@@ -80,3 +81,11 @@ func (mt *MuteTimeInterval) ResourceType() string {
 func (mt *MuteTimeInterval) ResourceID() string {
 	return mt.MuteTimeInterval.Name
 }
+
+// Config returns the mute timing as it is stored in the Alertmanager configuration.
+func (mt *MuteTimeInterval) Config() MuteTimeIntervalConfig {
+	return MuteTimeIntervalConfig{
+		MuteTimeInterval: mt.MuteTimeInterval,
+		DateRanges:       mt.DateRanges,
+	}
+}
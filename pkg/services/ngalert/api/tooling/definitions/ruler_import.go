@@ -0,0 +1,72 @@
+package definitions
+
+import (
+	"github.com/prometheus/common/model"
+)
+
+// swagger:route POST /api/ruler/grafana/api/v1/import/prometheus/{Namespace} ruler RoutePostPrometheusRulesImport
+//
+// Converts the rule groups of a Prometheus rule file to Grafana managed rules and creates or replaces the rule groups with the same names in the namespace.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: PrometheusRulesImportResult
+//       202: PrometheusRulesImportResult
+//       400: ValidationError
+//       404: NotFound
+
+// swagger:parameters RoutePostPrometheusRulesImport
+type PrometheusRulesImportConfig struct {
+	// in:path
+	Namespace string
+	// in:body
+	Body PrometheusRulesImportBody
+}
+
+// swagger:model
+type PrometheusRulesImportBody struct {
+	// UID of the Prometheus or Loki data source that is queried by the expressions of the rules.
+	// required: true
+	DatasourceUID string `json:"datasourceUid"`
+	// DryRun only converts the rules, without saving them.
+	DryRun bool `json:"dryRun,omitempty"`
+	// Groups are the rule groups of the Prometheus rule file.
+	// required: true
+	Groups []PrometheusRuleGroup `json:"groups"`
+}
+
+// PrometheusRuleGroup is a rule group of a Prometheus rule file.
+// swagger:model
+type PrometheusRuleGroup struct {
+	Name     string         `yaml:"name" json:"name"`
+	Interval model.Duration `yaml:"interval,omitempty" json:"interval,omitempty"`
+	Rules    []ApiRuleNode  `yaml:"rules" json:"rules"`
+}
+
+// PrometheusRuleFile is a Prometheus rule file.
+type PrometheusRuleFile struct {
+	Groups []PrometheusRuleGroup `yaml:"groups" json:"groups"`
+}
+
+// swagger:model
+type PrometheusRulesImportResult struct {
+	// Groups are the rule groups of Grafana managed rules that the Prometheus rule groups are converted to.
+	Groups []PostableRuleGroupConfig `json:"groups"`
+	// Skipped are the rules that could not be converted and are not imported.
+	Skipped []SkippedPrometheusRule `json:"skipped,omitempty"`
+}
+
+// swagger:model
+type SkippedPrometheusRule struct {
+	// Group is the name of the rule group of the rule.
+	Group string `json:"group"`
+	// Rule is the name of the alert or the recorded metric of the rule.
+	Rule string `json:"rule"`
+	// Reason is why the rule could not be converted.
+	Reason string `json:"reason"`
+}
@@ -4,6 +4,7 @@ package eval
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"runtime/debug"
@@ -559,6 +560,47 @@ func (evalResults Results) AsDataFrame() data.Frame {
 	return *frame
 }
 
+// ConditionWithLoadedDimensions returns a copy of the condition where the threshold expressions that have a
+// threshold to resolve know the labels of the dimensions that are firing, so that they can use that threshold for them.
+// The condition is returned unchanged if there are no such expressions.
+func ConditionWithLoadedDimensions(condition models.Condition, dimensions []data.Labels) (models.Condition, error) {
+	if len(dimensions) == 0 {
+		return condition, nil
+	}
+	var queries []models.AlertQuery
+	for i, q := range condition.Data {
+		if !expr.IsDataSource(q.DatasourceUID) {
+			continue
+		}
+		var model map[string]interface{}
+		if err := json.Unmarshal(q.Model, &model); err != nil {
+			return condition, fmt.Errorf("failed to parse query model of '%s': %w", q.RefID, err)
+		}
+		if !expr.SetLoadedDimensionsToHysteresisCommand(model, dimensions) {
+			continue
+		}
+		raw, err := json.Marshal(model)
+		if err != nil {
+			return condition, fmt.Errorf("failed to marshal query model of '%s': %w", q.RefID, err)
+		}
+		if queries == nil {
+			queries = make([]models.AlertQuery, len(condition.Data))
+			copy(queries, condition.Data)
+		}
+		queries[i] = models.AlertQuery{
+			RefID:             q.RefID,
+			QueryType:         q.QueryType,
+			RelativeTimeRange: q.RelativeTimeRange,
+			DatasourceUID:     q.DatasourceUID,
+			Model:             raw,
+		}
+	}
+	if queries == nil {
+		return condition, nil
+	}
+	return models.Condition{Condition: condition.Condition, Data: queries}, nil
+}
+
 // ConditionEval executes conditions and evaluates the result.
 func (e *evaluatorImpl) ConditionEval(ctx context.Context, user *user.SignedInUser, condition models.Condition, now time.Time) Results {
 	execResp, err := e.QueriesAndExpressionsEval(ctx, user, condition.Data, now)
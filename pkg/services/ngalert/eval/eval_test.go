@@ -455,3 +455,37 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestConditionWithLoadedDimensions(t *testing.T) {
+	dsQuery := models.GenerateAlertQuery()
+	threshold := models.AlertQuery{
+		RefID:         "B",
+		DatasourceUID: expr.DatasourceUID,
+		Model:         []byte(`{"type":"threshold","expression":"A","conditions":[{"evaluator":{"type":"gt","params":[80]},"unloadEvaluator":{"type":"lt","params":[70]}}]}`),
+	}
+	condition := models.Condition{Condition: "B", Data: []models.AlertQuery{dsQuery, threshold}}
+	dimensions := []data.Labels{{"host": "a"}}
+
+	t.Run("should set loaded dimensions to threshold with unload evaluator", func(t *testing.T) {
+		result, err := ConditionWithLoadedDimensions(condition, dimensions)
+		require.NoError(t, err)
+		require.Equal(t, "B", result.Condition)
+		require.Equal(t, dsQuery, result.Data[0])
+		require.JSONEq(t, `{"type":"threshold","expression":"A","conditions":[{"evaluator":{"type":"gt","params":[80]},"unloadEvaluator":{"type":"lt","params":[70]}}],"loadedDimensions":[{"host":"a"}]}`, string(result.Data[1].Model))
+		// the original condition must not be changed
+		require.NotContains(t, string(condition.Data[1].Model), "loadedDimensions")
+	})
+
+	t.Run("should return condition unchanged if there are no loaded dimensions", func(t *testing.T) {
+		result, err := ConditionWithLoadedDimensions(condition, nil)
+		require.NoError(t, err)
+		require.Equal(t, condition, result)
+	})
+
+	t.Run("should return condition unchanged if there is no threshold with unload evaluator", func(t *testing.T) {
+		c := models.Condition{Condition: "A", Data: []models.AlertQuery{dsQuery}}
+		result, err := ConditionWithLoadedDimensions(c, dimensions)
+		require.NoError(t, err)
+		require.Equal(t, c, result)
+	})
+}
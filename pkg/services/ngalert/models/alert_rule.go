@@ -10,6 +10,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	prometheusModel "github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/util/cmputil"
 )
@@ -17,6 +18,8 @@ import (
 var (
 	// ErrAlertRuleNotFound is an error for an unknown alert rule.
 	ErrAlertRuleNotFound = fmt.Errorf("could not find alert rule")
+	// ErrAlertRuleVersionNotFound is an error for an unknown version of an alert rule.
+	ErrAlertRuleVersionNotFound = fmt.Errorf("could not find alert rule version")
 	// ErrAlertRuleFailedGenerateUniqueUID is an error for failure to generate alert rule UID
 	ErrAlertRuleFailedGenerateUniqueUID = errors.New("failed to generate alert rule UID")
 	// ErrCannotEditNamespace is an error returned if the user does not have permissions to edit the namespace
@@ -123,6 +126,40 @@ var (
 	}
 )
 
+// RuleType is the type of alert rule, either an alerting rule or a recording rule.
+type RuleType string
+
+const (
+	RuleTypeAlerting  RuleType = "alerting"
+	RuleTypeRecording RuleType = "recording"
+)
+
+func (t RuleType) String() string {
+	return string(t)
+}
+
+// Record contains the information that is needed to write the result of a recording rule as a time series.
+type Record struct {
+	// Metric is the name of the metric the result is written to.
+	Metric string `json:"metric"`
+	// From is the RefID of the query or expression which result is written.
+	From string `json:"from"`
+}
+
+// Validate checks that the metric name is a valid Prometheus metric name and that the source of the data is specified.
+func (r *Record) Validate() error {
+	if r.Metric == "" {
+		return fmt.Errorf("%w: metric name for recording rule must not be empty", ErrAlertRuleFailedValidation)
+	}
+	if !prometheusModel.IsValidMetricName(prometheusModel.LabelValue(r.Metric)) {
+		return fmt.Errorf("%w: metric name for recording rule must be a valid Prometheus metric name", ErrAlertRuleFailedValidation)
+	}
+	if r.From == "" {
+		return fmt.Errorf("%w: refID of the query or expression to record must not be empty", ErrAlertRuleFailedValidation)
+	}
+	return nil
+}
+
 // AlertRuleGroup is the base model for a rule group in unified alerting.
 type AlertRuleGroup struct {
 	Title      string
@@ -152,9 +189,17 @@ type AlertRule struct {
 	ExecErrState    ExecutionErrorState
 	// ideally this field should have been apimodels.ApiDuration
 	// but this is currently not possible because of circular dependencies
-	For         time.Duration
-	Annotations map[string]string
-	Labels      map[string]string
+	For time.Duration
+	// KeepFiringFor is the duration an alert keeps firing after its condition stopped being true.
+	KeepFiringFor time.Duration `xorm:"keep_firing_for"`
+	Annotations   map[string]string
+	Labels        map[string]string
+	// Record is not nil if the rule is a recording rule.
+	Record *Record `xorm:"json 'record'"`
+	// IsPaused is true if the rule is not evaluated. The rule is kept in the database but it does not produce alerts.
+	IsPaused bool `xorm:"is_paused"`
+	// KeepIsPaused is true if the rule was submitted without a paused state. It makes PatchPartialAlertRule keep the paused state of the existing rule. It is not stored.
+	KeepIsPaused bool `xorm:"-"`
 }
 
 type LabelOption func(map[string]string)
@@ -180,6 +225,14 @@ func (alertRule *AlertRule) GetLabels(opts ...LabelOption) map[string]string {
 	return labels
 }
 
+// Type returns the type of the rule. A rule that has Record is a recording rule, otherwise it is an alerting rule.
+func (alertRule *AlertRule) Type() RuleType {
+	if alertRule.Record != nil {
+		return RuleTypeRecording
+	}
+	return RuleTypeAlerting
+}
+
 func (alertRule *AlertRule) GetEvalCondition() Condition {
 	return Condition{
 		Condition: alertRule.Condition,
@@ -316,9 +369,79 @@ type AlertRuleVersion struct {
 	ExecErrState    ExecutionErrorState
 	// ideally this field should have been apimodels.ApiDuration
 	// but this is currently not possible because of circular dependencies
-	For         time.Duration
-	Annotations map[string]string
-	Labels      map[string]string
+	For           time.Duration
+	KeepFiringFor time.Duration `xorm:"keep_firing_for"`
+	Annotations   map[string]string
+	Labels        map[string]string
+	Record        *Record `xorm:"json 'record'"`
+	IsPaused      bool    `xorm:"is_paused"`
+}
+
+// ToAlertRule returns the alert rule as it was defined at this version.
+func (v *AlertRuleVersion) ToAlertRule() (AlertRule, error) {
+	rule := AlertRule{
+		OrgID:           v.RuleOrgID,
+		Title:           v.Title,
+		Condition:       v.Condition,
+		Data:            v.Data,
+		Updated:         v.Created,
+		IntervalSeconds: v.IntervalSeconds,
+		Version:         v.Version,
+		UID:             v.RuleUID,
+		NamespaceUID:    v.RuleNamespaceUID,
+		RuleGroup:       v.RuleGroup,
+		RuleGroupIndex:  v.RuleGroupIndex,
+		NoDataState:     v.NoDataState,
+		ExecErrState:    v.ExecErrState,
+		For:             v.For,
+		KeepFiringFor:   v.KeepFiringFor,
+		Annotations:     v.Annotations,
+		Labels:          v.Labels,
+		Record:          v.Record,
+		IsPaused:        v.IsPaused,
+	}
+	if err := rule.SetDashboardAndPanel(); err != nil {
+		return AlertRule{}, fmt.Errorf("invalid version %d of alert rule %s: %w", v.Version, v.RuleUID, err)
+	}
+	return rule, nil
+}
+
+// Restore returns a copy of the rule with the definition of this version. The rule keeps its folder, group,
+// evaluation interval and whether it is paused, because these can have changed along with other rules of the group.
+func (v *AlertRuleVersion) Restore(rule AlertRule) (AlertRule, error) {
+	rule.Title = v.Title
+	rule.Condition = v.Condition
+	rule.Data = v.Data
+	rule.NoDataState = v.NoDataState
+	rule.ExecErrState = v.ExecErrState
+	rule.For = v.For
+	rule.KeepFiringFor = v.KeepFiringFor
+	rule.Annotations = v.Annotations
+	rule.Labels = v.Labels
+	rule.Record = v.Record
+	rule.DashboardUID = nil
+	rule.PanelID = nil
+	if err := rule.SetDashboardAndPanel(); err != nil {
+		return AlertRule{}, err
+	}
+	return rule, nil
+}
+
+// ListAlertRuleVersionsQuery is the query for listing all versions of an alert rule, latest first.
+type ListAlertRuleVersionsQuery struct {
+	UID   string
+	OrgID int64
+
+	Result []*AlertRuleVersion
+}
+
+// GetAlertRuleVersionQuery is the query for retrieving a specific version of an alert rule.
+type GetAlertRuleVersionQuery struct {
+	UID     string
+	OrgID   int64
+	Version int64
+
+	Result *AlertRuleVersion
 }
 
 // GetAlertRuleByUIDQuery is the query for retrieving/deleting an alert rule by UID and organisation ID.
@@ -385,6 +508,8 @@ type ListOrgRuleGroupsQuery struct {
 type UpdateRule struct {
 	Existing *AlertRule
 	New      AlertRule
+	// RestoredFrom is the version the new rule was restored from, if any.
+	RestoredFrom int64
 }
 
 // Condition contains backend expressions and queries and the RefID
@@ -408,9 +533,10 @@ func (c Condition) IsValid() bool {
 // There are several exceptions:
 // 1. Following fields are not patched and therefore will be ignored: AlertRule.ID, AlertRule.OrgID, AlertRule.Updated, AlertRule.Version, AlertRule.UID, AlertRule.DashboardUID, AlertRule.PanelID, AlertRule.Annotations and AlertRule.Labels
 // 2. There are fields that are patched together:
-//   - AlertRule.Condition and AlertRule.Data
+//   - AlertRule.Condition, AlertRule.Data and AlertRule.Record
 //
 // If either of the pair is specified, neither is patched.
+// 3. AlertRule.Record is patched if it is empty, because clients that do not support recording rules do not send it. Therefore, a recording rule stays a recording rule.
 func PatchPartialAlertRule(existingRule *AlertRule, ruleToPatch *AlertRule) {
 	if ruleToPatch.Title == "" {
 		ruleToPatch.Title = existingRule.Title
@@ -418,6 +544,10 @@ func PatchPartialAlertRule(existingRule *AlertRule, ruleToPatch *AlertRule) {
 	if ruleToPatch.Condition == "" || len(ruleToPatch.Data) == 0 {
 		ruleToPatch.Condition = existingRule.Condition
 		ruleToPatch.Data = existingRule.Data
+		ruleToPatch.Record = existingRule.Record
+	}
+	if ruleToPatch.Record == nil {
+		ruleToPatch.Record = existingRule.Record
 	}
 	if ruleToPatch.IntervalSeconds == 0 {
 		ruleToPatch.IntervalSeconds = existingRule.IntervalSeconds
@@ -437,6 +567,13 @@ func PatchPartialAlertRule(existingRule *AlertRule, ruleToPatch *AlertRule) {
 	if ruleToPatch.For == -1 {
 		ruleToPatch.For = existingRule.For
 	}
+	if ruleToPatch.KeepFiringFor == -1 {
+		ruleToPatch.KeepFiringFor = existingRule.KeepFiringFor
+	}
+	if ruleToPatch.KeepIsPaused {
+		ruleToPatch.IsPaused = existingRule.IsPaused
+		ruleToPatch.KeepIsPaused = false
+	}
 }
 
 func ValidateRuleGroupInterval(intervalSeconds, baseIntervalSeconds int64) error {
@@ -82,6 +82,43 @@ func TestErrStateFromString(t *testing.T) {
 	})
 }
 
+func TestRecordValidate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		record Record
+		valid  bool
+	}{
+		{
+			name:   "valid record",
+			record: Record{Metric: "job:http_requests:rate5m", From: "A"},
+			valid:  true,
+		},
+		{
+			name:   "empty metric",
+			record: Record{Metric: "", From: "A"},
+		},
+		{
+			name:   "invalid metric",
+			record: Record{Metric: "http requests", From: "A"},
+		},
+		{
+			name:   "empty from",
+			record: Record{Metric: "http_requests", From: ""},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.record.Validate()
+			if tc.valid {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrAlertRuleFailedValidation)
+		})
+	}
+}
+
 func TestPatchPartialAlertRule(t *testing.T) {
 	t.Run("patches", func(t *testing.T) {
 		testCases := []struct {
@@ -119,6 +156,25 @@ func TestPatchPartialAlertRule(t *testing.T) {
 					r.For = -1
 				},
 			},
+			{
+				name: "KeepFiringFor is -1",
+				mutator: func(r *AlertRule) {
+					r.KeepFiringFor = -1
+				},
+			},
+			{
+				name: "Record is empty",
+				mutator: func(r *AlertRule) {
+					r.Record = nil
+				},
+			},
+			{
+				name: "IsPaused is kept",
+				mutator: func(r *AlertRule) {
+					r.IsPaused = !r.IsPaused
+					r.KeepIsPaused = true
+				},
+			},
 		}
 
 		for _, testCase := range testCases {
@@ -127,6 +183,8 @@ func TestPatchPartialAlertRule(t *testing.T) {
 				for {
 					existing = AlertRuleGen(func(rule *AlertRule) {
 						rule.For = time.Duration(rand.Int63n(1000) + 1)
+						rule.KeepFiringFor = time.Duration(rand.Int63n(1000) + 1)
+						rule.Record = &Record{Metric: "test_metric", From: rule.Condition}
 					})()
 					cloned := *existing
 					testCase.mutator(&cloned)
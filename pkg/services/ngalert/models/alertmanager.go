@@ -20,6 +20,22 @@ type GetLatestAlertmanagerConfigurationQuery struct {
 	Result *AlertConfiguration
 }
 
+// GetAlertmanagerConfigurationHistoryQuery is the query to get the stored versions of the alertmanager configuration
+// of an organization, latest first.
+type GetAlertmanagerConfigurationHistoryQuery struct {
+	OrgID int64
+	// Limit is the maximum number of versions to return. All stored versions are returned if it is not positive.
+	Limit  int
+	Result []*AlertConfiguration
+}
+
+// GetAlertmanagerConfigurationQuery is the query to get a specific version of the alertmanager configuration.
+type GetAlertmanagerConfigurationQuery struct {
+	OrgID  int64
+	ID     int64
+	Result *AlertConfiguration
+}
+
 // SaveAlertmanagerConfigurationCmd is the command to save an alertmanager configuration.
 type SaveAlertmanagerConfigurationCmd struct {
 	AlertmanagerConfiguration string
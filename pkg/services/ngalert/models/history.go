@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// HistoryQuery is the query for retrieving the history of alert state transitions.
+type HistoryQuery struct {
+	OrgID int64
+	// RuleUID restricts the history to transitions of a single alert rule. Optional.
+	RuleUID string
+	// Labels restricts the history to transitions of alert instances that have all these labels. Optional.
+	Labels map[string]string
+	// RuleUIDs restricts the history to transitions of these alert rules, e.g. the rules the user can access.
+	// If it is nil, transitions of all rules are returned.
+	RuleUIDs []string
+	// NamespaceUIDs is a hint that all the rules of RuleUIDs are in these folders. Optional.
+	NamespaceUIDs []string
+	From          time.Time
+	To            time.Time
+	// Limit is the maximum number of transitions to return. If it is 0, the backend applies its default.
+	Limit int
+	// SignedInUser is the user who runs the query. Backends that enforce permissions on the history, such as annotations, require it.
+	SignedInUser *user.SignedInUser
+}
@@ -14,6 +14,8 @@ type AlertInstance struct {
 	CurrentStateSince time.Time
 	CurrentStateEnd   time.Time
 	LastEvalTime      time.Time
+	// KeepFiringSince is the time since the instance is kept firing because of the keep_firing_for of the rule.
+	KeepFiringSince time.Time
 }
 
 type AlertInstanceKey struct {
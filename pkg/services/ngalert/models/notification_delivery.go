@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+)
+
+// NotificationDeliveryStatus is the outcome of an attempt to deliver a notification.
+type NotificationDeliveryStatus string
+
+const (
+	NotificationDeliverySuccess NotificationDeliveryStatus = "success"
+	NotificationDeliveryFailure NotificationDeliveryStatus = "failure"
+)
+
+// NotificationDelivery is an attempt of an integration of a contact point to deliver a notification.
+// Every attempt is recorded, including the retries of a failed delivery.
+type NotificationDelivery struct {
+	ID    int64 `xorm:"pk autoincr 'id'"`
+	OrgID int64 `xorm:"org_id"`
+	// Receiver is the name of the contact point.
+	Receiver         string                     `xorm:"receiver"`
+	IntegrationUID   string                     `xorm:"integration_uid"`
+	IntegrationName  string                     `xorm:"integration_name"`
+	IntegrationType  string                     `xorm:"integration_type"`
+	IntegrationIndex int                        `xorm:"integration_index"`
+	GroupKey         string                     `xorm:"group_key"`
+	Alerts           int                        `xorm:"alerts"`
+	Status           NotificationDeliveryStatus `xorm:"status"`
+	// StatusCode is the HTTP status code of the response of the receiving service, or 0 if the integration
+	// does not use HTTP or no response was received.
+	StatusCode int    `xorm:"status_code"`
+	Error      string `xorm:"error"`
+	// Retry is true if the delivery failed and will be attempted again.
+	Retry      bool      `xorm:"retry"`
+	DurationMs int64     `xorm:"duration_ms"`
+	CreatedAt  time.Time `xorm:"created_at"`
+}
+
+// A XORM interface that defines the used table for this struct.
+func (d *NotificationDelivery) TableName() string {
+	return "alert_notification_delivery"
+}
+
+// GetNotificationDeliveriesQuery is the query to get the recorded notification deliveries of an organization, latest first.
+type GetNotificationDeliveriesQuery struct {
+	OrgID int64
+	// Receiver, IntegrationType, GroupKey and Status filter the deliveries if they are not empty.
+	Receiver        string
+	IntegrationType string
+	GroupKey        string
+	Status          NotificationDeliveryStatus
+	// From and To filter the deliveries by the time they were attempted if they are not zero.
+	From time.Time
+	To   time.Time
+	// Limit is the maximum number of deliveries to return. All matching deliveries are returned if it is not positive.
+	Limit  int
+	Result []*NotificationDelivery
+}
@@ -238,6 +238,8 @@ func CopyRule(r *AlertRule) *AlertRule {
 		NoDataState:     r.NoDataState,
 		ExecErrState:    r.ExecErrState,
 		For:             r.For,
+		KeepFiringFor:   r.KeepFiringFor,
+		IsPaused:        r.IsPaused,
 	}
 
 	if r.DashboardUID != nil {
@@ -275,6 +277,11 @@ func CopyRule(r *AlertRule) *AlertRule {
 		}
 	}
 
+	if r.Record != nil {
+		record := *r.Record
+		result.Record = &record
+	}
+
 	return &result
 }
 
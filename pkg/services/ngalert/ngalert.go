@@ -33,6 +33,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/state/historian"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/writer"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/rendering"
@@ -119,6 +120,7 @@ type AlertNG struct {
 	imageService        image.ImageService
 	schedule            schedule.ScheduleService
 	stateManager        *state.Manager
+	stateHistorian      stateHistorian
 	folderService       folder.Service
 	dashboardService    dashboards.DashboardService
 
@@ -182,17 +184,21 @@ func (ng *AlertNG) init() error {
 	ng.AlertsRouter = alertsRouter
 
 	schedCfg := schedule.SchedulerCfg{
-		Cfg:         ng.Cfg.UnifiedAlerting,
-		C:           clk,
-		Logger:      ng.Log,
-		Evaluator:   eval.NewEvaluator(ng.Cfg, ng.Log, ng.DataSourceCache, ng.ExpressionService),
-		RuleStore:   store,
-		Metrics:     ng.Metrics.GetSchedulerMetrics(),
-		AlertSender: alertsRouter,
+		Cfg:             ng.Cfg.UnifiedAlerting,
+		C:               clk,
+		Logger:          ng.Log,
+		Evaluator:       eval.NewEvaluator(ng.Cfg, ng.Log, ng.DataSourceCache, ng.ExpressionService),
+		RuleStore:       store,
+		Metrics:         ng.Metrics.GetSchedulerMetrics(),
+		AlertSender:     alertsRouter,
+		RecordingWriter: writer.New(ng.Cfg.UnifiedAlerting.RecordingRules, ng.Log.New("component", "recording-writer")),
 	}
 
-	historian := historian.NewAnnotationHistorian(ng.annotationsRepo, ng.dashboardService, ng.Log)
-	stateManager := state.NewManager(ng.Log, ng.Metrics.GetStateMetrics(), appUrl, store, store, ng.imageService, clk, historian)
+	history, err := configureHistorianBackend(ng.Cfg.UnifiedAlerting.StateHistory, ng.annotationsRepo, ng.dashboardService, store, ng.Log)
+	if err != nil {
+		return err
+	}
+	stateManager := state.NewManager(ng.Log, ng.Metrics.GetStateMetrics(), appUrl, store, store, ng.imageService, clk, history)
 	scheduler := schedule.NewScheduler(schedCfg, appUrl, stateManager)
 
 	// if it is required to include folder title to the alerts, we need to subscribe to changes of alert title
@@ -201,6 +207,7 @@ func (ng *AlertNG) init() error {
 	}
 
 	ng.stateManager = stateManager
+	ng.stateHistorian = history
 	ng.schedule = scheduler
 
 	// Provisioning
@@ -235,6 +242,7 @@ func (ng *AlertNG) init() error {
 		MuteTimings:          muteTimingService,
 		AlertRules:           alertRuleService,
 		AlertsRouter:         alertsRouter,
+		StateHistorian:       history,
 	}
 	api.RegisterAPIEndpoints(ng.Metrics.GetAPIMetrics())
 
@@ -285,6 +293,12 @@ func (ng *AlertNG) Run(ctx context.Context) error {
 			return ng.schedule.Run(subCtx)
 		})
 	}
+	// some backends record the history in the background
+	if h, ok := ng.stateHistorian.(interface{ Run(context.Context) error }); ok {
+		children.Go(func() error {
+			return h.Run(subCtx)
+		})
+	}
 	return children.Wait()
 }
 
@@ -295,3 +309,20 @@ func (ng *AlertNG) IsDisabled() bool {
 	}
 	return !ng.Cfg.UnifiedAlerting.IsEnabled()
 }
+
+// stateHistorian records the transitions of alert states and makes them available to the API.
+type stateHistorian interface {
+	state.Historian
+	api.StateHistorian
+}
+
+func configureHistorianBackend(cfg setting.UnifiedAlertingStateHistorySettings, ar annotations.Repository, ds dashboards.DashboardService, rs historian.RuleStore, l log.Logger) (stateHistorian, error) {
+	if cfg.Backend == setting.StateHistoryBackendLoki {
+		h, err := historian.NewLokiHistorian(cfg, l.New("component", "loki-state-historian"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize loki state historian: %w", err)
+		}
+		return h, nil
+	}
+	return historian.NewAnnotationHistorian(ar, ds, rs, l), nil
+}
@@ -17,7 +17,6 @@ import (
 
 	amv2 "github.com/prometheus/alertmanager/api/v2/models"
 	"github.com/prometheus/alertmanager/cluster"
-	"github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/alertmanager/dispatch"
 	"github.com/prometheus/alertmanager/inhibit"
 	"github.com/prometheus/alertmanager/nflog"
@@ -26,7 +25,6 @@ import (
 	"github.com/prometheus/alertmanager/provider/mem"
 	"github.com/prometheus/alertmanager/silence"
 	"github.com/prometheus/alertmanager/template"
-	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/prometheus/alertmanager/types"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
@@ -121,8 +119,8 @@ type Alertmanager struct {
 	receivers []*notify.Receiver
 
 	// muteTimes is a map where the key is the name of the mute_time_interval
-	// and the value represents all configured time_interval(s)
-	muteTimes map[string][]timeinterval.TimeInterval
+	// and the value represents all configured time_interval(s) and date_range(s)
+	muteTimes map[string]apimodels.MuteTimeIntervalConfig
 
 	stageMetrics      *notify.Metrics
 	dispatcherMetrics *dispatch.DispatcherMetrics
@@ -350,10 +348,10 @@ func (am *Alertmanager) templateFromPaths(paths ...string) (*template.Template,
 	return tmpl, nil
 }
 
-func (am *Alertmanager) buildMuteTimesMap(muteTimeIntervals []config.MuteTimeInterval) map[string][]timeinterval.TimeInterval {
-	muteTimes := make(map[string][]timeinterval.TimeInterval, len(muteTimeIntervals))
+func (am *Alertmanager) buildMuteTimesMap(muteTimeIntervals []apimodels.MuteTimeIntervalConfig) map[string]apimodels.MuteTimeIntervalConfig {
+	muteTimes := make(map[string]apimodels.MuteTimeIntervalConfig, len(muteTimeIntervals))
 	for _, ti := range muteTimeIntervals {
-		muteTimes[ti.Name] = ti.TimeIntervals
+		muteTimes[ti.Name] = ti
 	}
 	return muteTimes
 }
@@ -421,7 +419,7 @@ func (am *Alertmanager) applyConfig(cfg *apimodels.PostableUserConfig, rawConfig
 
 	meshStage := notify.NewGossipSettleStage(am.peer)
 	inhibitionStage := notify.NewMuteStage(am.inhibitor)
-	timeMuteStage := notify.NewTimeMuteStage(am.muteTimes)
+	timeMuteStage := newTimeMuteStage(am.muteTimes)
 	silencingStage := notify.NewMuteStage(am.silencer)
 
 	am.route = dispatch.NewRoute(cfg.AlertmanagerConfig.Route.AsAMRoute(), nil)
@@ -483,7 +481,9 @@ func (am *Alertmanager) buildReceiverIntegrations(receiver *apimodels.PostableAp
 		if err != nil {
 			return nil, err
 		}
-		integrations = append(integrations, notify.NewIntegration(n, n, r.Type, i))
+		// Every attempt to deliver a notification is recorded, so that it can be inspected through the API.
+		rn := newDeliveryRecordingNotifier(n, am.Store, am.logger, am.orgID, receiver.Name, r, i)
+		integrations = append(integrations, notify.NewIntegration(rn, rn, r.Type, i))
 	}
 	return integrations, nil
 }
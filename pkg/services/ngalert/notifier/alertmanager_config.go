@@ -2,12 +2,17 @@ package notifier
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/util/cmputil"
 )
 
 type UnknownReceiverError struct {
@@ -36,7 +41,23 @@ func (moa *MultiOrgAlertmanager) GetAlertmanagerConfiguration(ctx context.Contex
 	if err != nil {
 		return definitions.GettableUserConfig{}, fmt.Errorf("failed to get latest configuration: %w", err)
 	}
-	cfg, err := Load([]byte(query.Result.AlertmanagerConfiguration))
+	result, err := moa.gettableUserConfigFromRaw(query.Result.AlertmanagerConfiguration)
+	if err != nil {
+		return definitions.GettableUserConfig{}, err
+	}
+
+	result, err = moa.mergeProvenance(ctx, result, org)
+	if err != nil {
+		return definitions.GettableUserConfig{}, err
+	}
+
+	return result, nil
+}
+
+// gettableUserConfigFromRaw converts a stored configuration to the representation returned by the API,
+// where secure settings are replaced by fields that indicate whether they are set.
+func (moa *MultiOrgAlertmanager) gettableUserConfigFromRaw(raw string) (definitions.GettableUserConfig, error) {
+	cfg, err := Load([]byte(raw))
 	if err != nil {
 		return definitions.GettableUserConfig{}, fmt.Errorf("failed to unmarshal alertmanager configuration: %w", err)
 	}
@@ -81,11 +102,6 @@ func (moa *MultiOrgAlertmanager) GetAlertmanagerConfiguration(ctx context.Contex
 		result.AlertmanagerConfig.Receivers = append(result.AlertmanagerConfig.Receivers, &gettableApiReceiver)
 	}
 
-	result, err = moa.mergeProvenance(ctx, result, org)
-	if err != nil {
-		return definitions.GettableUserConfig{}, err
-	}
-
 	return result, nil
 }
 
@@ -161,3 +177,128 @@ func (moa *MultiOrgAlertmanager) mergeProvenance(ctx context.Context, config def
 
 	return config, nil
 }
+
+// GetAlertmanagerConfigurationHistory returns the stored configurations of the organization, latest first.
+// If limit is positive, at most limit configurations are returned.
+func (moa *MultiOrgAlertmanager) GetAlertmanagerConfigurationHistory(ctx context.Context, org int64, limit int) (definitions.GettableHistoricUserConfigs, error) {
+	query := models.GetAlertmanagerConfigurationHistoryQuery{OrgID: org, Limit: limit}
+	if err := moa.configStore.GetAlertmanagerConfigurationHistory(ctx, &query); err != nil {
+		return nil, fmt.Errorf("failed to get configuration history: %w", err)
+	}
+
+	result := make(definitions.GettableHistoricUserConfigs, 0, len(query.Result))
+	for _, config := range query.Result {
+		cfg, err := moa.gettableUserConfigFromRaw(config.AlertmanagerConfiguration)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert configuration %d: %w", config.ID, err)
+		}
+		result = append(result, definitions.GettableHistoricUserConfig{
+			ID:                 config.ID,
+			CreatedAt:          time.Unix(config.CreatedAt, 0).UTC(),
+			Default:            config.Default,
+			TemplateFiles:      cfg.TemplateFiles,
+			AlertmanagerConfig: cfg.AlertmanagerConfig,
+		})
+	}
+	return result, nil
+}
+
+// GetHistoricalAlertmanagerConfiguration returns a stored configuration of the organization as it would be posted to the API.
+// It returns store.ErrNoAlertmanagerConfiguration if the organization does not have a configuration with this ID.
+func (moa *MultiOrgAlertmanager) GetHistoricalAlertmanagerConfiguration(ctx context.Context, org int64, id int64) (*definitions.PostableUserConfig, error) {
+	query := models.GetAlertmanagerConfigurationQuery{OrgID: org, ID: id}
+	if err := moa.configStore.GetAlertmanagerConfiguration(ctx, &query); err != nil {
+		return nil, fmt.Errorf("failed to get configuration %d: %w", id, err)
+	}
+	cfg, err := Load([]byte(query.Result.AlertmanagerConfiguration))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal alertmanager configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+// DiffAlertmanagerConfigurations compares the stored configuration with the given ID with another stored configuration,
+// or with the current configuration if compareTo is 0. Secure settings are only compared by whether they are set.
+func (moa *MultiOrgAlertmanager) DiffAlertmanagerConfigurations(ctx context.Context, org int64, id int64, compareTo int64) (cmputil.DiffReport, error) {
+	query := models.GetAlertmanagerConfigurationQuery{OrgID: org, ID: id}
+	if err := moa.configStore.GetAlertmanagerConfiguration(ctx, &query); err != nil {
+		return nil, fmt.Errorf("failed to get configuration %d: %w", id, err)
+	}
+	left := query.Result
+
+	var right *models.AlertConfiguration
+	if compareTo == 0 {
+		latest := models.GetLatestAlertmanagerConfigurationQuery{OrgID: org}
+		if err := moa.configStore.GetLatestAlertmanagerConfiguration(ctx, &latest); err != nil {
+			return nil, fmt.Errorf("failed to get latest configuration: %w", err)
+		}
+		right = latest.Result
+	} else {
+		other := models.GetAlertmanagerConfigurationQuery{OrgID: org, ID: compareTo}
+		if err := moa.configStore.GetAlertmanagerConfiguration(ctx, &other); err != nil {
+			return nil, fmt.Errorf("failed to get configuration %d: %w", compareTo, err)
+		}
+		right = other.Result
+	}
+
+	l, err := moa.comparableConfig(left)
+	if err != nil {
+		return nil, err
+	}
+	r, err := moa.comparableConfig(right)
+	if err != nil {
+		return nil, err
+	}
+	reporter := cmputil.DiffReporter{}
+	cmp.Equal(l, r, cmp.Reporter(&reporter))
+	return reporter.Diffs, nil
+}
+
+// comparableConfig returns the API representation of a stored configuration decoded into generic maps and slices,
+// so that it can be compared regardless of the unexported fields of the underlying Alertmanager types.
+func (moa *MultiOrgAlertmanager) comparableConfig(config *models.AlertConfiguration) (interface{}, error) {
+	cfg, err := moa.gettableUserConfigFromRaw(config.AlertmanagerConfiguration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert configuration %d: %w", config.ID, err)
+	}
+	raw, err := json.Marshal(struct {
+		TemplateFiles      map[string]string                     `json:"template_files"`
+		AlertmanagerConfig definitions.GettableApiAlertingConfig `json:"alertmanager_config"`
+	}{
+		TemplateFiles:      cfg.TemplateFiles,
+		AlertmanagerConfig: cfg.AlertmanagerConfig,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize configuration %d: %w", config.ID, err)
+	}
+	var result interface{}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("failed to deserialize configuration %d: %w", config.ID, err)
+	}
+	return result, nil
+}
+
+// ActivateHistoricalConfiguration saves the stored configuration with the given ID as the latest configuration of the
+// organization and applies it. The configuration is not saved if it fails to be applied.
+func (moa *MultiOrgAlertmanager) ActivateHistoricalConfiguration(ctx context.Context, org int64, id int64) error {
+	cfg, err := moa.GetHistoricalAlertmanagerConfiguration(ctx, org, id)
+	if err != nil {
+		return err
+	}
+
+	am, err := moa.AlertmanagerFor(org)
+	if err != nil {
+		// It's okay if the alertmanager isn't ready yet, we're changing its config anyway.
+		if !errors.Is(err, ErrAlertmanagerNotReady) {
+			return err
+		}
+	}
+
+	// The secure settings of a stored configuration are already encrypted.
+	if err := am.SaveAndApplyConfig(ctx, cfg); err != nil {
+		moa.logger.Error("unable to save and apply historical alertmanager configuration", "err", err, "org", org, "id", id)
+		return AlertmanagerConfigRejectedError{err}
+	}
+
+	return nil
+}
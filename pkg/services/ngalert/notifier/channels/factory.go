@@ -54,6 +54,7 @@ var receiverFactories = map[string]func(FactoryConfig) (NotificationChannel, err
 	"googlechat":              GoogleChatFactory,
 	"kafka":                   KafkaFactory,
 	"line":                    LineFactory,
+	"mqtt":                    MQTTFactory,
 	"opsgenie":                OpsgenieFactory,
 	"pagerduty":               PagerdutyFactory,
 	"pushover":                PushoverFactory,
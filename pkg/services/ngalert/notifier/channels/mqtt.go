@@ -0,0 +1,326 @@
+package channels
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	// MQTTMessageFormatJSON publishes the alerts as a JSON object, like the webhook contact point.
+	MQTTMessageFormatJSON = "json"
+	// MQTTMessageFormatText publishes the templated message as plain text.
+	MQTTMessageFormatText = "text"
+
+	// mqttTimeout is the maximum time to wait for the broker to accept the connection and the message.
+	mqttTimeout = 30 * time.Second
+)
+
+// MQTTNotifier is responsible for sending
+// alert notifications to an MQTT broker.
+type MQTTNotifier struct {
+	*Base
+	log       log.Logger
+	images    ImageStore
+	tmpl      *template.Template
+	orgID     int64
+	settings  mqttSettings
+	qos       byte
+	tlsConfig *tls.Config
+}
+
+type mqttSettings struct {
+	BrokerURL     string `json:"brokerUrl,omitempty" yaml:"brokerUrl,omitempty"`
+	ClientID      string `json:"clientId,omitempty" yaml:"clientId,omitempty"`
+	Topic         string `json:"topic,omitempty" yaml:"topic,omitempty"`
+	MessageFormat string `json:"messageFormat,omitempty" yaml:"messageFormat,omitempty"`
+	Message       string `json:"message,omitempty" yaml:"message,omitempty"`
+	Username      string `json:"username,omitempty" yaml:"username,omitempty"`
+	Password      string `json:"password,omitempty" yaml:"password,omitempty"`
+	// QoS is the quality of service of the published messages: 0, 1 or 2.
+	QoS    interface{} `json:"qos,omitempty" yaml:"qos,omitempty"`
+	Retain bool        `json:"retain,omitempty" yaml:"retain,omitempty"`
+
+	// TLS
+	InsecureSkipVerify   bool   `json:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty"`
+	TLSCACertificate     string `json:"tlsCACertificate,omitempty" yaml:"tlsCACertificate,omitempty"`
+	TLSClientCertificate string `json:"tlsClientCertificate,omitempty" yaml:"tlsClientCertificate,omitempty"`
+	TLSClientKey         string `json:"tlsClientKey,omitempty" yaml:"tlsClientKey,omitempty"`
+}
+
+func buildMQTTSettings(factoryConfig FactoryConfig) (mqttSettings, error) {
+	settings := mqttSettings{}
+	err := factoryConfig.Config.unmarshalSettings(&settings)
+	if err != nil {
+		return settings, fmt.Errorf("failed to unmarshal settings: %w", err)
+	}
+	if settings.BrokerURL == "" {
+		return settings, errors.New("required field 'brokerUrl' is not specified")
+	}
+	u, err := url.Parse(settings.BrokerURL)
+	if err != nil {
+		return settings, fmt.Errorf("invalid broker URL: %w", err)
+	}
+	switch u.Scheme {
+	case "tcp", "mqtt", "ssl", "tls", "mqtts", "ws", "wss":
+	default:
+		return settings, fmt.Errorf("unsupported broker URL scheme %q, must be one of tcp, mqtt, ssl, tls, mqtts, ws or wss", u.Scheme)
+	}
+	if settings.Topic == "" {
+		return settings, errors.New("required field 'topic' is not specified")
+	}
+	switch settings.MessageFormat {
+	case "":
+		settings.MessageFormat = MQTTMessageFormatJSON
+	case MQTTMessageFormatJSON, MQTTMessageFormatText:
+	default:
+		return settings, fmt.Errorf("invalid message format %q, must be %q or %q", settings.MessageFormat, MQTTMessageFormatJSON, MQTTMessageFormatText)
+	}
+	if settings.Message == "" {
+		settings.Message = DefaultMessageEmbed
+	}
+	settings.Password = factoryConfig.DecryptFunc(context.Background(), factoryConfig.Config.SecureSettings, "password", settings.Password)
+	settings.TLSClientKey = factoryConfig.DecryptFunc(context.Background(), factoryConfig.Config.SecureSettings, "tlsClientKey", settings.TLSClientKey)
+	return settings, nil
+}
+
+func MQTTFactory(fc FactoryConfig) (NotificationChannel, error) {
+	notifier, err := buildMQTTNotifier(fc)
+	if err != nil {
+		return nil, receiverInitError{
+			Reason: err.Error(),
+			Cfg:    *fc.Config,
+		}
+	}
+	return notifier, nil
+}
+
+// buildMQTTNotifier is the constructor for
+// the MQTT notifier.
+func buildMQTTNotifier(factoryConfig FactoryConfig) (*MQTTNotifier, error) {
+	settings, err := buildMQTTSettings(factoryConfig)
+	if err != nil {
+		return nil, err
+	}
+	qos, err := parseMQTTQoS(settings.QoS)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := settings.buildTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return &MQTTNotifier{
+		Base: NewBase(&models.AlertNotification{
+			Uid:                   factoryConfig.Config.UID,
+			Name:                  factoryConfig.Config.Name,
+			Type:                  factoryConfig.Config.Type,
+			DisableResolveMessage: factoryConfig.Config.DisableResolveMessage,
+			Settings:              factoryConfig.Config.Settings,
+		}),
+		orgID:     factoryConfig.Config.OrgID,
+		log:       log.New("alerting.notifier.mqtt"),
+		images:    factoryConfig.ImageStore,
+		tmpl:      factoryConfig.Template,
+		settings:  settings,
+		qos:       qos,
+		tlsConfig: tlsConfig,
+	}, nil
+}
+
+func parseMQTTQoS(value interface{}) (byte, error) {
+	var qos int
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		qos = int(v)
+		if float64(qos) != v {
+			return 0, fmt.Errorf("invalid QoS %v, must be 0, 1 or 2", v)
+		}
+	case string:
+		if v == "" {
+			return 0, nil
+		}
+		var err error
+		qos, err = strconv.Atoi(v)
+		if err != nil {
+			return 0, fmt.Errorf("invalid QoS %q, must be 0, 1 or 2", v)
+		}
+	default:
+		return 0, fmt.Errorf("unexpected type of QoS %T, must be a number", value)
+	}
+	if qos < 0 || qos > 2 {
+		return 0, fmt.Errorf("invalid QoS %d, must be 0, 1 or 2", qos)
+	}
+	return byte(qos), nil
+}
+
+// buildTLSConfig returns the TLS configuration to connect to the broker, or nil if no TLS settings are set.
+// Brokers with a ssl, tls, mqtts or wss URL are still connected to with TLS using the system's root CAs.
+func (s mqttSettings) buildTLSConfig() (*tls.Config, error) {
+	if !s.InsecureSkipVerify && s.TLSCACertificate == "" && s.TLSClientCertificate == "" && s.TLSClientKey == "" {
+		return nil, nil
+	}
+	cfg := &tls.Config{
+		InsecureSkipVerify: s.InsecureSkipVerify,
+	}
+	if s.TLSCACertificate != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(s.TLSCACertificate)) {
+			return nil, errors.New("failed to parse the TLS CA certificate")
+		}
+		cfg.RootCAs = pool
+	}
+	if s.TLSClientCertificate != "" || s.TLSClientKey != "" {
+		if s.TLSClientCertificate == "" || s.TLSClientKey == "" {
+			return nil, errors.New("both the TLS client certificate and key must be specified")
+		}
+		cert, err := tls.X509KeyPair([]byte(s.TLSClientCertificate), []byte(s.TLSClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the TLS client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// MQTTMessage defines the JSON object published to the MQTT topic.
+type MQTTMessage struct {
+	*ExtendedData
+
+	// The protocol version.
+	Version  string `json:"version"`
+	GroupKey string `json:"groupKey"`
+	OrgID    int64  `json:"orgId"`
+	Title    string `json:"title"`
+	State    string `json:"state"`
+	Message  string `json:"message"`
+}
+
+// Notify implements the Notifier interface.
+func (n *MQTTNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	groupKey, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var tmplErr error
+	tmpl, data := TmplText(ctx, n.tmpl, as, n.log, &tmplErr)
+
+	var payload []byte
+	switch n.settings.MessageFormat {
+	case MQTTMessageFormatText:
+		payload = []byte(tmpl(n.settings.Message))
+	default:
+		// Augment our Alert data with ImageURLs if available.
+		_ = withStoredImages(ctx, n.log, n.images,
+			func(index int, image ngmodels.Image) error {
+				if len(image.URL) != 0 {
+					data.Alerts[index].ImageURL = image.URL
+				}
+				return nil
+			},
+			as...)
+
+		msg := &MQTTMessage{
+			Version:      "1",
+			ExtendedData: data,
+			GroupKey:     groupKey.String(),
+			OrgID:        n.orgID,
+			Title:        tmpl(DefaultMessageTitleEmbed),
+			Message:      tmpl(n.settings.Message),
+		}
+		if types.Alerts(as...).Status() == model.AlertFiring {
+			msg.State = string(models.AlertStateAlerting)
+		} else {
+			msg.State = string(models.AlertStateOK)
+		}
+		payload, err = json.Marshal(msg)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	topic := tmpl(n.settings.Topic)
+	if tmplErr != nil {
+		n.log.Warn("failed to template MQTT message", "err", tmplErr.Error())
+	}
+	if topic == "" || strings.ContainsAny(topic, "+#") {
+		return false, fmt.Errorf("invalid MQTT topic %q, it must not be empty or contain wildcards", topic)
+	}
+
+	if err := n.publish(ctx, topic, payload); err != nil {
+		n.log.Error("failed to publish MQTT message", "err", err, "broker", n.settings.BrokerURL, "topic", topic)
+		return false, err
+	}
+
+	return true, nil
+}
+
+// publish connects to the broker, publishes the payload and disconnects.
+func (n *MQTTNotifier) publish(ctx context.Context, topic string, payload []byte) error {
+	clientID := n.settings.ClientID
+	if clientID == "" {
+		// The client ID must be unique per broker, otherwise the broker disconnects the client that was connected first.
+		clientID = "grafana_" + util.GenerateShortUID()
+	}
+	opts := mqtt.NewClientOptions().
+		AddBroker(n.settings.BrokerURL).
+		SetClientID(clientID).
+		SetUsername(n.settings.Username).
+		SetPassword(n.settings.Password).
+		SetCleanSession(true).
+		SetAutoReconnect(false).
+		SetConnectTimeout(mqttTimeout)
+	if n.tlsConfig != nil {
+		opts.SetTLSConfig(n.tlsConfig)
+	}
+
+	client := mqtt.NewClient(opts)
+	if err := waitForMQTTToken(ctx, client.Connect()); err != nil {
+		return fmt.Errorf("failed to connect to the MQTT broker: %w", err)
+	}
+	defer client.Disconnect(250)
+
+	if err := waitForMQTTToken(ctx, client.Publish(topic, n.qos, n.settings.Retain, payload)); err != nil {
+		return fmt.Errorf("failed to publish the MQTT message: %w", err)
+	}
+	return nil
+}
+
+func waitForMQTTToken(ctx context.Context, token mqtt.Token) error {
+	timer := time.NewTimer(mqttTimeout)
+	defer timer.Stop()
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-timer.C:
+		return errors.New("timed out waiting for the MQTT broker")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (n *MQTTNotifier) SendResolved() bool {
+	return !n.GetDisableResolveMessage()
+}
@@ -0,0 +1,442 @@
+package channels
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
+)
+
+func TestMQTTNotifier(t *testing.T) {
+	tmpl := templateForTests(t)
+
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	alerts := []*types.Alert{
+		{
+			Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+				Annotations: model.LabelSet{"ann1": "annv1", "__dashboardUid__": "abcd", "__panelId__": "efgh"},
+			},
+		},
+	}
+	expMessage := "**Firing**\n\nValue: [no value]\nLabels:\n - alertname = alert1\n - lbl1 = val1\nAnnotations:\n - ann1 = annv1\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matcher=alertname%3Dalert1&matcher=lbl1%3Dval1\nDashboard: http://localhost/d/abcd\nPanel: http://localhost/d/abcd?viewPanel=efgh\n"
+
+	cases := []struct {
+		name           string
+		settings       string
+		brokerUsername string
+		brokerPassword string
+
+		expTopic    string
+		expQoS      byte
+		expRetain   bool
+		expClientID string
+		expUsername string
+		expPayload  func(t *testing.T, payload []byte)
+		expMsgError string
+	}{
+		{
+			name:     "Default config publishes JSON message",
+			settings: `{"topic": "grafana/alerts"}`,
+			expTopic: "grafana/alerts",
+			expPayload: func(t *testing.T, payload []byte) {
+				expMsg := &MQTTMessage{
+					ExtendedData: &ExtendedData{
+						Receiver: "my_receiver",
+						Status:   "firing",
+						Alerts: ExtendedAlerts{
+							{
+								Status: "firing",
+								Labels: template.KV{
+									"alertname": "alert1",
+									"lbl1":      "val1",
+								},
+								Annotations: template.KV{
+									"ann1": "annv1",
+								},
+								Fingerprint:  "fac0861a85de433a",
+								DashboardURL: "http://localhost/d/abcd",
+								PanelURL:     "http://localhost/d/abcd?viewPanel=efgh",
+								SilenceURL:   "http://localhost/alerting/silence/new?alertmanager=grafana&matcher=alertname%3Dalert1&matcher=lbl1%3Dval1",
+							},
+						},
+						GroupLabels: template.KV{
+							"alertname": "",
+						},
+						CommonLabels: template.KV{
+							"alertname": "alert1",
+							"lbl1":      "val1",
+						},
+						CommonAnnotations: template.KV{
+							"ann1": "annv1",
+						},
+						ExternalURL: "http://localhost",
+					},
+					Version:  "1",
+					GroupKey: "alertname",
+					OrgID:    1,
+					Title:    "[FIRING:1]  (val1)",
+					State:    "alerting",
+					Message:  expMessage,
+				}
+				expBody, err := json.Marshal(expMsg)
+				require.NoError(t, err)
+				require.JSONEq(t, string(expBody), string(payload))
+			},
+		},
+		{
+			name: "Custom config publishes templated text message",
+			settings: `{
+				"topic": "plant/{{ .CommonLabels.lbl1 }}/alerts",
+				"messageFormat": "text",
+				"message": "{{ len .Alerts.Firing }} firing",
+				"clientId": "grafana-plant",
+				"username": "user1",
+				"password": "mysecret",
+				"qos": "1",
+				"retain": true
+			}`,
+			brokerUsername: "user1",
+			brokerPassword: "mysecret",
+			expTopic:       "plant/val1/alerts",
+			expQoS:         1,
+			expRetain:      true,
+			expClientID:    "grafana-plant",
+			expUsername:    "user1",
+			expPayload: func(t *testing.T, payload []byte) {
+				require.Equal(t, "1 firing", string(payload))
+			},
+		},
+		{
+			name:     "Exactly once QoS",
+			settings: `{"topic": "grafana/alerts", "messageFormat": "text", "qos": 2}`,
+			expTopic: "grafana/alerts",
+			expQoS:   2,
+			expPayload: func(t *testing.T, payload []byte) {
+				require.Equal(t, expMessage, string(payload))
+			},
+		},
+		{
+			name:           "Wrong password fails",
+			settings:       `{"topic": "grafana/alerts", "username": "user1", "password": "wrong"}`,
+			brokerUsername: "user1",
+			brokerPassword: "mysecret",
+			expMsgError:    "failed to connect to the MQTT broker: not Authorized",
+		},
+		{
+			name:        "Topic with wildcard fails",
+			settings:    `{"topic": "grafana/#"}`,
+			expMsgError: `invalid MQTT topic "grafana/#", it must not be empty or contain wildcards`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			broker := newTestMQTTBroker(t, nil)
+			broker.username, broker.password = c.brokerUsername, c.brokerPassword
+
+			settingsJSON, err := simplejson.NewJson([]byte(c.settings))
+			require.NoError(t, err)
+			settingsJSON.Set("brokerUrl", "tcp://"+broker.listener.Addr().String())
+
+			notifier, err := buildMQTTNotifier(mqttFactoryConfigForTests(t, tmpl, settingsJSON))
+			require.NoError(t, err)
+
+			ctx := notify.WithGroupKey(context.Background(), "alertname")
+			ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": ""})
+			ctx = notify.WithReceiverName(ctx, "my_receiver")
+			ok, err := notifier.Notify(ctx, alerts...)
+			if c.expMsgError != "" {
+				require.False(t, ok)
+				require.EqualError(t, err, c.expMsgError)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			connect := <-broker.connects
+			if c.expClientID != "" {
+				require.Equal(t, c.expClientID, connect.ClientIdentifier)
+			} else {
+				require.True(t, strings.HasPrefix(connect.ClientIdentifier, "grafana_"))
+			}
+			require.Equal(t, c.expUsername, connect.Username)
+
+			msg := <-broker.messages
+			require.Equal(t, c.expTopic, msg.TopicName)
+			require.Equal(t, c.expQoS, msg.Qos)
+			require.Equal(t, c.expRetain, msg.Retain)
+			c.expPayload(t, msg.Payload)
+		})
+	}
+}
+
+func TestMQTTNotifier_TLS(t *testing.T) {
+	tmpl := templateForTests(t)
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	caCert, serverCert := generateTestCertificates(t)
+	broker := newTestMQTTBroker(t, &tls.Config{Certificates: []tls.Certificate{serverCert}})
+	alert := &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "alert1"}}}
+	ctx := notify.WithGroupKey(context.Background(), "alertname")
+
+	t.Run("should publish to a broker with a certificate signed by the CA", func(t *testing.T) {
+		settingsJSON := simplejson.NewFromAny(map[string]interface{}{
+			"brokerUrl":        "ssl://" + broker.listener.Addr().String(),
+			"topic":            "grafana/alerts",
+			"tlsCACertificate": caCert,
+		})
+		notifier, err := buildMQTTNotifier(mqttFactoryConfigForTests(t, tmpl, settingsJSON))
+		require.NoError(t, err)
+
+		ok, err := notifier.Notify(ctx, alert)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, "grafana/alerts", (<-broker.messages).TopicName)
+	})
+
+	t.Run("should fail to publish to a broker with an unknown certificate", func(t *testing.T) {
+		settingsJSON := simplejson.NewFromAny(map[string]interface{}{
+			"brokerUrl": "ssl://" + broker.listener.Addr().String(),
+			"topic":     "grafana/alerts",
+		})
+		notifier, err := buildMQTTNotifier(mqttFactoryConfigForTests(t, tmpl, settingsJSON))
+		require.NoError(t, err)
+
+		ok, err := notifier.Notify(ctx, alert)
+		require.False(t, ok)
+		require.ErrorContains(t, err, "failed to connect to the MQTT broker")
+	})
+
+	t.Run("should publish when skipping TLS verification", func(t *testing.T) {
+		settingsJSON := simplejson.NewFromAny(map[string]interface{}{
+			"brokerUrl":          "ssl://" + broker.listener.Addr().String(),
+			"topic":              "grafana/alerts",
+			"insecureSkipVerify": true,
+		})
+		notifier, err := buildMQTTNotifier(mqttFactoryConfigForTests(t, tmpl, settingsJSON))
+		require.NoError(t, err)
+
+		ok, err := notifier.Notify(ctx, alert)
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+}
+
+func TestBuildMQTTNotifier(t *testing.T) {
+	tmpl := templateForTests(t)
+
+	cases := []struct {
+		name         string
+		settings     string
+		expInitError string
+	}{
+		{
+			name:         "Missing broker URL",
+			settings:     `{"topic": "grafana/alerts"}`,
+			expInitError: "required field 'brokerUrl' is not specified",
+		},
+		{
+			name:         "Unsupported broker URL scheme",
+			settings:     `{"brokerUrl": "http://localhost:1883", "topic": "grafana/alerts"}`,
+			expInitError: `unsupported broker URL scheme "http", must be one of tcp, mqtt, ssl, tls, mqtts, ws or wss`,
+		},
+		{
+			name:         "Missing topic",
+			settings:     `{"brokerUrl": "tcp://localhost:1883"}`,
+			expInitError: "required field 'topic' is not specified",
+		},
+		{
+			name:         "Invalid message format",
+			settings:     `{"brokerUrl": "tcp://localhost:1883", "topic": "grafana/alerts", "messageFormat": "xml"}`,
+			expInitError: `invalid message format "xml", must be "json" or "text"`,
+		},
+		{
+			name:         "Invalid QoS",
+			settings:     `{"brokerUrl": "tcp://localhost:1883", "topic": "grafana/alerts", "qos": "3"}`,
+			expInitError: "invalid QoS 3, must be 0, 1 or 2",
+		},
+		{
+			name:         "Invalid CA certificate",
+			settings:     `{"brokerUrl": "ssl://localhost:8883", "topic": "grafana/alerts", "tlsCACertificate": "not a certificate"}`,
+			expInitError: "failed to parse the TLS CA certificate",
+		},
+		{
+			name:         "Client certificate without key",
+			settings:     `{"brokerUrl": "ssl://localhost:8883", "topic": "grafana/alerts", "tlsClientCertificate": "certificate"}`,
+			expInitError: "both the TLS client certificate and key must be specified",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			settingsJSON, err := simplejson.NewJson([]byte(c.settings))
+			require.NoError(t, err)
+
+			_, err = buildMQTTNotifier(mqttFactoryConfigForTests(t, tmpl, settingsJSON))
+			require.EqualError(t, err, c.expInitError)
+		})
+	}
+}
+
+func mqttFactoryConfigForTests(t *testing.T, tmpl *template.Template, settings *simplejson.Json) FactoryConfig {
+	t.Helper()
+	secretsService := secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore())
+	return FactoryConfig{
+		Config: &NotificationChannelConfig{
+			OrgID:          1,
+			Name:           "mqtt_testing",
+			Type:           "mqtt",
+			Settings:       settings,
+			SecureSettings: make(map[string][]byte),
+		},
+		DecryptFunc: secretsService.GetDecryptedValue,
+		ImageStore:  &UnavailableImageStore{},
+		Template:    tmpl,
+	}
+}
+
+// testMQTTBroker is a minimal in-process MQTT broker that accepts connections and records the published messages.
+type testMQTTBroker struct {
+	listener           net.Listener
+	username, password string
+	connects           chan *packets.ConnectPacket
+	messages           chan *packets.PublishPacket
+}
+
+func newTestMQTTBroker(t *testing.T, tlsConfig *tls.Config) *testMQTTBroker {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+	b := &testMQTTBroker{
+		listener: listener,
+		connects: make(chan *packets.ConnectPacket, 10),
+		messages: make(chan *packets.PublishPacket, 10),
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go b.handle(conn)
+		}
+	}()
+	return b
+}
+
+func (b *testMQTTBroker) handle(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+	for {
+		packet, err := packets.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+		var reply packets.ControlPacket
+		switch p := packet.(type) {
+		case *packets.ConnectPacket:
+			connack := packets.NewControlPacket(packets.Connack).(*packets.ConnackPacket)
+			if b.password != "" && (p.Username != b.username || string(p.Password) != b.password) {
+				connack.ReturnCode = packets.ErrRefusedNotAuthorised
+			} else {
+				b.connects <- p
+			}
+			reply = connack
+		case *packets.PublishPacket:
+			b.messages <- p
+			switch p.Qos {
+			case 1:
+				puback := packets.NewControlPacket(packets.Puback).(*packets.PubackPacket)
+				puback.MessageID = p.MessageID
+				reply = puback
+			case 2:
+				pubrec := packets.NewControlPacket(packets.Pubrec).(*packets.PubrecPacket)
+				pubrec.MessageID = p.MessageID
+				reply = pubrec
+			}
+		case *packets.PubrelPacket:
+			pubcomp := packets.NewControlPacket(packets.Pubcomp).(*packets.PubcompPacket)
+			pubcomp.MessageID = p.MessageID
+			reply = pubcomp
+		case *packets.PingreqPacket:
+			reply = packets.NewControlPacket(packets.Pingresp)
+		case *packets.DisconnectPacket:
+			return
+		}
+		if reply != nil {
+			if err := reply.Write(conn); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// generateTestCertificates returns a PEM encoded CA certificate and a server certificate for 127.0.0.1 signed by the CA.
+func generateTestCertificates(t *testing.T) (string, tls.Certificate) {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+
+	serverKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serverTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	serverDER, err := x509.CreateCertificate(rand.Reader, serverTemplate, caTemplate, &serverKey.PublicKey, caKey)
+	require.NoError(t, err)
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})
+	return string(caPEM), tls.Certificate{
+		Certificate: [][]byte{serverDER},
+		PrivateKey:  serverKey,
+	}
+}
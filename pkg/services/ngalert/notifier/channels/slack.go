@@ -218,6 +218,7 @@ var sendSlackRequest = func(request *http.Request, logger log.Logger) (retErr er
 			logger.Warn("failed to close response body", "err", err)
 		}
 	}()
+	notifications.RecordResponseStatusCode(request.Context(), resp.StatusCode)
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
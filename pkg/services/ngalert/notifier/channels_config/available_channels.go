@@ -164,6 +164,126 @@ func GetAvailableNotifiers() []*NotifierPlugin {
 				},
 			},
 		},
+		{
+			Type:        "mqtt",
+			Name:        "MQTT",
+			Description: "Publishes notifications to an MQTT broker",
+			Heading:     "MQTT settings",
+			Options: []NotifierOption{
+				{
+					Label:        "Broker URL",
+					Description:  "The URL of the MQTT broker, with the scheme tcp, ssl, ws or wss.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "tcp://localhost:1883",
+					PropertyName: "brokerUrl",
+					Required:     true,
+				},
+				{
+					Label:        "Topic",
+					Description:  "The topic to publish the notifications to. You can use template variables.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "grafana/alerts",
+					PropertyName: "topic",
+					Required:     true,
+				},
+				{
+					Label:   "Message format",
+					Element: ElementTypeSelect,
+					SelectOptions: []SelectOption{
+						{
+							Value: channels.MQTTMessageFormatJSON,
+							Label: "JSON",
+						},
+						{
+							Value: channels.MQTTMessageFormatText,
+							Label: "Text",
+						},
+					},
+					Description:  "JSON publishes the alerts like the webhook contact point, text publishes only the message.",
+					PropertyName: "messageFormat",
+				},
+				{
+					Label:        "Message",
+					Description:  "Custom message. You can use template variables.",
+					Element:      ElementTypeTextArea,
+					Placeholder:  channels.DefaultMessageEmbed,
+					PropertyName: "message",
+				},
+				{
+					Label:        "Client ID",
+					Description:  "The client ID to connect to the broker with. A random client ID is used if not set.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "clientId",
+				},
+				{
+					Label:        "Username",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "username",
+				},
+				{
+					Label:        "Password",
+					Element:      ElementTypeInput,
+					InputType:    InputTypePassword,
+					PropertyName: "password",
+					Secure:       true,
+				},
+				{
+					Label:   "QoS",
+					Element: ElementTypeSelect,
+					SelectOptions: []SelectOption{
+						{
+							Value: "0",
+							Label: "At most once (0)",
+						},
+						{
+							Value: "1",
+							Label: "At least once (1)",
+						},
+						{
+							Value: "2",
+							Label: "Exactly once (2)",
+						},
+					},
+					Description:  "The quality of service of the published messages.",
+					PropertyName: "qos",
+				},
+				{
+					Label:        "Retain",
+					Element:      ElementTypeCheckbox,
+					Description:  "Retain the last message on the topic, so that clients that subscribe later receive it.",
+					PropertyName: "retain",
+				},
+				{
+					Label:        "Skip TLS verification",
+					Element:      ElementTypeCheckbox,
+					Description:  "Do not verify the certificate of the broker.",
+					PropertyName: "insecureSkipVerify",
+				},
+				{
+					Label:        "TLS CA certificate",
+					Description:  "The PEM encoded CA certificate to verify the certificate of the broker with.",
+					Element:      ElementTypeTextArea,
+					PropertyName: "tlsCACertificate",
+				},
+				{
+					Label:        "TLS client certificate",
+					Description:  "The PEM encoded client certificate to authenticate to the broker with.",
+					Element:      ElementTypeTextArea,
+					PropertyName: "tlsClientCertificate",
+				},
+				{
+					Label:        "TLS client key",
+					Description:  "The PEM encoded key of the client certificate.",
+					Element:      ElementTypeTextArea,
+					PropertyName: "tlsClientKey",
+					Secure:       true,
+				},
+			},
+		},
 		{
 			Type:        "email",
 			Name:        "Email",
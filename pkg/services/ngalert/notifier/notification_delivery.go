@@ -0,0 +1,132 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels"
+	"github.com/grafana/grafana/pkg/services/notifications"
+)
+
+// notificationDeliveryTimeout is the maximum time to save a notification delivery. The delivery is saved with
+// its own timeout because the context of the notification can be already canceled when it failed.
+const notificationDeliveryTimeout = 10 * time.Second
+
+// NotificationDeliveryStore saves the notification deliveries.
+type NotificationDeliveryStore interface {
+	SaveNotificationDelivery(ctx context.Context, delivery *ngmodels.NotificationDelivery) error
+}
+
+// deliveryRecordingNotifier is a notification channel that records every attempt of the wrapped channel to
+// deliver a notification.
+type deliveryRecordingNotifier struct {
+	channels.NotificationChannel
+
+	logger          log.Logger
+	store           NotificationDeliveryStore
+	orgID           int64
+	receiver        string
+	integrationUID  string
+	integrationName string
+	integrationType string
+	index           int
+	now             func() time.Time
+}
+
+func newDeliveryRecordingNotifier(n channels.NotificationChannel, store NotificationDeliveryStore, logger log.Logger, orgID int64, receiver string, r *apimodels.PostableGrafanaReceiver, index int) *deliveryRecordingNotifier {
+	return &deliveryRecordingNotifier{
+		NotificationChannel: n,
+		logger:              logger,
+		store:               store,
+		orgID:               orgID,
+		receiver:            receiver,
+		integrationUID:      r.UID,
+		integrationName:     r.Name,
+		integrationType:     r.Type,
+		index:               index,
+		now:                 time.Now,
+	}
+}
+
+// Notify implements notify.Notifier. It notifies the wrapped channel and saves the outcome.
+func (n *deliveryRecordingNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	var mtx sync.Mutex
+	var statusCode int
+	ctx = notifications.WithResponseStatusCodeRecorder(ctx, func(code int) {
+		// Channels that send several requests, such as Telegram for images, record the status code of the last one.
+		mtx.Lock()
+		defer mtx.Unlock()
+		statusCode = code
+	})
+
+	start := n.now()
+	retry, err := n.NotificationChannel.Notify(ctx, alerts...)
+	duration := n.now().Sub(start)
+
+	delivery := &ngmodels.NotificationDelivery{
+		OrgID:            n.orgID,
+		Receiver:         n.receiver,
+		IntegrationUID:   n.integrationUID,
+		IntegrationName:  n.integrationName,
+		IntegrationType:  n.integrationType,
+		IntegrationIndex: n.index,
+		Alerts:           len(alerts),
+		Status:           ngmodels.NotificationDeliverySuccess,
+		DurationMs:       duration.Milliseconds(),
+		CreatedAt:        start.UTC(),
+	}
+	if groupKey, keyErr := notify.ExtractGroupKey(ctx); keyErr == nil {
+		delivery.GroupKey = groupKey.String()
+	}
+	mtx.Lock()
+	delivery.StatusCode = statusCode
+	mtx.Unlock()
+	if err != nil {
+		delivery.Status = ngmodels.NotificationDeliveryFailure
+		delivery.Error = err.Error()
+		delivery.Retry = retry
+	}
+
+	saveCtx, cancel := context.WithTimeout(context.Background(), notificationDeliveryTimeout)
+	defer cancel()
+	if saveErr := n.store.SaveNotificationDelivery(saveCtx, delivery); saveErr != nil {
+		n.logger.Warn("failed to save notification delivery", "receiver", n.receiver, "integration", n.integrationType, "err", saveErr)
+	}
+
+	return retry, err
+}
+
+// GetNotificationDeliveries returns the recorded notification deliveries of an organization that match the query, latest first.
+func (moa *MultiOrgAlertmanager) GetNotificationDeliveries(ctx context.Context, query *ngmodels.GetNotificationDeliveriesQuery) (apimodels.GettableNotificationDeliveries, error) {
+	if err := moa.configStore.GetNotificationDeliveries(ctx, query); err != nil {
+		return nil, fmt.Errorf("failed to get notification deliveries: %w", err)
+	}
+	result := make(apimodels.GettableNotificationDeliveries, 0, len(query.Result))
+	for _, d := range query.Result {
+		result = append(result, apimodels.GettableNotificationDelivery{
+			ID:               d.ID,
+			Timestamp:        d.CreatedAt,
+			Receiver:         d.Receiver,
+			IntegrationUID:   d.IntegrationUID,
+			IntegrationName:  d.IntegrationName,
+			IntegrationType:  d.IntegrationType,
+			IntegrationIndex: d.IntegrationIndex,
+			GroupKey:         d.GroupKey,
+			Alerts:           d.Alerts,
+			Status:           string(d.Status),
+			StatusCode:       d.StatusCode,
+			Error:            d.Error,
+			Retry:            d.Retry,
+			DurationMs:       d.DurationMs,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,114 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/notifications"
+)
+
+type fakeNotificationChannel struct {
+	statusCode int
+	retry      bool
+	err        error
+}
+
+func (f *fakeNotificationChannel) Notify(ctx context.Context, _ ...*types.Alert) (bool, error) {
+	if f.statusCode != 0 {
+		notifications.RecordResponseStatusCode(ctx, f.statusCode)
+	}
+	return f.retry, f.err
+}
+
+func (f *fakeNotificationChannel) SendResolved() bool {
+	return true
+}
+
+func TestDeliveryRecordingNotifier(t *testing.T) {
+	receiver := &apimodels.PostableGrafanaReceiver{UID: "uid-1", Name: "pager", Type: "pagerduty"}
+	alerts := []*types.Alert{
+		{Alert: model.Alert{Labels: model.LabelSet{"alertname": "a"}}},
+		{Alert: model.Alert{Labels: model.LabelSet{"alertname": "b"}}},
+	}
+	ctx := notify.WithGroupKey(context.Background(), "{}:{alertname=\"a\"}")
+
+	newNotifier := func(t *testing.T, channel *fakeNotificationChannel) (*deliveryRecordingNotifier, *FakeConfigStore) {
+		configStore := NewFakeConfigStore(t, map[int64]*ngmodels.AlertConfiguration{})
+		n := newDeliveryRecordingNotifier(channel, &configStore, log.NewNopLogger(), 1, "ops", receiver, 2)
+		start := time.Date(2022, 10, 1, 3, 12, 0, 0, time.UTC)
+		calls := 0
+		n.now = func() time.Time {
+			calls++
+			if calls == 1 {
+				return start
+			}
+			return start.Add(1500 * time.Millisecond)
+		}
+		return n, &configStore
+	}
+
+	t.Run("should record a successful delivery", func(t *testing.T) {
+		n, configStore := newNotifier(t, &fakeNotificationChannel{statusCode: 202})
+
+		retry, err := n.Notify(ctx, alerts...)
+		require.NoError(t, err)
+		require.False(t, retry)
+
+		query := &ngmodels.GetNotificationDeliveriesQuery{OrgID: 1}
+		require.NoError(t, configStore.GetNotificationDeliveries(context.Background(), query))
+		require.Equal(t, []*ngmodels.NotificationDelivery{{
+			ID:               1,
+			OrgID:            1,
+			Receiver:         "ops",
+			IntegrationUID:   "uid-1",
+			IntegrationName:  "pager",
+			IntegrationType:  "pagerduty",
+			IntegrationIndex: 2,
+			GroupKey:         "{}:{alertname=\"a\"}",
+			Alerts:           2,
+			Status:           ngmodels.NotificationDeliverySuccess,
+			StatusCode:       202,
+			DurationMs:       1500,
+			CreatedAt:        time.Date(2022, 10, 1, 3, 12, 0, 0, time.UTC),
+		}}, query.Result)
+	})
+
+	t.Run("should record a failed delivery and return the error of the channel", func(t *testing.T) {
+		n, configStore := newNotifier(t, &fakeNotificationChannel{statusCode: 503, retry: true, err: errors.New("service unavailable")})
+
+		retry, err := n.Notify(ctx, alerts...)
+		require.EqualError(t, err, "service unavailable")
+		require.True(t, retry)
+
+		query := &ngmodels.GetNotificationDeliveriesQuery{OrgID: 1}
+		require.NoError(t, configStore.GetNotificationDeliveries(context.Background(), query))
+		require.Len(t, query.Result, 1)
+		require.Equal(t, ngmodels.NotificationDeliveryFailure, query.Result[0].Status)
+		require.Equal(t, 503, query.Result[0].StatusCode)
+		require.Equal(t, "service unavailable", query.Result[0].Error)
+		require.True(t, query.Result[0].Retry)
+	})
+
+	t.Run("should record a delivery without a status code", func(t *testing.T) {
+		n, configStore := newNotifier(t, &fakeNotificationChannel{err: context.DeadlineExceeded})
+
+		_, err := n.Notify(ctx, alerts...)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		query := &ngmodels.GetNotificationDeliveriesQuery{OrgID: 1}
+		require.NoError(t, configStore.GetNotificationDeliveries(context.Background(), query))
+		require.Len(t, query.Result, 1)
+		require.Equal(t, 0, query.Result[0].StatusCode)
+		require.Equal(t, context.DeadlineExceeded.Error(), query.Result[0].Error)
+	})
+}
@@ -0,0 +1,226 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels"
+)
+
+var (
+	ErrUnknownIntegrationType = errors.New("unknown integration type")
+
+	// templateErrorRegexp matches the name of the template and the line of errors of text/template, such as
+	// "template: slack.tmpl:3: unexpected EOF" or "template: slack.tmpl:3:12: executing ...".
+	templateErrorRegexp = regexp.MustCompile(`^template: ([^:]*):(\d+)(?::\d+)?: `)
+	// templateDefineRegexp matches templates that define at least one named template.
+	templateDefineRegexp = regexp.MustCompile(`\{\{-?\s*define`)
+)
+
+// templatedSettings are the templated settings of the contact point types and their default templates.
+var templatedSettings = map[string]map[string]string{
+	"dingding":   {"message": channels.DefaultMessageEmbed},
+	"discord":    {"message": channels.DefaultMessageEmbed},
+	"email":      {"subject": channels.DefaultMessageTitleEmbed},
+	"googlechat": {"message": channels.DefaultMessageEmbed},
+	"mqtt":       {"message": channels.DefaultMessageEmbed},
+	"opsgenie":   {"message": `{{ template "default.title" . }}`},
+	"pagerduty":  {"summary": channels.DefaultMessageTitleEmbed},
+	"pushover":   {"message": channels.DefaultMessageEmbed},
+	"sensugo":    {"message": channels.DefaultMessageEmbed},
+	"slack":      {"title": channels.DefaultMessageTitleEmbed, "text": channels.DefaultMessageEmbed},
+	"teams":      {"title": channels.DefaultMessageTitleEmbed, "message": `{{ template "teams.default.message" .}}`},
+	"telegram":   {"message": channels.DefaultMessageEmbed},
+	"wecom":      {"title": channels.DefaultMessageTitleEmbed, "message": channels.DefaultMessageEmbed},
+}
+
+// TestTemplate renders the templated settings of contact point types with the templates of the Alertmanager, where
+// the tested template replaces the template with the same name. Errors of the template are returned in the results.
+func (am *Alertmanager) TestTemplate(ctx context.Context, c apimodels.TestTemplatesConfigBodyParams) (*apimodels.TestTemplatesResults, error) {
+	integrations, err := templateTestIntegrations(c.Integrations)
+	if err != nil {
+		return nil, err
+	}
+
+	am.reloadConfigMtx.RLock()
+	if !am.ready() {
+		am.reloadConfigMtx.RUnlock()
+		return nil, errors.New("alertmanager is not initialized")
+	}
+	templateFiles := make(map[string]string, len(am.config.TemplateFiles)+2)
+	for name, content := range am.config.TemplateFiles {
+		templateFiles[name] = content
+	}
+	am.reloadConfigMtx.RUnlock()
+
+	templateFiles["__default__.tmpl"] = channels.DefaultTemplateString
+	content, lineOffset := defineTemplate(c.Name, c.Template)
+	templateFiles[c.Name] = content
+
+	dir, err := os.MkdirTemp("", "grafana-templates-test")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create template directory: %w", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			am.logger.Warn("failed to remove template directory", "dir", dir, "err", err)
+		}
+	}()
+	paths, _, err := PersistTemplates(&apimodels.PostableUserConfig{TemplateFiles: templateFiles}, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &apimodels.TestTemplatesResults{Results: []apimodels.TestTemplatesResult{}}
+	newError := func(kind, integrationType, setting string, err error) apimodels.TestTemplatesErrorResult {
+		e := apimodels.TestTemplatesErrorResult{Kind: kind, Type: integrationType, Setting: setting, Message: err.Error()}
+		if m := templateErrorRegexp.FindStringSubmatch(err.Error()); m != nil {
+			e.Template = m[1]
+			e.Line, _ = strconv.Atoi(m[2])
+			if e.Template == c.Name {
+				e.Line -= lineOffset
+			}
+		}
+		return e
+	}
+
+	tmpl, err := am.templateFromPaths(paths...)
+	if err != nil {
+		result.Errors = append(result.Errors, newError(apimodels.TestTemplatesErrorInvalidTemplate, "", "", err))
+		return result, nil
+	}
+
+	alerts := templateTestAlerts(c.Alerts, time.Now())
+	ctx = notify.WithReceiverName(ctx, "TestReceiver")
+	ctx = notify.WithGroupLabels(ctx, groupLabels(alerts))
+
+	for _, integration := range integrations {
+		var tmplErr error
+		render, _ := channels.TmplText(ctx, tmpl, alerts, am.logger, &tmplErr)
+		rendered := apimodels.TestTemplatesResult{Type: integration.Type, Settings: make(map[string]string, len(integration.Settings))}
+		for _, setting := range sortedKeys(integration.Settings) {
+			tmplErr = nil
+			rendered.Settings[setting] = render(integration.Settings[setting])
+			if tmplErr != nil {
+				result.Errors = append(result.Errors, newError(apimodels.TestTemplatesErrorExecution, integration.Type, setting, tmplErr))
+			}
+		}
+		result.Results = append(result.Results, rendered)
+	}
+	return result, nil
+}
+
+// templateTestIntegrations returns the integrations to render with the default templates of their contact point types.
+// All the contact point types with templated settings are returned if there are no integrations.
+func templateTestIntegrations(integrations []apimodels.TestTemplatesIntegration) ([]apimodels.TestTemplatesIntegration, error) {
+	if len(integrations) == 0 {
+		for _, integrationType := range sortedKeys(templatedSettings) {
+			integrations = append(integrations, apimodels.TestTemplatesIntegration{Type: integrationType})
+		}
+	}
+	result := make([]apimodels.TestTemplatesIntegration, 0, len(integrations))
+	for _, integration := range integrations {
+		defaults, ok := templatedSettings[integration.Type]
+		if !ok && len(integration.Settings) == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownIntegrationType, integration.Type)
+		}
+		settings := make(map[string]string, len(defaults)+len(integration.Settings))
+		for setting, text := range defaults {
+			settings[setting] = text
+		}
+		for setting, text := range integration.Settings {
+			settings[setting] = text
+		}
+		result = append(result, apimodels.TestTemplatesIntegration{Type: integration.Type, Settings: settings})
+	}
+	return result, nil
+}
+
+// defineTemplate defines the content with the name of the template if it does not define any template, so it can be
+// used in the settings of integrations. Leading and trailing whitespace of the content is trimmed. It returns the
+// number of lines that were added before the content.
+func defineTemplate(name, content string) (string, int) {
+	if templateDefineRegexp.MatchString(content) {
+		return content, 0
+	}
+	return fmt.Sprintf("{{ define %q -}}\n%s\n{{- end }}", name, content), 1
+}
+
+// templateTestAlerts returns the alerts to render the templates against, or a sample alert if there are no alerts.
+func templateTestAlerts(postableAlerts []*amv2.PostableAlert, now time.Time) []*types.Alert {
+	alerts := make([]*types.Alert, 0, len(postableAlerts))
+	for _, a := range postableAlerts {
+		if a == nil {
+			continue
+		}
+		alert := &types.Alert{
+			Alert: model.Alert{
+				Labels:       model.LabelSet{},
+				Annotations:  model.LabelSet{},
+				StartsAt:     time.Time(a.StartsAt),
+				EndsAt:       time.Time(a.EndsAt),
+				GeneratorURL: a.GeneratorURL.String(),
+			},
+			UpdatedAt: now,
+		}
+		for k, v := range a.Labels {
+			if len(v) == 0 || k == ngmodels.NamespaceUIDLabel {
+				continue
+			}
+			alert.Labels[model.LabelName(k)] = model.LabelValue(v)
+		}
+		for k, v := range a.Annotations {
+			if len(v) == 0 {
+				continue
+			}
+			alert.Annotations[model.LabelName(k)] = model.LabelValue(v)
+		}
+		if alert.StartsAt.IsZero() {
+			alert.StartsAt = now
+		}
+		alerts = append(alerts, alert)
+	}
+	if len(alerts) == 0 {
+		alert := newTestAlert(apimodels.TestReceiversConfigBodyParams{}, now, now)
+		alerts = append(alerts, &alert)
+	}
+	return alerts
+}
+
+// groupLabels returns the labels that all the alerts have in common, as they would be grouped by all labels.
+func groupLabels(alerts []*types.Alert) model.LabelSet {
+	common := model.LabelSet{}
+	for name, value := range alerts[0].Labels {
+		common[name] = value
+	}
+	for _, a := range alerts[1:] {
+		for name, value := range common {
+			if a.Labels[name] != value {
+				delete(common, name)
+			}
+		}
+	}
+	return common
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,113 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestTestTemplate(t *testing.T) {
+	am := &Alertmanager{
+		Settings: &setting.Cfg{AppURL: "http://localhost:3000/"},
+		logger:   log.New("alertmanager-test"),
+		config: &apimodels.PostableUserConfig{
+			TemplateFiles: map[string]string{
+				"saved": `{{ define "saved.title" }}Saved {{ .CommonLabels.alertname }}{{ end }}`,
+			},
+		},
+	}
+
+	t.Run("should render the template with the sample alert", func(t *testing.T) {
+		result, err := am.TestTemplate(context.Background(), apimodels.TestTemplatesConfigBodyParams{
+			Name:     "slack",
+			Template: `{{ define "slack.title" }}{{ len .Alerts.Firing }} firing: {{ .CommonLabels.alertname }}{{ end }}`,
+			Integrations: []apimodels.TestTemplatesIntegration{
+				{Type: "slack", Settings: map[string]string{"title": `{{ template "slack.title" . }}`}},
+			},
+		})
+		require.NoError(t, err)
+		require.Empty(t, result.Errors)
+		require.Len(t, result.Results, 1)
+		require.Equal(t, "slack", result.Results[0].Type)
+		require.Equal(t, "1 firing: TestAlert", result.Results[0].Settings["title"])
+		require.Contains(t, result.Results[0].Settings["text"], "**Firing**")
+	})
+
+	t.Run("should render the template with the given alerts and saved templates", func(t *testing.T) {
+		result, err := am.TestTemplate(context.Background(), apimodels.TestTemplatesConfigBodyParams{
+			Name:     "email",
+			Template: `{{ template "saved.title" . }} on {{ .CommonLabels.instance }}`,
+			Alerts: []*amv2.PostableAlert{
+				{Alert: amv2.Alert{Labels: amv2.LabelSet{"alertname": "HighCPU", "instance": "web-1"}}},
+				{Alert: amv2.Alert{Labels: amv2.LabelSet{"alertname": "HighCPU", "instance": "web-2"}}},
+			},
+			Integrations: []apimodels.TestTemplatesIntegration{
+				{Type: "email", Settings: map[string]string{"subject": `{{ template "email" . }}`}},
+			},
+		})
+		require.NoError(t, err)
+		require.Empty(t, result.Errors)
+		require.Equal(t, "Saved HighCPU on ", result.Results[0].Settings["subject"])
+	})
+
+	t.Run("should render all contact point types if there are no integrations", func(t *testing.T) {
+		result, err := am.TestTemplate(context.Background(), apimodels.TestTemplatesConfigBodyParams{
+			Name:     "empty",
+			Template: `{{ define "empty" }}{{ end }}`,
+		})
+		require.NoError(t, err)
+		require.Empty(t, result.Errors)
+		require.Len(t, result.Results, len(templatedSettings))
+	})
+
+	t.Run("should return parse errors with the line of the template", func(t *testing.T) {
+		result, err := am.TestTemplate(context.Background(), apimodels.TestTemplatesConfigBodyParams{
+			Name:     "slack",
+			Template: "first line\n{{ .CommonLabels.alertname }\nlast line",
+			Integrations: []apimodels.TestTemplatesIntegration{
+				{Type: "slack"},
+			},
+		})
+		require.NoError(t, err)
+		require.Empty(t, result.Results)
+		require.Len(t, result.Errors, 1)
+		require.Equal(t, apimodels.TestTemplatesErrorInvalidTemplate, result.Errors[0].Kind)
+		require.Equal(t, "slack", result.Errors[0].Template)
+		require.Equal(t, 2, result.Errors[0].Line)
+	})
+
+	t.Run("should return execution errors per setting", func(t *testing.T) {
+		result, err := am.TestTemplate(context.Background(), apimodels.TestTemplatesConfigBodyParams{
+			Name:     "slack",
+			Template: `{{ define "slack.title" }}{{ template "missing" . }}{{ end }}`,
+			Integrations: []apimodels.TestTemplatesIntegration{
+				{Type: "slack", Settings: map[string]string{"title": `{{ template "slack.title" . }}`}},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Results, 1)
+		require.Len(t, result.Errors, 1)
+		require.Equal(t, apimodels.TestTemplatesErrorExecution, result.Errors[0].Kind)
+		require.Equal(t, "slack", result.Errors[0].Type)
+		require.Equal(t, "title", result.Errors[0].Setting)
+		require.Equal(t, "slack", result.Errors[0].Template)
+		require.Equal(t, 1, result.Errors[0].Line)
+	})
+
+	t.Run("should fail on unknown contact point types without settings", func(t *testing.T) {
+		_, err := am.TestTemplate(context.Background(), apimodels.TestTemplatesConfigBodyParams{
+			Name:     "slack",
+			Template: `{{ define "slack.title" }}{{ end }}`,
+			Integrations: []apimodels.TestTemplatesIntegration{
+				{Type: "carrier-pigeon"},
+			},
+		})
+		require.ErrorIs(t, err, ErrUnknownIntegrationType)
+	})
+}
@@ -16,6 +16,15 @@ import (
 
 type FakeConfigStore struct {
 	configs map[int64]*models.AlertConfiguration
+	// history contains every saved configuration, oldest first.
+	history []*models.AlertConfiguration
+	// deliveries contains every saved notification delivery, oldest first.
+	deliveries *fakeDeliveries
+}
+
+type fakeDeliveries struct {
+	mtx        sync.Mutex
+	deliveries []*models.NotificationDelivery
 }
 
 // Saves the image or returns an error.
@@ -34,9 +43,21 @@ func (f *FakeConfigStore) GetImages(ctx context.Context, tokens []string) ([]mod
 func NewFakeConfigStore(t *testing.T, configs map[int64]*models.AlertConfiguration) FakeConfigStore {
 	t.Helper()
 
-	return FakeConfigStore{
-		configs: configs,
+	f := FakeConfigStore{
+		configs:    configs,
+		deliveries: &fakeDeliveries{},
+	}
+	for _, config := range configs {
+		f.addToHistory(config)
+	}
+	return f
+}
+
+func (f *FakeConfigStore) addToHistory(config *models.AlertConfiguration) {
+	if config.ID == 0 {
+		config.ID = int64(len(f.history) + 1)
 	}
+	f.history = append(f.history, config)
 }
 
 func (f *FakeConfigStore) GetAllLatestAlertmanagerConfiguration(context.Context) ([]*models.AlertConfiguration, error) {
@@ -57,6 +78,29 @@ func (f *FakeConfigStore) GetLatestAlertmanagerConfiguration(_ context.Context,
 	return nil
 }
 
+func (f *FakeConfigStore) GetAlertmanagerConfigurationHistory(_ context.Context, query *models.GetAlertmanagerConfigurationHistoryQuery) error {
+	query.Result = nil
+	for i := len(f.history) - 1; i >= 0; i-- {
+		if query.Limit > 0 && len(query.Result) == query.Limit {
+			break
+		}
+		if f.history[i].OrgID == query.OrgID {
+			query.Result = append(query.Result, f.history[i])
+		}
+	}
+	return nil
+}
+
+func (f *FakeConfigStore) GetAlertmanagerConfiguration(_ context.Context, query *models.GetAlertmanagerConfigurationQuery) error {
+	for _, config := range f.history {
+		if config.OrgID == query.OrgID && config.ID == query.ID {
+			query.Result = config
+			return nil
+		}
+	}
+	return store.ErrNoAlertmanagerConfiguration
+}
+
 func (f *FakeConfigStore) SaveAlertmanagerConfiguration(_ context.Context, cmd *models.SaveAlertmanagerConfigurationCmd) error {
 	f.configs[cmd.OrgID] = &models.AlertConfiguration{
 		AlertmanagerConfiguration: cmd.AlertmanagerConfiguration,
@@ -64,6 +108,7 @@ func (f *FakeConfigStore) SaveAlertmanagerConfiguration(_ context.Context, cmd *
 		ConfigurationVersion:      "v1",
 		Default:                   cmd.Default,
 	}
+	f.addToHistory(f.configs[cmd.OrgID])
 
 	return nil
 }
@@ -79,6 +124,7 @@ func (f *FakeConfigStore) SaveAlertmanagerConfigurationWithCallback(_ context.Co
 	if err := callback(); err != nil {
 		return err
 	}
+	f.addToHistory(f.configs[cmd.OrgID])
 
 	return nil
 }
@@ -92,11 +138,50 @@ func (f *FakeConfigStore) UpdateAlertmanagerConfiguration(_ context.Context, cmd
 			ConfigurationVersion:      "v1",
 			Default:                   cmd.Default,
 		}
+		f.addToHistory(f.configs[cmd.OrgID])
 		return nil
 	}
 	return errors.New("config not found or hash not valid")
 }
 
+func (f *FakeConfigStore) SaveNotificationDelivery(_ context.Context, delivery *models.NotificationDelivery) error {
+	if f.deliveries == nil {
+		// The store was not created with NewFakeConfigStore, the deliveries are not recorded.
+		return nil
+	}
+	f.deliveries.mtx.Lock()
+	defer f.deliveries.mtx.Unlock()
+	delivery.ID = int64(len(f.deliveries.deliveries) + 1)
+	f.deliveries.deliveries = append(f.deliveries.deliveries, delivery)
+	return nil
+}
+
+func (f *FakeConfigStore) GetNotificationDeliveries(_ context.Context, query *models.GetNotificationDeliveriesQuery) error {
+	query.Result = nil
+	if f.deliveries == nil {
+		return nil
+	}
+	f.deliveries.mtx.Lock()
+	defer f.deliveries.mtx.Unlock()
+	for i := len(f.deliveries.deliveries) - 1; i >= 0; i-- {
+		if query.Limit > 0 && len(query.Result) == query.Limit {
+			break
+		}
+		d := f.deliveries.deliveries[i]
+		if d.OrgID != query.OrgID ||
+			(query.Receiver != "" && d.Receiver != query.Receiver) ||
+			(query.IntegrationType != "" && d.IntegrationType != query.IntegrationType) ||
+			(query.GroupKey != "" && d.GroupKey != query.GroupKey) ||
+			(query.Status != "" && d.Status != query.Status) ||
+			(!query.From.IsZero() && d.CreatedAt.Before(query.From)) ||
+			(!query.To.IsZero() && d.CreatedAt.After(query.To)) {
+			continue
+		}
+		query.Result = append(query.Result, d)
+	}
+	return nil
+}
+
 type FakeOrgStore struct {
 	orgs []int64
 }
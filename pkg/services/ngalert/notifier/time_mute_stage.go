@@ -0,0 +1,49 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// timeMuteStage mutes the alerts of routes that are within one of their mute time intervals. It replaces
+// notify.TimeMuteStage because the mute time intervals of Grafana have date ranges in addition to time intervals.
+type timeMuteStage struct {
+	muteTimes map[string]apimodels.MuteTimeIntervalConfig
+}
+
+func newTimeMuteStage(muteTimes map[string]apimodels.MuteTimeIntervalConfig) *timeMuteStage {
+	return &timeMuteStage{muteTimes: muteTimes}
+}
+
+// Exec implements notify.Stage.
+func (s *timeMuteStage) Exec(ctx context.Context, l log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+	muteTimeIntervalNames, ok := notify.MuteTimeIntervalNames(ctx)
+	if !ok {
+		return ctx, alerts, nil
+	}
+	now, ok := notify.Now(ctx)
+	if !ok {
+		return ctx, alerts, errors.New("missing now timestamp")
+	}
+
+	for _, name := range muteTimeIntervalNames {
+		mt, ok := s.muteTimes[name]
+		if !ok {
+			return ctx, alerts, fmt.Errorf("time interval %s doesn't exist in config", name)
+		}
+		// If the current time is inside a mute time, all alerts are removed from the pipeline.
+		if mt.ContainsTime(now) {
+			level.Debug(l).Log("msg", "Notifications not sent, route is within mute time", "mute_time_interval", name)
+			return ctx, nil, nil
+		}
+	}
+	return ctx, alerts, nil
+}
@@ -0,0 +1,68 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestTimeMuteStage(t *testing.T) {
+	stage := newTimeMuteStage(map[string]apimodels.MuteTimeIntervalConfig{
+		"maintenance": {
+			MuteTimeInterval: config.MuteTimeInterval{Name: "maintenance"},
+			DateRanges: []apimodels.DateRange{
+				{StartTime: "2022-10-20T22:00:00Z", EndTime: "2022-10-21T02:00:00Z"},
+			},
+		},
+	})
+	alerts := []*types.Alert{{Alert: model.Alert{Labels: model.LabelSet{"alertname": "a"}}}}
+
+	testCases := []struct {
+		name      string
+		ctx       context.Context
+		expAlerts int
+		expErr    string
+	}{
+		{
+			name:      "should not mute alerts of routes without mute time intervals",
+			ctx:       notify.WithNow(context.Background(), time.Date(2022, 10, 20, 23, 0, 0, 0, time.UTC)),
+			expAlerts: 1,
+		},
+		{
+			name:      "should mute alerts within a date range",
+			ctx:       notify.WithMuteTimeIntervals(notify.WithNow(context.Background(), time.Date(2022, 10, 20, 23, 0, 0, 0, time.UTC)), []string{"maintenance"}),
+			expAlerts: 0,
+		},
+		{
+			name:      "should not mute alerts outside of the date ranges",
+			ctx:       notify.WithMuteTimeIntervals(notify.WithNow(context.Background(), time.Date(2022, 10, 21, 2, 0, 0, 0, time.UTC)), []string{"maintenance"}),
+			expAlerts: 1,
+		},
+		{
+			name:      "should fail if the mute time interval does not exist",
+			ctx:       notify.WithMuteTimeIntervals(notify.WithNow(context.Background(), time.Date(2022, 10, 20, 23, 0, 0, 0, time.UTC)), []string{"unknown"}),
+			expAlerts: 1,
+			expErr:    "time interval unknown doesn't exist in config",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, result, err := stage.Exec(tc.ctx, log.NewNopLogger(), alerts...)
+			if tc.expErr != "" {
+				require.EqualError(t, err, tc.expErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Len(t, result, tc.expAlerts)
+		})
+	}
+}
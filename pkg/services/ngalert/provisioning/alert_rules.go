@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -11,6 +12,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/util/cmputil"
 )
 
 type AlertRuleService struct {
@@ -128,6 +130,57 @@ func (service *AlertRuleService) GetRuleGroup(ctx context.Context, orgID int64,
 	return res, nil
 }
 
+// GetRuleGroups returns the rule groups of the folders, ordered by folder and title. If group is set, only the rule
+// groups with this title are returned.
+func (service *AlertRuleService) GetRuleGroups(ctx context.Context, orgID int64, folderUIDs []string, group string) ([]models.AlertRuleGroup, error) {
+	if len(folderUIDs) == 0 {
+		return nil, nil
+	}
+	q := models.ListAlertRulesQuery{
+		OrgID:         orgID,
+		NamespaceUIDs: folderUIDs,
+		RuleGroup:     group,
+	}
+	if err := service.ruleStore.ListAlertRules(ctx, &q); err != nil {
+		return nil, err
+	}
+	groups := make(map[models.AlertRuleGroupKey]*models.AlertRuleGroup)
+	keys := make([]models.AlertRuleGroupKey, 0)
+	for _, r := range q.Result {
+		if r == nil {
+			continue
+		}
+		key := r.GetGroupKey()
+		g, ok := groups[key]
+		if !ok {
+			g = &models.AlertRuleGroup{
+				Title:     r.RuleGroup,
+				FolderUID: r.NamespaceUID,
+				Interval:  r.IntervalSeconds,
+				Rules:     []models.AlertRule{},
+			}
+			groups[key] = g
+			keys = append(keys, key)
+		}
+		g.Rules = append(g.Rules, *r)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].NamespaceUID != keys[j].NamespaceUID {
+			return keys[i].NamespaceUID < keys[j].NamespaceUID
+		}
+		return keys[i].RuleGroup < keys[j].RuleGroup
+	})
+	result := make([]models.AlertRuleGroup, 0, len(keys))
+	for _, key := range keys {
+		g := groups[key]
+		sort.SliceStable(g.Rules, func(i, j int) bool {
+			return g.Rules[i].RuleGroupIndex < g.Rules[j].RuleGroupIndex
+		})
+		result = append(result, *g)
+	}
+	return result, nil
+}
+
 // UpdateRuleGroup will update the interval for all rules in the group.
 func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string, intervalSeconds int64) error {
 	if err := models.ValidateRuleGroupInterval(intervalSeconds, service.baseIntervalSeconds); err != nil {
@@ -159,6 +212,44 @@ func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, orgID int6
 	})
 }
 
+// SetRuleGroupPaused pauses or resumes evaluation of all rules in the group and returns the updated group.
+func (service *AlertRuleService) SetRuleGroupPaused(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string, isPaused bool, provenance models.Provenance) (models.AlertRuleGroup, error) {
+	var result models.AlertRuleGroup
+	err := service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		group, err := service.GetRuleGroup(ctx, orgID, namespaceUID, ruleGroup)
+		if err != nil {
+			return err
+		}
+		updateRules := make([]models.UpdateRule, 0, len(group.Rules))
+		for i := range group.Rules {
+			rule := &group.Rules[i]
+			if rule.IsPaused == isPaused {
+				continue
+			}
+			storedProvenance, err := service.provenanceStore.GetProvenance(ctx, rule, orgID)
+			if err != nil {
+				return err
+			}
+			if storedProvenance != provenance && storedProvenance != models.ProvenanceNone {
+				return fmt.Errorf("cannot update with provided provenance '%s', needs '%s'", provenance, storedProvenance)
+			}
+			existing := *rule
+			rule.IsPaused = isPaused
+			rule.Updated = time.Now()
+			updateRules = append(updateRules, models.UpdateRule{
+				Existing: &existing,
+				New:      *rule,
+			})
+		}
+		result = group
+		return service.ruleStore.UpdateAlertRules(ctx, updateRules)
+	})
+	if err != nil {
+		return models.AlertRuleGroup{}, err
+	}
+	return result, nil
+}
+
 func (service *AlertRuleService) ReplaceRuleGroup(ctx context.Context, orgID int64, group models.AlertRuleGroup, userID int64, provenance models.Provenance) error {
 	if err := models.ValidateRuleGroupInterval(group.Interval, service.baseIntervalSeconds); err != nil {
 		return err
@@ -298,6 +389,107 @@ func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, rule model
 	return rule, err
 }
 
+// GetAlertRuleVersions returns all versions of the alert rule, latest first.
+func (service *AlertRuleService) GetAlertRuleVersions(ctx context.Context, orgID int64, ruleUID string) ([]*models.AlertRuleVersion, error) {
+	query := &models.ListAlertRuleVersionsQuery{
+		OrgID: orgID,
+		UID:   ruleUID,
+	}
+	err := service.ruleStore.ListAlertRuleVersions(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	// every rule has at least one version that is created along with the rule.
+	if len(query.Result) == 0 {
+		return nil, models.ErrAlertRuleNotFound
+	}
+	return query.Result, nil
+}
+
+// GetAlertRuleVersion returns a specific version of the alert rule.
+func (service *AlertRuleService) GetAlertRuleVersion(ctx context.Context, orgID int64, ruleUID string, version int64) (*models.AlertRuleVersion, error) {
+	query := &models.GetAlertRuleVersionQuery{
+		OrgID:   orgID,
+		UID:     ruleUID,
+		Version: version,
+	}
+	err := service.ruleStore.GetAlertRuleVersion(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return query.Result, nil
+}
+
+// DiffAlertRuleVersions calculates the difference between two versions of the alert rule.
+// If the version to compare with is 0, the version is compared with the current alert rule.
+func (service *AlertRuleService) DiffAlertRuleVersions(ctx context.Context, orgID int64, ruleUID string, version int64, compareTo int64) (cmputil.DiffReport, error) {
+	from, err := service.GetAlertRuleVersion(ctx, orgID, ruleUID, version)
+	if err != nil {
+		return nil, err
+	}
+	fromRule, err := from.ToAlertRule()
+	if err != nil {
+		return nil, err
+	}
+
+	var toRule models.AlertRule
+	if compareTo == 0 {
+		toRule, _, err = service.GetAlertRule(ctx, orgID, ruleUID)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		to, err := service.GetAlertRuleVersion(ctx, orgID, ruleUID, compareTo)
+		if err != nil {
+			return nil, err
+		}
+		toRule, err = to.ToAlertRule()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return fromRule.Diff(&toRule, store.AlertRuleFieldsToIgnoreInDiff[:]...), nil
+}
+
+// RestoreAlertRuleVersion replaces the definition of the alert rule with the one of the given version and
+// stores it as a new version that is marked as restored from that version.
+func (service *AlertRuleService) RestoreAlertRuleVersion(ctx context.Context, orgID int64, ruleUID string, version int64, provenance models.Provenance) (models.AlertRule, error) {
+	storedRule, storedProvenance, err := service.GetAlertRule(ctx, orgID, ruleUID)
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	if storedProvenance != provenance && storedProvenance != models.ProvenanceNone {
+		return models.AlertRule{}, fmt.Errorf("cannot changed provenance from '%s' to '%s'", storedProvenance, provenance)
+	}
+	restored, err := service.GetAlertRuleVersion(ctx, orgID, ruleUID, version)
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+
+	rule, err := restored.Restore(storedRule)
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	rule.Updated = time.Now()
+	err = service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		err := service.ruleStore.UpdateAlertRules(ctx, []models.UpdateRule{
+			{
+				Existing:     &storedRule,
+				New:          rule,
+				RestoredFrom: version,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		return service.provenanceStore.SetProvenance(ctx, &rule, rule.OrgID, provenance)
+	})
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	return rule, nil
+}
+
 func (service *AlertRuleService) DeleteAlertRule(ctx context.Context, orgID int64, ruleUID string, provenance models.Provenance) error {
 	rule := &models.AlertRule{
 		OrgID: orgID,
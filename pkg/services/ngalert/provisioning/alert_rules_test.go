@@ -11,6 +11,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util/cmputil"
 
 	"github.com/stretchr/testify/require"
 )
@@ -51,6 +52,35 @@ func TestAlertRuleService(t *testing.T) {
 		}
 	})
 
+	t.Run("rule groups of folders should be listed in order", func(t *testing.T) {
+		var orgID int64 = 3
+		groupB := createDummyGroup("group-b", orgID)
+		groupB.Rules = append(groupB.Rules, dummyRule("group-b-rule-2", orgID))
+		groupA := createDummyGroup("group-a", orgID)
+		other := createDummyGroup("group-other", orgID)
+		other.FolderUID = "other-namespace"
+		for _, g := range []models.AlertRuleGroup{groupB, groupA, other} {
+			require.NoError(t, ruleService.ReplaceRuleGroup(context.Background(), orgID, g, 0, models.ProvenanceAPI))
+		}
+
+		groups, err := ruleService.GetRuleGroups(context.Background(), orgID, []string{"my-namespace"}, "")
+		require.NoError(t, err)
+		require.Len(t, groups, 2)
+		require.Equal(t, "group-a", groups[0].Title)
+		require.Equal(t, "group-b", groups[1].Title)
+		require.Equal(t, "group-b-rule-1", groups[1].Rules[0].Title)
+		require.Equal(t, "group-b-rule-2", groups[1].Rules[1].Title)
+
+		groups, err = ruleService.GetRuleGroups(context.Background(), orgID, []string{"my-namespace", "other-namespace"}, "group-other")
+		require.NoError(t, err)
+		require.Len(t, groups, 1)
+		require.Equal(t, "other-namespace", groups[0].FolderUID)
+
+		groups, err = ruleService.GetRuleGroups(context.Background(), orgID, nil, "")
+		require.NoError(t, err)
+		require.Empty(t, groups)
+	})
+
 	t.Run("alert rule group should be updated correctly", func(t *testing.T) {
 		var orgID int64 = 1
 		rule := dummyRule("test#3", orgID)
@@ -68,6 +98,44 @@ func TestAlertRuleService(t *testing.T) {
 		require.Equal(t, interval, rule.IntervalSeconds)
 	})
 
+	t.Run("alert rule group should be paused and resumed", func(t *testing.T) {
+		var orgID int64 = 1
+		rule := dummyRule("test#pause", orgID)
+		rule.RuleGroup = "pause-group"
+		rule, err := ruleService.CreateAlertRule(context.Background(), rule, models.ProvenanceNone, 0)
+		require.NoError(t, err)
+
+		group, err := ruleService.SetRuleGroupPaused(context.Background(), orgID, rule.NamespaceUID, rule.RuleGroup, true, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Len(t, group.Rules, 1)
+		require.True(t, group.Rules[0].IsPaused)
+
+		paused, _, err := ruleService.GetAlertRule(context.Background(), orgID, rule.UID)
+		require.NoError(t, err)
+		require.True(t, paused.IsPaused)
+		require.Equal(t, rule.Version+1, paused.Version)
+
+		_, err = ruleService.SetRuleGroupPaused(context.Background(), orgID, rule.NamespaceUID, rule.RuleGroup, false, models.ProvenanceAPI)
+		require.NoError(t, err)
+		resumed, _, err := ruleService.GetAlertRule(context.Background(), orgID, rule.UID)
+		require.NoError(t, err)
+		require.False(t, resumed.IsPaused)
+
+		_, err = ruleService.SetRuleGroupPaused(context.Background(), orgID, rule.NamespaceUID, "does not exist", true, models.ProvenanceAPI)
+		require.ErrorIs(t, err, store.ErrAlertRuleGroupNotFound)
+	})
+
+	t.Run("alert rule group pause should check provenance", func(t *testing.T) {
+		var orgID int64 = 1
+		rule := dummyRule("test#pause-provenance", orgID)
+		rule.RuleGroup = "pause-provenance-group"
+		rule, err := ruleService.CreateAlertRule(context.Background(), rule, models.ProvenanceFile, 0)
+		require.NoError(t, err)
+
+		_, err = ruleService.SetRuleGroupPaused(context.Background(), orgID, rule.NamespaceUID, rule.RuleGroup, true, models.ProvenanceAPI)
+		require.Error(t, err)
+	})
+
 	t.Run("if a folder was renamed the interval should be fetched from the renamed folder", func(t *testing.T) {
 		var orgID int64 = 2
 		rule := dummyRule("test#1", orgID)
@@ -287,6 +355,61 @@ func TestAlertRuleService(t *testing.T) {
 		}
 	})
 
+	t.Run("alert rule versions should be listed, compared and restored", func(t *testing.T) {
+		var orgID int64 = 1
+		rule := dummyRule("test#versions", orgID)
+		rule.Data[0].RelativeTimeRange.From = models.Duration(time.Minute)
+		rule.Labels = map[string]string{"team": "a"}
+		rule, err := ruleService.CreateAlertRule(context.Background(), rule, models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+
+		updated := rule
+		updated.Title = "test#versions updated"
+		updated.Labels = map[string]string{"team": "b"}
+		_, err = ruleService.UpdateAlertRule(context.Background(), updated, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		versions, err := ruleService.GetAlertRuleVersions(context.Background(), orgID, rule.UID)
+		require.NoError(t, err)
+		require.Len(t, versions, 2)
+		require.Equal(t, "test#versions updated", versions[0].Title)
+		require.Equal(t, "test#versions", versions[1].Title)
+
+		diff, err := ruleService.DiffAlertRuleVersions(context.Background(), orgID, rule.UID, versions[1].Version, versions[0].Version)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"Title", "Labels[team]"}, diffPaths(diff))
+
+		restored, err := ruleService.RestoreAlertRuleVersion(context.Background(), orgID, rule.UID, versions[1].Version, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, "test#versions", restored.Title)
+		require.Equal(t, map[string]string{"team": "a"}, restored.Labels)
+
+		diff, err = ruleService.DiffAlertRuleVersions(context.Background(), orgID, rule.UID, versions[1].Version, 0)
+		require.NoError(t, err)
+		require.Empty(t, diff)
+
+		versions, err = ruleService.GetAlertRuleVersions(context.Background(), orgID, rule.UID)
+		require.NoError(t, err)
+		require.Len(t, versions, 3)
+		require.Equal(t, versions[2].Version, versions[0].RestoredFrom)
+	})
+
+	t.Run("alert rule versions of unknown rule should not be found", func(t *testing.T) {
+		_, err := ruleService.GetAlertRuleVersions(context.Background(), 1, "does-not-exist")
+		require.ErrorIs(t, err, models.ErrAlertRuleNotFound)
+	})
+
+	t.Run("alert rule restore should check provenance", func(t *testing.T) {
+		var orgID int64 = 1
+		rule := dummyRule("test#versions-provenance", orgID)
+		rule.Data[0].RelativeTimeRange.From = models.Duration(time.Minute)
+		rule, err := ruleService.CreateAlertRule(context.Background(), rule, models.ProvenanceFile, 0)
+		require.NoError(t, err)
+
+		_, err = ruleService.RestoreAlertRuleVersion(context.Background(), orgID, rule.UID, rule.Version, models.ProvenanceAPI)
+		require.Error(t, err)
+	})
+
 	t.Run("quota met causes create to be rejected", func(t *testing.T) {
 		ruleService := createAlertRuleService(t)
 		checker := &MockQuotaChecker{}
@@ -334,6 +457,14 @@ func createAlertRuleService(t *testing.T) AlertRuleService {
 	}
 }
 
+func diffPaths(report cmputil.DiffReport) []string {
+	result := make([]string, 0, len(report))
+	for _, d := range report {
+		result = append(result, d.Path)
+	}
+	return result
+}
+
 func dummyRule(title string, orgID int64) models.AlertRule {
 	return createTestRule(title, "my-cool-group", orgID)
 }
@@ -38,6 +38,8 @@ type ContactPointQuery struct {
 	// Optionally filter by name.
 	Name  string
 	OrgID int64
+	// Decrypt returns the secure settings instead of redacting them.
+	Decrypt bool
 }
 
 func (ecp *ContactPointService) GetContactPoints(ctx context.Context, q ContactPointQuery) ([]apimodels.EmbeddedContactPoint, error) {
@@ -74,7 +76,11 @@ func (ecp *ContactPointService) GetContactPoints(ctx context.Context, q ContactP
 			if decryptedValue == "" {
 				continue
 			}
-			embeddedContactPoint.Settings.Set(k, apimodels.RedactedValue)
+			if q.Decrypt {
+				embeddedContactPoint.Settings.Set(k, decryptedValue)
+			} else {
+				embeddedContactPoint.Settings.Set(k, apimodels.RedactedValue)
+			}
 		}
 
 		contactPoints = append(contactPoints, embeddedContactPoint)
@@ -60,6 +60,25 @@ func TestContactPointService(t *testing.T) {
 		require.Equal(t, "slack", cps[1].Type)
 	})
 
+	t.Run("service redacts secure settings unless they are decrypted", func(t *testing.T) {
+		sut := createContactPointServiceSut(secretsService)
+		newCp := createTestContactPoint()
+		_, err := sut.CreateContactPoint(context.Background(), 1, newCp, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		q := ContactPointQuery{OrgID: 1, Name: "test-contact-point"}
+		cps, err := sut.GetContactPoints(context.Background(), q)
+		require.NoError(t, err)
+		require.Len(t, cps, 1)
+		require.Equal(t, definitions.RedactedValue, cps[0].Settings.Get("token").MustString())
+
+		q.Decrypt = true
+		cps, err = sut.GetContactPoints(context.Background(), q)
+		require.NoError(t, err)
+		require.Len(t, cps, 1)
+		require.Equal(t, "value_token", cps[0].Settings.Get("token").MustString())
+	})
+
 	t.Run("it's possible to use a custom uid", func(t *testing.T) {
 		customUID := "1337"
 		sut := createContactPointServiceSut(secretsService)
@@ -39,7 +39,7 @@ func (svc *MuteTimingService) GetMuteTimings(ctx context.Context, orgID int64) (
 
 	result := make([]definitions.MuteTimeInterval, 0, len(rev.cfg.AlertmanagerConfig.MuteTimeIntervals))
 	for _, interval := range rev.cfg.AlertmanagerConfig.MuteTimeIntervals {
-		result = append(result, definitions.MuteTimeInterval{MuteTimeInterval: interval})
+		result = append(result, definitions.MuteTimeInterval{MuteTimeInterval: interval.MuteTimeInterval, DateRanges: interval.DateRanges})
 	}
 	return result, nil
 }
@@ -56,14 +56,14 @@ func (svc *MuteTimingService) CreateMuteTiming(ctx context.Context, mt definitio
 	}
 
 	if revision.cfg.AlertmanagerConfig.MuteTimeIntervals == nil {
-		revision.cfg.AlertmanagerConfig.MuteTimeIntervals = []config.MuteTimeInterval{}
+		revision.cfg.AlertmanagerConfig.MuteTimeIntervals = []definitions.MuteTimeIntervalConfig{}
 	}
 	for _, existing := range revision.cfg.AlertmanagerConfig.MuteTimeIntervals {
 		if mt.Name == existing.Name {
 			return nil, fmt.Errorf("%w: %s", ErrValidation, "a mute timing with this name already exists")
 		}
 	}
-	revision.cfg.AlertmanagerConfig.MuteTimeIntervals = append(revision.cfg.AlertmanagerConfig.MuteTimeIntervals, mt.MuteTimeInterval)
+	revision.cfg.AlertmanagerConfig.MuteTimeIntervals = append(revision.cfg.AlertmanagerConfig.MuteTimeIntervals, mt.Config())
 
 	serialized, err := serializeAlertmanagerConfig(*revision.cfg)
 	if err != nil {
@@ -111,7 +111,7 @@ func (svc *MuteTimingService) UpdateMuteTiming(ctx context.Context, mt definitio
 	updated := false
 	for i, existing := range revision.cfg.AlertmanagerConfig.MuteTimeIntervals {
 		if mt.Name == existing.Name {
-			revision.cfg.AlertmanagerConfig.MuteTimeIntervals[i] = mt.MuteTimeInterval
+			revision.cfg.AlertmanagerConfig.MuteTimeIntervals[i] = mt.Config()
 			updated = true
 			break
 		}
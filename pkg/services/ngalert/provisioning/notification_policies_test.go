@@ -32,10 +32,12 @@ func TestNotificationPolicyService(t *testing.T) {
 			Return(
 				func(ctx context.Context, query *models.GetLatestAlertmanagerConfigurationQuery) error {
 					cfg := createTestAlertingConfig()
-					cfg.AlertmanagerConfig.MuteTimeIntervals = []config.MuteTimeInterval{
+					cfg.AlertmanagerConfig.MuteTimeIntervals = []definitions.MuteTimeIntervalConfig{
 						{
-							Name:          "not-the-one-we-need",
-							TimeIntervals: []timeinterval.TimeInterval{},
+							MuteTimeInterval: config.MuteTimeInterval{
+								Name:          "not-the-one-we-need",
+								TimeIntervals: []timeinterval.TimeInterval{},
+							},
 						},
 					}
 					data, _ := serializeAlertmanagerConfig(*cfg)
@@ -64,10 +66,12 @@ func TestNotificationPolicyService(t *testing.T) {
 			Return(
 				func(ctx context.Context, query *models.GetLatestAlertmanagerConfigurationQuery) error {
 					cfg := createTestAlertingConfig()
-					cfg.AlertmanagerConfig.MuteTimeIntervals = []config.MuteTimeInterval{
+					cfg.AlertmanagerConfig.MuteTimeIntervals = []definitions.MuteTimeIntervalConfig{
 						{
-							Name:          "existing",
-							TimeIntervals: []timeinterval.TimeInterval{},
+							MuteTimeInterval: config.MuteTimeInterval{
+								Name:          "existing",
+								TimeIntervals: []timeinterval.TimeInterval{},
+							},
 						},
 					}
 					data, _ := serializeAlertmanagerConfig(*cfg)
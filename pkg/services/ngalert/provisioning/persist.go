@@ -42,6 +42,8 @@ type RuleStore interface {
 	UpdateAlertRules(ctx context.Context, rule []models.UpdateRule) error
 	DeleteAlertRulesByUID(ctx context.Context, orgID int64, ruleUID ...string) error
 	GetAlertRulesGroupByRuleUID(ctx context.Context, query *models.GetAlertRulesGroupByRuleUIDQuery) error
+	ListAlertRuleVersions(ctx context.Context, query *models.ListAlertRuleVersionsQuery) error
+	GetAlertRuleVersion(ctx context.Context, query *models.GetAlertRuleVersionQuery) error
 }
 
 // QuotaChecker represents the ability to evaluate whether quotas are met.
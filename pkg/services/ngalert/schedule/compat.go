@@ -161,3 +161,16 @@ func FromAlertsStateToStoppedAlert(firingStates []*state.State, appURL *url.URL,
 	}
 	return alerts
 }
+
+// FromAlertStatesToFiringAlerts converts the states that have evaluation state either eval.Alerting or eval.NoData or eval.Error
+// to models.PostableAlert as they are sent to notifiers, whether or not they were already sent.
+func FromAlertStatesToFiringAlerts(states []*state.State, appURL *url.URL) []*models.PostableAlert {
+	alerts := make([]*models.PostableAlert, 0, len(states))
+	for _, alertState := range states {
+		if alertState.State == eval.Normal || alertState.State == eval.Pending {
+			continue
+		}
+		alerts = append(alerts, stateToPostableAlert(alertState, appURL))
+	}
+	return alerts
+}
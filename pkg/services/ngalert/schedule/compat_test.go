@@ -244,6 +244,32 @@ func Test_FromAlertsStateToStoppedAlert(t *testing.T) {
 	require.Equal(t, expected, result.PostableAlerts)
 }
 
+func Test_FromAlertStatesToFiringAlerts(t *testing.T) {
+	appURL := &url.URL{
+		Scheme: "http:",
+		Host:   fmt.Sprintf("host-%d", rand.Int()),
+		Path:   fmt.Sprintf("path-%d", rand.Int()),
+	}
+
+	evalStates := [...]eval.State{eval.Normal, eval.Alerting, eval.Pending, eval.Error, eval.NoData}
+	states := make([]*state.State, 0, len(evalStates))
+	for _, s := range evalStates {
+		states = append(states, randomState(s))
+	}
+
+	expected := make([]*models.PostableAlert, 0, len(states))
+	for _, s := range states {
+		if !(s.State == eval.Alerting || s.State == eval.Error || s.State == eval.NoData) {
+			continue
+		}
+		expected = append(expected, stateToPostableAlert(s, appURL))
+	}
+
+	result := FromAlertStatesToFiringAlerts(states, appURL)
+
+	require.Equal(t, expected, result)
+}
+
 func randomMapOfStrings() map[string]string {
 	max := 5
 	result := make(map[string]string, max)
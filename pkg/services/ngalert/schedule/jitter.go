@@ -0,0 +1,31 @@
+package schedule
+
+import (
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// jitterOffsetInTicks returns the number of base ticks by which the evaluation of the rule is delayed within its interval.
+// The offset is derived from the rule group or the rule UID, so it is stable across restarts and across Grafana instances.
+func jitterOffsetInTicks(r *ngmodels.AlertRule, baseInterval time.Duration, strategy string) int64 {
+	if strategy == setting.EvaluationJitterDisabled || strategy == "" {
+		return 0
+	}
+	itemFrequency := r.IntervalSeconds / int64(baseInterval.Seconds())
+	if itemFrequency <= 1 {
+		return 0
+	}
+	h := fnv.New64a()
+	// We can ignore err as fnv64 does not return an error
+	// nolint:errcheck,gosec
+	h.Write([]byte(fmt.Sprintf("%d\x00%s\x00%s", r.OrgID, r.NamespaceUID, r.RuleGroup)))
+	if strategy == setting.EvaluationJitterByRule {
+		// nolint:errcheck,gosec
+		h.Write([]byte("\x00" + r.UID))
+	}
+	return int64(h.Sum64() % uint64(itemFrequency))
+}
@@ -0,0 +1,66 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestJitterOffsetInTicks(t *testing.T) {
+	baseInterval := 10 * time.Second
+	withInterval := func(interval time.Duration) models.AlertRuleMutator {
+		return func(rule *models.AlertRule) {
+			rule.IntervalSeconds = int64(interval.Seconds())
+		}
+	}
+
+	t.Run("should return zero when jitter is disabled", func(t *testing.T) {
+		rules := models.GenerateAlertRules(100, models.AlertRuleGen(withInterval(time.Minute)))
+		for _, rule := range rules {
+			require.Zero(t, jitterOffsetInTicks(rule, baseInterval, setting.EvaluationJitterDisabled))
+			require.Zero(t, jitterOffsetInTicks(rule, baseInterval, ""))
+		}
+	})
+
+	t.Run("should return zero when interval equals the base interval", func(t *testing.T) {
+		rules := models.GenerateAlertRules(100, models.AlertRuleGen(withInterval(baseInterval)))
+		for _, rule := range rules {
+			require.Zero(t, jitterOffsetInTicks(rule, baseInterval, setting.EvaluationJitterByRule))
+		}
+	})
+
+	t.Run("should keep rules of the same group together when jitter is by group", func(t *testing.T) {
+		gen := models.AlertRuleGen(withInterval(5*time.Minute), func(rule *models.AlertRule) {
+			rule.OrgID = 1
+			rule.NamespaceUID = "folder"
+			rule.RuleGroup = "group"
+		})
+		rules := models.GenerateAlertRules(20, gen)
+		expected := jitterOffsetInTicks(rules[0], baseInterval, setting.EvaluationJitterByGroup)
+		for _, rule := range rules {
+			require.Equal(t, expected, jitterOffsetInTicks(rule, baseInterval, setting.EvaluationJitterByGroup))
+		}
+	})
+
+	for _, strategy := range []string{setting.EvaluationJitterByGroup, setting.EvaluationJitterByRule} {
+		t.Run("should spread rules across the interval when jitter is "+strategy, func(t *testing.T) {
+			rules := models.GenerateAlertRules(600, models.AlertRuleGen(withInterval(time.Minute)))
+			ticks := make(map[int64]int)
+			for _, rule := range rules {
+				offset := jitterOffsetInTicks(rule, baseInterval, strategy)
+				require.GreaterOrEqual(t, offset, int64(0))
+				require.Less(t, offset, int64(6))
+				require.Equal(t, offset, jitterOffsetInTicks(rule, baseInterval, strategy), "offset should be stable")
+				ticks[offset]++
+			}
+			require.Len(t, ticks, 6)
+			for offset, count := range ticks {
+				require.Greaterf(t, count, 50, "too few rules are evaluated at tick %d", offset)
+			}
+		})
+	}
+}
@@ -16,12 +16,14 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/writer"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util/ticker"
 
 	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -92,6 +94,12 @@ type schedule struct {
 	alertsSender    AlertsSender
 	minRuleInterval time.Duration
 
+	// jitterEvaluations determines how the evaluations of rules with the same interval are spread across that interval.
+	jitterEvaluations string
+
+	// recordingWriter writes the results of recording rules.
+	recordingWriter writer.Writer
+
 	// schedulableAlertRules contains the alert rules that are considered for
 	// evaluation in the current tick. The evaluation of an alert rule in the
 	// current tick depends on its evaluation interval and when it was
@@ -110,6 +118,7 @@ type SchedulerCfg struct {
 	RuleStore       RulesStore
 	Metrics         *metrics.Scheduler
 	AlertSender     AlertsSender
+	RecordingWriter writer.Writer
 }
 
 // NewScheduler returns a new schedule.
@@ -129,8 +138,10 @@ func NewScheduler(cfg SchedulerCfg, appURL *url.URL, stateManager *state.Manager
 		disableGrafanaFolder:  cfg.Cfg.ReservedLabels.IsReservedLabelDisabled(ngmodels.FolderTitleLabel),
 		stateManager:          stateManager,
 		minRuleInterval:       cfg.Cfg.MinInterval,
+		jitterEvaluations:     cfg.Cfg.EvaluationJitter,
 		schedulableAlertRules: alertRulesRegistry{rules: make(map[ngmodels.AlertRuleKey]*ngmodels.AlertRule)},
 		alertsSender:          cfg.AlertSender,
+		recordingWriter:       cfg.RecordingWriter,
 	}
 
 	return &sch
@@ -242,7 +253,8 @@ func (sch *schedule) schedulePeriodic(ctx context.Context, t *ticker.T) error {
 				}
 
 				itemFrequency := item.IntervalSeconds / int64(sch.baseInterval.Seconds())
-				if item.IntervalSeconds != 0 && tickNum%itemFrequency == 0 {
+				offset := jitterOffsetInTicks(item, sch.baseInterval, sch.jitterEvaluations)
+				if item.IntervalSeconds != 0 && tickNum%itemFrequency == offset {
 					var folderTitle string
 					if !sch.disableGrafanaFolder {
 						title, ok := folderTitles[item.NamespaceUID]
@@ -313,6 +325,7 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 	orgID := fmt.Sprint(key.OrgID)
 	evalTotal := sch.metrics.EvalTotal.WithLabelValues(orgID)
 	evalDuration := sch.metrics.EvalDuration.WithLabelValues(orgID)
+	evalLateness := sch.metrics.EvalLateness.WithLabelValues(orgID)
 	evalTotalFailures := sch.metrics.EvalFailures.WithLabelValues(orgID)
 
 	clearState := func() {
@@ -326,6 +339,9 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 	evaluate := func(ctx context.Context, attempt int64, e *evaluation) {
 		logger := logger.New("version", e.rule.Version, "attempt", attempt, "now", e.scheduledAt)
 		start := sch.clock.Now()
+		if attempt == 0 {
+			evalLateness.Observe(start.Sub(e.scheduledAt).Seconds())
+		}
 
 		schedulerUser := &user.SignedInUser{
 			UserID:  -1,
@@ -341,7 +357,26 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 			},
 		}
 
-		results := sch.evaluator.ConditionEval(ctx, schedulerUser, e.rule.GetEvalCondition(), e.scheduledAt)
+		if e.rule.Type() == ngmodels.RuleTypeRecording {
+			err := sch.recordRule(ctx, schedulerUser, e)
+			dur := sch.clock.Now().Sub(start)
+			evalTotal.Inc()
+			evalDuration.Observe(dur.Seconds())
+			if err != nil {
+				evalTotalFailures.Inc()
+				logger.Error("failed to evaluate recording rule", "error", err, "duration", dur)
+			} else {
+				logger.Debug("recording rule evaluated", "duration", dur)
+			}
+			return
+		}
+
+		condition, err := eval.ConditionWithLoadedDimensions(e.rule.GetEvalCondition(), sch.getLoadedDimensions(e.rule))
+		if err != nil {
+			logger.Error("failed to set firing dimensions to the condition, thresholds to resolve are ignored", "error", err)
+			condition = e.rule.GetEvalCondition()
+		}
+		results := sch.evaluator.ConditionEval(ctx, schedulerUser, condition, e.scheduledAt)
 		dur := sch.clock.Now().Sub(start)
 		evalTotal.Inc()
 		evalDuration.Observe(dur.Seconds())
@@ -375,6 +410,7 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 	}
 
 	evalRunning := false
+	isPaused := false
 	var currentRuleVersion int64 = 0
 	defer sch.stopApplied(key)
 	for {
@@ -419,6 +455,16 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 						}
 						currentRuleVersion = newVersion
 					}
+					if ctx.rule.IsPaused {
+						if !isPaused {
+							logger.Info("rule is paused. Clear up the state and skip evaluation", "version", currentRuleVersion)
+							// resolve the alerts that were firing before the rule was paused
+							clearState()
+							isPaused = true
+						}
+						return nil
+					}
+					isPaused = false
 					evaluate(grafanaCtx, attempt, ctx)
 					return nil
 				})
@@ -455,6 +501,42 @@ func (sch *schedule) stopApplied(alertDefKey ngmodels.AlertRuleKey) {
 	sch.stopAppliedFunc(alertDefKey)
 }
 
+// recordRule evaluates queries and expressions of the recording rule and writes the result of the node it records from.
+func (sch *schedule) recordRule(ctx context.Context, user *user.SignedInUser, e *evaluation) error {
+	// do not query data sources if the result cannot be written
+	if !writer.IsConfigured(sch.recordingWriter) {
+		return writer.ErrNotConfigured
+	}
+	resp, err := sch.evaluator.QueriesAndExpressionsEval(ctx, user, e.rule.Data, e.scheduledAt)
+	if err != nil {
+		return err
+	}
+	result, ok := resp.Responses[e.rule.Record.From]
+	if !ok {
+		return fmt.Errorf("no result for the node %s", e.rule.Record.From)
+	}
+	if result.Error != nil {
+		return fmt.Errorf("failed to evaluate node %s: %w", e.rule.Record.From, result.Error)
+	}
+	if ctx.Err() != nil { // check if the context is not cancelled. The evaluation can be a long-running task.
+		return ctx.Err()
+	}
+	return sch.recordingWriter.Write(ctx, e.rule.Record.Metric, e.scheduledAt, result.Frames, e.rule.Labels)
+}
+
+// getLoadedDimensions returns the labels of the evaluation results of the rule whose states are pending or firing.
+// Threshold expressions that have a threshold to resolve use it instead of the threshold to fire for these results.
+func (sch *schedule) getLoadedDimensions(rule *ngmodels.AlertRule) []data.Labels {
+	var dimensions []data.Labels
+	for _, s := range sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID) {
+		if s.ResultLabels == nil || (s.State != eval.Alerting && s.State != eval.Pending) {
+			continue
+		}
+		dimensions = append(dimensions, s.ResultLabels)
+	}
+	return dimensions
+}
+
 func (sch *schedule) getRuleExtraLabels(evalCtx *evaluation) map[string]string {
 	extraLabels := make(map[string]string, 4)
 
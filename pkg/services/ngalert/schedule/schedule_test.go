@@ -14,6 +14,7 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/prometheus/client_golang/prometheus"
+	prometheusModel "github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -51,6 +52,7 @@ func TestWarmStateCache(t *testing.T) {
 			OrgID:        rule.OrgID,
 			CacheID:      `[["test1","testValue1"]]`,
 			Labels:       data.Labels{"test1": "testValue1"},
+			ResultLabels: data.Labels{"test1": "testValue1"},
 			State:        eval.Normal,
 			Results: []state.Evaluation{
 				{EvaluationTime: evaluationTime, EvaluationState: eval.Normal},
@@ -64,6 +66,7 @@ func TestWarmStateCache(t *testing.T) {
 			OrgID:        rule.OrgID,
 			CacheID:      `[["test2","testValue2"]]`,
 			Labels:       data.Labels{"test2": "testValue2"},
+			ResultLabels: data.Labels{"test2": "testValue2"},
 			State:        eval.Alerting,
 			Results: []state.Evaluation{
 				{EvaluationTime: evaluationTime, EvaluationState: eval.Alerting},
@@ -135,6 +138,48 @@ func TestWarmStateCache(t *testing.T) {
 	})
 }
 
+func TestWarmStateCacheRestoresResultLabels(t *testing.T) {
+	evaluationTime, err := time.Parse("2006-01-02", "2021-03-25")
+	require.NoError(t, err)
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, 1)
+
+	const mainOrgID int64 = 1
+	rule := tests.CreateTestAlertRuleWithLabels(t, ctx, dbstore, 600, mainOrgID, map[string]string{"team": "test"})
+
+	labels := models.InstanceLabels{
+		models.NamespaceUIDLabel:       rule.NamespaceUID,
+		models.RuleUIDLabel:            rule.UID,
+		models.FolderTitleLabel:        "folder",
+		prometheusModel.AlertNameLabel: rule.Title,
+		"team":                         "test",
+		"host":                         "host1",
+	}
+	_, hash, _ := labels.StringAndHash()
+	instance := models.AlertInstance{
+		AlertInstanceKey: models.AlertInstanceKey{
+			RuleOrgID:  rule.OrgID,
+			RuleUID:    rule.UID,
+			LabelsHash: hash,
+		},
+		CurrentState:      models.InstanceStateFiring,
+		LastEvalTime:      evaluationTime,
+		CurrentStateSince: evaluationTime.Add(-1 * time.Minute),
+		CurrentStateEnd:   evaluationTime.Add(1 * time.Minute),
+		Labels:            labels,
+	}
+	require.NoError(t, dbstore.SaveAlertInstances(ctx, instance))
+
+	st := state.NewManager(log.New("ngalert cache warming test"), testMetrics.GetStateMetrics(), nil, dbstore, dbstore, &image.NoopImageService{}, clock.NewMock(), &state.FakeHistorian{})
+	st.Warm(ctx)
+
+	states := st.GetStatesForRuleUID(rule.OrgID, rule.UID)
+	require.Len(t, states, 1)
+	require.Equal(t, eval.Alerting, states[0].State)
+	require.Equal(t, data.Labels(labels), states[0].Labels)
+	require.Equal(t, data.Labels{"host": "host1"}, states[0].ResultLabels)
+}
+
 func TestAlertingTicker(t *testing.T) {
 	ctx := context.Background()
 	_, dbstore := tests.SetupTestEnv(t, 1)
@@ -27,6 +27,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/writer"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -287,6 +288,62 @@ func TestSchedule_ruleRoutine(t *testing.T) {
 		})
 	})
 
+	t.Run("when rule is paused", func(t *testing.T) {
+		rule := models.AlertRuleGen(withQueryForState(t, eval.Alerting))()
+
+		evalChan := make(chan *evaluation)
+		evalAppliedChan := make(chan time.Time)
+
+		sender := AlertsSenderMock{}
+		sender.EXPECT().Send(rule.GetKey(), mock.Anything).Return()
+
+		sch, ruleStore, _, reg := createSchedule(evalAppliedChan, &sender)
+		ruleStore.PutRule(context.Background(), rule)
+
+		go func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+			_ = sch.ruleRoutine(ctx, rule.GetKey(), evalChan, make(chan ruleVersion))
+		}()
+
+		evalChan <- &evaluation{
+			scheduledAt: sch.clock.Now(),
+			rule:        rule,
+		}
+		waitForTimeChannel(t, evalAppliedChan)
+		require.NotEmpty(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID))
+		sender.AssertNumberOfCalls(t, "Send", 1)
+
+		paused := models.CopyRule(rule)
+		paused.IsPaused = true
+		for i := 0; i < 2; i++ {
+			evalChan <- &evaluation{
+				scheduledAt: sch.clock.Now(),
+				rule:        paused,
+			}
+			waitForTimeChannel(t, evalAppliedChan)
+		}
+
+		t.Run("it should clear the state and expire firing alerts", func(t *testing.T) {
+			require.Empty(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID))
+			sender.AssertNumberOfCalls(t, "Send", 2)
+			args, ok := sender.Calls[1].Arguments[1].(definitions.PostableAlerts)
+			require.Truef(t, ok, fmt.Sprintf("expected argument of function was supposed to be 'definitions.PostableAlerts' but got %T", sender.Calls[1].Arguments[1]))
+			require.Len(t, args.PostableAlerts, 1)
+		})
+
+		t.Run("it should not evaluate the rule", func(t *testing.T) {
+			expectedMetric := fmt.Sprintf(
+				`# HELP grafana_alerting_rule_evaluations_total The total number of rule evaluations.
+        	            	# TYPE grafana_alerting_rule_evaluations_total counter
+        	            	grafana_alerting_rule_evaluations_total{org="%[1]d"} 1
+				`, rule.OrgID)
+
+			err := testutil.GatherAndCompare(reg, bytes.NewBufferString(expectedMetric), "grafana_alerting_rule_evaluations_total")
+			require.NoError(t, err)
+		})
+	})
+
 	t.Run("when evaluation fails", func(t *testing.T) {
 		rule := models.AlertRuleGen(withQueryForState(t, eval.Error))()
 		rule.ExecErrState = models.ErrorErrState
@@ -420,6 +477,110 @@ func TestSchedule_ruleRoutine(t *testing.T) {
 
 		require.NotEmpty(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID))
 	})
+
+	t.Run("when rule is a recording rule", func(t *testing.T) {
+		rule := models.AlertRuleGen(withQueryForState(t, eval.Alerting), func(rule *models.AlertRule) {
+			rule.Record = &models.Record{Metric: "test_metric", From: "A"}
+		})()
+
+		evalChan := make(chan *evaluation)
+		evalAppliedChan := make(chan time.Time)
+
+		sender := AlertsSenderMock{}
+		sender.EXPECT().Send(rule.GetKey(), mock.Anything).Return()
+
+		sch, ruleStore, _, reg := createSchedule(evalAppliedChan, &sender)
+		ruleStore.PutRule(context.Background(), rule)
+		writer := &fakeRecordingWriter{}
+		sch.recordingWriter = writer
+
+		go func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+			_ = sch.ruleRoutine(ctx, rule.GetKey(), evalChan, make(chan ruleVersion))
+		}()
+
+		expectedTime := time.UnixMicro(rand.Int63())
+		evalChan <- &evaluation{
+			scheduledAt: expectedTime,
+			rule:        rule,
+		}
+
+		waitForTimeChannel(t, evalAppliedChan)
+
+		t.Run("it should write the result of the recorded node", func(t *testing.T) {
+			writes := writer.getWrites()
+			require.Len(t, writes, 1)
+			require.Equal(t, "test_metric", writes[0].Name)
+			require.Equal(t, expectedTime, writes[0].T)
+			require.Equal(t, rule.Labels, writes[0].ExtraLabels)
+			require.NotEmpty(t, writes[0].Frames)
+		})
+
+		t.Run("it should not produce alert states", func(t *testing.T) {
+			sender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+			require.Empty(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID))
+		})
+
+		t.Run("it reports metrics", func(t *testing.T) {
+			expectedMetric := fmt.Sprintf(
+				`# HELP grafana_alerting_rule_evaluation_failures_total The total number of rule evaluation failures.
+        	            	# TYPE grafana_alerting_rule_evaluation_failures_total counter
+        	            	grafana_alerting_rule_evaluation_failures_total{org="%[1]d"} 0
+        	            	# HELP grafana_alerting_rule_evaluations_total The total number of rule evaluations.
+        	            	# TYPE grafana_alerting_rule_evaluations_total counter
+        	            	grafana_alerting_rule_evaluations_total{org="%[1]d"} 1
+				`, rule.OrgID)
+
+			err := testutil.GatherAndCompare(reg, bytes.NewBufferString(expectedMetric), "grafana_alerting_rule_evaluation_failures_total", "grafana_alerting_rule_evaluations_total")
+			require.NoError(t, err)
+		})
+	})
+
+	t.Run("when recording rules are not enabled", func(t *testing.T) {
+		rule := models.AlertRuleGen(withQueryForState(t, eval.Alerting), func(rule *models.AlertRule) {
+			rule.Record = &models.Record{Metric: "test_metric", From: "A"}
+		})()
+
+		evalChan := make(chan *evaluation)
+		evalAppliedChan := make(chan time.Time)
+
+		sch, ruleStore, _, reg := createSchedule(evalAppliedChan, nil)
+		evaluator := &eval.FakeEvaluator{}
+		sch.evaluator = evaluator
+		ruleStore.PutRule(context.Background(), rule)
+
+		go func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+			_ = sch.ruleRoutine(ctx, rule.GetKey(), evalChan, make(chan ruleVersion))
+		}()
+
+		evalChan <- &evaluation{
+			scheduledAt: time.UnixMicro(rand.Int63()),
+			rule:        rule,
+		}
+
+		waitForTimeChannel(t, evalAppliedChan)
+
+		t.Run("it does not query data sources", func(t *testing.T) {
+			evaluator.AssertNotCalled(t, "QueriesAndExpressionsEval", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+
+		t.Run("it reports the evaluation as failed", func(t *testing.T) {
+			expectedMetric := fmt.Sprintf(
+				`# HELP grafana_alerting_rule_evaluation_failures_total The total number of rule evaluation failures.
+        	            	# TYPE grafana_alerting_rule_evaluation_failures_total counter
+        	            	grafana_alerting_rule_evaluation_failures_total{org="%[1]d"} 1
+        	            	# HELP grafana_alerting_rule_evaluations_total The total number of rule evaluations.
+        	            	# TYPE grafana_alerting_rule_evaluations_total counter
+        	            	grafana_alerting_rule_evaluations_total{org="%[1]d"} 1
+				`, rule.OrgID)
+
+			err := testutil.GatherAndCompare(reg, bytes.NewBufferString(expectedMetric), "grafana_alerting_rule_evaluation_failures_total", "grafana_alerting_rule_evaluations_total")
+			require.NoError(t, err)
+		})
+	})
 }
 
 func TestSchedule_UpdateAlertRule(t *testing.T) {
@@ -478,6 +639,31 @@ func TestSchedule_DeleteAlertRule(t *testing.T) {
 	})
 }
 
+func TestSchedule_getLoadedDimensions(t *testing.T) {
+	sch := setupScheduler(t, nil, nil, nil, nil, nil)
+	rule := models.AlertRuleGen()()
+
+	newState := func(s eval.State, resultLabels data.Labels) *state.State {
+		return &state.State{
+			AlertRuleUID: rule.UID,
+			OrgID:        rule.OrgID,
+			CacheID:      util.GenerateShortUID(),
+			State:        s,
+			ResultLabels: resultLabels,
+		}
+	}
+	sch.stateManager.Put([]*state.State{
+		newState(eval.Alerting, data.Labels{"host": "alerting"}),
+		newState(eval.Pending, data.Labels{"host": "pending"}),
+		newState(eval.Normal, data.Labels{"host": "normal"}),
+		newState(eval.NoData, data.Labels{"host": "nodata"}),
+		// restored from the database
+		newState(eval.Alerting, nil),
+	})
+
+	require.ElementsMatch(t, []data.Labels{{"host": "alerting"}, {"host": "pending"}}, sch.getLoadedDimensions(rule))
+}
+
 func setupScheduler(t *testing.T, rs *fakeRulesStore, is *state.FakeInstanceStore, registry *prometheus.Registry, senderMock *AlertsSenderMock, evalMock *eval.FakeEvaluator) *schedule {
 	t.Helper()
 
@@ -525,6 +711,8 @@ func setupScheduler(t *testing.T, rs *fakeRulesStore, is *state.FakeInstanceStor
 		Logger:      logger,
 		Metrics:     m.GetSchedulerMetrics(),
 		AlertSender: senderMock,
+		// Recording rules are not enabled unless a test sets its own writer.
+		RecordingWriter: writer.New(setting.UnifiedAlertingRecordingRulesSettings{}, logger),
 	}
 
 	stateRs := state.FakeRuleReader{}
@@ -2,9 +2,12 @@ package schedule
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
@@ -74,3 +77,28 @@ func (f *fakeRulesStore) PutRule(_ context.Context, rules ...*models.AlertRule)
 func (f *fakeRulesStore) getNamespaceTitle(uid string) string {
 	return "TEST-FOLDER-" + uid
 }
+
+type fakeRecordingWrite struct {
+	Name        string
+	T           time.Time
+	Frames      data.Frames
+	ExtraLabels map[string]string
+}
+
+type fakeRecordingWriter struct {
+	mtx    sync.Mutex
+	writes []fakeRecordingWrite
+}
+
+func (f *fakeRecordingWriter) Write(_ context.Context, name string, t time.Time, frames data.Frames, extraLabels map[string]string) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.writes = append(f.writes, fakeRecordingWrite{Name: name, T: t, Frames: frames, ExtraLabels: extraLabels})
+	return nil
+}
+
+func (f *fakeRecordingWriter) getWrites() []fakeRecordingWrite {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return append([]fakeRecordingWrite(nil), f.writes...)
+}
@@ -113,6 +113,7 @@ func (rs *ruleStates) getOrCreate(ctx context.Context, log log.Logger, alertRule
 		}
 		state.Annotations = annotations
 		state.Values = values
+		state.ResultLabels = result.Instance
 		rs.states[id] = state
 		return state
 	}
@@ -124,6 +125,7 @@ func (rs *ruleStates) getOrCreate(ctx context.Context, log log.Logger, alertRule
 		OrgID:              alertRule.OrgID,
 		CacheID:            id,
 		Labels:             lbs,
+		ResultLabels:       result.Instance,
 		Annotations:        annotations,
 		EvaluationDuration: result.EvaluationDuration,
 		Values:             values,
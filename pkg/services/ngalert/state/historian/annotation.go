@@ -2,7 +2,9 @@ package historian
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -15,17 +17,25 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 )
 
+// RuleStore represents the ability to fetch alert rules. It is used to find the rules the annotations belong to.
+type RuleStore interface {
+	GetAlertRuleByUID(ctx context.Context, query *ngmodels.GetAlertRuleByUIDQuery) error
+	ListAlertRules(ctx context.Context, query *ngmodels.ListAlertRulesQuery) error
+}
+
 // AnnotationStateHistorian is an implementation of state.Historian that uses Grafana Annotations as the backing datastore.
 type AnnotationStateHistorian struct {
 	annotations annotations.Repository
 	dashboards  *dashboardResolver
+	rules       RuleStore
 	log         log.Logger
 }
 
-func NewAnnotationHistorian(annotations annotations.Repository, dashboards dashboards.DashboardService, log log.Logger) *AnnotationStateHistorian {
+func NewAnnotationHistorian(annotations annotations.Repository, dashboards dashboards.DashboardService, rules RuleStore, log log.Logger) *AnnotationStateHistorian {
 	return &AnnotationStateHistorian{
 		annotations: annotations,
 		dashboards:  newDashboardResolver(dashboards, log, defaultDashboardCacheExpiry),
+		rules:       rules,
 		log:         log,
 	}
 }
@@ -71,6 +81,157 @@ func (h *AnnotationStateHistorian) RecordState(ctx context.Context, rule *ngmode
 	}
 }
 
+// QueryStates returns the state transitions that match the query as a data frame with one row per transition, oldest first.
+// Annotations do not store the labels and values of a transition in a structured way. Therefore, the labels are parsed from
+// the text of the annotation, which cannot be done reliably if label values contain ", ", and the values are always empty.
+func (h *AnnotationStateHistorian) QueryStates(ctx context.Context, query ngmodels.HistoryQuery) (*data.Frame, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultQueryLimit
+	}
+	q := annotations.ItemQuery{
+		OrgId:        query.OrgID,
+		From:         query.From.UnixMilli(),
+		To:           query.To.UnixMilli(),
+		Type:         "alert",
+		Limit:        int64(limit),
+		SignedInUser: query.SignedInUser,
+	}
+
+	isVisible, ok := ruleFilter(query)
+	if !ok {
+		return newStateHistoryFrame(0), nil
+	}
+
+	// annotations reference the alert rule by its ID.
+	rules := make(map[int64]*ngmodels.AlertRule)
+	if query.RuleUID != "" {
+		ruleQuery := ngmodels.GetAlertRuleByUIDQuery{OrgID: query.OrgID, UID: query.RuleUID}
+		if err := h.rules.GetAlertRuleByUID(ctx, &ruleQuery); err != nil {
+			return nil, fmt.Errorf("failed to get alert rule: %w", err)
+		}
+		rules[ruleQuery.Result.ID] = ruleQuery.Result
+		q.AlertId = ruleQuery.Result.ID
+	} else {
+		rulesQuery := ngmodels.ListAlertRulesQuery{OrgID: query.OrgID, NamespaceUIDs: query.NamespaceUIDs}
+		if err := h.rules.ListAlertRules(ctx, &rulesQuery); err != nil {
+			return nil, fmt.Errorf("failed to list alert rules: %w", err)
+		}
+		for _, rule := range rulesQuery.Result {
+			if isVisible(rule.UID) {
+				rules[rule.ID] = rule
+			}
+		}
+	}
+
+	type row struct {
+		t      time.Time
+		rule   *ngmodels.AlertRule
+		item   *annotations.ItemDTO
+		labels json.RawMessage
+	}
+	rows := make([]row, 0)
+	seen := make(map[int64]struct{})
+	// the annotations are filtered after they are fetched. Therefore, pages of annotations are fetched, latest first,
+	// until there are enough transitions or there are no more annotations in the time range.
+	for {
+		items, err := h.annotations.Find(ctx, &q)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find annotations: %w", err)
+		}
+		found := 0
+		oldest := q.To
+		for _, item := range items {
+			if item.Time < oldest {
+				oldest = item.Time
+			}
+			if _, ok := seen[item.Id]; ok {
+				continue
+			}
+			seen[item.Id] = struct{}{}
+			found++
+			rule, ok := rules[item.AlertId]
+			if !ok {
+				// the annotation belongs to a legacy alert, to a rule that was deleted or to a rule the user cannot access.
+				continue
+			}
+			labels := parseAnnotationLabels(rule.Title, item.Text)
+			if !matchesLabels(labels, query.Labels) {
+				continue
+			}
+			labelsJSON, err := json.Marshal(labels)
+			if err != nil {
+				return nil, fmt.Errorf("failed to serialize labels: %w", err)
+			}
+			rows = append(rows, row{t: time.UnixMilli(item.Time), rule: rule, item: item, labels: labelsJSON})
+		}
+		if len(rows) >= limit || int64(len(items)) < q.Limit {
+			break
+		}
+		if found == 0 {
+			// the page only has annotations at the same time that were fetched before. Annotations cannot be paged
+			// within the same time, therefore, the page is enlarged until it reaches past that time.
+			q.Limit *= 2
+			continue
+		}
+		// the next page ends with the oldest annotation of this page. Annotations at the same time are fetched again but skipped.
+		q.To = oldest
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].t.Before(rows[j].t)
+	})
+	// the latest transitions are returned
+	if len(rows) > limit {
+		rows = rows[len(rows)-limit:]
+	}
+
+	frame := newStateHistoryFrame(len(rows))
+	for i, r := range rows {
+		frame.Set(0, i, r.t)
+		frame.Set(1, i, r.rule.UID)
+		frame.Set(2, i, r.item.PrevState)
+		frame.Set(3, i, r.item.NewState)
+		frame.Set(4, i, r.labels)
+		frame.Set(5, i, json.RawMessage("{}"))
+	}
+	return frame, nil
+}
+
+// parseAnnotationLabels extracts the labels from the text of an annotation created by RecordState.
+func parseAnnotationLabels(title, text string) map[string]string {
+	labels := make(map[string]string)
+	start := len(title) + len(" {")
+	if !strings.HasPrefix(text, title+" {") {
+		// the rule was renamed after the annotation was created.
+		start = strings.Index(text, " {") + len(" {")
+		if start < len(" {") {
+			return labels
+		}
+	}
+	end := strings.LastIndex(text, "} - ")
+	if end < start {
+		return labels
+	}
+	for _, pair := range strings.Split(text[start:end], ", ") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		labels[kv[0]] = kv[1]
+	}
+	return labels
+}
+
+// matchesLabels returns true if labels contain all the expected labels.
+func matchesLabels(labels map[string]string, expected map[string]string) bool {
+	for k, v := range expected {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
 func removePrivateLabels(labels data.Labels) data.Labels {
 	result := make(data.Labels)
 	for k, v := range labels {
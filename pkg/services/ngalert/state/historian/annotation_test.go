@@ -0,0 +1,178 @@
+package historian
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+)
+
+func TestParseAnnotationLabels(t *testing.T) {
+	testCases := []struct {
+		name     string
+		title    string
+		text     string
+		expected map[string]string
+	}{
+		{
+			name:     "labels of annotation",
+			title:    "rule",
+			text:     "rule {job=api, team=a} - Alerting",
+			expected: map[string]string{"job": "api", "team": "a"},
+		},
+		{
+			name:     "no labels",
+			title:    "rule",
+			text:     "rule {} - Normal",
+			expected: map[string]string{},
+		},
+		{
+			name:     "title with braces",
+			title:    "rule {x} - y",
+			text:     "rule {x} - y {team=a} - Pending",
+			expected: map[string]string{"team": "a"},
+		},
+		{
+			name:     "renamed rule",
+			title:    "new title",
+			text:     "old title {team=a} - Alerting",
+			expected: map[string]string{"team": "a"},
+		},
+		{
+			name:     "unexpected text",
+			title:    "rule",
+			text:     "something else",
+			expected: map[string]string{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, parseAnnotationLabels(tc.title, tc.text))
+		})
+	}
+}
+
+func TestMatchesLabels(t *testing.T) {
+	labels := map[string]string{"job": "api", "team": "a"}
+	require.True(t, matchesLabels(labels, nil))
+	require.True(t, matchesLabels(labels, map[string]string{"team": "a"}))
+	require.False(t, matchesLabels(labels, map[string]string{"team": "b"}))
+	require.False(t, matchesLabels(labels, map[string]string{"env": "prod"}))
+}
+
+func TestAnnotationStateHistorianQueryStates(t *testing.T) {
+	visible := &ngmodels.AlertRule{ID: 1, UID: "visible", OrgID: 1, Title: "visible"}
+	hidden := &ngmodels.AlertRule{ID: 2, UID: "hidden", OrgID: 1, Title: "hidden"}
+	rules := fakes.NewRuleStore(t)
+	rules.PutRule(context.Background(), visible, hidden)
+
+	repo := &fakePagedAnnotations{}
+	for i, rule := range []*ngmodels.AlertRule{visible, hidden, hidden, visible, hidden, hidden, visible} {
+		team := "a"
+		if i == 6 {
+			team = "b"
+		}
+		repo.items = append(repo.items, &annotations.ItemDTO{
+			Id:       int64(i + 1),
+			AlertId:  rule.ID,
+			Time:     int64(1000 * (i + 1)),
+			Text:     rule.Title + " {team=" + team + "} - Alerting",
+			NewState: "Alerting",
+		})
+	}
+	h := NewAnnotationHistorian(repo, nil, rules, log.NewNopLogger())
+
+	t.Run("should filter before the limit is applied", func(t *testing.T) {
+		repo.queries = 0
+		frame, err := h.QueryStates(context.Background(), ngmodels.HistoryQuery{
+			OrgID:    1,
+			Labels:   map[string]string{"team": "a"},
+			RuleUIDs: []string{visible.UID},
+			From:     time.UnixMilli(1),
+			To:       time.UnixMilli(10000),
+			Limit:    2,
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, time.UnixMilli(1000), frame.At(0, 0))
+		require.Equal(t, time.UnixMilli(4000), frame.At(0, 1))
+		require.Equal(t, "visible", frame.At(1, 0))
+		require.Greater(t, repo.queries, 1)
+	})
+
+	t.Run("should return nothing if no rule is visible", func(t *testing.T) {
+		repo.queries = 0
+		frame, err := h.QueryStates(context.Background(), ngmodels.HistoryQuery{
+			OrgID:    1,
+			RuleUIDs: []string{},
+			From:     time.UnixMilli(1),
+			To:       time.UnixMilli(10000),
+		})
+		require.NoError(t, err)
+		require.Equal(t, 0, frame.Rows())
+		require.Equal(t, 0, repo.queries)
+	})
+}
+
+func TestAnnotationStateHistorianQueryStatesSameTime(t *testing.T) {
+	visible := &ngmodels.AlertRule{ID: 1, UID: "visible", OrgID: 1, Title: "visible"}
+	hidden := &ngmodels.AlertRule{ID: 2, UID: "hidden", OrgID: 1, Title: "hidden"}
+	rules := fakes.NewRuleStore(t)
+	rules.PutRule(context.Background(), visible, hidden)
+
+	// more annotations than the limit share the time of the latest annotation.
+	repo := &fakePagedAnnotations{}
+	for i, rule := range []*ngmodels.AlertRule{visible, hidden, hidden, hidden, hidden, visible} {
+		at := int64(5000)
+		if i == 0 {
+			at = 1000
+		}
+		repo.items = append(repo.items, &annotations.ItemDTO{
+			Id:       int64(i + 1),
+			AlertId:  rule.ID,
+			Time:     at,
+			Text:     rule.Title + " {team=a} - Alerting",
+			NewState: "Alerting",
+		})
+	}
+	h := NewAnnotationHistorian(repo, nil, rules, log.NewNopLogger())
+
+	frame, err := h.QueryStates(context.Background(), ngmodels.HistoryQuery{
+		OrgID:    1,
+		RuleUIDs: []string{visible.UID},
+		From:     time.UnixMilli(1),
+		To:       time.UnixMilli(10000),
+		Limit:    2,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, 2, frame.Rows())
+	require.Equal(t, time.UnixMilli(1000), frame.At(0, 0))
+	require.Equal(t, time.UnixMilli(5000), frame.At(0, 1))
+}
+
+// fakePagedAnnotations returns the annotations that match the time range of the query, latest first, up to the limit.
+type fakePagedAnnotations struct {
+	annotations.Repository
+	items   []*annotations.ItemDTO
+	queries int
+}
+
+func (f *fakePagedAnnotations) Find(_ context.Context, query *annotations.ItemQuery) ([]*annotations.ItemDTO, error) {
+	f.queries++
+	result := make([]*annotations.ItemDTO, 0)
+	for i := len(f.items) - 1; i >= 0 && int64(len(result)) < query.Limit; i-- {
+		item := f.items[i]
+		if item.Time <= query.To && item.Time >= query.From && (query.AlertId == 0 || item.AlertId == query.AlertId) {
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
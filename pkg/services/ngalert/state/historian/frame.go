@@ -0,0 +1,45 @@
+package historian
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// defaultQueryLimit is the maximum number of state transitions returned by a query that does not specify a limit.
+const defaultQueryLimit = 1000
+
+// newStateHistoryFrame creates a data frame with the given number of rows that holds state transitions.
+// All backends return the history in this shape so that the consumers do not depend on the backend.
+func newStateHistoryFrame(length int) *data.Frame {
+	return data.NewFrame("states",
+		data.NewField("time", nil, make([]time.Time, length)),
+		data.NewField("ruleUID", nil, make([]string, length)),
+		data.NewField("previous", nil, make([]string, length)),
+		data.NewField("current", nil, make([]string, length)),
+		data.NewField("labels", nil, make([]json.RawMessage, length)),
+		data.NewField("values", nil, make([]json.RawMessage, length)),
+	)
+}
+
+// ruleFilter returns a function that reports whether the transitions of the rule match the rules of the query.
+// The second result is false if the query cannot match any transition.
+func ruleFilter(query ngmodels.HistoryQuery) (func(ruleUID string) bool, bool) {
+	if query.RuleUIDs == nil {
+		return func(string) bool { return true }, true
+	}
+	visible := make(map[string]struct{}, len(query.RuleUIDs))
+	for _, uid := range query.RuleUIDs {
+		visible[uid] = struct{}{}
+	}
+	if _, ok := visible[query.RuleUID]; query.RuleUID != "" && !ok {
+		return nil, false
+	}
+	return func(ruleUID string) bool {
+		_, ok := visible[ruleUID]
+		return ok
+	}, len(visible) > 0
+}
@@ -0,0 +1,336 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// stateHistoryLabelKey and stateHistoryLabelValue mark the streams that contain the state history in Loki.
+	stateHistoryLabelKey   = "from"
+	stateHistoryLabelValue = "state-history"
+	orgIDLabel             = "orgID"
+	groupLabel             = "group"
+	folderUIDLabel         = "folderUID"
+
+	lokiEntrySchemaVersion = 1
+)
+
+// lokiEntry is the log line of a single state transition.
+type lokiEntry struct {
+	SchemaVersion int                   `json:"schemaVersion"`
+	RuleUID       string                `json:"ruleUID"`
+	RuleTitle     string                `json:"ruleTitle"`
+	Previous      string                `json:"previous"`
+	Current       string                `json:"current"`
+	Values        map[string]floatValue `json:"values,omitempty"`
+	Labels        map[string]string     `json:"labels"`
+}
+
+// floatValue is a float64 that is serialized as a string if it is not a finite number, because JSON does not support NaN and infinity.
+type floatValue float64
+
+func (f floatValue) MarshalJSON() ([]byte, error) {
+	v := float64(f)
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return json.Marshal(strconv.FormatFloat(v, 'f', -1, 64))
+	}
+	return json.Marshal(v)
+}
+
+const (
+	// lokiQueueSize is the number of state transitions that can wait to be pushed to Loki. Transitions are dropped if the queue is full.
+	lokiQueueSize = 10000
+	// lokiBatchSize is the maximum number of state transitions that are pushed to Loki in a single request.
+	lokiBatchSize = 500
+	// lokiFlushInterval is how often the state transitions are pushed to Loki if the batch is not full.
+	lokiFlushInterval = time.Second
+)
+
+// LokiStateHistorian is an implementation of state.Historian that pushes the state transitions to Loki as structured log lines.
+// The transitions are queued and pushed in batches by Run, so recording a transition does not wait for Loki.
+type LokiStateHistorian struct {
+	client        *httpLokiClient
+	queue         chan stream
+	batchSize     int
+	flushInterval time.Duration
+	log           log.Logger
+}
+
+func NewLokiHistorian(cfg setting.UnifiedAlertingStateHistorySettings, log log.Logger) (*LokiStateHistorian, error) {
+	client, err := newLokiClient(cfg, log)
+	if err != nil {
+		return nil, err
+	}
+	return &LokiStateHistorian{
+		client:        client,
+		queue:         make(chan stream, lokiQueueSize),
+		batchSize:     lokiBatchSize,
+		flushInterval: lokiFlushInterval,
+		log:           log,
+	}, nil
+}
+
+func (h *LokiStateHistorian) RecordState(ctx context.Context, rule *ngmodels.AlertRule, labels data.Labels, evaluatedAt time.Time, currentData, previousData state.InstanceStateAndReason) {
+	h.log.Debug("alert state changed queueing it for loki", "alertRuleUID", rule.UID, "newState", currentData.String(), "oldState", previousData.String())
+
+	entry := lokiEntry{
+		SchemaVersion: lokiEntrySchemaVersion,
+		RuleUID:       rule.UID,
+		RuleTitle:     rule.Title,
+		Previous:      previousData.String(),
+		Current:       currentData.String(),
+		Labels:        removePrivateLabels(labels),
+	}
+	if len(currentData.Values) > 0 {
+		entry.Values = make(map[string]floatValue, len(currentData.Values))
+		for k, v := range currentData.Values {
+			entry.Values[k] = floatValue(v)
+		}
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		h.log.Error("error serializing state transition", "alertRuleUID", rule.UID, "err", err.Error())
+		return
+	}
+
+	s := stream{
+		Stream: map[string]string{
+			stateHistoryLabelKey: stateHistoryLabelValue,
+			orgIDLabel:           strconv.FormatInt(rule.OrgID, 10),
+			groupLabel:           rule.RuleGroup,
+			folderUIDLabel:       rule.NamespaceUID,
+		},
+		Values: []sample{{T: evaluatedAt, V: string(line)}},
+	}
+	select {
+	case h.queue <- s:
+	default:
+		h.log.Error("state history queue is full, dropping state transition", "alertRuleUID", rule.UID)
+	}
+}
+
+// Run pushes the queued state transitions to Loki in batches until the context is cancelled.
+// The transitions that are still queued when the context is cancelled are pushed before Run returns.
+func (h *LokiStateHistorian) Run(ctx context.Context) error {
+	ticker := time.NewTicker(h.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]stream, 0, h.batchSize)
+	flush := func(ctx context.Context) {
+		if len(batch) == 0 {
+			return
+		}
+		if err := h.client.push(ctx, mergeStreams(batch)); err != nil {
+			h.log.Error("error pushing state transitions to loki", "transitions", len(batch), "err", err.Error())
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case s := <-h.queue:
+			batch = append(batch, s)
+			if len(batch) >= h.batchSize {
+				flush(ctx)
+			}
+		case <-ticker.C:
+			flush(ctx)
+		case <-ctx.Done():
+			// the context is cancelled, therefore, the remaining pushes are only limited by the client timeout.
+			for {
+				select {
+				case s := <-h.queue:
+					batch = append(batch, s)
+					if len(batch) >= h.batchSize {
+						flush(context.Background())
+					}
+				default:
+					flush(context.Background())
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// mergeStreams merges the streams with the same labels, so each stream is pushed to Loki once.
+func mergeStreams(streams []stream) []stream {
+	byLabels := make(map[string]int, len(streams))
+	result := make([]stream, 0, len(streams))
+	for _, s := range streams {
+		key := data.Labels(s.Stream).String()
+		if i, ok := byLabels[key]; ok {
+			result[i].Values = append(result[i].Values, s.Values...)
+			continue
+		}
+		byLabels[key] = len(result)
+		result = append(result, stream{
+			Stream: s.Stream,
+			Values: append(make([]sample, 0, len(s.Values)), s.Values...),
+		})
+	}
+	for _, s := range result {
+		sort.SliceStable(s.Values, func(i, j int) bool {
+			return s.Values[i].T.Before(s.Values[j].T)
+		})
+	}
+	return result
+}
+
+// QueryStates returns the state transitions that match the query as a data frame with one row per transition, oldest first.
+func (h *LokiStateHistorian) QueryStates(ctx context.Context, query ngmodels.HistoryQuery) (*data.Frame, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultQueryLimit
+	}
+	isVisible, ok := ruleFilter(query)
+	if !ok {
+		return newStateHistoryFrame(0), nil
+	}
+
+	type row struct {
+		t     time.Time
+		entry lokiEntry
+		// labels and values are kept as is because they are returned as JSON.
+		labels json.RawMessage
+		values json.RawMessage
+	}
+	rows := make([]row, 0)
+	logQL := buildLogQuery(query)
+	type entryKey struct {
+		t    int64
+		line string
+	}
+	seen := make(map[entryKey]struct{})
+	start := query.From
+	// some entries are filtered after they are fetched. Therefore, pages of entries are fetched, oldest first,
+	// until there are enough transitions or there are no more entries in the time range.
+	for {
+		res, err := h.client.rangeQuery(ctx, logQL, start, query.To, limit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query loki: %w", err)
+		}
+		fetched, found := 0, 0
+		latest := start
+		for _, s := range res.Data.Result {
+			for _, smp := range s.Values {
+				fetched++
+				if smp.T.After(latest) {
+					latest = smp.T
+				}
+				key := entryKey{t: smp.T.UnixNano(), line: smp.V}
+				if _, ok := seen[key]; ok {
+					continue
+				}
+				seen[key] = struct{}{}
+				found++
+
+				var raw struct {
+					lokiEntry
+					Labels json.RawMessage `json:"labels"`
+					Values json.RawMessage `json:"values"`
+				}
+				if err := json.Unmarshal([]byte(smp.V), &raw); err != nil {
+					h.log.Warn("skipping state history entry that cannot be parsed", "err", err)
+					continue
+				}
+				if raw.SchemaVersion != lokiEntrySchemaVersion {
+					h.log.Warn("skipping state history entry with unsupported schema", "schemaVersion", raw.SchemaVersion)
+					continue
+				}
+				if !isVisible(raw.RuleUID) {
+					continue
+				}
+				// line filters can match other fields of the entry, therefore, the labels are matched again.
+				if len(query.Labels) > 0 {
+					var labels map[string]string
+					if err := json.Unmarshal(raw.Labels, &labels); err != nil || !matchLabels(labels, query.Labels) {
+						continue
+					}
+				}
+				rows = append(rows, row{t: smp.T, entry: raw.lokiEntry, labels: raw.Labels, values: raw.Values})
+			}
+		}
+		if len(rows) >= limit || fetched < limit || found == 0 {
+			break
+		}
+		// the next page starts with the latest entry of this page. Entries at the same time are fetched again but skipped.
+		start = latest
+	}
+	// streams are returned in no particular order, therefore, the entries of all streams are merged by time.
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].t.Before(rows[j].t)
+	})
+	if len(rows) > limit {
+		rows = rows[:limit]
+	}
+
+	frame := newStateHistoryFrame(len(rows))
+	for i, r := range rows {
+		values := r.values
+		if len(values) == 0 {
+			values = json.RawMessage("{}")
+		}
+		frame.Set(0, i, r.t)
+		frame.Set(1, i, r.entry.RuleUID)
+		frame.Set(2, i, r.entry.Previous)
+		frame.Set(3, i, r.entry.Current)
+		frame.Set(4, i, r.labels)
+		frame.Set(5, i, values)
+	}
+	return frame, nil
+}
+
+// buildLogQuery builds the LogQL query that selects the state history of the organization and filters it by rule UID and labels.
+// Labels are matched by line filters on their JSON encoding in the log line rather than by the labels extracted by the json parser,
+// because the parser replaces invalid characters of the label names, which can map different labels to the same name.
+func buildLogQuery(query ngmodels.HistoryQuery) string {
+	b := strings.Builder{}
+	b.WriteString(fmt.Sprintf(`{%s=%q,%s=%q}`, stateHistoryLabelKey, stateHistoryLabelValue, orgIDLabel, strconv.FormatInt(query.OrgID, 10)))
+
+	keys := make([]string, 0, len(query.Labels))
+	for k := range query.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.WriteString(fmt.Sprintf(" |= %q", labelFilter(k, query.Labels[k])))
+	}
+
+	b.WriteString(" | json")
+	if query.RuleUID != "" {
+		b.WriteString(fmt.Sprintf(" | ruleUID=%q", query.RuleUID))
+	}
+	return b.String()
+}
+
+// labelFilter returns the label as it is encoded in the labels of the log line.
+func labelFilter(key, value string) string {
+	k, _ := json.Marshal(key)
+	v, _ := json.Marshal(value)
+	return string(k) + ":" + string(v)
+}
+
+// matchLabels returns true if the labels contain all the labels of the query.
+func matchLabels(labels map[string]string, query map[string]string) bool {
+	for k, v := range query {
+		if l, ok := labels[k]; !ok || l != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,171 @@
+package historian
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	lokiPushPath       = "/loki/api/v1/push"
+	lokiQueryRangePath = "/loki/api/v1/query_range"
+)
+
+// stream is a set of log lines that share the same labels, as used by the Loki push and query APIs.
+type stream struct {
+	Stream map[string]string `json:"stream"`
+	Values []sample          `json:"values"`
+}
+
+// sample is a single log line with its timestamp.
+type sample struct {
+	T time.Time
+	V string
+}
+
+// MarshalJSON encodes the sample as a tuple of the timestamp in nanoseconds as a string and the log line, as expected by Loki.
+func (s sample) MarshalJSON() ([]byte, error) {
+	return json.Marshal([2]string{strconv.FormatInt(s.T.UnixNano(), 10), s.V})
+}
+
+func (s *sample) UnmarshalJSON(b []byte) error {
+	var tuple [2]string
+	if err := json.Unmarshal(b, &tuple); err != nil {
+		return fmt.Errorf("failed to deserialize sample: %w", err)
+	}
+	ns, err := strconv.ParseInt(tuple[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("timestamp of sample is not an integer: %w", err)
+	}
+	s.T = time.Unix(0, ns)
+	s.V = tuple[1]
+	return nil
+}
+
+type queryRes struct {
+	Status string    `json:"status"`
+	Data   queryData `json:"data"`
+}
+
+type queryData struct {
+	ResultType string   `json:"resultType"`
+	Result     []stream `json:"result"`
+}
+
+// httpLokiClient is a minimal client of the Loki HTTP API.
+type httpLokiClient struct {
+	url               *url.URL
+	tenantID          string
+	basicAuthUsername string
+	basicAuthPassword string
+	client            *http.Client
+	log               log.Logger
+}
+
+func newLokiClient(cfg setting.UnifiedAlertingStateHistorySettings, logger log.Logger) (*httpLokiClient, error) {
+	u, err := url.Parse(cfg.LokiRemoteURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse loki remote URL: %w", err)
+	}
+	return &httpLokiClient{
+		url:               u,
+		tenantID:          cfg.LokiTenantID,
+		basicAuthUsername: cfg.LokiBasicAuthUsername,
+		basicAuthPassword: cfg.LokiBasicAuthPassword,
+		client:            &http.Client{Timeout: cfg.LokiTimeout},
+		log:               logger,
+	}, nil
+}
+
+func (c *httpLokiClient) push(ctx context.Context, streams []stream) error {
+	body, err := json.Marshal(struct {
+		Streams []stream `json:"streams"`
+	}{Streams: streams})
+	if err != nil {
+		return fmt.Errorf("failed to serialize streams: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint(lokiPushPath).String(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create push request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	_, err = c.do(req)
+	return err
+}
+
+func (c *httpLokiClient) rangeQuery(ctx context.Context, logQL string, start, end time.Time, limit int) (queryRes, error) {
+	values := url.Values{}
+	values.Set("query", logQL)
+	values.Set("start", strconv.FormatInt(start.UnixNano(), 10))
+	values.Set("end", strconv.FormatInt(end.UnixNano(), 10))
+	values.Set("limit", strconv.Itoa(limit))
+	values.Set("direction", "forward")
+
+	u := c.endpoint(lokiQueryRangePath)
+	u.RawQuery = values.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return queryRes{}, fmt.Errorf("failed to create query request: %w", err)
+	}
+
+	body, err := c.do(req)
+	if err != nil {
+		return queryRes{}, err
+	}
+	var result queryRes
+	if err := json.Unmarshal(body, &result); err != nil {
+		return queryRes{}, fmt.Errorf("failed to deserialize query response: %w", err)
+	}
+	if result.Status != "success" {
+		return queryRes{}, fmt.Errorf("query returned status %q", result.Status)
+	}
+	return result, nil
+}
+
+// endpoint returns the URL of the API endpoint relative to the configured URL, which can contain a path prefix.
+func (c *httpLokiClient) endpoint(p string) *url.URL {
+	u := *c.url
+	u.Path = path.Join(u.Path, p)
+	return &u
+}
+
+// do sends the request with the authentication headers and returns the body of a successful response.
+func (c *httpLokiClient) do(req *http.Request) ([]byte, error) {
+	if c.tenantID != "" {
+		req.Header.Set("X-Scope-OrgID", c.tenantID)
+	}
+	if c.basicAuthUsername != "" || c.basicAuthPassword != "" {
+		req.SetBasicAuth(c.basicAuthUsername, c.basicAuthPassword)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to loki: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.log.Warn("failed to close response body", "err", err)
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response from loki: %w", err)
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("unexpected response code %d from loki: %s", resp.StatusCode, string(body))
+	}
+	return body, nil
+}
@@ -0,0 +1,287 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestLokiStateHistorian(t *testing.T) {
+	t.Run("RecordState queues the transition and Run pushes it as a log line", func(t *testing.T) {
+		var req *http.Request
+		var body []byte
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			req = r
+			var err error
+			body, err = io.ReadAll(r.Body)
+			require.NoError(t, err)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		t.Cleanup(srv.Close)
+		h := createTestLokiHistorian(t, srv.URL+"/prefix")
+
+		rule := &ngmodels.AlertRule{UID: "rule-uid", OrgID: 1, Title: "rule", RuleGroup: "group", NamespaceUID: "folder"}
+		evaluatedAt := time.Unix(1000, 0)
+		h.RecordState(context.Background(), rule, data.Labels{"team": "a", "__alert_rule_uid__": "rule-uid"}, evaluatedAt,
+			state.InstanceStateAndReason{State: eval.Alerting, Values: map[string]float64{"B": 1.5, "C": math.NaN()}},
+			state.InstanceStateAndReason{State: eval.Normal},
+		)
+		require.Nil(t, req)
+
+		// the queued transitions are pushed when the historian stops
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.NoError(t, h.Run(ctx))
+
+		require.NotNil(t, req)
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/prefix/loki/api/v1/push", req.URL.Path)
+		require.Equal(t, "tenant", req.Header.Get("X-Scope-OrgID"))
+		user, pass, ok := req.BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "user", user)
+		require.Equal(t, "pass", pass)
+
+		pushed := parsePush(t, body)
+		require.Len(t, pushed.Streams, 1)
+		require.Equal(t, map[string]string{"from": "state-history", "orgID": "1", "group": "group", "folderUID": "folder"}, pushed.Streams[0].Stream)
+		require.Len(t, pushed.Streams[0].Values, 1)
+		require.Equal(t, "1000000000000", pushed.Streams[0].Values[0][0])
+		require.JSONEq(t, `{
+			"schemaVersion": 1,
+			"ruleUID": "rule-uid",
+			"ruleTitle": "rule",
+			"previous": "Normal",
+			"current": "Alerting",
+			"values": {"B": 1.5, "C": "NaN"},
+			"labels": {"team": "a"}
+		}`, pushed.Streams[0].Values[0][1])
+	})
+
+	t.Run("Run pushes transitions in batches and merges streams with the same labels", func(t *testing.T) {
+		bodies := make(chan []byte, 10)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			bodies <- body
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		t.Cleanup(srv.Close)
+		h := createTestLokiHistorian(t, srv.URL)
+		h.batchSize = 3
+		h.flushInterval = time.Hour
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			done <- h.Run(ctx)
+		}()
+
+		ruleA := &ngmodels.AlertRule{UID: "a", OrgID: 1, RuleGroup: "group", NamespaceUID: "folder"}
+		ruleB := &ngmodels.AlertRule{UID: "b", OrgID: 1, RuleGroup: "other", NamespaceUID: "folder"}
+		for i, rule := range []*ngmodels.AlertRule{ruleA, ruleB, ruleA, ruleA} {
+			h.RecordState(context.Background(), rule, data.Labels{}, time.Unix(int64(10-i), 0),
+				state.InstanceStateAndReason{State: eval.Alerting},
+				state.InstanceStateAndReason{State: eval.Normal},
+			)
+		}
+
+		var pushed lokiPush
+		select {
+		case body := <-bodies:
+			pushed = parsePush(t, body)
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "transitions were not pushed when the batch was full")
+		}
+		require.Len(t, pushed.Streams, 2)
+		require.Equal(t, "group", pushed.Streams[0].Stream["group"])
+		require.Len(t, pushed.Streams[0].Values, 2)
+		// values are sorted by time
+		require.Equal(t, "8000000000", pushed.Streams[0].Values[0][0])
+		require.Equal(t, "10000000000", pushed.Streams[0].Values[1][0])
+		require.Equal(t, "other", pushed.Streams[1].Stream["group"])
+
+		cancel()
+		require.NoError(t, <-done)
+		pushed = parsePush(t, <-bodies)
+		require.Len(t, pushed.Streams, 1)
+		require.Equal(t, "7000000000", pushed.Streams[0].Values[0][0])
+	})
+
+	t.Run("RecordState drops transitions if the queue is full", func(t *testing.T) {
+		h := createTestLokiHistorian(t, "http://localhost")
+		h.queue = make(chan stream, 1)
+		rule := &ngmodels.AlertRule{UID: "rule-uid", OrgID: 1}
+		for i := 0; i < 2; i++ {
+			h.RecordState(context.Background(), rule, data.Labels{}, time.Now(),
+				state.InstanceStateAndReason{State: eval.Alerting},
+				state.InstanceStateAndReason{State: eval.Normal},
+			)
+		}
+		require.Len(t, h.queue, 1)
+	})
+
+	t.Run("QueryStates returns transitions as a data frame", func(t *testing.T) {
+		var req *http.Request
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			req = r
+			_, err := w.Write([]byte(`{
+				"status": "success",
+				"data": {
+					"resultType": "streams",
+					"result": [
+						{"stream": {"group": "b"}, "values": [
+							["3000000000", "{\"schemaVersion\":1,\"ruleUID\":\"rule-uid\",\"previous\":\"Alerting\",\"current\":\"Normal\",\"labels\":{\"team\":\"a\"}}"]
+						]},
+						{"stream": {"group": "a"}, "values": [
+							["1000000000", "{\"schemaVersion\":1,\"ruleUID\":\"rule-uid\",\"previous\":\"Normal\",\"current\":\"Alerting\",\"values\":{\"B\":1},\"labels\":{\"team\":\"a\"}}"],
+							["2000000000", "not json"],
+							["2500000000", "{\"schemaVersion\":1,\"ruleUID\":\"rule-uid\",\"previous\":\"Normal\",\"current\":\"Alerting\",\"ruleTitle\":\"\\\"team\\\":\\\"a\\\"\",\"labels\":{\"team\":\"b\"}}"]
+						]}
+					]
+				}
+			}`))
+			require.NoError(t, err)
+		}))
+		t.Cleanup(srv.Close)
+		h := createTestLokiHistorian(t, srv.URL)
+
+		frame, err := h.QueryStates(context.Background(), ngmodels.HistoryQuery{
+			OrgID:   1,
+			RuleUID: "rule-uid",
+			Labels:  map[string]string{"team": "a"},
+			From:    time.Unix(0, 0),
+			To:      time.Unix(10, 0),
+		})
+		require.NoError(t, err)
+
+		require.NotNil(t, req)
+		require.Equal(t, "/loki/api/v1/query_range", req.URL.Path)
+		require.Equal(t, `{from="state-history",orgID="1"} |= "\"team\":\"a\"" | json | ruleUID="rule-uid"`, req.URL.Query().Get("query"))
+		require.Equal(t, "0", req.URL.Query().Get("start"))
+		require.Equal(t, "10000000000", req.URL.Query().Get("end"))
+		require.Equal(t, "1000", req.URL.Query().Get("limit"))
+
+		// the entry that matches the line filter but not the labels is skipped
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, time.Unix(1, 0), frame.At(0, 0))
+		require.Equal(t, "Alerting", frame.At(3, 0))
+		require.Equal(t, json.RawMessage(`{"B":1}`), frame.At(5, 0))
+		require.Equal(t, time.Unix(3, 0), frame.At(0, 1))
+		require.Equal(t, "Normal", frame.At(3, 1))
+		require.Equal(t, json.RawMessage(`{"team":"a"}`), frame.At(4, 1))
+		require.Equal(t, json.RawMessage(`{}`), frame.At(5, 1))
+	})
+
+	t.Run("QueryStates fetches more entries until the limit is reached by entries of visible rules", func(t *testing.T) {
+		entry := func(ruleUID string) string {
+			line, err := json.Marshal(lokiEntry{SchemaVersion: lokiEntrySchemaVersion, RuleUID: ruleUID, Labels: map[string]string{}})
+			require.NoError(t, err)
+			return string(line)
+		}
+		pages := map[string][][2]string{
+			"0":          {{"1000000000", entry("hidden")}, {"2000000000", entry("visible")}},
+			"2000000000": {{"2000000000", entry("visible")}, {"3000000000", entry("visible")}},
+		}
+		starts := make([]string, 0)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := r.URL.Query().Get("start")
+			starts = append(starts, start)
+			body, err := json.Marshal(map[string]interface{}{
+				"status": "success",
+				"data": map[string]interface{}{
+					"resultType": "streams",
+					"result":     []interface{}{map[string]interface{}{"stream": map[string]string{}, "values": pages[start]}},
+				},
+			})
+			require.NoError(t, err)
+			_, err = w.Write(body)
+			require.NoError(t, err)
+		}))
+		t.Cleanup(srv.Close)
+		h := createTestLokiHistorian(t, srv.URL)
+
+		frame, err := h.QueryStates(context.Background(), ngmodels.HistoryQuery{
+			OrgID:    1,
+			RuleUIDs: []string{"visible"},
+			From:     time.Unix(0, 0),
+			To:       time.Unix(10, 0),
+			Limit:    2,
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"0", "2000000000"}, starts)
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, time.Unix(2, 0), frame.At(0, 0))
+		require.Equal(t, time.Unix(3, 0), frame.At(0, 1))
+		require.Equal(t, "visible", frame.At(1, 1))
+	})
+
+	t.Run("QueryStates fails if loki responds with an error", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		t.Cleanup(srv.Close)
+		h := createTestLokiHistorian(t, srv.URL)
+
+		_, err := h.QueryStates(context.Background(), ngmodels.HistoryQuery{OrgID: 1})
+		require.ErrorContains(t, err, "400")
+	})
+}
+
+func TestBuildLogQuery(t *testing.T) {
+	q := buildLogQuery(ngmodels.HistoryQuery{
+		OrgID:  2,
+		Labels: map[string]string{"z": "1", "a.b": `quoted "value"`},
+	})
+	require.Equal(t, `{from="state-history",orgID="2"} |= "\"a.b\":\"quoted \\\"value\\\"\"" |= "\"z\":\"1\"" | json`, q)
+}
+
+func TestMatchLabels(t *testing.T) {
+	// labels that would be sanitized to the same name are matched exactly
+	require.True(t, matchLabels(map[string]string{"a.b": "1", "a_b": "2"}, map[string]string{"a.b": "1"}))
+	require.False(t, matchLabels(map[string]string{"a_b": "1"}, map[string]string{"a.b": "1"}))
+	require.False(t, matchLabels(map[string]string{"a.b": "2"}, map[string]string{"a.b": "1"}))
+}
+
+type lokiPush struct {
+	Streams []struct {
+		Stream map[string]string `json:"stream"`
+		Values [][2]string       `json:"values"`
+	} `json:"streams"`
+}
+
+func parsePush(t *testing.T, body []byte) lokiPush {
+	t.Helper()
+	var pushed lokiPush
+	require.NoError(t, json.Unmarshal(body, &pushed))
+	return pushed
+}
+
+func createTestLokiHistorian(t *testing.T, url string) *LokiStateHistorian {
+	t.Helper()
+	h, err := NewLokiHistorian(setting.UnifiedAlertingStateHistorySettings{
+		Backend:               setting.StateHistoryBackendLoki,
+		LokiRemoteURL:         url,
+		LokiTenantID:          "tenant",
+		LokiBasicAuthUsername: "user",
+		LokiBasicAuthPassword: "pass",
+		LokiTimeout:           time.Second,
+	}, log.NewNopLogger())
+	require.NoError(t, err)
+	return h
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/benbjohnson/clock"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	prometheusModel "github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
@@ -125,6 +126,7 @@ func (st *Manager) Warm(ctx context.Context) {
 				OrgID:                entry.RuleOrgID,
 				CacheID:              cacheID,
 				Labels:               lbs,
+				ResultLabels:         resultLabelsOf(ruleForEntry, lbs),
 				State:                translateInstanceState(entry.CurrentState),
 				StateReason:          entry.CurrentReason,
 				LastEvaluationString: "",
@@ -133,6 +135,10 @@ func (st *Manager) Warm(ctx context.Context) {
 				LastEvaluationTime:   entry.LastEvalTime,
 				Annotations:          ruleForEntry.Annotations,
 			}
+			// instances saved before the time was stored have zero Unix time.
+			if entry.KeepFiringSince.Unix() > 0 {
+				rulesStates.states[cacheID].KeepFiringSince = entry.KeepFiringSince
+			}
 			statesCount++
 		}
 	}
@@ -140,6 +146,24 @@ func (st *Manager) Warm(ctx context.Context) {
 	st.log.Info("State cache has been initialized", "loaded_states", statesCount, "duration", time.Since(startTime))
 }
 
+// resultLabelsOf rebuilds the labels of the evaluation result from the labels of a state that was restored
+// from the database by removing the labels of the rule and the labels that are added to every alert.
+// A label of the result that has the same name as one of these labels cannot be recovered.
+func resultLabelsOf(rule *ngModels.AlertRule, lbs data.Labels) data.Labels {
+	result := make(data.Labels, len(lbs))
+	for k, v := range lbs {
+		if _, ok := rule.Labels[k]; ok {
+			continue
+		}
+		switch k {
+		case ngModels.NamespaceUIDLabel, ngModels.RuleUIDLabel, ngModels.FolderTitleLabel, prometheusModel.AlertNameLabel:
+			continue
+		}
+		result[k] = v
+	}
+	return result
+}
+
 func (st *Manager) Get(orgID int64, alertRuleUID, stateId string) *State {
 	return st.cache.get(orgID, alertRuleUID, stateId)
 }
@@ -266,7 +290,7 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 
 	shouldUpdateAnnotation := oldState != currentState.State || oldReason != currentState.StateReason
 	if shouldUpdateAnnotation {
-		go st.historian.RecordState(ctx, alertRule, currentState.Labels, result.EvaluatedAt, InstanceStateAndReason{State: currentState.State, Reason: currentState.StateReason}, InstanceStateAndReason{State: oldState, Reason: oldReason})
+		go st.historian.RecordState(ctx, alertRule, currentState.Labels, result.EvaluatedAt, InstanceStateAndReason{State: currentState.State, Reason: currentState.StateReason, Values: currentState.Values}, InstanceStateAndReason{State: oldState, Reason: oldReason})
 	}
 	return currentState
 }
@@ -336,6 +360,7 @@ func (st *Manager) saveAlertStates(ctx context.Context, states ...*State) (saved
 			LastEvalTime:      s.LastEvaluationTime,
 			CurrentStateSince: s.StartsAt,
 			CurrentStateEnd:   s.EndsAt,
+			KeepFiringSince:   s.KeepFiringSince,
 		}
 		instances = append(instances, fields)
 	}
@@ -367,6 +392,8 @@ func translateInstanceState(state ngModels.InstanceStateType) eval.State {
 type InstanceStateAndReason struct {
 	State  eval.State
 	Reason string
+	// Values contains the values of the evaluation that caused the state. It is not set for the previous state.
+	Values map[string]float64
 }
 
 func (i InstanceStateAndReason) String() string {
@@ -377,14 +404,22 @@ func (i InstanceStateAndReason) String() string {
 	return s
 }
 
+// staleResultsHandler removes the states of series that are missing from the results and resolves them if they were firing.
+// Firing states that are kept firing because of the KeepFiringFor of the rule are not removed. Returns the states that
+// were resolved or kept firing.
 func (st *Manager) staleResultsHandler(ctx context.Context, evaluatedAt time.Time, alertRule *ngModels.AlertRule, states map[string]*State) []*State {
 	var resolvedStates []*State
+	var keptStates []*State
 	allStates := st.GetStatesForRuleUID(alertRule.OrgID, alertRule.UID)
 	toDelete := make([]ngModels.AlertInstanceKey, 0)
 
 	for _, s := range allStates {
 		// Is the cached state in our recently processed results? If not, is it stale?
 		if _, ok := states[s.CacheID]; !ok && stateIsStale(evaluatedAt, s.LastEvaluationTime, alertRule.IntervalSeconds) {
+			if s.State == eval.Alerting && s.keepFiringMissing(alertRule, evaluatedAt) {
+				keptStates = append(keptStates, s)
+				continue
+			}
 			st.log.Debug("removing stale state entry", "orgID", s.OrgID, "alertRuleUID", s.AlertRuleUID, "cacheID", s.CacheID)
 			st.cache.deleteEntry(s.OrgID, s.AlertRuleUID, s.CacheID)
 			ilbs := ngModels.InstanceLabels(s.Labels)
@@ -414,7 +449,11 @@ func (st *Manager) staleResultsHandler(ctx context.Context, evaluatedAt time.Tim
 		st.log.Error("unable to delete stale instances from database", "err", err.Error(),
 			"orgID", alertRule.OrgID, "alertRuleUID", alertRule.UID, "count", len(toDelete))
 	}
-	return resolvedStates
+	if len(keptStates) > 0 {
+		// the time since the states are kept firing is saved, so it is observed after a restart.
+		_, _ = st.saveAlertStates(ctx, keptStates...)
+	}
+	return append(resolvedStates, keptStates...)
 }
 
 func stateIsStale(evaluatedAt time.Time, lastEval time.Time, intervalSeconds int64) bool {
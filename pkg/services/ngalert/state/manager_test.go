@@ -39,7 +39,7 @@ func TestDashboardAnnotations(t *testing.T) {
 	_, dbstore := tests.SetupTestEnv(t, 1)
 
 	fakeAnnoRepo := annotationstest.NewFakeAnnotationsRepo()
-	hist := historian.NewAnnotationHistorian(fakeAnnoRepo, &dashboards.FakeDashboardService{}, log.NewNopLogger())
+	hist := historian.NewAnnotationHistorian(fakeAnnoRepo, &dashboards.FakeDashboardService{}, nil, log.NewNopLogger())
 	st := state.NewManager(log.New("test_stale_results_handler"), testMetrics.GetStateMetrics(), nil, dbstore, dbstore, &image.NoopImageService{}, clock.New(), hist)
 
 	const mainOrgID int64 = 1
@@ -125,8 +125,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Normal,
+					ResultLabels: data.Labels{"instance_label": "test"},
+					Values:       make(map[string]float64),
+					State:        eval.Normal,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -180,8 +181,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label_1":             "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Normal,
+					ResultLabels: data.Labels{"instance_label_1": "test"},
+					Values:       make(map[string]float64),
+					State:        eval.Normal,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -204,8 +206,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label_2":             "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Alerting,
+					ResultLabels: data.Labels{"instance_label_2": "test"},
+					Values:       make(map[string]float64),
+					State:        eval.Alerting,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -262,8 +265,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Normal,
+					ResultLabels: data.Labels{"instance_label": "test"},
+					Values:       make(map[string]float64),
+					State:        eval.Normal,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -324,8 +328,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Alerting,
+					ResultLabels: data.Labels{"instance_label": "test"},
+					Values:       make(map[string]float64),
+					State:        eval.Alerting,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -397,8 +402,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Alerting,
+					ResultLabels: data.Labels{"instance_label": "test"},
+					Values:       make(map[string]float64),
+					State:        eval.Alerting,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -492,8 +498,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Pending,
+					ResultLabels: data.Labels{"instance_label": "test"},
+					Values:       make(map[string]float64),
+					State:        eval.Pending,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime.Add(30 * time.Second),
@@ -574,8 +581,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.NoData,
+					ResultLabels: data.Labels{"instance_label": "test"},
+					Values:       make(map[string]float64),
+					State:        eval.NoData,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime.Add(20 * time.Second),
@@ -639,8 +647,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Pending,
+					ResultLabels: data.Labels{"instance_label": "test"},
+					Values:       make(map[string]float64),
+					State:        eval.Pending,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -704,8 +713,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Pending,
+					ResultLabels: data.Labels{"instance_label": "test"},
+					Values:       make(map[string]float64),
+					State:        eval.Pending,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -726,6 +736,245 @@ func TestProcessEvalResults(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "alerting -> normal keeps firing when KeepFiringFor is set but not exceeded",
+			alertRule: &models.AlertRule{
+				OrgID:           1,
+				Title:           "test_title",
+				UID:             "test_alert_rule_uid_2",
+				NamespaceUID:    "test_namespace_uid",
+				Annotations:     map[string]string{"annotation": "test"},
+				Labels:          map[string]string{"label": "test"},
+				IntervalSeconds: 10,
+				KeepFiringFor:   1 * time.Minute,
+			},
+			evalResults: []eval.Results{
+				{
+					eval.Result{
+						Instance:           data.Labels{"instance_label": "test"},
+						State:              eval.Alerting,
+						EvaluatedAt:        evaluationTime,
+						EvaluationDuration: evaluationDuration,
+					},
+				},
+				{
+					eval.Result{
+						Instance:           data.Labels{"instance_label": "test"},
+						State:              eval.Normal,
+						EvaluatedAt:        evaluationTime.Add(10 * time.Second),
+						EvaluationDuration: evaluationDuration,
+					},
+				},
+				{
+					eval.Result{
+						Instance:           data.Labels{"instance_label": "test"},
+						State:              eval.Normal,
+						EvaluatedAt:        evaluationTime.Add(20 * time.Second),
+						EvaluationDuration: evaluationDuration,
+					},
+				},
+			},
+			expectedAnnotations: 1,
+			expectedStates: map[string]*state.State{
+				`[["__alert_rule_namespace_uid__","test_namespace_uid"],["__alert_rule_uid__","test_alert_rule_uid_2"],["alertname","test_title"],["instance_label","test"],["label","test"]]`: {
+					AlertRuleUID: "test_alert_rule_uid_2",
+					OrgID:        1,
+					CacheID:      `[["__alert_rule_namespace_uid__","test_namespace_uid"],["__alert_rule_uid__","test_alert_rule_uid_2"],["alertname","test_title"],["instance_label","test"],["label","test"]]`,
+					Labels: data.Labels{
+						"__alert_rule_namespace_uid__": "test_namespace_uid",
+						"__alert_rule_uid__":           "test_alert_rule_uid_2",
+						"alertname":                    "test_title",
+						"label":                        "test",
+						"instance_label":               "test",
+					},
+					ResultLabels: data.Labels{"instance_label": "test"},
+					Values:       make(map[string]float64),
+					State:        eval.Alerting,
+					Results: []state.Evaluation{
+						{
+							EvaluationTime:  evaluationTime,
+							EvaluationState: eval.Alerting,
+							Values:          make(map[string]*float64),
+						},
+						{
+							EvaluationTime:  evaluationTime.Add(10 * time.Second),
+							EvaluationState: eval.Normal,
+							Values:          make(map[string]*float64),
+						},
+						{
+							EvaluationTime:  evaluationTime.Add(20 * time.Second),
+							EvaluationState: eval.Normal,
+							Values:          make(map[string]*float64),
+						},
+					},
+					KeepFiringSince:    evaluationTime.Add(10 * time.Second),
+					StartsAt:           evaluationTime,
+					EndsAt:             evaluationTime.Add(20 * time.Second).Add(state.ResendDelay * 3),
+					LastEvaluationTime: evaluationTime.Add(20 * time.Second),
+					EvaluationDuration: evaluationDuration,
+					Annotations:        map[string]string{"annotation": "test"},
+				},
+			},
+		},
+		{
+			desc: "alerting -> normal when KeepFiringFor is exceeded",
+			alertRule: &models.AlertRule{
+				OrgID:           1,
+				Title:           "test_title",
+				UID:             "test_alert_rule_uid_2",
+				NamespaceUID:    "test_namespace_uid",
+				Annotations:     map[string]string{"annotation": "test"},
+				Labels:          map[string]string{"label": "test"},
+				IntervalSeconds: 10,
+				KeepFiringFor:   1 * time.Minute,
+			},
+			evalResults: []eval.Results{
+				{
+					eval.Result{
+						Instance:           data.Labels{"instance_label": "test"},
+						State:              eval.Alerting,
+						EvaluatedAt:        evaluationTime,
+						EvaluationDuration: evaluationDuration,
+					},
+				},
+				{
+					eval.Result{
+						Instance:           data.Labels{"instance_label": "test"},
+						State:              eval.Normal,
+						EvaluatedAt:        evaluationTime.Add(10 * time.Second),
+						EvaluationDuration: evaluationDuration,
+					},
+				},
+				{
+					eval.Result{
+						Instance:           data.Labels{"instance_label": "test"},
+						State:              eval.Normal,
+						EvaluatedAt:        evaluationTime.Add(80 * time.Second),
+						EvaluationDuration: evaluationDuration,
+					},
+				},
+			},
+			expectedAnnotations: 2,
+			expectedStates: map[string]*state.State{
+				`[["__alert_rule_namespace_uid__","test_namespace_uid"],["__alert_rule_uid__","test_alert_rule_uid_2"],["alertname","test_title"],["instance_label","test"],["label","test"]]`: {
+					AlertRuleUID: "test_alert_rule_uid_2",
+					OrgID:        1,
+					CacheID:      `[["__alert_rule_namespace_uid__","test_namespace_uid"],["__alert_rule_uid__","test_alert_rule_uid_2"],["alertname","test_title"],["instance_label","test"],["label","test"]]`,
+					Labels: data.Labels{
+						"__alert_rule_namespace_uid__": "test_namespace_uid",
+						"__alert_rule_uid__":           "test_alert_rule_uid_2",
+						"alertname":                    "test_title",
+						"label":                        "test",
+						"instance_label":               "test",
+					},
+					ResultLabels: data.Labels{"instance_label": "test"},
+					Values:       make(map[string]float64),
+					State:        eval.Normal,
+					Resolved:     true,
+					Results: []state.Evaluation{
+						{
+							EvaluationTime:  evaluationTime,
+							EvaluationState: eval.Alerting,
+							Values:          make(map[string]*float64),
+						},
+						{
+							EvaluationTime:  evaluationTime.Add(10 * time.Second),
+							EvaluationState: eval.Normal,
+							Values:          make(map[string]*float64),
+						},
+						{
+							EvaluationTime:  evaluationTime.Add(80 * time.Second),
+							EvaluationState: eval.Normal,
+							Values:          make(map[string]*float64),
+						},
+					},
+					StartsAt:           evaluationTime.Add(80 * time.Second),
+					EndsAt:             evaluationTime.Add(80 * time.Second),
+					LastEvaluationTime: evaluationTime.Add(80 * time.Second),
+					EvaluationDuration: evaluationDuration,
+					Annotations:        map[string]string{"annotation": "test"},
+				},
+			},
+		},
+		{
+			desc: "alerting -> normal -> alerting keeps firing and resets KeepFiringSince",
+			alertRule: &models.AlertRule{
+				OrgID:           1,
+				Title:           "test_title",
+				UID:             "test_alert_rule_uid_2",
+				NamespaceUID:    "test_namespace_uid",
+				Annotations:     map[string]string{"annotation": "test"},
+				Labels:          map[string]string{"label": "test"},
+				IntervalSeconds: 10,
+				KeepFiringFor:   1 * time.Minute,
+			},
+			evalResults: []eval.Results{
+				{
+					eval.Result{
+						Instance:           data.Labels{"instance_label": "test"},
+						State:              eval.Alerting,
+						EvaluatedAt:        evaluationTime,
+						EvaluationDuration: evaluationDuration,
+					},
+				},
+				{
+					eval.Result{
+						Instance:           data.Labels{"instance_label": "test"},
+						State:              eval.Normal,
+						EvaluatedAt:        evaluationTime.Add(10 * time.Second),
+						EvaluationDuration: evaluationDuration,
+					},
+				},
+				{
+					eval.Result{
+						Instance:           data.Labels{"instance_label": "test"},
+						State:              eval.Alerting,
+						EvaluatedAt:        evaluationTime.Add(20 * time.Second),
+						EvaluationDuration: evaluationDuration,
+					},
+				},
+			},
+			expectedAnnotations: 1,
+			expectedStates: map[string]*state.State{
+				`[["__alert_rule_namespace_uid__","test_namespace_uid"],["__alert_rule_uid__","test_alert_rule_uid_2"],["alertname","test_title"],["instance_label","test"],["label","test"]]`: {
+					AlertRuleUID: "test_alert_rule_uid_2",
+					OrgID:        1,
+					CacheID:      `[["__alert_rule_namespace_uid__","test_namespace_uid"],["__alert_rule_uid__","test_alert_rule_uid_2"],["alertname","test_title"],["instance_label","test"],["label","test"]]`,
+					Labels: data.Labels{
+						"__alert_rule_namespace_uid__": "test_namespace_uid",
+						"__alert_rule_uid__":           "test_alert_rule_uid_2",
+						"alertname":                    "test_title",
+						"label":                        "test",
+						"instance_label":               "test",
+					},
+					ResultLabels: data.Labels{"instance_label": "test"},
+					Values:       make(map[string]float64),
+					State:        eval.Alerting,
+					Results: []state.Evaluation{
+						{
+							EvaluationTime:  evaluationTime,
+							EvaluationState: eval.Alerting,
+							Values:          make(map[string]*float64),
+						},
+						{
+							EvaluationTime:  evaluationTime.Add(10 * time.Second),
+							EvaluationState: eval.Normal,
+							Values:          make(map[string]*float64),
+						},
+						{
+							EvaluationTime:  evaluationTime.Add(20 * time.Second),
+							EvaluationState: eval.Alerting,
+							Values:          make(map[string]*float64),
+						},
+					},
+					StartsAt:           evaluationTime,
+					EndsAt:             evaluationTime.Add(20 * time.Second).Add(state.ResendDelay * 3),
+					LastEvaluationTime: evaluationTime.Add(20 * time.Second),
+					EvaluationDuration: evaluationDuration,
+					Annotations:        map[string]string{"annotation": "test"},
+				},
+			},
+		},
 		{
 			desc: "normal -> alerting when result is NoData and NoDataState is alerting",
 			alertRule: &models.AlertRule{
@@ -769,9 +1018,10 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values:      make(map[string]float64),
-					State:       eval.Alerting,
-					StateReason: eval.NoData.String(),
+					ResultLabels: data.Labels{"instance_label": "test"},
+					Values:       make(map[string]float64),
+					State:        eval.Alerting,
+					StateReason:  eval.NoData.String(),
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -835,8 +1085,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.NoData,
+					ResultLabels: data.Labels{"instance_label": "test"},
+					Values:       make(map[string]float64),
+					State:        eval.NoData,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -900,8 +1151,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Normal,
+					ResultLabels: data.Labels{"instance_label": "test"},
+					Values:       make(map[string]float64),
+					State:        eval.Normal,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -925,8 +1177,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"alertname":                    "test_title",
 						"label":                        "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.NoData,
+					ResultLabels: data.Labels{},
+					Values:       make(map[string]float64),
+					State:        eval.NoData,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime.Add(10 * time.Second),
@@ -991,8 +1244,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test-1",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Normal,
+					ResultLabels: data.Labels{"instance_label": "test-1"},
+					Values:       make(map[string]float64),
+					State:        eval.Normal,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -1017,8 +1271,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test-2",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Normal,
+					ResultLabels: data.Labels{"instance_label": "test-2"},
+					Values:       make(map[string]float64),
+					State:        eval.Normal,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -1042,8 +1297,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"alertname":                    "test_title",
 						"label":                        "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.NoData,
+					ResultLabels: data.Labels{},
+					Values:       make(map[string]float64),
+					State:        eval.NoData,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime.Add(10 * time.Second),
@@ -1110,8 +1366,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Normal,
+					ResultLabels: data.Labels{"instance_label": "test"},
+					Values:       make(map[string]float64),
+					State:        eval.Normal,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -1140,8 +1397,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"alertname":                    "test_title",
 						"label":                        "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.NoData,
+					ResultLabels: data.Labels{},
+					Values:       make(map[string]float64),
+					State:        eval.NoData,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime.Add(10 * time.Second),
@@ -1200,9 +1458,10 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values:      make(map[string]float64),
-					State:       eval.Normal,
-					StateReason: eval.NoData.String(),
+					ResultLabels: data.Labels{"instance_label": "test"},
+					Values:       make(map[string]float64),
+					State:        eval.Normal,
+					StateReason:  eval.NoData.String(),
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -1267,9 +1526,10 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values:      make(map[string]float64),
-					State:       eval.Alerting,
-					StateReason: eval.NoData.String(),
+					ResultLabels: data.Labels{"instance_label": "test"},
+					Values:       make(map[string]float64),
+					State:        eval.Alerting,
+					StateReason:  eval.NoData.String(),
 
 					Results: []state.Evaluation{
 						{
@@ -1335,9 +1595,10 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values:      make(map[string]float64),
-					State:       eval.Pending,
-					StateReason: eval.Error.String(),
+					ResultLabels: data.Labels{"instance_label": "test"},
+					Values:       make(map[string]float64),
+					State:        eval.Pending,
+					StateReason:  eval.Error.String(),
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -1426,9 +1687,10 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values:      make(map[string]float64),
-					State:       eval.Alerting,
-					StateReason: eval.Error.String(),
+					ResultLabels: data.Labels{"instance_label": "test"},
+					Values:       make(map[string]float64),
+					State:        eval.Alerting,
+					StateReason:  eval.Error.String(),
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime.Add(20 * time.Second),
@@ -1508,8 +1770,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"datasource_uid":               "datasource_uid_1",
 						"ref_id":                       "A",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Error,
+					ResultLabels: data.Labels{"instance_label": "test"},
+					Values:       make(map[string]float64),
+					State:        eval.Error,
 					Error: expr.QueryError{
 						RefID: "A",
 						Err:   errors.New("this is an error"),
@@ -1586,10 +1849,11 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values:      make(map[string]float64),
-					State:       eval.Normal,
-					StateReason: eval.Error.String(),
-					Error:       nil,
+					ResultLabels: data.Labels{"instance_label": "test"},
+					Values:       make(map[string]float64),
+					State:        eval.Normal,
+					StateReason:  eval.Error.String(),
+					Error:        nil,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -1660,10 +1924,11 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values:      make(map[string]float64),
-					State:       eval.Normal,
-					StateReason: eval.Error.String(),
-					Error:       nil,
+					ResultLabels: data.Labels{"instance_label": "test"},
+					Values:       make(map[string]float64),
+					State:        eval.Normal,
+					StateReason:  eval.Error.String(),
+					Error:        nil,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -1760,8 +2025,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Error,
+					ResultLabels: data.Labels{"instance_label": "test"},
+					Values:       make(map[string]float64),
+					State:        eval.Error,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime.Add(40 * time.Second),
@@ -1842,8 +2108,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Alerting,
+					ResultLabels: data.Labels{"instance_label": "test"},
+					Values:       make(map[string]float64),
+					State:        eval.Alerting,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime.Add(30 * time.Second),
@@ -1930,8 +2197,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.NoData,
+					ResultLabels: data.Labels{"instance_label": "test"},
+					Values:       make(map[string]float64),
+					State:        eval.NoData,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime.Add(30 * time.Second),
@@ -1993,8 +2261,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"job":                          "prod/grafana",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Normal,
+					ResultLabels: data.Labels{"cluster": "us-central-1", "namespace": "prod", "pod": "grafana"},
+					Values:       make(map[string]float64),
+					State:        eval.Normal,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -2012,7 +2281,7 @@ func TestProcessEvalResults(t *testing.T) {
 
 	for _, tc := range testCases {
 		fakeAnnoRepo := annotationstest.NewFakeAnnotationsRepo()
-		hist := historian.NewAnnotationHistorian(fakeAnnoRepo, &dashboards.FakeDashboardService{}, log.NewNopLogger())
+		hist := historian.NewAnnotationHistorian(fakeAnnoRepo, &dashboards.FakeDashboardService{}, nil, log.NewNopLogger())
 		st := state.NewManager(log.New("test_state_manager"), testMetrics.GetStateMetrics(), nil, nil, &state.FakeInstanceStore{}, &image.NotAvailableImageService{}, clock.New(), hist)
 		t.Run(tc.desc, func(t *testing.T) {
 			for _, res := range tc.evalResults {
@@ -2147,8 +2416,9 @@ func TestStaleResultsHandler(t *testing.T) {
 						"alertname":                    rule.Title,
 						"test1":                        "testValue1",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Normal,
+					ResultLabels: data.Labels{"test1": "testValue1"},
+					Values:       make(map[string]float64),
+					State:        eval.Normal,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -2295,4 +2565,55 @@ func TestStaleResults(t *testing.T) {
 			getCacheID(t, rule, results[0]): {},
 		})
 	})
+
+	t.Run("should keep missing firing states until KeepFiringFor is exceeded", func(t *testing.T) {
+		ctx := context.Background()
+		_, dbstore := tests.SetupTestEnv(t, 1)
+		clk := clock.NewMock()
+		clk.Set(time.Now().Truncate(time.Second))
+
+		st := state.NewManager(log.New("test_stale_results_handler"), testMetrics.GetStateMetrics(), nil, dbstore, dbstore, &image.NoopImageService{}, clk, &state.FakeHistorian{})
+
+		orgID := rand.Int63()
+		rule := tests.CreateTestAlertRule(t, ctx, dbstore, 10, orgID)
+		rule.KeepFiringFor = time.Minute
+		interval := time.Duration(rule.IntervalSeconds) * time.Second
+
+		initResults := eval.Results{
+			eval.Result{
+				Instance:    data.Labels{"test1": "testValue1"},
+				State:       eval.Alerting,
+				EvaluatedAt: clk.Now(),
+			},
+		}
+		cacheID := getCacheID(t, rule, initResults[0])
+		st.ProcessEvalResults(ctx, clk.Now(), rule, initResults, nil)
+		missingSince := clk.Now().Add(interval)
+
+		// the series is stale but kept firing.
+		clk.Add(2 * interval)
+		processed := st.ProcessEvalResults(ctx, clk.Now(), rule, eval.Results{}, nil)
+		checkExpectedStates(t, processed, map[string]struct{}{cacheID: {}})
+		require.Equal(t, eval.Alerting, processed[0].State)
+		require.False(t, processed[0].Resolved)
+		require.Equal(t, missingSince, processed[0].KeepFiringSince)
+		require.True(t, processed[0].EndsAt.After(clk.Now()))
+		checkExpectedStates(t, st.GetStatesForRuleUID(orgID, rule.UID), map[string]struct{}{cacheID: {}})
+
+		// the time since the state is kept firing is restored after a restart.
+		restarted := state.NewManager(log.New("test_stale_results_handler"), testMetrics.GetStateMetrics(), nil, dbstore, dbstore, &image.NoopImageService{}, clk, &state.FakeHistorian{})
+		restarted.Warm(ctx)
+		restored := restarted.Get(orgID, rule.UID, cacheID)
+		require.NotNil(t, restored)
+		require.Equal(t, missingSince.Unix(), restored.KeepFiringSince.Unix())
+
+		// the series is resolved once KeepFiringFor is exceeded.
+		clk.Set(missingSince.Add(rule.KeepFiringFor))
+		processed = st.ProcessEvalResults(ctx, clk.Now(), rule, eval.Results{}, nil)
+		checkExpectedStates(t, processed, map[string]struct{}{cacheID: {}})
+		require.Equal(t, eval.Normal, processed[0].State)
+		require.True(t, processed[0].Resolved)
+		require.Equal(t, models.StateReasonMissingSeries, processed[0].StateReason)
+		require.Empty(t, st.GetStatesForRuleUID(orgID, rule.UID))
+	})
 }
@@ -51,10 +51,19 @@ type State struct {
 	// If a label is templated then the template is first evaluated to derive the final label.
 	Labels data.Labels
 
+	// ResultLabels contains the labels of the evaluation result the state was created for, without
+	// the custom labels from the alert rule. For states that were restored from the database it is rebuilt
+	// from Labels.
+	ResultLabels data.Labels
+
 	// Values contains the values of any instant vectors, reduce and math expressions, or classic
 	// conditions.
 	Values map[string]float64
 
+	// KeepFiringSince is the time of the first evaluation with a Normal result while the state
+	// kept firing because of the KeepFiringFor of the rule. It is zero if the state is not kept firing.
+	KeepFiringSince time.Time
+
 	StartsAt             time.Time
 	EndsAt               time.Time
 	LastSentAt           time.Time
@@ -90,8 +99,19 @@ func NewEvaluationValues(m map[string]eval.NumberValueCapture) map[string]*float
 	return result
 }
 
-func (a *State) resultNormal(_ *models.AlertRule, result eval.Result) {
+func (a *State) resultNormal(alertRule *models.AlertRule, result eval.Result) {
 	a.Error = nil // should be nil since state is not error
+	if a.State == eval.Alerting && alertRule.KeepFiringFor > 0 {
+		if a.KeepFiringSince.IsZero() {
+			a.KeepFiringSince = result.EvaluatedAt
+		}
+		// KeepFiringFor is observed the same way For is, but for the transition from Alerting to Normal.
+		if result.EvaluatedAt.Sub(a.KeepFiringSince) < alertRule.KeepFiringFor {
+			a.setEndsAt(alertRule, result)
+			return
+		}
+	}
+	a.KeepFiringSince = time.Time{}
 	if a.State != eval.Normal {
 		a.EndsAt = result.EvaluatedAt
 		a.StartsAt = result.EvaluatedAt
@@ -99,8 +119,25 @@ func (a *State) resultNormal(_ *models.AlertRule, result eval.Result) {
 	a.State = eval.Normal
 }
 
+// keepFiringMissing returns true if the firing state keeps firing because of the KeepFiringFor of the rule although
+// its series is missing from the results. KeepFiringFor is observed since the first evaluation without the series.
+func (a *State) keepFiringMissing(alertRule *models.AlertRule, evaluatedAt time.Time) bool {
+	if alertRule.KeepFiringFor <= 0 {
+		return false
+	}
+	if a.KeepFiringSince.IsZero() {
+		a.KeepFiringSince = a.LastEvaluationTime.Add(time.Duration(alertRule.IntervalSeconds) * time.Second)
+	}
+	if evaluatedAt.Sub(a.KeepFiringSince) >= alertRule.KeepFiringFor {
+		return false
+	}
+	a.setEndsAt(alertRule, eval.Result{EvaluatedAt: evaluatedAt})
+	return true
+}
+
 func (a *State) resultAlerting(alertRule *models.AlertRule, result eval.Result) {
 	a.Error = result.Error // should be nil since the state is not an error
+	a.KeepFiringSince = time.Time{}
 
 	switch a.State {
 	case eval.Alerting:
@@ -153,6 +190,7 @@ func (a *State) resultError(alertRule *models.AlertRule, result eval.Result) {
 	default:
 		a.Error = fmt.Errorf("cannot map error to a state because option [%s] is not supported. evaluation error: %w", alertRule.ExecErrState, a.Error)
 	}
+	a.KeepFiringSince = time.Time{}
 
 	switch a.State {
 	case eval.Alerting, eval.Error:
@@ -184,6 +222,7 @@ func (a *State) resultError(alertRule *models.AlertRule, result eval.Result) {
 
 func (a *State) resultNoData(alertRule *models.AlertRule, result eval.Result) {
 	a.Error = result.Error
+	a.KeepFiringSince = time.Time{}
 
 	if a.StartsAt.IsZero() {
 		a.StartsAt = result.EvaluatedAt
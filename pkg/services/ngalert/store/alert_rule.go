@@ -107,6 +107,39 @@ func (st DBstore) GetAlertRulesGroupByRuleUID(ctx context.Context, query *ngmode
 	})
 }
 
+// ListAlertRuleVersions is a handler for retrieving all versions of an alert rule, latest first.
+func (st DBstore) ListAlertRuleVersions(ctx context.Context, query *ngmodels.ListAlertRuleVersionsQuery) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		versions := make([]*ngmodels.AlertRuleVersion, 0)
+		err := sess.Table(ngmodels.AlertRuleVersion{}).
+			Where("rule_org_id = ? AND rule_uid = ?", query.OrgID, query.UID).
+			Desc("version").
+			Find(&versions)
+		if err != nil {
+			return err
+		}
+		query.Result = versions
+		return nil
+	})
+}
+
+// GetAlertRuleVersion is a handler for retrieving a specific version of an alert rule.
+// It returns ngmodels.ErrAlertRuleVersionNotFound if the rule does not have the requested version.
+func (st DBstore) GetAlertRuleVersion(ctx context.Context, query *ngmodels.GetAlertRuleVersionQuery) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		version := ngmodels.AlertRuleVersion{}
+		has, err := sess.Where("rule_org_id = ? AND rule_uid = ? AND version = ?", query.OrgID, query.UID, query.Version).Get(&version)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return ngmodels.ErrAlertRuleVersionNotFound
+		}
+		query.Result = &version
+		return nil
+	})
+}
+
 // InsertAlertRules is a handler for creating/updating alert rules.
 func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRule) (map[string]int64, error) {
 	ids := make(map[string]int64, len(rules))
@@ -145,8 +178,11 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 				NoDataState:      r.NoDataState,
 				ExecErrState:     r.ExecErrState,
 				For:              r.For,
+				KeepFiringFor:    r.KeepFiringFor,
 				Annotations:      r.Annotations,
 				Labels:           r.Labels,
+				Record:           r.Record,
+				IsPaused:         r.IsPaused,
 			})
 		}
 		if len(newRules) > 0 {
@@ -204,6 +240,7 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 				RuleGroup:        r.New.RuleGroup,
 				RuleGroupIndex:   r.New.RuleGroupIndex,
 				ParentVersion:    parentVersion,
+				RestoredFrom:     r.RestoredFrom,
 				Version:          r.New.Version + 1,
 				Created:          r.New.Updated,
 				Condition:        r.New.Condition,
@@ -213,8 +250,11 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 				NoDataState:      r.New.NoDataState,
 				ExecErrState:     r.New.ExecErrState,
 				For:              r.New.For,
+				KeepFiringFor:    r.New.KeepFiringFor,
 				Annotations:      r.New.Annotations,
 				Labels:           r.New.Labels,
+				Record:           r.New.Record,
+				IsPaused:         r.New.IsPaused,
 			})
 		}
 		if len(ruleVersions) > 0 {
@@ -492,5 +532,18 @@ func (st DBstore) validateAlertRule(alertRule ngmodels.AlertRule) error {
 	if alertRule.For < 0 {
 		return fmt.Errorf("%w: field `for` cannot be negative", ngmodels.ErrAlertRuleFailedValidation)
 	}
+
+	if alertRule.KeepFiringFor < 0 {
+		return fmt.Errorf("%w: field `keep_firing_for` cannot be negative", ngmodels.ErrAlertRuleFailedValidation)
+	}
+
+	if alertRule.Record != nil {
+		if err := alertRule.Record.Validate(); err != nil {
+			return err
+		}
+		if alertRule.Record.From != alertRule.Condition {
+			return fmt.Errorf("%w: condition of a recording rule must be the same as the refID it records from", ngmodels.ErrAlertRuleFailedValidation)
+		}
+	}
 	return nil
 }
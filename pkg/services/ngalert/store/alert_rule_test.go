@@ -84,6 +84,74 @@ func TestIntegrationUpdateAlertRules(t *testing.T) {
 
 		require.ErrorIs(t, err, ErrOptimisticLock)
 	})
+
+	t.Run("should store record of recording rule", func(t *testing.T) {
+		rule := createRule(t)
+		newRule := models.CopyRule(rule)
+		newRule.Record = &models.Record{Metric: "test_metric", From: rule.Condition}
+		err := store.UpdateAlertRules(context.Background(), []models.UpdateRule{{
+			Existing: rule,
+			New:      *newRule,
+		},
+		})
+		require.NoError(t, err)
+
+		dbrule := &models.AlertRule{}
+		err = sqlStore.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+			exist, err := sess.Table(models.AlertRule{}).ID(rule.ID).Get(dbrule)
+			require.Truef(t, exist, fmt.Sprintf("rule with ID %d does not exist", rule.ID))
+			return err
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, newRule.Record, dbrule.Record)
+		require.Equal(t, models.RuleTypeRecording, dbrule.Type())
+	})
+
+	t.Run("should list versions of the rule and store the restored version", func(t *testing.T) {
+		rule := createRule(t)
+		newRule := models.CopyRule(rule)
+		newRule.Title = util.GenerateShortUID()
+		err := store.UpdateAlertRules(context.Background(), []models.UpdateRule{{
+			Existing: rule,
+			New:      *newRule,
+		},
+		})
+		require.NoError(t, err)
+
+		updated := models.CopyRule(newRule)
+		updated.Version = rule.Version + 1
+		restored := models.CopyRule(rule)
+		err = store.UpdateAlertRules(context.Background(), []models.UpdateRule{{
+			Existing:     updated,
+			New:          *restored,
+			RestoredFrom: rule.Version + 1,
+		},
+		})
+		require.NoError(t, err)
+
+		listQuery := &models.ListAlertRuleVersionsQuery{OrgID: rule.OrgID, UID: rule.UID}
+		require.NoError(t, store.ListAlertRuleVersions(context.Background(), listQuery))
+		require.Len(t, listQuery.Result, 2)
+		require.Equal(t, rule.Version+2, listQuery.Result[0].Version)
+		require.Equal(t, rule.Version+1, listQuery.Result[0].ParentVersion)
+		require.Equal(t, rule.Version+1, listQuery.Result[0].RestoredFrom)
+		require.Equal(t, rule.Title, listQuery.Result[0].Title)
+		require.Equal(t, rule.Version+1, listQuery.Result[1].Version)
+		require.Equal(t, newRule.Title, listQuery.Result[1].Title)
+
+		getQuery := &models.GetAlertRuleVersionQuery{OrgID: rule.OrgID, UID: rule.UID, Version: rule.Version + 1}
+		require.NoError(t, store.GetAlertRuleVersion(context.Background(), getQuery))
+		require.Equal(t, newRule.Title, getQuery.Result.Title)
+		require.Zero(t, getQuery.Result.RestoredFrom)
+	})
+
+	t.Run("should return not found if the version does not exist", func(t *testing.T) {
+		rule := createRule(t)
+		query := &models.GetAlertRuleVersionQuery{OrgID: rule.OrgID, UID: rule.UID, Version: rule.Version}
+		err := store.GetAlertRuleVersion(context.Background(), query)
+		require.ErrorIs(t, err, models.ErrAlertRuleVersionNotFound)
+	})
 }
 
 func withIntervalMatching(baseInterval time.Duration) func(*models.AlertRule) {
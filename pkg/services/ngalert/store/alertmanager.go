@@ -45,6 +45,42 @@ func (st *DBstore) GetLatestAlertmanagerConfiguration(ctx context.Context, query
 	})
 }
 
+// GetAlertmanagerConfigurationHistory returns the stored versions of the alertmanager configuration of an organization, latest first.
+func (st *DBstore) GetAlertmanagerConfigurationHistory(ctx context.Context, query *models.GetAlertmanagerConfigurationHistoryQuery) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		configs := make([]*models.AlertConfiguration, 0)
+		q := sess.Desc("id").Where("org_id = ?", query.OrgID)
+		if query.Limit > 0 {
+			q = q.Limit(query.Limit)
+		}
+		if err := q.Find(&configs); err != nil {
+			return err
+		}
+
+		query.Result = configs
+		return nil
+	})
+}
+
+// GetAlertmanagerConfiguration returns a specific version of the alertmanager configuration of an organization.
+// It returns ErrNoAlertmanagerConfiguration if no configuration is found.
+func (st *DBstore) GetAlertmanagerConfiguration(ctx context.Context, query *models.GetAlertmanagerConfigurationQuery) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		c := &models.AlertConfiguration{}
+		ok, err := sess.Where("org_id = ? AND id = ?", query.OrgID, query.ID).Get(c)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return ErrNoAlertmanagerConfiguration
+		}
+
+		query.Result = c
+		return nil
+	})
+}
+
 // GetAllLatestAlertmanagerConfiguration returns the latest configuration of every organization
 func (st *DBstore) GetAllLatestAlertmanagerConfiguration(ctx context.Context) ([]*models.AlertConfiguration, error) {
 	var result []*models.AlertConfiguration
@@ -174,6 +174,66 @@ func TestIntegrationAlertManagerConfigCleanup(t *testing.T) {
 	})
 }
 
+func TestIntegrationAlertManagerConfigHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore := sqlstore.InitTestDB(t)
+	store := &DBstore{
+		SQLStore: sqlStore,
+		Logger:   log.NewNopLogger(),
+	}
+	for _, config := range []string{"first-config", "second-config", "third-config"} {
+		setupConfig(t, config, store)
+	}
+	err := store.SaveAlertmanagerConfiguration(context.Background(), &models.SaveAlertmanagerConfigurationCmd{
+		AlertmanagerConfiguration: "other-org-config",
+		ConfigurationVersion:      "v1",
+		OrgID:                     2,
+	})
+	require.NoError(t, err)
+
+	t.Run("should return all configs of the org latest first", func(t *testing.T) {
+		req := &models.GetAlertmanagerConfigurationHistoryQuery{OrgID: 1}
+		err := store.GetAlertmanagerConfigurationHistory(context.Background(), req)
+		require.NoError(t, err)
+		require.Len(t, req.Result, 3)
+		require.Equal(t, "third-config", req.Result[0].AlertmanagerConfiguration)
+		require.Equal(t, "second-config", req.Result[1].AlertmanagerConfiguration)
+		require.Equal(t, "first-config", req.Result[2].AlertmanagerConfiguration)
+	})
+
+	t.Run("should limit the number of configs", func(t *testing.T) {
+		req := &models.GetAlertmanagerConfigurationHistoryQuery{OrgID: 1, Limit: 2}
+		err := store.GetAlertmanagerConfigurationHistory(context.Background(), req)
+		require.NoError(t, err)
+		require.Len(t, req.Result, 2)
+		require.Equal(t, "third-config", req.Result[0].AlertmanagerConfiguration)
+	})
+
+	t.Run("should return config by id", func(t *testing.T) {
+		history := &models.GetAlertmanagerConfigurationHistoryQuery{OrgID: 1}
+		err := store.GetAlertmanagerConfigurationHistory(context.Background(), history)
+		require.NoError(t, err)
+
+		req := &models.GetAlertmanagerConfigurationQuery{OrgID: 1, ID: history.Result[1].ID}
+		err = store.GetAlertmanagerConfiguration(context.Background(), req)
+		require.NoError(t, err)
+		require.Equal(t, "second-config", req.Result.AlertmanagerConfiguration)
+	})
+
+	t.Run("should not return config of another org", func(t *testing.T) {
+		history := &models.GetAlertmanagerConfigurationHistoryQuery{OrgID: 2}
+		err := store.GetAlertmanagerConfigurationHistory(context.Background(), history)
+		require.NoError(t, err)
+		require.Len(t, history.Result, 1)
+
+		req := &models.GetAlertmanagerConfigurationQuery{OrgID: 1, ID: history.Result[0].ID}
+		err = store.GetAlertmanagerConfiguration(context.Background(), req)
+		require.ErrorIs(t, err, ErrNoAlertmanagerConfiguration)
+	})
+}
+
 func setupConfig(t *testing.T, config string, store *DBstore) (string, string) {
 	t.Helper()
 	config, configMD5 := config, fmt.Sprintf("%x", md5.Sum([]byte(config)))
@@ -25,9 +25,13 @@ const AlertDefinitionMaxTitleLength = 190
 type AlertingStore interface {
 	GetLatestAlertmanagerConfiguration(ctx context.Context, query *models.GetLatestAlertmanagerConfigurationQuery) error
 	GetAllLatestAlertmanagerConfiguration(ctx context.Context) ([]*models.AlertConfiguration, error)
+	GetAlertmanagerConfigurationHistory(ctx context.Context, query *models.GetAlertmanagerConfigurationHistoryQuery) error
+	GetAlertmanagerConfiguration(ctx context.Context, query *models.GetAlertmanagerConfigurationQuery) error
 	SaveAlertmanagerConfiguration(ctx context.Context, cmd *models.SaveAlertmanagerConfigurationCmd) error
 	SaveAlertmanagerConfigurationWithCallback(ctx context.Context, cmd *models.SaveAlertmanagerConfigurationCmd, callback SaveCallback) error
 	UpdateAlertmanagerConfiguration(ctx context.Context, cmd *models.SaveAlertmanagerConfigurationCmd) error
+	SaveNotificationDelivery(ctx context.Context, delivery *models.NotificationDelivery) error
+	GetNotificationDeliveries(ctx context.Context, query *models.GetNotificationDeliveriesQuery) error
 }
 
 // DBstore stores the alert definitions and instances in the database.
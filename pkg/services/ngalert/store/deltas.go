@@ -95,6 +95,11 @@ func CalculateChanges(ctx context.Context, ruleReader RuleReader, groupKey model
 			continue
 		}
 
+		// a recording rule submitted without record keeps it, and therefore its condition must be the node it records from
+		if r.Record == nil && existing.Record != nil && r.Condition != "" && len(r.Data) != 0 && r.Condition != existing.Record.From {
+			return nil, fmt.Errorf("%w: rule %s is a recording rule that records %s, specify record to change what it records", models.ErrAlertRuleFailedValidation, r.UID, existing.Record.From)
+		}
+
 		models.PatchPartialAlertRule(existing, r)
 
 		diff := existing.Diff(r, AlertRuleFieldsToIgnoreInDiff[:]...)
@@ -182,6 +182,39 @@ func TestCalculateChanges(t *testing.T) {
 		}
 	})
 
+	t.Run("should keep record of a recording rule submitted without it", func(t *testing.T) {
+		dbRule := models.AlertRuleGen(withOrgID(orgId), func(rule *models.AlertRule) {
+			rule.Record = &models.Record{Metric: "test_metric", From: rule.Condition}
+		})()
+		fakeStore := fakes.NewRuleStore(t)
+		fakeStore.PutRule(context.Background(), dbRule)
+
+		submitted := models.CopyRule(dbRule)
+		submitted.Record = nil
+		submitted.Title = "updated title"
+
+		changes, err := CalculateChanges(context.Background(), fakeStore, dbRule.GetGroupKey(), []*models.AlertRule{submitted})
+		require.NoError(t, err)
+		require.Len(t, changes.Update, 1)
+		require.Equal(t, dbRule.Record, changes.Update[0].New.Record)
+		require.Equal(t, dbRule.Condition, changes.Update[0].New.Condition)
+	})
+
+	t.Run("should fail if condition of a recording rule submitted without record changes", func(t *testing.T) {
+		dbRule := models.AlertRuleGen(withOrgID(orgId), func(rule *models.AlertRule) {
+			rule.Record = &models.Record{Metric: "test_metric", From: rule.Condition}
+		})()
+		fakeStore := fakes.NewRuleStore(t)
+		fakeStore.PutRule(context.Background(), dbRule)
+
+		submitted := models.CopyRule(dbRule)
+		submitted.Record = nil
+		submitted.Condition = "other-" + dbRule.Condition
+
+		_, err := CalculateChanges(context.Background(), fakeStore, dbRule.GetGroupKey(), []*models.AlertRule{submitted})
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+
 	t.Run("should be able to find alerts by UID in other group/namespace", func(t *testing.T) {
 		sourceGroupKey := models.GenerateGroupKey(orgId)
 		inDatabaseMap, inDatabase := models.GenerateUniqueAlertRules(rand.Intn(10)+10, models.AlertRuleGen(withGroupKey(sourceGroupKey)))
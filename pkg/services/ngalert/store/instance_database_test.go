@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -147,9 +148,10 @@ func TestIntegrationAlertInstanceOperations(t *testing.T) {
 				RuleUID:    alertRule1.UID,
 				LabelsHash: hash,
 			},
-			CurrentState:  models.InstanceStateFiring,
-			CurrentReason: string(models.InstanceStateError),
-			Labels:        labels,
+			CurrentState:    models.InstanceStateFiring,
+			CurrentReason:   string(models.InstanceStateError),
+			Labels:          labels,
+			KeepFiringSince: time.Unix(1666000000, 0),
 		}
 		err := dbstore.SaveAlertInstances(ctx, instance)
 		require.NoError(t, err)
@@ -166,6 +168,7 @@ func TestIntegrationAlertInstanceOperations(t *testing.T) {
 		require.Equal(t, alertRule1.OrgID, listCmd.Result[0].RuleOrgID)
 		require.Equal(t, alertRule1.UID, listCmd.Result[0].RuleUID)
 		require.Equal(t, instance.CurrentReason, listCmd.Result[0].CurrentReason)
+		require.Equal(t, instance.KeepFiringSince.Unix(), listCmd.Result[0].KeepFiringSince.Unix())
 	})
 
 	t.Run("can save and read new alert instance with no labels", func(t *testing.T) {
@@ -195,6 +198,7 @@ func TestIntegrationAlertInstanceOperations(t *testing.T) {
 		require.Equal(t, alertRule2.OrgID, listCmd.Result[0].RuleOrgID)
 		require.Equal(t, alertRule2.UID, listCmd.Result[0].RuleUID)
 		require.Equal(t, instance.Labels, listCmd.Result[0].Labels)
+		require.True(t, listCmd.Result[0].KeepFiringSince.IsZero())
 	})
 
 	t.Run("can save two instances with same org_id, uid and different labels", func(t *testing.T) {
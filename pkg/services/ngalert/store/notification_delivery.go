@@ -0,0 +1,73 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// NotificationDeliveryRecordsLimit defines the limit of how many notification deliveries
+// are kept per organization. Older deliveries are deleted when a new one is saved.
+var NotificationDeliveryRecordsLimit = 1000
+
+// SaveNotificationDelivery saves a notification delivery and deletes the oldest deliveries of the organization
+// that exceed NotificationDeliveryRecordsLimit.
+func (st DBstore) SaveNotificationDelivery(ctx context.Context, delivery *models.NotificationDelivery) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		if _, err := sess.Insert(delivery); err != nil {
+			return fmt.Errorf("failed to save notification delivery: %w", err)
+		}
+
+		oldest := &models.NotificationDelivery{}
+		ok, err := sess.Where("org_id = ?", delivery.OrgID).Desc("id").Limit(1, NotificationDeliveryRecordsLimit-1).Cols("id").Get(oldest)
+		if err != nil {
+			return fmt.Errorf("failed to find old notification deliveries: %w", err)
+		}
+		if !ok {
+			// Fewer than the limit of deliveries exist. Nothing to clean up.
+			return nil
+		}
+		if _, err := sess.Where("org_id = ? AND id < ?", delivery.OrgID, oldest.ID).Delete(&models.NotificationDelivery{}); err != nil {
+			return fmt.Errorf("failed to delete old notification deliveries: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetNotificationDeliveries returns the notification deliveries of an organization that match the query, latest first.
+func (st DBstore) GetNotificationDeliveries(ctx context.Context, query *models.GetNotificationDeliveriesQuery) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		q := sess.Where("org_id = ?", query.OrgID)
+		if query.Receiver != "" {
+			q = q.And("receiver = ?", query.Receiver)
+		}
+		if query.IntegrationType != "" {
+			q = q.And("integration_type = ?", query.IntegrationType)
+		}
+		if query.GroupKey != "" {
+			q = q.And("group_key = ?", query.GroupKey)
+		}
+		if query.Status != "" {
+			q = q.And("status = ?", query.Status)
+		}
+		if !query.From.IsZero() {
+			q = q.And("created_at >= ?", query.From.UTC())
+		}
+		if !query.To.IsZero() {
+			q = q.And("created_at <= ?", query.To.UTC())
+		}
+		q = q.Desc("id")
+		if query.Limit > 0 {
+			q = q.Limit(query.Limit)
+		}
+
+		deliveries := make([]*models.NotificationDelivery, 0)
+		if err := q.Find(&deliveries); err != nil {
+			return err
+		}
+		query.Result = deliveries
+		return nil
+	})
+}
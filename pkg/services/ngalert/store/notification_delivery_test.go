@@ -0,0 +1,124 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+func TestIntegrationNotificationDeliveries(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore := sqlstore.InitTestDB(t)
+	store := &DBstore{
+		SQLStore: sqlStore,
+		Logger:   log.NewNopLogger(),
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+
+	deliveries := []*models.NotificationDelivery{
+		{OrgID: 1, Receiver: "ops", IntegrationType: "pagerduty", GroupKey: "{}:{alertname=\"a\"}", Status: models.NotificationDeliverySuccess, StatusCode: 202, CreatedAt: now.Add(-3 * time.Minute)},
+		{OrgID: 1, Receiver: "ops", IntegrationType: "slack", GroupKey: "{}:{alertname=\"a\"}", Status: models.NotificationDeliveryFailure, StatusCode: 500, Error: "request failed", Retry: true, CreatedAt: now.Add(-2 * time.Minute)},
+		{OrgID: 1, Receiver: "dev", IntegrationType: "email", GroupKey: "{}:{alertname=\"b\"}", Status: models.NotificationDeliverySuccess, CreatedAt: now.Add(-time.Minute)},
+		{OrgID: 2, Receiver: "ops", IntegrationType: "pagerduty", GroupKey: "{}:{alertname=\"a\"}", Status: models.NotificationDeliverySuccess, CreatedAt: now},
+	}
+	for _, d := range deliveries {
+		require.NoError(t, store.SaveNotificationDelivery(context.Background(), d))
+	}
+
+	receivers := func(result []*models.NotificationDelivery) []string {
+		names := make([]string, 0, len(result))
+		for _, d := range result {
+			names = append(names, fmt.Sprintf("%s/%s", d.Receiver, d.IntegrationType))
+		}
+		return names
+	}
+
+	t.Run("should return the deliveries of the org latest first", func(t *testing.T) {
+		query := &models.GetNotificationDeliveriesQuery{OrgID: 1}
+		require.NoError(t, store.GetNotificationDeliveries(context.Background(), query))
+		require.Equal(t, []string{"dev/email", "ops/slack", "ops/pagerduty"}, receivers(query.Result))
+
+		failed := query.Result[1]
+		require.Equal(t, models.NotificationDeliveryFailure, failed.Status)
+		require.Equal(t, 500, failed.StatusCode)
+		require.Equal(t, "request failed", failed.Error)
+		require.True(t, failed.Retry)
+		require.True(t, now.Add(-2*time.Minute).Equal(failed.CreatedAt))
+	})
+
+	t.Run("should filter the deliveries", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			query    models.GetNotificationDeliveriesQuery
+			expected []string
+		}{
+			{
+				name:     "by receiver",
+				query:    models.GetNotificationDeliveriesQuery{Receiver: "ops"},
+				expected: []string{"ops/slack", "ops/pagerduty"},
+			},
+			{
+				name:     "by integration type",
+				query:    models.GetNotificationDeliveriesQuery{IntegrationType: "pagerduty"},
+				expected: []string{"ops/pagerduty"},
+			},
+			{
+				name:     "by group key",
+				query:    models.GetNotificationDeliveriesQuery{GroupKey: "{}:{alertname=\"b\"}"},
+				expected: []string{"dev/email"},
+			},
+			{
+				name:     "by status",
+				query:    models.GetNotificationDeliveriesQuery{Status: models.NotificationDeliveryFailure},
+				expected: []string{"ops/slack"},
+			},
+			{
+				name:     "by time range",
+				query:    models.GetNotificationDeliveriesQuery{From: now.Add(-150 * time.Second), To: now.Add(-30 * time.Second)},
+				expected: []string{"dev/email", "ops/slack"},
+			},
+			{
+				name:     "by limit",
+				query:    models.GetNotificationDeliveriesQuery{Limit: 1},
+				expected: []string{"dev/email"},
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				query := tc.query
+				query.OrgID = 1
+				require.NoError(t, store.GetNotificationDeliveries(context.Background(), &query))
+				require.Equal(t, tc.expected, receivers(query.Result))
+			})
+		}
+	})
+
+	t.Run("should delete the oldest deliveries of the org above the limit", func(t *testing.T) {
+		origLimit := NotificationDeliveryRecordsLimit
+		t.Cleanup(func() {
+			NotificationDeliveryRecordsLimit = origLimit
+		})
+		NotificationDeliveryRecordsLimit = 2
+
+		require.NoError(t, store.SaveNotificationDelivery(context.Background(), &models.NotificationDelivery{
+			OrgID: 1, Receiver: "dev", IntegrationType: "webhook", Status: models.NotificationDeliverySuccess, CreatedAt: now,
+		}))
+
+		query := &models.GetNotificationDeliveriesQuery{OrgID: 1}
+		require.NoError(t, store.GetNotificationDeliveries(context.Background(), query))
+		require.Equal(t, []string{"dev/webhook", "dev/email"}, receivers(query.Result))
+
+		other := &models.GetNotificationDeliveriesQuery{OrgID: 2}
+		require.NoError(t, store.GetNotificationDeliveries(context.Background(), other))
+		require.Len(t, other.Result, 1)
+	})
+}
@@ -24,6 +24,8 @@ type RuleStore struct {
 	Hook        func(cmd interface{}) error // use Hook if you need to intercept some query and return an error
 	RecordedOps []interface{}
 	Folders     map[int64][]*models2.Folder
+	// Versions contains the history of rules by rule UID, latest first. Rules without history have only the current version.
+	Versions map[string][]*models.AlertRuleVersion
 }
 
 type GenericRecordedQuery struct {
@@ -141,6 +143,76 @@ func (f *RuleStore) GetAlertRuleByUID(_ context.Context, q *models.GetAlertRuleB
 	return nil
 }
 
+// ListAlertRuleVersions returns the versions of the rule from Versions or, if there are none, the current version of the rule.
+func (f *RuleStore) ListAlertRuleVersions(_ context.Context, q *models.ListAlertRuleVersionsQuery) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.RecordedOps = append(f.RecordedOps, *q)
+	if err := f.Hook(*q); err != nil {
+		return err
+	}
+	q.Result = nil
+	if versions, ok := f.Versions[q.UID]; ok {
+		q.Result = append(q.Result, versions...)
+		return nil
+	}
+	for _, rule := range f.Rules[q.OrgID] {
+		if rule.UID == q.UID {
+			q.Result = append(q.Result, VersionOfRule(rule))
+			break
+		}
+	}
+	return nil
+}
+
+// GetAlertRuleVersion returns the version of the rule from Versions or the rule if its current version is the requested one.
+func (f *RuleStore) GetAlertRuleVersion(_ context.Context, q *models.GetAlertRuleVersionQuery) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.RecordedOps = append(f.RecordedOps, *q)
+	if err := f.Hook(*q); err != nil {
+		return err
+	}
+	for _, v := range f.Versions[q.UID] {
+		if v.RuleOrgID == q.OrgID && v.Version == q.Version {
+			q.Result = v
+			return nil
+		}
+	}
+	for _, rule := range f.Rules[q.OrgID] {
+		if rule.UID == q.UID && rule.Version == q.Version {
+			q.Result = VersionOfRule(rule)
+			return nil
+		}
+	}
+	return models.ErrAlertRuleVersionNotFound
+}
+
+// VersionOfRule returns the version of the alert rule that the store would create for its current definition.
+func VersionOfRule(rule *models.AlertRule) *models.AlertRuleVersion {
+	return &models.AlertRuleVersion{
+		RuleOrgID:        rule.OrgID,
+		RuleUID:          rule.UID,
+		RuleNamespaceUID: rule.NamespaceUID,
+		RuleGroup:        rule.RuleGroup,
+		RuleGroupIndex:   rule.RuleGroupIndex,
+		Version:          rule.Version,
+		Created:          rule.Updated,
+		Title:            rule.Title,
+		Condition:        rule.Condition,
+		Data:             rule.Data,
+		IntervalSeconds:  rule.IntervalSeconds,
+		NoDataState:      rule.NoDataState,
+		ExecErrState:     rule.ExecErrState,
+		For:              rule.For,
+		KeepFiringFor:    rule.KeepFiringFor,
+		Annotations:      rule.Annotations,
+		Labels:           rule.Labels,
+		Record:           rule.Record,
+		IsPaused:         rule.IsPaused,
+	}
+}
+
 func (f *RuleStore) GetAlertRulesGroupByRuleUID(_ context.Context, q *models.GetAlertRulesGroupByRuleUIDQuery) error {
 	f.mtx.Lock()
 	defer f.mtx.Unlock()
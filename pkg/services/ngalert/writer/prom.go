@@ -0,0 +1,74 @@
+package writer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/live/remotewrite"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// PrometheusWriter writes the results of recording rules to a Prometheus remote write endpoint.
+type PrometheusWriter struct {
+	url               string
+	basicAuthUsername string
+	basicAuthPassword string
+	client            *http.Client
+	logger            log.Logger
+}
+
+func NewPrometheusWriter(cfg setting.UnifiedAlertingRecordingRulesSettings, logger log.Logger) *PrometheusWriter {
+	return &PrometheusWriter{
+		url:               cfg.RemoteWriteURL,
+		basicAuthUsername: cfg.BasicAuthUsername,
+		basicAuthPassword: cfg.BasicAuthPassword,
+		client:            &http.Client{Timeout: cfg.Timeout},
+		logger:            logger,
+	}
+}
+
+func (w *PrometheusWriter) Write(ctx context.Context, name string, t time.Time, frames data.Frames, extraLabels map[string]string) error {
+	series := remotewrite.TimeSeriesFromInstantFrames(name, t, extraLabels, frames...)
+	if len(series) == 0 {
+		w.logger.Debug("no series to write", "metric", name)
+		return nil
+	}
+
+	body, err := remotewrite.TimeSeriesToBytes(series)
+	if err != nil {
+		return fmt.Errorf("failed to serialize series: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create remote write request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	if w.basicAuthUsername != "" || w.basicAuthPassword != "" {
+		req.SetBasicAuth(w.basicAuthUsername, w.basicAuthPassword)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send remote write request: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			w.logger.Warn("failed to close response body", "err", err)
+		}
+	}()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected response code %d from remote write endpoint", resp.StatusCode)
+	}
+	w.logger.Debug("series written", "metric", name, "series", len(series))
+	return nil
+}
@@ -0,0 +1,77 @@
+package writer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestPrometheusWriter_Write(t *testing.T) {
+	var received *prompb.WriteRequest
+	var user, password string
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, _ = r.BasicAuth()
+		require.Equal(t, "snappy", r.Header.Get("Content-Encoding"))
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		decoded, err := snappy.Decode(nil, b)
+		require.NoError(t, err)
+		received = &prompb.WriteRequest{}
+		require.NoError(t, proto.Unmarshal(decoded, received))
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	writer := NewPrometheusWriter(setting.UnifiedAlertingRecordingRulesSettings{
+		RemoteWriteURL:    server.URL,
+		BasicAuthUsername: "user",
+		BasicAuthPassword: "password",
+		Timeout:           time.Second,
+	}, log.NewNopLogger())
+
+	now := time.Now()
+	frames := data.Frames{
+		data.NewFrame("", data.NewField("B", data.Labels{"instance": "a"}, []float64{42})),
+	}
+
+	t.Run("should send series to remote write endpoint", func(t *testing.T) {
+		err := writer.Write(context.Background(), "test_metric", now, frames, map[string]string{"team": "sre"})
+		require.NoError(t, err)
+		require.Equal(t, "user", user)
+		require.Equal(t, "password", password)
+		require.NotNil(t, received)
+		require.Len(t, received.Timeseries, 1)
+		require.Equal(t, []prompb.Label{
+			{Name: "__name__", Value: "test_metric"},
+			{Name: "instance", Value: "a"},
+			{Name: "team", Value: "sre"},
+		}, received.Timeseries[0].Labels)
+		require.Equal(t, []prompb.Sample{{Value: 42, Timestamp: now.UnixMilli()}}, received.Timeseries[0].Samples)
+	})
+
+	t.Run("should not send request if there are no series", func(t *testing.T) {
+		received = nil
+		err := writer.Write(context.Background(), "test_metric", now, data.Frames{data.NewFrame("")}, nil)
+		require.NoError(t, err)
+		require.Nil(t, received)
+	})
+
+	t.Run("should fail if endpoint responds with error", func(t *testing.T) {
+		status = http.StatusBadRequest
+		err := writer.Write(context.Background(), "test_metric", now, frames, nil)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,46 @@
+package writer
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// Writer writes the results of recording rules to a time series storage.
+type Writer interface {
+	// Write writes frames as a metric with the given name. All samples are written at time t,
+	// and extraLabels are added to the labels of every series.
+	Write(ctx context.Context, name string, t time.Time, frames data.Frames, extraLabels map[string]string) error
+}
+
+// ErrNotConfigured is returned by the Writer used when the remote write endpoint of recording rules is not configured.
+var ErrNotConfigured = errors.New("recording rules are not enabled: remote write endpoint is not configured")
+
+// disabledWriter is a Writer that rejects all data so that results of recording rules are not lost silently.
+type disabledWriter struct{}
+
+func (w disabledWriter) Write(_ context.Context, _ string, _ time.Time, _ data.Frames, _ map[string]string) error {
+	return ErrNotConfigured
+}
+
+// IsConfigured returns false if w is the Writer used when the remote write endpoint of recording rules is not configured.
+func IsConfigured(w Writer) bool {
+	if w == nil {
+		return false
+	}
+	_, disabled := w.(disabledWriter)
+	return !disabled
+}
+
+// New creates a Writer according to the configuration of recording rules.
+func New(cfg setting.UnifiedAlertingRecordingRulesSettings, logger log.Logger) Writer {
+	if !cfg.IsEnabled() {
+		return disabledWriter{}
+	}
+	return NewPrometheusWriter(cfg, logger)
+}
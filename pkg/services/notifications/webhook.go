@@ -47,6 +47,21 @@ var netClient WebhookClient = &http.Client{
 	Transport: netTransport,
 }
 
+type responseStatusCodeRecorderKey struct{}
+
+// WithResponseStatusCodeRecorder returns a context that passes the HTTP status code of the responses
+// to the webhooks sent with it to record.
+func WithResponseStatusCodeRecorder(ctx context.Context, record func(statusCode int)) context.Context {
+	return context.WithValue(ctx, responseStatusCodeRecorderKey{}, record)
+}
+
+// RecordResponseStatusCode passes the HTTP status code of a response to the recorder of the context, if any.
+func RecordResponseStatusCode(ctx context.Context, statusCode int) {
+	if record, ok := ctx.Value(responseStatusCodeRecorderKey{}).(func(int)); ok {
+		record(statusCode)
+	}
+}
+
 func (ns *NotificationService) sendWebRequestSync(ctx context.Context, webhook *Webhook) error {
 	if webhook.HttpMethod == "" {
 		webhook.HttpMethod = http.MethodPost
@@ -87,6 +102,7 @@ func (ns *NotificationService) sendWebRequestSync(ctx context.Context, webhook *
 			ns.log.Warn("Failed to close response body", "err", err)
 		}
 	}()
+	RecordResponseStatusCode(ctx, resp.StatusCode)
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
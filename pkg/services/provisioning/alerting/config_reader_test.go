@@ -1,11 +1,22 @@
 package alerting
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
-	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
 const (
@@ -29,6 +40,8 @@ const (
 	testFileCorrectProperties_mt        = "./testdata/mute_times/correct-properties"
 	testFileCorrectPropertiesWithOrg_mt = "./testdata/mute_times/correct-properties-with-org"
 	testFileMultipleMts                 = "./testdata/mute_times/multiple-mute-times"
+	testFileDateRanges_mt               = "./testdata/mute_times/date-ranges"
+	testFileInvalidDateRange_mt         = "./testdata/mute_times/invalid-date-range"
 	testFileCorrectProperties_t         = "./testdata/templates/correct-properties"
 	testFileCorrectPropertiesWithOrg_t  = "./testdata/templates/correct-properties-with-org"
 	testFileMultipleTs                  = "./testdata/templates/multiple-templates"
@@ -142,6 +155,19 @@ func TestConfigReader(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, file[0].MuteTimes, 2)
 	})
+	t.Run("a mute times file with date ranges should not error", func(t *testing.T) {
+		file, err := configReader.readConfig(ctx, testFileDateRanges_mt)
+		require.NoError(t, err)
+		mt := file[0].MuteTimes[0].MuteTime
+		require.Equal(t, "America/New_York", mt.TimeIntervals[0].Location.String())
+		require.Len(t, mt.DateRanges, 2)
+		require.Equal(t, "Europe/Berlin", mt.DateRanges[0].Location.String())
+		require.True(t, mt.Config().ContainsTime(time.Date(2022, 10, 20, 20, 30, 0, 0, time.UTC)))
+	})
+	t.Run("a mute times file with an invalid date range should error", func(t *testing.T) {
+		_, err := configReader.readConfig(ctx, testFileInvalidDateRange_mt)
+		require.ErrorContains(t, err, "must be before end time")
+	})
 	t.Run("a template file with correct properties and specific org should not error", func(t *testing.T) {
 		_, err := configReader.readConfig(ctx, testFileCorrectProperties_t)
 		require.NoError(t, err)
@@ -158,4 +184,149 @@ func TestConfigReader(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, file[0].Templates, 2)
 	})
+	t.Run("exported files should be read the same as they were exported", func(t *testing.T) {
+		export := testAlertingFileExport(t)
+		dir := t.TempDir()
+		var yamlFile bytes.Buffer
+		enc := yaml.NewEncoder(&yamlFile)
+		enc.SetIndent(2)
+		require.NoError(t, enc.Encode(export))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "export.yaml"), yamlFile.Bytes(), 0600))
+		jsonFile, err := json.Marshal(export)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "export.json"), jsonFile, 0600))
+
+		files, err := configReader.readConfig(ctx, dir)
+		require.NoError(t, err)
+		require.Len(t, files, 2)
+		for _, file := range files {
+			require.Len(t, file.Groups, 1, file.Filename)
+			group := file.Groups[0]
+			require.Equal(t, "my $group", group.Name)
+			require.Equal(t, "my folder", group.Folder)
+			require.Equal(t, 90*time.Second, group.Interval)
+			require.Len(t, group.Rules, 2)
+			rule := group.Rules[0]
+			require.Equal(t, "rule-uid", rule.UID)
+			require.Equal(t, "high $ usage", rule.Title)
+			require.Equal(t, "B", rule.Condition)
+			require.Equal(t, 5*time.Minute, rule.For)
+			require.Equal(t, 10*time.Minute, rule.KeepFiringFor)
+			require.Equal(t, map[string]string{"team": "$team"}, rule.Labels)
+			require.Equal(t, map[string]string{"summary": "{{ $value }}"}, rule.Annotations)
+			require.Equal(t, models.NoDataState("OK"), rule.NoDataState)
+			require.Equal(t, models.ExecutionErrorState("Error"), rule.ExecErrState)
+			require.Equal(t, "dashboard-uid", *rule.DashboardUID)
+			require.Equal(t, int64(3), *rule.PanelID)
+			require.Len(t, rule.Data, 2)
+			require.Equal(t, models.RelativeTimeRange{From: models.Duration(10 * time.Minute)}, rule.Data[0].RelativeTimeRange)
+			require.JSONEq(t, `{"expr":"up{job=\"$job\"}","refId":"A"}`, string(rule.Data[0].Model))
+			require.True(t, group.Rules[1].IsPaused)
+			require.Equal(t, &models.Record{Metric: "job:up", From: "A"}, group.Rules[1].Record)
+
+			require.Len(t, file.ContactPoints, 1)
+			cps := file.ContactPoints[0].ContactPoints
+			require.Len(t, cps, 2)
+			require.Equal(t, "my contact point", cps[0].Name)
+			require.Equal(t, "pa$word", cps[0].Settings.Get("password").MustString())
+			require.Equal(t, "slack-uid", cps[1].UID)
+
+			require.Len(t, file.Policies, 1)
+			require.Equal(t, "my contact point", file.Policies[0].Policy.Receiver)
+			require.Equal(t, "team", file.Policies[0].Policy.Routes[0].ObjectMatchers[0].Name)
+			require.Len(t, file.MuteTimes, 1)
+			require.Equal(t, "maintenance", file.MuteTimes[0].MuteTime.Name)
+			require.Len(t, file.MuteTimes[0].MuteTime.DateRanges, 1)
+			require.Len(t, file.Templates, 1)
+			require.Equal(t, "{{ define \"a\" }}$labels{{ end }}", file.Templates[0].Data.Template)
+		}
+	})
+}
+
+func testAlertingFileExport(t *testing.T) definitions.AlertingFileExport {
+	t.Helper()
+	dashboardUID := "dashboard-uid"
+	panelID := int64(3)
+	query := models.AlertQuery{
+		RefID:             "A",
+		DatasourceUID:     "prometheus",
+		RelativeTimeRange: models.RelativeTimeRange{From: models.Duration(10 * time.Minute)},
+		Model:             json.RawMessage(`{"expr":"up{job=\"$job\"}","refId":"A"}`),
+	}
+	condition := models.AlertQuery{
+		RefID:         "B",
+		DatasourceUID: "__expr__",
+		QueryType:     "__expr__",
+		Model:         json.RawMessage(`{"type":"math","expression":"$A > 0","refId":"B"}`),
+	}
+	group, err := definitions.NewAlertRuleGroupExport(1, "my folder", models.AlertRuleGroup{
+		Title:    "my $group",
+		Interval: 90,
+		Rules: []models.AlertRule{
+			{
+				UID:           "rule-uid",
+				Title:         "high $ usage",
+				Condition:     "B",
+				Data:          []models.AlertQuery{query, condition},
+				DashboardUID:  &dashboardUID,
+				PanelID:       &panelID,
+				NoDataState:   models.OK,
+				ExecErrState:  models.ErrorErrState,
+				For:           5 * time.Minute,
+				KeepFiringFor: 10 * time.Minute,
+				Labels:        map[string]string{"team": "$team"},
+				Annotations:   map[string]string{"summary": "{{ $value }}"},
+			},
+			{
+				UID:          "recording-uid",
+				Title:        "job:up",
+				Condition:    "A",
+				Data:         []models.AlertQuery{query},
+				NoDataState:  models.NoData,
+				ExecErrState: models.AlertingErrState,
+				Record:       &models.Record{Metric: "job:up", From: "A"},
+				IsPaused:     true,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	cps, err := definitions.NewContactPointExports(1, []definitions.EmbeddedContactPoint{
+		{UID: "email-uid", Name: "my contact point", Type: "email", Settings: simplejson.NewFromAny(map[string]interface{}{
+			"addresses": "team@example.com",
+			"password":  "pa$word",
+		})},
+		{UID: "slack-uid", Name: "my contact point", Type: "slack", Settings: simplejson.NewFromAny(map[string]interface{}{
+			"url": "https://hooks.slack.com/services/token",
+		})},
+	})
+	require.NoError(t, err)
+
+	matchers, err := labels.NewMatcher(labels.MatchEqual, "team", "ops")
+	require.NoError(t, err)
+	policy := definitions.NewNotificationPolicyExport(1, definitions.Route{
+		Receiver:   "my contact point",
+		Provenance: models.ProvenanceAPI,
+		Routes: []*definitions.Route{
+			{Receiver: "my contact point", ObjectMatchers: definitions.ObjectMatchers{matchers}},
+		},
+	})
+
+	var mt definitions.MuteTimeInterval
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"name": "maintenance",
+		"time_intervals": [{"weekdays": ["monday:friday"], "times": [{"start_time": "22:00", "end_time": "23:59"}]}],
+		"date_ranges": [{"start_time": "2022-10-20 22:00", "end_time": "2022-10-21 02:00", "location": "Europe/Berlin"}]
+	}`), &mt))
+
+	export := definitions.NewAlertingFileExport()
+	export.Groups = []definitions.AlertRuleGroupExport{group}
+	export.ContactPoints = cps
+	export.Policies = []definitions.NotificationPolicyExport{policy}
+	export.MuteTimes = []definitions.MuteTimeIntervalExport{definitions.NewMuteTimeIntervalExport(1, mt)}
+	export.Templates = []definitions.MessageTemplateExport{definitions.NewMessageTemplateExport(1, definitions.MessageTemplate{
+		Name:     "a",
+		Template: `{{ define "a" }}$labels{{ end }}`,
+	})}
+	return export
 }
@@ -2,6 +2,7 @@ package alerting
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
@@ -13,7 +14,10 @@ type MuteTimeV1 struct {
 	MuteTime definitions.MuteTimeInterval `json:",inline" yaml:",inline"`
 }
 
-func (v1 *MuteTimeV1) mapToModel() MuteTime {
+func (v1 *MuteTimeV1) mapToModel() (MuteTime, error) {
+	if err := v1.MuteTime.Validate(); err != nil {
+		return MuteTime{}, fmt.Errorf("invalid mute time %q: %w", v1.MuteTime.Name, err)
+	}
 	orgID := v1.OrgID.Value()
 	if orgID < 1 {
 		orgID = 1
@@ -21,7 +25,7 @@ func (v1 *MuteTimeV1) mapToModel() MuteTime {
 	return MuteTime{
 		OrgID:    orgID,
 		MuteTime: v1.MuteTime,
-	}
+	}, nil
 }
 
 type MuteTime struct {
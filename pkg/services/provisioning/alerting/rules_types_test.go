@@ -2,6 +2,7 @@ package alerting
 
 import (
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/provisioning/values"
@@ -165,6 +166,60 @@ func TestRules(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, ruleMapped.NoDataState, models.NoData)
 	})
+	t.Run("a rule with out isPaused should not be paused", func(t *testing.T) {
+		rule := validRuleV1(t)
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.False(t, ruleMapped.IsPaused)
+	})
+	t.Run("a rule with isPaused should map it correctly", func(t *testing.T) {
+		rule := validRuleV1(t)
+		isPaused := values.BoolValue{}
+		err := yaml.Unmarshal([]byte("true"), &isPaused)
+		require.NoError(t, err)
+		rule.IsPaused = isPaused
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.True(t, ruleMapped.IsPaused)
+	})
+	t.Run("a rule with keepFiringFor should map it correctly", func(t *testing.T) {
+		rule := validRuleV1(t)
+		keepFiringFor := values.StringValue{}
+		err := yaml.Unmarshal([]byte("5m"), &keepFiringFor)
+		require.NoError(t, err)
+		rule.KeepFiringFor = keepFiringFor
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, 5*time.Minute, ruleMapped.KeepFiringFor)
+	})
+	t.Run("a rule with an invalid keepFiringFor should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		keepFiringFor := values.StringValue{}
+		err := yaml.Unmarshal([]byte("soon"), &keepFiringFor)
+		require.NoError(t, err)
+		rule.KeepFiringFor = keepFiringFor
+		_, err = rule.mapToModel(1)
+		require.Error(t, err)
+	})
+	t.Run("a recording rule without a condition should use the node it records from", func(t *testing.T) {
+		rule := validRuleV1(t)
+		rule.Condition = values.StringValue{}
+		rule.Record = validRecordV1(t)
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, "A", ruleMapped.Condition)
+		require.Equal(t, &models.Record{Metric: "test_metric", From: "A"}, ruleMapped.Record)
+	})
+	t.Run("a recording rule with an invalid metric name should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		rule.Record = validRecordV1(t)
+		metric := values.StringValue{}
+		err := yaml.Unmarshal([]byte("invalid metric"), &metric)
+		require.NoError(t, err)
+		rule.Record.Metric = metric
+		_, err = rule.mapToModel(1)
+		require.Error(t, err)
+	})
 }
 
 func validRuleGroupV1(t *testing.T) AlertRuleGroupV1 {
@@ -216,3 +271,19 @@ func validRuleV1(t *testing.T) AlertRuleV1 {
 		Data:      []QueryV1{{}},
 	}
 }
+
+func validRecordV1(t *testing.T) *RecordV1 {
+	t.Helper()
+	var (
+		metric values.StringValue
+		from   values.StringValue
+	)
+	err := yaml.Unmarshal([]byte("test_metric"), &metric)
+	require.NoError(t, err)
+	err = yaml.Unmarshal([]byte("A"), &from)
+	require.NoError(t, err)
+	return &RecordV1{
+		Metric: metric,
+		From:   from,
+	}
+}
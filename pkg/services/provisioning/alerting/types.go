@@ -77,7 +77,11 @@ func (fileV1 *AlertingFileV1) mapTemplates(alertingFile *AlertingFile) error {
 
 func (fileV1 *AlertingFileV1) mapMuteTimes(alertingFile *AlertingFile) error {
 	for _, mtV1 := range fileV1.MuteTimes {
-		alertingFile.MuteTimes = append(alertingFile.MuteTimes, mtV1.mapToModel())
+		mt, err := mtV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		alertingFile.MuteTimes = append(alertingFile.MuteTimes, mt)
 	}
 	for _, deleteV1 := range fileV1.DeleteMuteTimes {
 		delReq, err := deleteV1.mapToModel()
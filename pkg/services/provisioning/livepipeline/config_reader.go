@@ -0,0 +1,118 @@
+package livepipeline
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+)
+
+type configReader struct {
+	orgService org.Service
+	log        log.Logger
+}
+
+func (cr *configReader) readConfig(ctx context.Context, path string) ([]*pipelineConfig, error) {
+	var configs []*pipelineConfig
+	cr.log.Debug("Looking for live pipeline provisioning files", "path", path)
+
+	files, err := os.ReadDir(path)
+	if err != nil {
+		cr.log.Error("Can't read live pipeline provisioning files from directory", "path", path, "error", err)
+		return configs, nil
+	}
+
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".yaml") && !strings.HasSuffix(file.Name(), ".yml") {
+			continue
+		}
+		cr.log.Debug("Parsing live pipeline provisioning file", "path", path, "file.Name", file.Name())
+		cfg, err := cr.parseConfig(path, file)
+		if err != nil {
+			return nil, fmt.Errorf("failure to parse file %s: %w", file.Name(), err)
+		}
+		if cfg != nil {
+			configs = append(configs, cfg)
+		}
+	}
+
+	if err := cr.validate(ctx, configs); err != nil {
+		return nil, err
+	}
+	return configs, nil
+}
+
+func (cr *configReader) parseConfig(path string, file fs.DirEntry) (*pipelineConfig, error) {
+	filename, _ := filepath.Abs(filepath.Join(path, file.Name()))
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var cfg *pipelineConfigV1
+	if err := yaml.Unmarshal(yamlFile, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		return nil, nil
+	}
+	if v := cfg.APIVersion.Value(); v != 1 {
+		return nil, fmt.Errorf("unsupported apiVersion %d", v)
+	}
+	return cfg.mapToModel()
+}
+
+// validate checks required fields and organizations. Organization ID defaults to 1.
+func (cr *configReader) validate(ctx context.Context, configs []*pipelineConfig) error {
+	checkOrg := func(orgID *int64) error {
+		if *orgID < 1 {
+			*orgID = 1
+			return nil
+		}
+		return utils.CheckOrgExists(ctx, cr.orgService, *orgID)
+	}
+	for _, cfg := range configs {
+		for _, rule := range cfg.ChannelRules {
+			if rule.Pattern == "" {
+				return fmt.Errorf("channel rule pattern is required")
+			}
+			if err := checkOrg(&rule.OrgID); err != nil {
+				return fmt.Errorf("failed to provision channel rule %q: %w", rule.Pattern, err)
+			}
+		}
+		for _, rule := range cfg.DeleteChannelRules {
+			if rule.Pattern == "" {
+				return fmt.Errorf("pattern of channel rule to delete is required")
+			}
+			if rule.OrgID < 1 {
+				rule.OrgID = 1
+			}
+		}
+		for _, writeConfig := range cfg.WriteConfigs {
+			if writeConfig.UID == "" {
+				return fmt.Errorf("write config uid is required")
+			}
+			if err := checkOrg(&writeConfig.OrgID); err != nil {
+				return fmt.Errorf("failed to provision write config %q: %w", writeConfig.UID, err)
+			}
+		}
+		for _, writeConfig := range cfg.DeleteWriteConfigs {
+			if writeConfig.UID == "" {
+				return fmt.Errorf("uid of write config to delete is required")
+			}
+			if writeConfig.OrgID < 1 {
+				writeConfig.OrgID = 1
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package livepipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+// ErrUnsupportedStorage is returned when there is something to provision
+// but the Live pipeline is not configured to use the database storage.
+var ErrUnsupportedStorage = errors.New("provisioning of live pipeline requires [live] pipeline_storage = database")
+
+// Provision Live pipeline channel rules and write configs. Storage is nil when the
+// pipeline doesn't use the database storage.
+func Provision(ctx context.Context, configDirectory string, storage pipeline.Storage, orgService org.Service) error {
+	logger := log.New("provisioning.live_pipeline")
+	p := &Provisioner{
+		log:     logger,
+		storage: storage,
+		cfgProvider: &configReader{
+			orgService: orgService,
+			log:        logger,
+		},
+	}
+	return p.applyChanges(ctx, configDirectory)
+}
+
+// Provisioner is responsible for provisioning Live pipeline channel rules and write configs.
+type Provisioner struct {
+	log         log.Logger
+	cfgProvider *configReader
+	storage     pipeline.Storage
+}
+
+func (p *Provisioner) applyChanges(ctx context.Context, configPath string) error {
+	configs, err := p.cfgProvider.readConfig(ctx, configPath)
+	if err != nil {
+		return err
+	}
+	for _, cfg := range configs {
+		if cfg.empty() {
+			continue
+		}
+		if p.storage == nil {
+			return ErrUnsupportedStorage
+		}
+		if err := p.apply(ctx, cfg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply deletes first, and provisions write configs before the channel
+// rules which may reference them.
+func (p *Provisioner) apply(ctx context.Context, cfg *pipelineConfig) error {
+	for _, rule := range cfg.DeleteChannelRules {
+		p.log.Info("Deleting live pipeline channel rule", "orgId", rule.OrgID, "pattern", rule.Pattern)
+		err := p.storage.DeleteChannelRule(ctx, rule.OrgID, pipeline.ChannelRuleDeleteCmd{Pattern: rule.Pattern})
+		if err != nil {
+			p.log.Warn("Failed to delete live pipeline channel rule", "orgId", rule.OrgID, "pattern", rule.Pattern, "error", err)
+		}
+	}
+	for _, writeConfig := range cfg.DeleteWriteConfigs {
+		p.log.Info("Deleting live pipeline write config", "orgId", writeConfig.OrgID, "uid", writeConfig.UID)
+		err := p.storage.DeleteWriteConfig(ctx, writeConfig.OrgID, pipeline.WriteConfigDeleteCmd{UID: writeConfig.UID})
+		if err != nil {
+			p.log.Warn("Failed to delete live pipeline write config", "orgId", writeConfig.OrgID, "uid", writeConfig.UID, "error", err)
+		}
+	}
+	for _, writeConfig := range cfg.WriteConfigs {
+		p.log.Debug("Provisioning live pipeline write config", "orgId", writeConfig.OrgID, "uid", writeConfig.UID)
+		_, err := p.storage.UpdateWriteConfig(ctx, writeConfig.OrgID, pipeline.WriteConfigUpdateCmd{
+			UID:            writeConfig.UID,
+			Settings:       writeConfig.Settings,
+			SecureSettings: writeConfig.SecureSettings,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to provision write config %q: %w", writeConfig.UID, err)
+		}
+	}
+	for _, rule := range cfg.ChannelRules {
+		p.log.Debug("Provisioning live pipeline channel rule", "orgId", rule.OrgID, "pattern", rule.Pattern)
+		_, err := p.storage.UpdateChannelRule(ctx, rule.OrgID, pipeline.ChannelRuleUpdateCmd{
+			Pattern:  rule.Pattern,
+			Settings: rule.Settings,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to provision channel rule %q: %w", rule.Pattern, err)
+		}
+	}
+	return nil
+}
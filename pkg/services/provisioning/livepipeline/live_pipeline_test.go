@@ -0,0 +1,100 @@
+package livepipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+)
+
+type fakeStorage struct {
+	pipeline.Storage
+	calls        []string
+	rules        []pipeline.ChannelRule
+	writeConfigs []pipeline.WriteConfigUpdateCmd
+}
+
+func (s *fakeStorage) UpdateWriteConfig(_ context.Context, orgID int64, cmd pipeline.WriteConfigUpdateCmd) (pipeline.WriteConfig, error) {
+	s.calls = append(s.calls, "updateWriteConfig")
+	s.writeConfigs = append(s.writeConfigs, cmd)
+	return pipeline.WriteConfig{OrgId: orgID, UID: cmd.UID, Settings: cmd.Settings}, nil
+}
+
+func (s *fakeStorage) DeleteWriteConfig(_ context.Context, _ int64, _ pipeline.WriteConfigDeleteCmd) error {
+	s.calls = append(s.calls, "deleteWriteConfig")
+	return nil
+}
+
+func (s *fakeStorage) UpdateChannelRule(_ context.Context, orgID int64, cmd pipeline.ChannelRuleUpdateCmd) (pipeline.ChannelRule, error) {
+	s.calls = append(s.calls, "updateChannelRule")
+	rule := pipeline.ChannelRule{OrgId: orgID, Pattern: cmd.Pattern, Settings: cmd.Settings}
+	s.rules = append(s.rules, rule)
+	return rule, nil
+}
+
+func (s *fakeStorage) DeleteChannelRule(_ context.Context, _ int64, _ pipeline.ChannelRuleDeleteCmd) error {
+	s.calls = append(s.calls, "deleteChannelRule")
+	return nil
+}
+
+func TestProvision(t *testing.T) {
+	orgService := orgtest.NewOrgServiceFake()
+	orgService.ExpectedOrg = &org.Org{}
+
+	t.Run("Should provision write configs and channel rules", func(t *testing.T) {
+		t.Setenv("REMOTE_WRITE_PASSWORD", "secret")
+		t.Setenv("MQTT_PASSWORD", "mqtt-secret")
+		storage := &fakeStorage{}
+		err := Provision(context.Background(), "testdata/correct", storage, orgService)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"deleteChannelRule", "deleteWriteConfig", "updateWriteConfig", "updateChannelRule"}, storage.calls)
+
+		require.Len(t, storage.writeConfigs, 1)
+		writeConfig := storage.writeConfigs[0]
+		require.Equal(t, "metrics", writeConfig.UID)
+		require.Equal(t, "http://prometheus:9090/api/v1/write", writeConfig.Settings.Endpoint)
+		require.Equal(t, "grafana", writeConfig.Settings.BasicAuth.User)
+		require.Equal(t, map[string]string{"basicAuthPassword": "secret"}, writeConfig.SecureSettings)
+
+		require.Len(t, storage.rules, 1)
+		rule := storage.rules[0]
+		require.Equal(t, int64(2), rule.OrgId)
+		require.Equal(t, "stream/sensors/fleet", rule.Pattern)
+		require.Equal(t, pipeline.ConverterTypeInfluxAuto, rule.Settings.Converter.Type)
+		require.Equal(t, "labels_column", rule.Settings.Converter.AutoInfluxConverterConfig.FrameFormat)
+		require.Len(t, rule.Settings.Sources, 1)
+		require.Equal(t, "ssl://mqtt:8883", rule.Settings.Sources[0].MQTTSourceConfig.URL)
+		require.Equal(t, "sensors/#", rule.Settings.Sources[0].MQTTSourceConfig.Topic)
+		require.Equal(t, &pipeline.BasicAuth{User: "grafana", Password: "mqtt-secret"}, rule.Settings.Sources[0].BasicAuth)
+		require.Equal(t, byte(1), rule.Settings.Sources[0].MQTTSourceConfig.QoS)
+		require.Equal(t, pipeline.FrameOutputTypeManagedStream, rule.Settings.FrameOutputters[0].Type)
+	})
+
+	t.Run("Should fail without database storage when there is something to provision", func(t *testing.T) {
+		err := Provision(context.Background(), "testdata/correct", nil, orgService)
+		require.ErrorIs(t, err, ErrUnsupportedStorage)
+	})
+
+	t.Run("Should ignore missing directory and empty files", func(t *testing.T) {
+		err := Provision(context.Background(), "testdata/does-not-exist", nil, orgService)
+		require.NoError(t, err)
+		err = Provision(context.Background(), "testdata/empty", nil, orgService)
+		require.NoError(t, err)
+	})
+
+	t.Run("Should fail on invalid files", func(t *testing.T) {
+		for dir, expectedErr := range map[string]string{
+			"testdata/broken-yaml":         "failure to parse file pipeline.yaml",
+			"testdata/missing-uid":         "write config uid is required",
+			"testdata/unsupported-version": "unsupported apiVersion 2",
+		} {
+			err := Provision(context.Background(), dir, &fakeStorage{}, orgService)
+			require.ErrorContains(t, err, expectedErr, dir)
+		}
+	})
+}
@@ -0,0 +1,134 @@
+package livepipeline
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+// configVersion is the version of the Live pipeline provisioning file format.
+type configVersion struct {
+	APIVersion values.Int64Value `json:"apiVersion" yaml:"apiVersion"`
+}
+
+// pipelineConfig is a normalized Live pipeline provisioning file.
+type pipelineConfig struct {
+	ChannelRules       []*channelRuleConfig
+	DeleteChannelRules []*deleteChannelRuleConfig
+	WriteConfigs       []*writeConfigConfig
+	DeleteWriteConfigs []*deleteWriteConfigConfig
+}
+
+func (cfg *pipelineConfig) empty() bool {
+	return len(cfg.ChannelRules) == 0 && len(cfg.DeleteChannelRules) == 0 &&
+		len(cfg.WriteConfigs) == 0 && len(cfg.DeleteWriteConfigs) == 0
+}
+
+type channelRuleConfig struct {
+	OrgID    int64
+	Pattern  string
+	Settings pipeline.ChannelRuleSettings
+}
+
+type deleteChannelRuleConfig struct {
+	OrgID   int64
+	Pattern string
+}
+
+type writeConfigConfig struct {
+	OrgID          int64
+	UID            string
+	Settings       pipeline.WriteSettings
+	SecureSettings map[string]string
+}
+
+type deleteWriteConfigConfig struct {
+	OrgID int64
+	UID   string
+}
+
+type pipelineConfigV1 struct {
+	configVersion      `yaml:",inline"`
+	ChannelRules       []*channelRuleConfigV1       `json:"channelRules" yaml:"channelRules"`
+	DeleteChannelRules []*deleteChannelRuleConfigV1 `json:"deleteChannelRules" yaml:"deleteChannelRules"`
+	WriteConfigs       []*writeConfigConfigV1       `json:"writeConfigs" yaml:"writeConfigs"`
+	DeleteWriteConfigs []*deleteWriteConfigConfigV1 `json:"deleteWriteConfigs" yaml:"deleteWriteConfigs"`
+}
+
+type channelRuleConfigV1 struct {
+	OrgID    values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Pattern  values.StringValue `json:"pattern" yaml:"pattern"`
+	Settings values.JSONValue   `json:"settings" yaml:"settings"`
+}
+
+type deleteChannelRuleConfigV1 struct {
+	OrgID   values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Pattern values.StringValue `json:"pattern" yaml:"pattern"`
+}
+
+type writeConfigConfigV1 struct {
+	OrgID          values.Int64Value     `json:"orgId" yaml:"orgId"`
+	UID            values.StringValue    `json:"uid" yaml:"uid"`
+	Settings       values.JSONValue      `json:"settings" yaml:"settings"`
+	SecureSettings values.StringMapValue `json:"secureSettings" yaml:"secureSettings"`
+}
+
+type deleteWriteConfigConfigV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	UID   values.StringValue `json:"uid" yaml:"uid"`
+}
+
+func (cfg *pipelineConfigV1) mapToModel() (*pipelineConfig, error) {
+	r := &pipelineConfig{}
+	for _, rule := range cfg.ChannelRules {
+		settings := pipeline.ChannelRuleSettings{}
+		if err := remarshal(rule.Settings.Value(), &settings); err != nil {
+			return nil, fmt.Errorf("invalid settings of channel rule %q: %w", rule.Pattern.Value(), err)
+		}
+		r.ChannelRules = append(r.ChannelRules, &channelRuleConfig{
+			OrgID:    rule.OrgID.Value(),
+			Pattern:  rule.Pattern.Value(),
+			Settings: settings,
+		})
+	}
+	for _, rule := range cfg.DeleteChannelRules {
+		r.DeleteChannelRules = append(r.DeleteChannelRules, &deleteChannelRuleConfig{
+			OrgID:   rule.OrgID.Value(),
+			Pattern: rule.Pattern.Value(),
+		})
+	}
+	for _, writeConfig := range cfg.WriteConfigs {
+		settings := pipeline.WriteSettings{}
+		if err := remarshal(writeConfig.Settings.Value(), &settings); err != nil {
+			return nil, fmt.Errorf("invalid settings of write config %q: %w", writeConfig.UID.Value(), err)
+		}
+		r.WriteConfigs = append(r.WriteConfigs, &writeConfigConfig{
+			OrgID:          writeConfig.OrgID.Value(),
+			UID:            writeConfig.UID.Value(),
+			Settings:       settings,
+			SecureSettings: writeConfig.SecureSettings.Value(),
+		})
+	}
+	for _, writeConfig := range cfg.DeleteWriteConfigs {
+		r.DeleteWriteConfigs = append(r.DeleteWriteConfigs, &deleteWriteConfigConfig{
+			OrgID: writeConfig.OrgID.Value(),
+			UID:   writeConfig.UID.Value(),
+		})
+	}
+	return r, nil
+}
+
+// remarshal converts settings read from YAML to pipeline config types,
+// which are defined with JSON tags.
+func remarshal(settings map[string]interface{}, v interface{}) error {
+	if settings == nil {
+		return nil
+	}
+	b, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
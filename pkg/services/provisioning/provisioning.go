@@ -15,7 +15,9 @@ import (
 	dashboardservice "github.com/grafana/grafana/pkg/services/dashboards"
 	datasourceservice "github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/encryption"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/notifications"
@@ -24,6 +26,7 @@ import (
 	prov_alerting "github.com/grafana/grafana/pkg/services/provisioning/alerting"
 	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
 	"github.com/grafana/grafana/pkg/services/provisioning/datasources"
+	"github.com/grafana/grafana/pkg/services/provisioning/livepipeline"
 	"github.com/grafana/grafana/pkg/services/provisioning/notifiers"
 	"github.com/grafana/grafana/pkg/services/provisioning/plugins"
 	"github.com/grafana/grafana/pkg/services/quota"
@@ -64,6 +67,7 @@ func ProvideService(
 		provisionDatasources:         datasources.Provision,
 		provisionPlugins:             plugins.Provision,
 		provisionAlerting:            prov_alerting.Provision,
+		provisionLivePipeline:        livepipeline.Provision,
 		dashboardProvisioningService: dashboardProvisioningService,
 		dashboardService:             dashboardService,
 		datasourceService:            datasourceService,
@@ -87,6 +91,7 @@ type ProvisioningService interface {
 	ProvisionNotifications(ctx context.Context) error
 	ProvisionDashboards(ctx context.Context) error
 	ProvisionAlerting(ctx context.Context) error
+	ProvisionLivePipeline(ctx context.Context) error
 	GetDashboardProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
 }
@@ -135,6 +140,7 @@ type ProvisioningServiceImpl struct {
 	provisionDatasources         func(context.Context, string, datasources.Store, datasources.CorrelationsStore, org.Service) error
 	provisionPlugins             func(context.Context, string, plugifaces.Store, pluginsettings.Service, org.Service) error
 	provisionAlerting            func(context.Context, prov_alerting.ProvisionerConfig) error
+	provisionLivePipeline        func(context.Context, string, pipeline.Storage, org.Service) error
 	mutex                        sync.Mutex
 	dashboardProvisioningService dashboardservice.DashboardProvisioningService
 	dashboardService             dashboardservice.DashboardService
@@ -168,6 +174,11 @@ func (ps *ProvisioningServiceImpl) RunInitProvisioners(ctx context.Context) erro
 		return err
 	}
 
+	err = ps.ProvisionLivePipeline(ctx)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -293,6 +304,27 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 	return ps.provisionAlerting(ctx, cfg)
 }
 
+func (ps *ProvisioningServiceImpl) ProvisionLivePipeline(ctx context.Context) error {
+	if ps.provisionLivePipeline == nil || ps.Cfg.IsFeatureToggleEnabled == nil || !ps.Cfg.IsFeatureToggleEnabled(featuremgmt.FlagLivePipeline) {
+		return nil
+	}
+	livePipelinePath := filepath.Join(ps.Cfg.ProvisioningPath, "live")
+	// Provisioned rules must be visible to all Grafana servers, so only the database storage is supported.
+	var storage pipeline.Storage
+	if ps.Cfg.LivePipelineStorage == "database" {
+		storage = &pipeline.SQLStorage{
+			SQLStore:       ps.SQLStore,
+			SecretsService: ps.secretService,
+		}
+	}
+	if err := ps.provisionLivePipeline(ctx, livePipelinePath, storage, ps.orgService); err != nil {
+		err = fmt.Errorf("%v: %w", "Live pipeline provisioning error", err)
+		ps.log.Error("Failed to provision live pipeline", "error", err)
+		return err
+	}
+	return nil
+}
+
 func (ps *ProvisioningServiceImpl) GetDashboardProvisionerResolvedPath(name string) string {
 	return ps.dashboardProvisioner.GetProvisionerResolvedPath(name)
 }
@@ -22,3 +22,42 @@ func addLiveChannelMigrations(mg *migrator.Migrator) {
 	//mg.AddMigration("create live message table", migrator.NewAddTableMigration(liveMessage))
 	//mg.AddMigration("add index live_message.org_id_channel_unique", migrator.NewAddIndexMigration(liveMessage, liveMessage.Indices[0]))
 }
+
+func addLivePipelineMigrations(mg *migrator.Migrator) {
+	channelRule := migrator.Table{
+		Name: "live_pipeline_channel_rule",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "pattern", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "settings", Type: migrator.DB_MediumText, Nullable: false},
+			{Name: "created", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "updated", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "pattern"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create live pipeline channel rule table", migrator.NewAddTableMigration(channelRule))
+	mg.AddMigration("add index live_pipeline_channel_rule.org_id_pattern", migrator.NewAddIndexMigration(channelRule, channelRule.Indices[0]))
+
+	writeConfig := migrator.Table{
+		Name: "live_pipeline_write_config",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "settings", Type: migrator.DB_Text, Nullable: false},
+			{Name: "secure_settings", Type: migrator.DB_Text, Nullable: false},
+			{Name: "created", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "updated", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "uid"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create live pipeline write config table", migrator.NewAddTableMigration(writeConfig))
+	mg.AddMigration("add index live_pipeline_write_config.org_id_uid", migrator.NewAddIndexMigration(writeConfig, writeConfig.Indices[0]))
+}
@@ -99,6 +99,8 @@ func (*OSSMigrations) AddMigration(mg *Migrator) {
 	accesscontrol.AddManagedFolderAlertActionsRepeatMigration(mg)
 	accesscontrol.AddAdminOnlyMigration(mg)
 	accesscontrol.AddSeedAssignmentMigrations(mg)
+
+	addLivePipelineMigrations(mg)
 }
 
 func addMigrationLogMigrations(mg *Migrator) {
@@ -33,6 +33,8 @@ func AddTablesMigrations(mg *migrator.Migrator) {
 	AddProvisioningMigrations(mg)
 
 	AddAlertImageMigrations(mg)
+
+	AddNotificationDeliveryMigrations(mg)
 }
 
 // AddAlertDefinitionMigrations should not be modified.
@@ -166,6 +168,11 @@ func AlertInstanceMigration(mg *migrator.Migrator) {
 		migrator.NewAddColumnMigration(alertInstance, &migrator.Column{
 			Name: "current_reason", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: true,
 		}))
+
+	mg.AddMigration("add keep_firing_since column to alert_instance",
+		migrator.NewAddColumnMigration(alertInstance, &migrator.Column{
+			Name: "keep_firing_since", Type: migrator.DB_BigInt, Nullable: false, Default: "0",
+		}))
 }
 
 func AddAlertRuleMigrations(mg *migrator.Migrator, defaultIntervalSeconds int64) {
@@ -257,6 +264,32 @@ func AddAlertRuleMigrations(mg *migrator.Migrator, defaultIntervalSeconds int64)
 			Default:  "1",
 		},
 	))
+	mg.AddMigration("add record column to alert_rule", migrator.NewAddColumnMigration(
+		migrator.Table{Name: "alert_rule"},
+		&migrator.Column{
+			Name:     "record",
+			Type:     migrator.DB_Text,
+			Nullable: true,
+		},
+	))
+	mg.AddMigration("add is_paused column to alert_rule", migrator.NewAddColumnMigration(
+		migrator.Table{Name: "alert_rule"},
+		&migrator.Column{
+			Name:     "is_paused",
+			Type:     migrator.DB_Bool,
+			Nullable: false,
+			Default:  "0",
+		},
+	))
+	mg.AddMigration("add keep_firing_for column to alert_rule", migrator.NewAddColumnMigration(
+		migrator.Table{Name: "alert_rule"},
+		&migrator.Column{
+			Name:     "keep_firing_for",
+			Type:     migrator.DB_BigInt,
+			Nullable: false,
+			Default:  "0",
+		},
+	))
 }
 
 func AddAlertRuleVersionMigrations(mg *migrator.Migrator) {
@@ -310,6 +343,32 @@ func AddAlertRuleVersionMigrations(mg *migrator.Migrator) {
 			Default:  "1",
 		},
 	))
+	mg.AddMigration("add record column to alert_rule_version", migrator.NewAddColumnMigration(
+		migrator.Table{Name: "alert_rule_version"},
+		&migrator.Column{
+			Name:     "record",
+			Type:     migrator.DB_Text,
+			Nullable: true,
+		},
+	))
+	mg.AddMigration("add is_paused column to alert_rule_version", migrator.NewAddColumnMigration(
+		migrator.Table{Name: "alert_rule_version"},
+		&migrator.Column{
+			Name:     "is_paused",
+			Type:     migrator.DB_Bool,
+			Nullable: false,
+			Default:  "0",
+		},
+	))
+	mg.AddMigration("add keep_firing_for column to alert_rule_version", migrator.NewAddColumnMigration(
+		migrator.Table{Name: "alert_rule_version"},
+		&migrator.Column{
+			Name:     "keep_firing_for",
+			Type:     migrator.DB_BigInt,
+			Nullable: false,
+			Default:  "0",
+		},
+	))
 }
 
 func AddAlertmanagerConfigMigrations(mg *migrator.Migrator) {
@@ -410,3 +469,34 @@ func AddAlertImageMigrations(mg *migrator.Migrator) {
 		Postgres("ALTER TABLE alert_image ALTER COLUMN url TYPE VARCHAR(2048);").
 		Mysql("ALTER TABLE alert_image MODIFY url VARCHAR(2048) NOT NULL;"))
 }
+
+func AddNotificationDeliveryMigrations(mg *migrator.Migrator) {
+	deliveryTable := migrator.Table{
+		Name: "alert_notification_delivery",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "receiver", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "integration_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "integration_name", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "integration_type", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "integration_index", Type: migrator.DB_Int, Nullable: false},
+			{Name: "group_key", Type: migrator.DB_Text, Nullable: false},
+			{Name: "alerts", Type: migrator.DB_Int, Nullable: false},
+			{Name: "status", Type: migrator.DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "status_code", Type: migrator.DB_Int, Nullable: false},
+			{Name: "error", Type: migrator.DB_Text, Nullable: true},
+			{Name: "retry", Type: migrator.DB_Bool, Nullable: false},
+			{Name: "duration_ms", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "created_at", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "created_at"}},
+			{Cols: []string{"org_id", "receiver"}},
+		},
+	}
+
+	mg.AddMigration("create alert_notification_delivery table", migrator.NewAddTableMigration(deliveryTable))
+	mg.AddMigration("add index in alert_notification_delivery on org_id and created_at columns", migrator.NewAddIndexMigration(deliveryTable, deliveryTable.Indices[0]))
+	mg.AddMigration("add index in alert_notification_delivery on org_id and receiver columns", migrator.NewAddIndexMigration(deliveryTable, deliveryTable.Indices[1]))
+}
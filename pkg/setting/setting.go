@@ -272,6 +272,7 @@ type Cfg struct {
 	PluginsAppsSkipVerifyTLS         bool
 	PluginSettings                   PluginSettings
 	PluginsAllowUnsigned             []string
+	PluginRepositoriesConfig         string
 	PluginCatalogURL                 string
 	PluginCatalogHiddenPlugins       []string
 	PluginAdminEnabled               bool
@@ -447,6 +448,16 @@ type Cfg struct {
 	// LiveAllowedOrigins is a set of origins accepted by Live. If not provided
 	// then Live uses AppURL as the only allowed origin.
 	LiveAllowedOrigins []string
+	// LivePipelineStorage is a type of storage for Live pipeline channel
+	// rules and write configs: "file" or "database".
+	LivePipelineStorage string
+	// LiveManagedStreamHistorySize is a number of frames kept per managed
+	// stream channel to replay them on subscribe, 0 means no size limit.
+	LiveManagedStreamHistorySize int
+	// LiveManagedStreamHistoryMaxAge is a maximum age of frames kept per
+	// managed stream channel, 0 means no age limit. If both limits are 0
+	// only the last frame is kept.
+	LiveManagedStreamHistoryMaxAge time.Duration
 
 	// Grafana.com URL
 	GrafanaComURL string
@@ -1697,6 +1708,21 @@ func (cfg *Cfg) readLiveSettings(iniFile *ini.File) error {
 		return fmt.Errorf("unsupported live HA engine type: %s", cfg.LiveHAEngine)
 	}
 	cfg.LiveHAEngineAddress = section.Key("ha_engine_address").MustString("127.0.0.1:6379")
+	cfg.LivePipelineStorage = section.Key("pipeline_storage").MustString("file")
+	switch cfg.LivePipelineStorage {
+	case "file", "database":
+	default:
+		return fmt.Errorf("unsupported live pipeline storage type: %s", cfg.LivePipelineStorage)
+	}
+	cfg.LiveManagedStreamHistorySize = section.Key("managed_stream_history_size").MustInt(0)
+	if cfg.LiveManagedStreamHistorySize < 0 {
+		return fmt.Errorf("unexpected value %d for [live] managed_stream_history_size", cfg.LiveManagedStreamHistorySize)
+	}
+	historyMaxAge, err := gtime.ParseDuration(section.Key("managed_stream_history_max_age").MustString("0"))
+	if err != nil {
+		return fmt.Errorf("invalid value for [live] managed_stream_history_max_age: %w", err)
+	}
+	cfg.LiveManagedStreamHistoryMaxAge = historyMaxAge
 
 	var originPatterns []string
 	allowedOrigins := section.Key("allowed_origins").MustString("")
@@ -1707,7 +1733,7 @@ func (cfg *Cfg) readLiveSettings(iniFile *ini.File) error {
 		}
 		originPatterns = append(originPatterns, originPattern)
 	}
-	_, err := GetAllowedOriginGlobs(originPatterns)
+	_, err = GetAllowedOriginGlobs(originPatterns)
 	if err != nil {
 		return err
 	}
@@ -37,6 +37,11 @@ func (cfg *Cfg) readPluginSettings(iniFile *ini.File) error {
 		cfg.PluginsAllowUnsigned = append(cfg.PluginsAllowUnsigned, plug)
 	}
 
+	cfg.PluginRepositoriesConfig = pluginsSection.Key("repositories_config").MustString("")
+	if cfg.PluginRepositoriesConfig != "" {
+		cfg.PluginRepositoriesConfig = makeAbsolute(cfg.PluginRepositoriesConfig, cfg.HomePath)
+	}
+
 	cfg.PluginCatalogURL = pluginsSection.Key("plugin_catalog_url").MustString("https://grafana.com/grafana/plugins/")
 	cfg.PluginAdminEnabled = pluginsSection.Key("plugin_admin_enabled").MustBool(true)
 	cfg.PluginAdminExternalManageEnabled = pluginsSection.Key("plugin_admin_external_manage_enabled").MustBool(false)
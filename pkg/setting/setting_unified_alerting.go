@@ -52,6 +52,8 @@ const (
 	screenshotsDefaultCapture               = false
 	screenshotsDefaultMaxConcurrent         = 5
 	screenshotsDefaultUploadImageStorage    = false
+	recordingRulesDefaultTimeout            = 10 * time.Second
+	stateHistoryDefaultLokiTimeout          = 10 * time.Second
 	// SchedulerBaseInterval base interval of the scheduler. Controls how often the scheduler fetches database for new changes as well as schedules evaluation of a rule
 	// changing this value is discouraged because this could cause existing alert definition
 	// with intervals that are not exactly divided by this number not to be evaluated
@@ -72,6 +74,7 @@ type UnifiedAlertingSettings struct {
 	MaxAttempts                    int64
 	MinInterval                    time.Duration
 	EvaluationTimeout              time.Duration
+	EvaluationJitter               string
 	ExecuteAlerts                  bool
 	DefaultConfiguration           string
 	Enabled                        *bool // determines whether unified alerting is enabled. If it is nil then user did not define it and therefore its value will be determined during migration. Services should not use it directly.
@@ -83,8 +86,19 @@ type UnifiedAlertingSettings struct {
 	DefaultRuleEvaluationInterval time.Duration
 	Screenshots                   UnifiedAlertingScreenshotSettings
 	ReservedLabels                UnifiedAlertingReservedLabelSettings
+	RecordingRules                UnifiedAlertingRecordingRulesSettings
+	StateHistory                  UnifiedAlertingStateHistorySettings
 }
 
+const (
+	// EvaluationJitterDisabled evaluates all rules with the same interval at the same tick of the scheduler.
+	EvaluationJitterDisabled = "disabled"
+	// EvaluationJitterByGroup evaluates all rules of a group at the same tick, and spreads the groups across the interval.
+	EvaluationJitterByGroup = "by_group"
+	// EvaluationJitterByRule spreads each rule across the interval independently of its group.
+	EvaluationJitterByRule = "by_rule"
+)
+
 type UnifiedAlertingScreenshotSettings struct {
 	Capture                    bool
 	MaxConcurrentScreenshots   int64
@@ -95,12 +109,40 @@ type UnifiedAlertingReservedLabelSettings struct {
 	DisabledLabels map[string]struct{}
 }
 
+type UnifiedAlertingRecordingRulesSettings struct {
+	RemoteWriteURL    string
+	BasicAuthUsername string
+	BasicAuthPassword string
+	Timeout           time.Duration
+}
+
+const (
+	// StateHistoryBackendAnnotations stores the history of alert states as annotations in the Grafana database.
+	StateHistoryBackendAnnotations = "annotations"
+	// StateHistoryBackendLoki pushes the history of alert states to Loki.
+	StateHistoryBackendLoki = "loki"
+)
+
+type UnifiedAlertingStateHistorySettings struct {
+	Backend               string
+	LokiRemoteURL         string
+	LokiTenantID          string
+	LokiBasicAuthUsername string
+	LokiBasicAuthPassword string
+	LokiTimeout           time.Duration
+}
+
 // IsEnabled returns true if UnifiedAlertingSettings.Enabled is either nil or true.
 // It hides the implementation details of the Enabled and simplifies its usage.
 func (u *UnifiedAlertingSettings) IsEnabled() bool {
 	return u.Enabled == nil || *u.Enabled
 }
 
+// IsEnabled returns true if the results of recording rules can be written, i.e. the remote write URL is configured.
+func (r *UnifiedAlertingRecordingRulesSettings) IsEnabled() bool {
+	return r.RemoteWriteURL != ""
+}
+
 // IsReservedLabelDisabled returns true if UnifiedAlertingReservedLabelSettings.DisabledLabels contains the given reserved label.
 func (u *UnifiedAlertingReservedLabelSettings) IsReservedLabelDisabled(label string) bool {
 	_, ok := u.DisabledLabels[label]
@@ -277,6 +319,13 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		uaCfg.DefaultRuleEvaluationInterval = uaMinInterval
 	}
 
+	uaCfg.EvaluationJitter = strings.ToLower(valueAsString(ua, "evaluation_jitter", EvaluationJitterDisabled))
+	switch uaCfg.EvaluationJitter {
+	case EvaluationJitterDisabled, EvaluationJitterByGroup, EvaluationJitterByRule:
+	default:
+		return fmt.Errorf("unknown evaluation jitter %q, should be one of %q, %q or %q", uaCfg.EvaluationJitter, EvaluationJitterDisabled, EvaluationJitterByGroup, EvaluationJitterByRule)
+	}
+
 	screenshots := iniFile.Section("unified_alerting.screenshots")
 	uaCfgScreenshots := uaCfg.Screenshots
 
@@ -294,6 +343,41 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	}
 	uaCfg.ReservedLabels = uaCfgReservedLabels
 
+	recordingRules := iniFile.Section("unified_alerting.recording_rules")
+	uaCfgRecordingRules := UnifiedAlertingRecordingRulesSettings{
+		RemoteWriteURL:    recordingRules.Key("remote_write_url").MustString(""),
+		BasicAuthUsername: recordingRules.Key("basic_auth_username").MustString(""),
+		BasicAuthPassword: recordingRules.Key("basic_auth_password").MustString(""),
+	}
+	uaCfgRecordingRules.Timeout, err = gtime.ParseDuration(valueAsString(recordingRules, "timeout", recordingRulesDefaultTimeout.String()))
+	if err != nil {
+		return err
+	}
+	uaCfg.RecordingRules = uaCfgRecordingRules
+
+	stateHistory := iniFile.Section("unified_alerting.state_history")
+	uaCfgStateHistory := UnifiedAlertingStateHistorySettings{
+		Backend:               strings.ToLower(stateHistory.Key("backend").MustString(StateHistoryBackendAnnotations)),
+		LokiRemoteURL:         stateHistory.Key("loki_remote_url").MustString(""),
+		LokiTenantID:          stateHistory.Key("loki_tenant_id").MustString(""),
+		LokiBasicAuthUsername: stateHistory.Key("loki_basic_auth_username").MustString(""),
+		LokiBasicAuthPassword: stateHistory.Key("loki_basic_auth_password").MustString(""),
+	}
+	switch uaCfgStateHistory.Backend {
+	case StateHistoryBackendAnnotations:
+	case StateHistoryBackendLoki:
+		if uaCfgStateHistory.LokiRemoteURL == "" {
+			return errors.New("loki_remote_url is required when the state history backend is loki")
+		}
+	default:
+		return fmt.Errorf("unknown state history backend %q, should be either %q or %q", uaCfgStateHistory.Backend, StateHistoryBackendAnnotations, StateHistoryBackendLoki)
+	}
+	uaCfgStateHistory.LokiTimeout, err = gtime.ParseDuration(valueAsString(stateHistory, "loki_timeout", stateHistoryDefaultLokiTimeout.String()))
+	if err != nil {
+		return err
+	}
+	uaCfg.StateHistory = uaCfgStateHistory
+
 	cfg.UnifiedAlerting = uaCfg
 	return nil
 }
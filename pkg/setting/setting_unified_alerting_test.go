@@ -266,3 +266,120 @@ func TestMinInterval(t *testing.T) {
 		})
 	}
 }
+
+func TestStateHistorySettings(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		options   map[string]string
+		verifyCfg func(*testing.T, *Cfg, error)
+	}{
+		{
+			desc: "should use annotations by default",
+			verifyCfg: func(t *testing.T, cfg *Cfg, err error) {
+				require.NoError(t, err)
+				require.Equal(t, StateHistoryBackendAnnotations, cfg.UnifiedAlerting.StateHistory.Backend)
+				require.Equal(t, stateHistoryDefaultLokiTimeout, cfg.UnifiedAlerting.StateHistory.LokiTimeout)
+			},
+		},
+		{
+			desc: "should read loki settings",
+			options: map[string]string{
+				"backend":         "Loki",
+				"loki_remote_url": "http://localhost:3100",
+				"loki_tenant_id":  "tenant",
+				"loki_timeout":    "30s",
+			},
+			verifyCfg: func(t *testing.T, cfg *Cfg, err error) {
+				require.NoError(t, err)
+				require.Equal(t, StateHistoryBackendLoki, cfg.UnifiedAlerting.StateHistory.Backend)
+				require.Equal(t, "http://localhost:3100", cfg.UnifiedAlerting.StateHistory.LokiRemoteURL)
+				require.Equal(t, "tenant", cfg.UnifiedAlerting.StateHistory.LokiTenantID)
+				require.Equal(t, 30*time.Second, cfg.UnifiedAlerting.StateHistory.LokiTimeout)
+			},
+		},
+		{
+			desc: "should fail if loki backend has no URL",
+			options: map[string]string{
+				"backend": "loki",
+			},
+			verifyCfg: func(t *testing.T, cfg *Cfg, err error) {
+				require.ErrorContains(t, err, "loki_remote_url")
+			},
+		},
+		{
+			desc: "should fail if backend is unknown",
+			options: map[string]string{
+				"backend": "elasticsearch",
+			},
+			verifyCfg: func(t *testing.T, cfg *Cfg, err error) {
+				require.ErrorContains(t, err, "unknown state history backend")
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			f := ini.Empty()
+			section, err := f.NewSection("unified_alerting.state_history")
+			require.NoError(t, err)
+			for k, v := range testCase.options {
+				_, err = section.NewKey(k, v)
+				require.NoError(t, err)
+			}
+			cfg := NewCfg()
+			cfg.IsFeatureToggleEnabled = func(key string) bool { return false }
+			err = cfg.ReadUnifiedAlertingSettings(f)
+			testCase.verifyCfg(t, cfg, err)
+		})
+	}
+}
+
+func TestEvaluationJitterSettings(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		value    string
+		expected string
+		err      string
+	}{
+		{
+			desc:     "should be disabled by default",
+			expected: EvaluationJitterDisabled,
+		},
+		{
+			desc:     "should accept jitter by group",
+			value:    "by_group",
+			expected: EvaluationJitterByGroup,
+		},
+		{
+			desc:     "should accept jitter by rule case-insensitively",
+			value:    "By_Rule",
+			expected: EvaluationJitterByRule,
+		},
+		{
+			desc:  "should fail if strategy is unknown",
+			value: "random",
+			err:   "unknown evaluation jitter",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			f := ini.Empty()
+			section, err := f.NewSection("unified_alerting")
+			require.NoError(t, err)
+			if testCase.value != "" {
+				_, err = section.NewKey("evaluation_jitter", testCase.value)
+				require.NoError(t, err)
+			}
+			cfg := NewCfg()
+			cfg.IsFeatureToggleEnabled = func(key string) bool { return false }
+			err = cfg.ReadUnifiedAlertingSettings(f)
+			if testCase.err != "" {
+				require.ErrorContains(t, err, testCase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, cfg.UnifiedAlerting.EvaluationJitter)
+		})
+	}
+}
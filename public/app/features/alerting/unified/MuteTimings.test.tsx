@@ -57,6 +57,11 @@ const ui = {
   years: byTestId('mute-timing-years'),
 
   addInterval: byRole('button', { name: /add another time interval/i }),
+
+  dateRangeStart: byTestId('mute-timing-date-range-start'),
+  dateRangeEnd: byTestId('mute-timing-date-range-end'),
+  dateRangeLocation: byTestId('mute-timing-date-range-location'),
+  addDateRange: byRole('button', { name: /add date range/i }),
   submitButton: byText(/submit/i),
 };
 
@@ -76,6 +81,18 @@ const muteTimeInterval: MuteTimeInterval = {
   ],
 };
 
+const maintenanceWindow: MuteTimeInterval = {
+  name: 'maintenance-window',
+  time_intervals: [],
+  date_ranges: [
+    {
+      start_time: '2022-10-20 22:00',
+      end_time: '2022-10-21 02:00',
+      location: 'Europe/Berlin',
+    },
+  ],
+};
+
 const defaultConfig: AlertManagerCortexConfig = {
   alertmanager_config: {
     receivers: [{ name: 'default' }, { name: 'critical' }],
@@ -292,4 +309,52 @@ describe('Mute timings', () => {
       template_files: {},
     });
   });
+
+  it('keeps and edits the date ranges of a mute timing', async () => {
+    const config: AlertManagerCortexConfig = {
+      ...defaultConfig,
+      alertmanager_config: {
+        ...defaultConfig.alertmanager_config,
+        mute_time_intervals: [maintenanceWindow],
+      },
+    };
+    mocks.api.fetchAlertManagerConfig.mockImplementation(() => Promise.resolve(config));
+
+    await renderMuteTimings(
+      '/alerting/routes/mute-timing/edit' + `?muteName=${encodeURIComponent(maintenanceWindow.name)}`
+    );
+
+    await waitFor(() => expect(mocks.api.fetchAlertManagerConfig).toHaveBeenCalled());
+    expect(ui.nameField.get()).toHaveValue(maintenanceWindow.name);
+    expect(ui.dateRangeStart.get()).toHaveValue('2022-10-20 22:00');
+    expect(ui.dateRangeEnd.get()).toHaveValue('2022-10-21 02:00');
+    expect(ui.dateRangeLocation.get()).toHaveValue('Europe/Berlin');
+
+    await userEvent.click(ui.addDateRange.get());
+    await userEvent.type(ui.dateRangeStart.getAll()[1], '2022-12-24');
+    await userEvent.type(ui.dateRangeEnd.getAll()[1], '2022-12-27');
+
+    fireEvent.submit(ui.form.get());
+
+    await waitFor(() => expect(mocks.api.updateAlertManagerConfig).toHaveBeenCalled());
+    expect(mocks.api.updateAlertManagerConfig).toHaveBeenCalledWith('grafana', {
+      ...config,
+      alertmanager_config: {
+        ...config.alertmanager_config,
+        mute_time_intervals: [
+          {
+            name: maintenanceWindow.name,
+            time_intervals: [],
+            date_ranges: [
+              ...maintenanceWindow.date_ranges!,
+              {
+                start_time: '2022-12-24',
+                end_time: '2022-12-27',
+              },
+            ],
+          },
+        ],
+      },
+    });
+  });
 });
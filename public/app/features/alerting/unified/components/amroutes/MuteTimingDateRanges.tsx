@@ -0,0 +1,107 @@
+import { css } from '@emotion/css';
+import React from 'react';
+import { useFieldArray, useFormContext } from 'react-hook-form';
+
+import { GrafanaTheme2 } from '@grafana/data';
+import { Button, Field, FieldSet, InlineField, InlineFieldRow, Input, useStyles2 } from '@grafana/ui';
+
+import { MuteTimingFields } from '../../types/mute-timing-form';
+import { defaultDateRange } from '../../utils/mute-timings';
+
+const DATE_RANGE_TIME = /^\d{4}-\d{2}-\d{2}([ T]\d{2}:\d{2}(:\d{2})?)?(Z|[+-]\d{2}:\d{2})?$/;
+
+const validateDateRangeTime = (value: string) => !value || DATE_RANGE_TIME.test(value) || 'Time is invalid';
+
+export const MuteTimingDateRanges = () => {
+  const styles = useStyles2(getStyles);
+  const { formState, register } = useFormContext<MuteTimingFields>();
+  const {
+    fields: dateRanges,
+    append: addDateRange,
+    remove: removeDateRange,
+  } = useFieldArray<MuteTimingFields>({
+    name: 'date_ranges',
+  });
+
+  return (
+    <FieldSet className={styles.dateRangeLegend} label="Date ranges">
+      <>
+        <p>
+          A date range is an absolute period of time, such as a maintenance window. The start time is inclusive and the
+          end time is exclusive. Times are in the format YYYY-MM-DD HH:MM in the location of the date range, or in
+          RFC3339 format.
+        </p>
+        {dateRanges.map((dateRange, index) => {
+          const errors = formState.errors.date_ranges?.[index];
+          return (
+            <div key={dateRange.id} className={styles.dateRangeSection}>
+              <Field
+                invalid={!!errors?.start_time || !!errors?.end_time}
+                error={errors?.start_time || errors?.end_time ? 'Times must be in the format YYYY-MM-DD HH:MM' : ''}
+              >
+                <InlineFieldRow>
+                  <InlineField label="Start time" invalid={!!errors?.start_time}>
+                    <Input
+                      {...register(`date_ranges.${index}.start_time`, { validate: validateDateRangeTime })}
+                      className={styles.input}
+                      placeholder="2022-10-20 22:00"
+                      data-testid="mute-timing-date-range-start"
+                    />
+                  </InlineField>
+                  <InlineField label="End time" invalid={!!errors?.end_time}>
+                    <Input
+                      {...register(`date_ranges.${index}.end_time`, { validate: validateDateRangeTime })}
+                      className={styles.input}
+                      placeholder="2022-10-21 02:00"
+                      data-testid="mute-timing-date-range-end"
+                    />
+                  </InlineField>
+                  <InlineField label="Location">
+                    <Input
+                      {...register(`date_ranges.${index}.location`)}
+                      className={styles.input}
+                      placeholder="UTC"
+                      data-testid="mute-timing-date-range-location"
+                    />
+                  </InlineField>
+                </InlineFieldRow>
+              </Field>
+              <Button type="button" variant="destructive" icon="trash-alt" onClick={() => removeDateRange(index)}>
+                Remove date range
+              </Button>
+            </div>
+          );
+        })}
+        <Button
+          type="button"
+          variant="secondary"
+          className={styles.addDateRangeButton}
+          onClick={() => addDateRange(defaultDateRange)}
+          icon="plus"
+        >
+          Add date range
+        </Button>
+      </>
+    </FieldSet>
+  );
+};
+
+const getStyles = (theme: GrafanaTheme2) => ({
+  input: css`
+    width: 180px;
+  `,
+  dateRangeLegend: css`
+    legend {
+      font-size: 1.25rem;
+    }
+  `,
+  dateRangeSection: css`
+    background-color: ${theme.colors.background.secondary};
+    padding: ${theme.spacing(1)};
+    margin-bottom: ${theme.spacing(1)};
+  `,
+  addDateRangeButton: css`
+    margin-top: ${theme.spacing(1)};
+    margin-bottom: ${theme.spacing(2)};
+  `,
+});
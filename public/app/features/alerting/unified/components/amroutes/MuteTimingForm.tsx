@@ -18,12 +18,13 @@ import { updateAlertManagerConfigAction } from '../../state/actions';
 import { MuteTimingFields } from '../../types/mute-timing-form';
 import { renameMuteTimings } from '../../utils/alertmanager';
 import { makeAMLink } from '../../utils/misc';
-import { createMuteTiming, defaultTimeInterval } from '../../utils/mute-timings';
+import { createMuteTiming, defaultDateRange, defaultTimeInterval } from '../../utils/mute-timings';
 import { initialAsyncRequestState } from '../../utils/redux';
 import { AlertManagerPicker } from '../AlertManagerPicker';
 import { AlertingPageWrapper } from '../AlertingPageWrapper';
 import { ProvisionedResource, ProvisioningAlert } from '../Provisioning';
 
+import { MuteTimingDateRanges } from './MuteTimingDateRanges';
 import { MuteTimingTimeInterval } from './MuteTimingTimeInterval';
 
 interface Props {
@@ -37,6 +38,7 @@ const useDefaultValues = (muteTiming?: MuteTimeInterval): MuteTimingFields => {
     const defaultValues = {
       name: '',
       time_intervals: [defaultTimeInterval],
+      date_ranges: [],
     };
 
     if (!muteTiming) {
@@ -49,11 +51,19 @@ const useDefaultValues = (muteTiming?: MuteTimeInterval): MuteTimingFields => {
       days_of_month: interval?.days_of_month?.join(', ') ?? defaultTimeInterval.days_of_month,
       months: interval?.months?.join(', ') ?? defaultTimeInterval.months,
       years: interval?.years?.join(', ') ?? defaultTimeInterval.years,
+      location: interval.location ?? defaultTimeInterval.location,
+    }));
+
+    const dateRanges = (muteTiming.date_ranges ?? []).map((range) => ({
+      start_time: range.start_time,
+      end_time: range.end_time,
+      location: range.location ?? defaultDateRange.location,
     }));
 
     return {
       name: muteTiming.name,
       time_intervals: intervals,
+      date_ranges: dateRanges,
     };
   }, [muteTiming]);
 };
@@ -152,6 +162,7 @@ const MuteTimingForm = ({ muteTiming, showError, provenance }: Props) => {
                 />
               </Field>
               <MuteTimingTimeInterval />
+              <MuteTimingDateRanges />
               <LinkButton
                 type="button"
                 variant="secondary"
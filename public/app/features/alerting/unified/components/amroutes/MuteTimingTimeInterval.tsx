@@ -119,6 +119,16 @@ export const MuteTimingTimeInterval = () => {
                   data-testid="mute-timing-years"
                 />
               </Field>
+              <Field label="Location" description="The time zone of the time interval. It defaults to UTC">
+                <Input
+                  {...register(`time_intervals.${timeIntervalIndex}.location`)}
+                  className={styles.input}
+                  placeholder="Example: Europe/Berlin"
+                  // @ts-ignore react-hook-form doesn't handle nested field arrays well
+                  defaultValue={timeInterval.location}
+                  data-testid="mute-timing-location"
+                />
+              </Field>
               <Button
                 type="button"
                 variant="destructive"
@@ -4,7 +4,12 @@ import React, { FC, useMemo, useState } from 'react';
 import { GrafanaTheme2 } from '@grafana/data';
 import { IconButton, LinkButton, Link, useStyles2, ConfirmModal } from '@grafana/ui';
 import { contextSrv } from 'app/core/services/context_srv';
-import { AlertManagerCortexConfig, MuteTimeInterval, TimeInterval } from 'app/plugins/datasource/alertmanager/types';
+import {
+  AlertManagerCortexConfig,
+  DateRange,
+  MuteTimeInterval,
+  TimeInterval,
+} from 'app/plugins/datasource/alertmanager/types';
 import { useDispatch } from 'app/types';
 
 import { Authorize } from '../../components/Authorize';
@@ -137,6 +142,11 @@ function useColumns(alertManagerSourceName: string, hideActions = false, setMute
         label: 'Time range',
         renderCell: ({ data }) => renderTimeIntervals(data.time_intervals),
       },
+      {
+        id: 'dateRanges',
+        label: 'Date ranges',
+        renderCell: ({ data }) => renderDateRanges(data.date_ranges ?? []),
+      },
     ];
     if (showActions) {
       columns.push({
@@ -204,6 +214,15 @@ function renderTimeIntervals(timeIntervals: TimeInterval[]) {
   });
 }
 
+function renderDateRanges(dateRanges: DateRange[]) {
+  return dateRanges.map((range, index) => (
+    <React.Fragment key={JSON.stringify(range) + index}>
+      {`${range.start_time} - ${range.end_time}${range.location ? ` (${range.location})` : ''}`}
+      <br />
+    </React.Fragment>
+  ));
+}
+
 const getStyles = (theme: GrafanaTheme2) => ({
   container: css`
     display: flex;
@@ -3,6 +3,7 @@ import { TimeRange } from 'app/plugins/datasource/alertmanager/types';
 export type MuteTimingFields = {
   name: string;
   time_intervals: MuteTimingIntervalFields[];
+  date_ranges: MuteTimingDateRangeFields[];
 };
 
 export type MuteTimingIntervalFields = {
@@ -11,4 +12,11 @@ export type MuteTimingIntervalFields = {
   days_of_month: string;
   months: string;
   years: string;
+  location: string;
+};
+
+export type MuteTimingDateRangeFields = {
+  start_time: string;
+  end_time: string;
+  location: string;
 };
@@ -1,8 +1,8 @@
 import { omitBy, isUndefined } from 'lodash';
 
-import { MuteTimeInterval, TimeInterval } from 'app/plugins/datasource/alertmanager/types';
+import { DateRange, MuteTimeInterval, TimeInterval } from 'app/plugins/datasource/alertmanager/types';
 
-import { MuteTimingFields, MuteTimingIntervalFields } from '../types/mute-timing-form';
+import { MuteTimingDateRangeFields, MuteTimingFields, MuteTimingIntervalFields } from '../types/mute-timing-form';
 
 export const DAYS_OF_THE_WEEK = ['monday', 'tuesday', 'wednesday', 'thursday', 'friday', 'saturday', 'sunday'];
 
@@ -27,6 +27,13 @@ export const defaultTimeInterval: MuteTimingIntervalFields = {
   days_of_month: '',
   months: '',
   years: '',
+  location: '',
+};
+
+export const defaultDateRange: MuteTimingDateRangeFields = {
+  start_time: '',
+  end_time: '',
+  location: '',
 };
 
 export const validateArrayField = (value: string, validateValue: (input: string) => boolean, invalidText: string) => {
@@ -48,21 +55,29 @@ const convertStringToArray = (str: string) => {
 
 export const createMuteTiming = (fields: MuteTimingFields): MuteTimeInterval => {
   const timeIntervals: TimeInterval[] = fields.time_intervals.map(
-    ({ times, weekdays, days_of_month, months, years }) => {
+    ({ times, weekdays, days_of_month, months, years, location }) => {
       const interval = {
         times: times.filter(({ start_time, end_time }) => !!start_time && !!end_time),
         weekdays: convertStringToArray(weekdays)?.map((v) => v.toLowerCase()),
         days_of_month: convertStringToArray(days_of_month),
         months: convertStringToArray(months),
         years: convertStringToArray(years),
+        location: location || undefined,
       };
 
       return omitBy(interval, isUndefined);
     }
   );
 
+  const dateRanges: DateRange[] = (fields.date_ranges ?? [])
+    .filter(({ start_time, end_time }) => !!start_time && !!end_time)
+    .map(({ start_time, end_time, location }) =>
+      location ? { start_time, end_time, location } : { start_time, end_time }
+    );
+
   return {
     name: fields.name,
     time_intervals: timeIntervals,
+    ...(dateRanges.length > 0 && { date_ranges: dateRanges }),
   };
 };
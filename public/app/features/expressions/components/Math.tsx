@@ -1,11 +1,11 @@
 import { css } from '@emotion/css';
 import React, { ChangeEvent, FC } from 'react';
 
-import { GrafanaTheme2 } from '@grafana/data';
-import { Icon, InlineField, InlineLabel, Stack, TextArea, useStyles2 } from '@grafana/ui';
+import { GrafanaTheme2, SelectableValue } from '@grafana/data';
+import { Icon, InlineField, InlineFieldRow, InlineLabel, Input, Select, Stack, TextArea, useStyles2 } from '@grafana/ui';
 import { HoverCard } from 'app/features/alerting/unified/components/HoverCard';
 
-import { ExpressionQuery } from '../types';
+import { ExpressionQuery, LabelMatching } from '../types';
 
 interface Props {
   labelWidth: number | 'auto';
@@ -18,12 +18,38 @@ const mathPlaceholder =
   'Math operations on one or more queries. You reference the query by ${refId} ie. $A, $B, $C etc\n' +
   'The sum of two scalar values: $A + $B > 10';
 
+const matchingModes: Array<SelectableValue<LabelMatching['mode']>> = [
+  { value: undefined, label: 'Default', description: 'Match results with equal or contained labels' },
+  { value: 'on', label: 'On', description: 'Match results only by the listed labels' },
+  { value: 'ignoring', label: 'Ignoring', description: 'Match results by all labels but the listed ones' },
+];
+
+const matchingGroups: Array<SelectableValue<LabelMatching['group']>> = [
+  { value: undefined, label: 'One-to-one' },
+  { value: 'left', label: 'Many-to-one', description: 'Several results of the left side can match one of the right side' },
+  { value: 'right', label: 'One-to-many', description: 'One result of the left side can match several of the right side' },
+];
+
+const splitLabels = (value: string): string[] | undefined => {
+  const labels = value
+    .split(',')
+    .map((l) => l.trim())
+    .filter((l) => l !== '');
+  return labels.length > 0 ? labels : undefined;
+};
+
 export const Math: FC<Props> = ({ labelWidth, onChange, query, onRunQuery }) => {
   const onExpressionChange = (event: ChangeEvent<HTMLTextAreaElement>) => {
     onChange({ ...query, expression: event.target.value });
   };
 
+  const onMatchingChange = (matching: LabelMatching) => {
+    const isDefault = !matching.mode && !matching.group;
+    onChange({ ...query, matching: isDefault ? undefined : matching });
+  };
+
   const styles = useStyles2(getStyles);
+  const matching = query.matching ?? {};
 
   const executeQuery = () => {
     if (query.expression) {
@@ -32,7 +58,7 @@ export const Math: FC<Props> = ({ labelWidth, onChange, query, onRunQuery }) =>
   };
 
   return (
-    <Stack direction="row">
+    <Stack direction="column">
       <InlineField
         label={
           <InlineLabel width="auto">
@@ -90,6 +116,22 @@ export const Math: FC<Props> = ({ labelWidth, onChange, query, onRunQuery }) =>
                       name="floor"
                       description="rounds the number down to the nearest integer value. It's able to operate on series or escalar values."
                     />
+                    <DocumentedFunction
+                      name="rate and delta"
+                      description="returns the per-second rate of change or the difference between each point of a series and the point before it."
+                    />
+                    <DocumentedFunction
+                      name="time_shift"
+                      description='moves the points of a series forward in time by a duration, e.g. time_shift($A, "1d").'
+                    />
+                    <DocumentedFunction
+                      name="moving_avg"
+                      description="returns the average of each point of a series and the points before it, e.g. moving_avg($A, 5)."
+                    />
+                    <DocumentedFunction
+                      name="cumsum"
+                      description="returns the running sum of the values of a series."
+                    />
                   </div>
                   <div>
                     See our additional documentation on{' '}
@@ -125,6 +167,45 @@ export const Math: FC<Props> = ({ labelWidth, onChange, query, onRunQuery }) =>
           style={{ minWidth: 250, lineHeight: '26px', minHeight: 32 }}
         />
       </InlineField>
+      <InlineFieldRow>
+        <InlineField label="Match" tooltip="How the results of both sides of an operation are joined by their labels">
+          <Select
+            options={matchingModes}
+            value={matching.mode}
+            onChange={(v) => onMatchingChange({ ...matching, mode: v.value, labels: v.value ? matching.labels : undefined })}
+            width={14}
+          />
+        </InlineField>
+        {matching.mode && (
+          <InlineField label="Labels">
+            <Input
+              defaultValue={matching.labels?.join(', ')}
+              placeholder="host, instance"
+              onBlur={(e) => onMatchingChange({ ...matching, labels: splitLabels(e.currentTarget.value) })}
+              width={20}
+            />
+          </InlineField>
+        )}
+        <InlineField label="Group">
+          <Select
+            options={matchingGroups}
+            value={matching.group}
+            onChange={(v) =>
+              onMatchingChange({ ...matching, group: v.value, include: v.value ? matching.include : undefined })
+            }
+            width={16}
+          />
+        </InlineField>
+        {matching.group && (
+          <InlineField label="Include" tooltip="Labels of the other side that are copied to the results">
+            <Input
+              defaultValue={matching.include?.join(', ')}
+              onBlur={(e) => onMatchingChange({ ...matching, include: splitLabels(e.currentTarget.value) })}
+              width={20}
+            />
+          </InlineField>
+        )}
+      </InlineFieldRow>
     </Stack>
   );
 };
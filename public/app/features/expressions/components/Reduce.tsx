@@ -20,7 +20,12 @@ export const Reduce: FC<Props> = ({ labelWidth = 'auto', onChange, refIds, query
   };
 
   const onSelectReducer = (value: SelectableValue<string>) => {
-    onChange({ ...query, reducer: value.value });
+    const reducerParams = value.value === 'percentile' ? query.reducerParams ?? [95] : undefined;
+    onChange({ ...query, reducer: value.value, reducerParams });
+  };
+
+  const onPercentileChanged = (e: React.FormEvent<HTMLInputElement>) => {
+    onChange({ ...query, reducerParams: [e.currentTarget.valueAsNumber] });
   };
 
   const onSettingsChanged = (settings: ExpressionQuerySettings) => {
@@ -66,11 +71,30 @@ export const Reduce: FC<Props> = ({ labelWidth = 'auto', onChange, refIds, query
     );
   };
 
+  const percentile = () => {
+    if (query.reducer !== 'percentile') {
+      return;
+    }
+    return (
+      <InlineField label="Percentile" labelWidth={labelWidth}>
+        <Input
+          type="number"
+          min={0}
+          max={100}
+          width={10}
+          onChange={onPercentileChanged}
+          value={query.reducerParams?.[0] ?? 95}
+        />
+      </InlineField>
+    );
+  };
+
   return (
     <InlineFieldRow>
       <InlineField label="Function" labelWidth={labelWidth}>
         <Select options={reducerTypes} value={reducer} onChange={onSelectReducer} width={25} />
       </InlineField>
+      {percentile()}
       <InlineField label="Input" labelWidth={labelWidth}>
         <Select onChange={onRefIdChange} options={refIds} value={query.expression} width={20} />
       </InlineField>
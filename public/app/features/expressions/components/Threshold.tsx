@@ -2,7 +2,7 @@ import { css } from '@emotion/css';
 import React, { FC, FormEvent } from 'react';
 
 import { GrafanaTheme2, SelectableValue } from '@grafana/data';
-import { ButtonSelect, InlineField, InlineFieldRow, Input, Select, useStyles2 } from '@grafana/ui';
+import { ButtonSelect, InlineField, InlineFieldRow, InlineSwitch, Input, Select, Stack, useStyles2 } from '@grafana/ui';
 import { EvalFunction } from 'app/features/alerting/state/alertDef';
 
 import { ClassicCondition, ExpressionQuery, thresholdFunctions } from '../types';
@@ -63,45 +63,115 @@ export const Threshold: FC<Props> = ({ labelWidth, onChange, refIds, query }) =>
     });
   };
 
+  const onUnloadEvaluatorToggle = () => {
+    const unloadEvaluator = condition.unloadEvaluator ? undefined : { type: EvalFunction.IsBelow, params: [0, 0] };
+    onChange({ ...query, conditions: [{ ...condition, unloadEvaluator }] });
+  };
+
+  const onUnloadEvalFunctionChange = (value: SelectableValue<EvalFunction>) => {
+    const type = value.value ?? EvalFunction.IsBelow;
+    onChange({
+      ...query,
+      conditions: [{ ...condition, unloadEvaluator: { params: [0, 0], ...condition.unloadEvaluator, type } }],
+    });
+  };
+
+  const onUnloadValueChange = (event: FormEvent<HTMLInputElement>, index: number) => {
+    const newParams = [...(condition.unloadEvaluator?.params ?? [0, 0])];
+    newParams[index] = parseFloat(event.currentTarget.value);
+    onChange({
+      ...query,
+      conditions: [
+        {
+          ...condition,
+          unloadEvaluator: { type: EvalFunction.IsBelow, ...condition.unloadEvaluator, params: newParams },
+        },
+      ],
+    });
+  };
+
   const isRange =
     condition.evaluator.type === EvalFunction.IsWithinRange || condition.evaluator.type === EvalFunction.IsOutsideRange;
 
+  const unloadEvaluator = condition.unloadEvaluator;
+  const isUnloadRange =
+    unloadEvaluator?.type === EvalFunction.IsWithinRange || unloadEvaluator?.type === EvalFunction.IsOutsideRange;
+
   return (
-    <InlineFieldRow>
-      <InlineField label="Input" labelWidth={labelWidth}>
-        <Select onChange={onRefIdChange} options={refIds} value={query.expression} width={20} />
-      </InlineField>
-      <ButtonSelect
-        className={styles.buttonSelectText}
-        options={thresholdFunctions}
-        onChange={onEvalFunctionChange}
-        value={thresholdFunction}
-      />
-      {isRange ? (
-        <>
+    <Stack direction="column" gap={0}>
+      <InlineFieldRow>
+        <InlineField label="Input" labelWidth={labelWidth}>
+          <Select onChange={onRefIdChange} options={refIds} value={query.expression} width={20} />
+        </InlineField>
+        <ButtonSelect
+          className={styles.buttonSelectText}
+          options={thresholdFunctions}
+          onChange={onEvalFunctionChange}
+          value={thresholdFunction}
+        />
+        {isRange ? (
+          <>
+            <Input
+              type="number"
+              width={10}
+              onChange={(event) => onEvaluateValueChange(event, 0)}
+              defaultValue={condition.evaluator.params[0]}
+            />
+            <div className={styles.button}>TO</div>
+            <Input
+              type="number"
+              width={10}
+              onChange={(event) => onEvaluateValueChange(event, 1)}
+              defaultValue={condition.evaluator.params[1]}
+            />
+          </>
+        ) : (
           <Input
             type="number"
             width={10}
             onChange={(event) => onEvaluateValueChange(event, 0)}
-            defaultValue={condition.evaluator.params[0]}
+            defaultValue={conditions[0].evaluator.params[0] || 0}
           />
-          <div className={styles.button}>TO</div>
-          <Input
-            type="number"
-            width={10}
-            onChange={(event) => onEvaluateValueChange(event, 1)}
-            defaultValue={condition.evaluator.params[1]}
-          />
-        </>
-      ) : (
-        <Input
-          type="number"
-          width={10}
-          onChange={(event) => onEvaluateValueChange(event, 0)}
-          defaultValue={conditions[0].evaluator.params[0] || 0}
-        />
-      )}
-    </InlineFieldRow>
+        )}
+      </InlineFieldRow>
+      <InlineFieldRow>
+        <InlineField
+          label="Custom recovery threshold"
+          labelWidth={labelWidth}
+          tooltip="Resolve firing alerts with a different threshold than the one that makes them fire, to prevent alerts from flapping when the value stays around the threshold"
+        >
+          <InlineSwitch value={!!unloadEvaluator} onChange={onUnloadEvaluatorToggle} />
+        </InlineField>
+        {unloadEvaluator && (
+          <>
+            <div className={styles.button}>Stop alerting when input</div>
+            <ButtonSelect
+              className={styles.buttonSelectText}
+              options={thresholdFunctions}
+              onChange={onUnloadEvalFunctionChange}
+              value={thresholdFunctions.find((fn) => fn.value === unloadEvaluator.type)}
+            />
+            <Input
+              type="number"
+              width={10}
+              onChange={(event) => onUnloadValueChange(event, 0)}
+              defaultValue={unloadEvaluator.params[0] || 0}
+            />
+            {isUnloadRange && (
+              <>
+                <div className={styles.button}>TO</div>
+                <Input
+                  type="number"
+                  width={10}
+                  onChange={(event) => onUnloadValueChange(event, 1)}
+                  defaultValue={unloadEvaluator.params[1] || 0}
+                />
+              </>
+            )}
+          </>
+        )}
+      </InlineFieldRow>
+    </Stack>
   );
 };
 
@@ -48,6 +48,12 @@ export const reducerTypes: Array<SelectableValue<string>> = [
   { value: ReducerID.sum, label: 'Sum', description: 'Get the sum of all values' },
   { value: ReducerID.count, label: 'Count', description: 'Get the number of values' },
   { value: ReducerID.last, label: 'Last', description: 'Get the last value' },
+  { value: ReducerID.first, label: 'First', description: 'Get the first value' },
+  { value: ReducerID.diff, label: 'Difference', description: 'Get the last value minus the first value' },
+  { value: 'count_non_null', label: 'Count non-null', description: 'Get the number of values that are not null' },
+  { value: 'median', label: 'Median', description: 'Get the median value' },
+  { value: 'stddev', label: 'Standard deviation', description: 'Get the standard deviation of the values' },
+  { value: 'percentile', label: 'Percentile', description: 'Get the value at the given percentile' },
 ];
 
 export enum ReducerMode {
@@ -107,6 +113,18 @@ export interface ExpressionQuery extends DataQuery {
   upsampler?: string;
   conditions?: ClassicCondition[];
   settings?: ExpressionQuerySettings;
+  reducerParams?: number[];
+  matching?: LabelMatching;
+}
+
+/**
+ * Controls how the results of the two sides of binary math operations are joined by their labels.
+ */
+export interface LabelMatching {
+  mode?: 'on' | 'ignoring';
+  labels?: string[];
+  group?: 'left' | 'right';
+  include?: string[];
 }
 
 export interface ExpressionQuerySettings {
@@ -119,6 +137,14 @@ export interface ClassicCondition {
     params: number[];
     type: EvalFunction;
   };
+  /**
+   * Only used by threshold expressions: the condition that resolves a firing alert instance.
+   * If it is not set, the instance is resolved when the evaluator is no longer met.
+   */
+  unloadEvaluator?: {
+    params: number[];
+    type: EvalFunction;
+  };
   operator?: {
     type: string;
   };
@@ -139,7 +165,10 @@ export type ReducerType =
   | 'sum'
   | 'count'
   | 'last'
+  | 'first'
   | 'median'
+  | 'stddev'
+  | 'percentile'
   | 'diff'
   | 'diff_abs'
   | 'percent_diff'
@@ -9,6 +9,35 @@ export interface ChannelAuthConfig {
   subscribe?: ChannelAuthCheckConfig;
   publish?: ChannelAuthCheckConfig;
 }
+export interface KafkaSourceConfig {
+  brokers: string[];
+  tls?: SourceTLSConfig;
+  topic: string;
+  groupId: string;
+}
+export interface SourceTLSConfig {
+  caCert?: string;
+  serverName?: string;
+  insecureSkipVerify?: boolean;
+}
+export interface MQTTSourceConfig {
+  url: string;
+  tls?: SourceTLSConfig;
+  topic: string;
+  qos?: number;
+  clientId?: string;
+}
+export interface BasicAuth {
+  user?: string;
+  password?: string;
+}
+export interface SourceConfig {
+  type: Omit<keyof SourceConfig, 'type'>;
+  basicAuth?: BasicAuth;
+  secureSettings?: { [key: string]: string };
+  mqtt?: MQTTSourceConfig;
+  kafka?: KafkaSourceConfig;
+}
 export interface ChangeLogOutputConfig {
   fieldName: string;
   channel: string;
@@ -57,6 +86,21 @@ export interface FrameOutputterConfig {
   loki?: LokiOutputConfig;
   changeLog?: ChangeLogOutputConfig;
 }
+export interface ExpressionField {
+  name: string;
+  type?: string;
+  expression: string;
+  config?: FieldConfig;
+}
+export interface ExpressionFrameProcessorConfig {
+  fields: ExpressionField[];
+}
+export interface AggregateFrameProcessorConfig {
+  intervalMilliseconds: number;
+  functions: string[];
+  groupBy?: string[];
+  timeField?: string;
+}
 export interface MultipleFrameProcessorConfig {
   processors: FrameProcessorConfig[];
 }
@@ -71,6 +115,14 @@ export interface FrameProcessorConfig {
   dropFields?: DropFieldsFrameProcessorConfig;
   keepFields?: KeepFieldsFrameProcessorConfig;
   multiple?: MultipleFrameProcessorConfig;
+  aggregate?: AggregateFrameProcessorConfig;
+  expression?: ExpressionFrameProcessorConfig;
+}
+export interface AutoOTLPConverterConfig {
+  frameFormat: string;
+}
+export interface AutoPrometheusConverterConfig {
+  frameFormat: string;
 }
 export interface JsonFrameConverterConfig {}
 export interface AutoInfluxConverterConfig {
@@ -99,6 +151,8 @@ export interface ConverterConfig {
   jsonExact?: ExactJsonConverterConfig;
   influxAuto?: AutoInfluxConverterConfig;
   jsonFrame?: JsonFrameConverterConfig;
+  prometheusAuto?: AutoPrometheusConverterConfig;
+  otlpAuto?: AutoOTLPConverterConfig;
 }
 export interface LokiOutputConfig {
   uid: string;
@@ -125,6 +179,7 @@ export interface ChannelRuleSettings {
   converter?: ConverterConfig;
   frameProcessors?: FrameProcessorConfig[];
   frameOutputs?: FrameOutputterConfig[];
+  sources?: SourceConfig[];
 }
 export interface ChannelRule {
   pattern: string;
@@ -307,11 +307,20 @@ export interface TimeInterval {
   days_of_month?: string[];
   months?: string[];
   years?: string[];
+  location?: string;
+}
+
+export interface DateRange {
+  /** Times are in RFC3339 format or in format `YYYY-MM-DD HH:MM` in the location of the range */
+  start_time: string;
+  end_time: string;
+  location?: string;
 }
 
 export type MuteTimeInterval = {
   name: string;
   time_intervals: TimeInterval[];
+  date_ranges?: DateRange[];
   provenance?: string;
 };
 
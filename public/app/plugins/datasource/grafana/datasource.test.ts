@@ -1,7 +1,7 @@
-import { AnnotationQueryRequest, DataSourceInstanceSettings, dateTime } from '@grafana/data';
+import { AnnotationQueryRequest, DataSourceInstanceSettings, dateTime, FieldType, toDataFrame } from '@grafana/data';
 import { backendSrv } from 'app/core/services/backend_srv'; // will use the version in __mocks__
 
-import { GrafanaDatasource } from './datasource';
+import { filterFrameByTimeRange, GrafanaDatasource } from './datasource';
 import { GrafanaAnnotationQuery, GrafanaAnnotationType, GrafanaQuery } from './types';
 
 jest.mock('@grafana/runtime', () => ({
@@ -78,6 +78,31 @@ describe('grafana data source', () => {
   });
 });
 
+describe('filterFrameByTimeRange', () => {
+  const frame = toDataFrame({
+    fields: [
+      { name: 'time', type: FieldType.time, values: [1000, 2000, 3000, 4000] },
+      { name: 'value', type: FieldType.number, values: [1, 2, 3, 4] },
+    ],
+  });
+
+  it('should skip rows out of the time range', () => {
+    const filtered = filterFrameByTimeRange(frame, 2000, 3000);
+    expect(filtered.length).toBe(2);
+    expect(filtered.fields[0].values.toArray()).toEqual([2000, 3000]);
+    expect(filtered.fields[1].values.toArray()).toEqual([2, 3]);
+  });
+
+  it('should keep rows after the start of a relative time range', () => {
+    const filtered = filterFrameByTimeRange(frame, 2000);
+    expect(filtered.fields[1].values.toArray()).toEqual([2, 3, 4]);
+  });
+
+  it('should return the same frame if all rows are in the time range', () => {
+    expect(filterFrameByTimeRange(frame, 0)).toBe(frame);
+  });
+});
+
 function setupAnnotationQueryOptions(annotation: Partial<GrafanaAnnotationQuery>, dashboard?: { uid: string }) {
   return {
     annotation: {
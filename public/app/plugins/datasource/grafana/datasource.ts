@@ -5,6 +5,8 @@ import { map } from 'rxjs/operators';
 import {
   AnnotationQuery,
   AnnotationQueryRequest,
+  ArrayVector,
+  DataFrame,
   DataFrameView,
   DataQueryRequest,
   DataQueryResponse,
@@ -15,6 +17,8 @@ import {
   parseLiveChannelAddress,
   toDataFrame,
   dataFrameFromJSON,
+  FieldType,
+  isDataFrame,
   LoadingState,
 } from '@grafana/data';
 import {
@@ -111,13 +115,28 @@ export class GrafanaDatasource extends DataSourceWithBackend<GrafanaQuery> {
           buffer.maxDelta = request.range.to.valueOf() - request.range.from.valueOf();
         }
 
+        // Managed streams replay their history on subscribe. The channel is shared by all panels,
+        // so rows out of the time range of this panel are skipped here. Relative ranges keep
+        // receiving new data after the request.
+        const rangeFrom = request.range.from.valueOf();
+        const rangeTo = request.rangeRaw?.to === 'now' ? undefined : request.range.to.valueOf();
+
         results.push(
-          getGrafanaLiveSrv().getDataStream({
-            key: `${request.requestId}.${counter++}`,
-            addr: addr!,
-            filter,
-            buffer,
-          })
+          getGrafanaLiveSrv()
+            .getDataStream({
+              key: `${request.requestId}.${counter++}`,
+              addr: addr!,
+              filter,
+              buffer,
+            })
+            .pipe(
+              map((response) => ({
+                ...response,
+                data: response.data.map((frame) =>
+                  isDataFrame(frame) ? filterFrameByTimeRange(frame, rangeFrom, rangeTo) : frame
+                ),
+              }))
+            )
         );
       } else {
         if (!target.queryType) {
@@ -232,3 +251,33 @@ export interface FileElement {
   name: string;
   ['media-type']: string;
 }
+
+/**
+ * Returns the frame without rows out of the time range, or the frame itself if all rows are in range.
+ * Rows after `to` are kept if it is undefined.
+ */
+export function filterFrameByTimeRange(frame: DataFrame, from: number, to?: number): DataFrame {
+  const timeField = frame.fields.find((f) => f.type === FieldType.time);
+  if (!timeField) {
+    return frame;
+  }
+  const rows: number[] = [];
+  for (let i = 0; i < frame.length; i++) {
+    const time = timeField.values.get(i);
+    if (time != null && (time < from || (to !== undefined && time > to))) {
+      continue;
+    }
+    rows.push(i);
+  }
+  if (rows.length === frame.length) {
+    return frame;
+  }
+  // A plain frame, not a streaming one, so panels process all of its rows.
+  return {
+    name: frame.name,
+    refId: frame.refId,
+    meta: frame.meta,
+    fields: frame.fields.map((f) => ({ ...f, values: new ArrayVector(rows.map((i) => f.values.get(i))) })),
+    length: rows.length,
+  };
+}